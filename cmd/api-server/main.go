@@ -4,24 +4,30 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
 	"syscall"
 
-	"github.com/go-chi/chi/v5"
-	"github.com/go-chi/chi/v5/middleware"
-	"github.com/jackc/pgx/v5/pgxpool"
 	_ "github.com/kodeyeen/shortify/docs"
 	"github.com/kodeyeen/shortify/internal/config"
-	"github.com/kodeyeen/shortify/internal/delivery/http/httpmw"
+	grpcdel "github.com/kodeyeen/shortify/internal/delivery/grpc"
 	httpdel "github.com/kodeyeen/shortify/internal/delivery/http/v1"
+	"github.com/kodeyeen/shortify/internal/generation/blocklist"
+	"github.com/kodeyeen/shortify/internal/generation/keyspace"
+	"github.com/kodeyeen/shortify/internal/generation/pool"
 	"github.com/kodeyeen/shortify/internal/generation/rand"
-	"github.com/kodeyeen/shortify/internal/persistence"
-	"github.com/kodeyeen/shortify/internal/persistence/inmemory"
+	"github.com/kodeyeen/shortify/internal/generation/sequence"
+	"github.com/kodeyeen/shortify/internal/generation/words"
+	"github.com/kodeyeen/shortify/internal/opengraph"
 	"github.com/kodeyeen/shortify/internal/persistence/postgres"
+	"github.com/kodeyeen/shortify/internal/storage"
 	"github.com/kodeyeen/shortify/internal/url"
+	"github.com/kodeyeen/shortify/internal/webhook"
+	"github.com/kodeyeen/shortify/migrations"
 	httpswagger "github.com/swaggo/http-swagger/v2"
+	"google.golang.org/grpc"
 )
 
 //	@title			Shortify API
@@ -35,6 +41,12 @@ import (
 
 // @license.name	BSD 3-Clause "New" or "Revised" License
 func main() {
+	os.Exit(run())
+}
+
+// run starts the servers and returns the exit code once they are stopped.
+// Returning instead of exiting lets the deferred cleanup close the storage.
+func run() int {
 	cfg := config.MustLoad()
 
 	ctx := context.Background()
@@ -44,55 +56,211 @@ func main() {
 	log.Info("starting shortify", slog.String("env", cfg.Env))
 	log.Debug("debug log level enabled")
 
-	var urlRepo url.Repository
-
-	switch cfg.PersistenceType {
-	case config.PersistenceTypeInmemory:
-		urlRepo = inmemory.NewURLRepository()
-	case config.PersistenceTypePostgres:
-		connString := persistence.NewConnString(
-			"postgres",
-			cfg.Postgres.Username,
-			cfg.Postgres.Password,
-			cfg.Postgres.Host,
-			cfg.Postgres.Database,
-		)
+	store, err := storage.Open(ctx, cfg)
+	if err != nil {
+		log.Error("failed to open storage", slog.String("error", err.Error()))
+		return 1
+	}
+
+	// deferred first so it runs after the background workers writing to it stop
+	defer func() {
+		if err := store.Close(); err != nil {
+			log.Error("failed to close storage", slog.String("error", err.Error()))
+		}
+	}()
 
-		dbpool, err := pgxpool.New(ctx, connString)
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		err := runMigrate(ctx, store.DB, os.Args[2:], os.Stdout)
 		if err != nil {
-			log.Error("failed to create dbpool", slog.String("error", err.Error()))
-			os.Exit(1)
+			log.Error("failed to migrate", slog.String("error", err.Error()))
+			return 1
 		}
-		defer dbpool.Close()
 
-		err = dbpool.Ping(ctx)
+		return 0
+	}
+
+	if store.DB != nil && cfg.Postgres.AutoMigrate {
+		applied, err := postgres.NewMigrator(store.DB, migrations.FS).Up(ctx)
 		if err != nil {
-			log.Error("failed to ping db", slog.String("error", err.Error()))
-			os.Exit(1)
+			log.Error("failed to apply migrations", slog.String("error", err.Error()))
+			return 1
 		}
 
-		urlRepo = postgres.NewURLRepository(dbpool)
-	default:
-		log.Error("invalid persistence type config", slog.String("persistence_type", cfg.PersistenceType))
-		os.Exit(1)
+		log.Info("migrations applied", slog.Int("count", len(applied)))
 	}
 
+	urlRepo := store.URLs
+	webhookRepo := store.Webhooks
+
 	log.Info("initialized url repository", slog.String("persistence_type", cfg.PersistenceType))
 
-	aliasPrvr := rand.NewAliasProvider(cfg.Alias.Charset, cfg.Alias.Length)
-	urlSvc := url.NewService(urlRepo, aliasPrvr, log)
+	var urlOpts []url.Option
+
+	if cfg.OpenGraph.Enabled {
+		ogFetcher := opengraph.NewFetcher(
+			opengraph.NewHTTPClient(cfg.OpenGraph.Timeout, cfg.OpenGraph.AllowPrivateNetworks),
+			cfg.OpenGraph.MaxBodySize,
+		)
+		ogPool := opengraph.NewPool(
+			ogFetcher,
+			urlRepo,
+			cfg.OpenGraph.Workers,
+			cfg.OpenGraph.QueueSize,
+			cfg.OpenGraph.Timeout,
+			log,
+		)
+
+		ogPool.Start(ctx)
+		defer ogPool.Stop()
+
+		urlOpts = append(urlOpts, url.WithMetadataQueue(ogPool))
+
+		log.Info("open graph fetching enabled", slog.Int("workers", cfg.OpenGraph.Workers))
+	}
+
+	webhookSvc := webhook.NewService(webhookRepo, log)
+	webhookClr := httpdel.NewWebhookController(webhookSvc, log)
+
+	if cfg.Webhooks.Enabled {
+		dispatcher := webhook.NewDispatcher(
+			webhookRepo,
+			opengraph.NewHTTPClient(cfg.Webhooks.Timeout, cfg.Webhooks.AllowPrivateNetworks),
+			webhook.DispatcherConfig{
+				PollInterval:   cfg.Webhooks.PollInterval,
+				Timeout:        cfg.Webhooks.Timeout,
+				BatchSize:      cfg.Webhooks.BatchSize,
+				Workers:        cfg.Webhooks.Workers,
+				MaxAttempts:    cfg.Webhooks.MaxAttempts,
+				InitialBackoff: cfg.Webhooks.InitialBackoff,
+				MaxBackoff:     cfg.Webhooks.MaxBackoff,
+			},
+			log,
+		)
+
+		dispatchCtx, stopDispatch := context.WithCancel(ctx)
+		dispatched := dispatcher.Start(dispatchCtx)
+
+		defer func() {
+			stopDispatch()
+			<-dispatched
+		}()
+
+		urlOpts = append(urlOpts,
+			url.WithNotifier(webhookSvc),
+			url.WithClickThresholds(cfg.Webhooks.ClickThresholds),
+		)
+
+		log.Info("webhooks enabled", slog.Int("workers", cfg.Webhooks.Workers))
+	}
+
+	wordsPrvr, err := words.NewAliasProvider(words.DefaultDictionary(), cfg.Alias.Words.Count, cfg.Alias.Words.Separator)
+	if err != nil {
+		log.Error("failed to create word alias provider", slog.String("error", err.Error()))
+		return 1
+	}
+
+	aliasPrvr, err := newAliasProvider(cfg, store, wordsPrvr)
+	if err != nil {
+		log.Error("failed to create alias provider", slog.String("error", err.Error()))
+		return 1
+	}
+
+	aliasKeyspace := keyspace.Size(cfg.Alias.Charset, cfg.Alias.Length)
+	if cfg.Alias.Provider == config.AliasProviderWords {
+		aliasKeyspace = wordsPrvr.Keyspace()
+	}
+
+	log.Info("initialized alias provider",
+		slog.String("provider", cfg.Alias.Provider),
+		slog.String("keyspace", aliasKeyspace.String()),
+		slog.Float64("words_entropy_bits", wordsPrvr.Entropy()),
+		slog.String("words_keyspace", wordsPrvr.Keyspace().String()),
+	)
+
+	for _, warning := range keyspace.Check(cfg.Alias.Charset) {
+		log.Warn(warning)
+	}
+
+	// the providers requests may choose by name
+	aliasPrvrs := map[string]url.AliasProvider{
+		config.AliasProviderRandom: rand.NewAliasProvider(cfg.Alias.Charset, cfg.Alias.Length),
+		config.AliasProviderWords:  wordsPrvr,
+		cfg.Alias.Provider:         aliasPrvr,
+	}
+
+	if cfg.Alias.Blocklist.Enabled {
+		blockList, err := newBlocklist(cfg, log)
+		if err != nil {
+			log.Error("failed to load alias blocklist", slog.String("error", err.Error()))
+			return 1
+		}
+
+		blockList.Start(ctx)
+		defer blockList.Stop()
+
+		for name, prvr := range aliasPrvrs {
+			aliasPrvrs[name] = blocklist.NewFilter(prvr, blockList)
+		}
+
+		log.Info("alias blocklist enabled", slog.String("path", cfg.Alias.Blocklist.Path))
+	}
+
+	aliasPrvr = aliasPrvrs[cfg.Alias.Provider]
+
+	if cfg.Alias.Pool.Enabled {
+		var poolStore pool.Store = pool.NewMemoryStore(urlRepo)
+		if store.DB != nil {
+			poolStore = postgres.NewAliasPool(store.DB)
+		}
+
+		aliasPool := pool.New(aliasPrvr, poolStore, pool.Config{
+			Size:           cfg.Alias.Pool.Size,
+			LowWater:       cfg.Alias.Pool.LowWater,
+			RefillInterval: cfg.Alias.Pool.RefillInterval,
+			TTL:            cfg.Alias.Pool.TTL,
+			Timeout:        cfg.Alias.Pool.Timeout,
+		}, log)
+
+		aliasPool.Start(ctx)
+		defer aliasPool.Stop(context.Background())
+
+		aliasPrvr = aliasPool
+		aliasPrvrs[cfg.Alias.Provider] = aliasPool
+
+		log.Info("alias pool enabled", slog.Int("size", cfg.Alias.Pool.Size))
+	}
+
+	urlOpts = append(urlOpts,
+		url.WithKeyspace(cfg.Alias.Provider, aliasKeyspace),
+		url.WithAliasProviders(aliasPrvrs),
+		url.WithAliasQuarantine(cfg.Alias.Quarantine),
+		url.WithAuditLog(store.Audit),
+		url.WithTrashRetention(cfg.Trash.Retention),
+	)
+
+	if cfg.Alias.RedirectRetired {
+		urlOpts = append(urlOpts, url.WithRetiredAliasRedirect())
+	}
+
+	if cfg.Alias.CaseInsensitive {
+		urlOpts = append(urlOpts, url.WithCaseInsensitiveAliases())
+	}
+
+	urlSvc := url.NewService(urlRepo, aliasPrvr, log, urlOpts...)
 	urlClr := httpdel.NewURLController(urlSvc, log)
 
-	router := chi.NewRouter()
-	router.Use(middleware.RequestID)
-	router.Use(middleware.Recoverer)
-	router.Use(middleware.URLFormat)
-	router.Use(httpmw.NewLogger(log))
+	purgeCtx, stopPurge := context.WithCancel(ctx)
+	purged := urlSvc.StartPurge(purgeCtx, cfg.Trash.PurgeInterval)
+
+	// the purge may be writing to the storage, so it's waited for before the storage is closed
+	defer func() {
+		stopPurge()
+		<-purged
+	}()
+
+	log.Info("trash purging started", slog.Duration("retention", cfg.Trash.Retention))
 
-	router.Route("/api/v1", func(r chi.Router) {
-		r.Post("/urls", urlClr.Create)
-		r.Get("/urls/{alias}", urlClr.GetByAlias)
-	})
+	router := httpdel.NewRouter(urlClr, webhookClr, log)
 
 	router.Get("/swagger/*", httpswagger.Handler(
 		httpswagger.URL(fmt.Sprintf("http://localhost:%d/swagger/doc.json", cfg.HTTPServer.Port)),
@@ -121,19 +289,96 @@ func main() {
 
 	log.Info("server started")
 
+	var grpcSrv *grpc.Server
+
+	if cfg.GRPCServer.Enabled {
+		grpcAddr := fmt.Sprintf(":%d", cfg.GRPCServer.Port)
+
+		lis, err := net.Listen("tcp", grpcAddr)
+		if err != nil {
+			log.Error("failed to listen grpc address", slog.String("error", err.Error()))
+			return 1
+		}
+
+		grpcSrv = grpcdel.NewServer(grpcdel.NewURLServer(urlSvc, log), log)
+
+		go func() {
+			if err := grpcSrv.Serve(lis); err != nil {
+				log.Error("failed to start grpc server", slog.String("error", err.Error()))
+			}
+		}()
+
+		log.Info("grpc server started", slog.String("address", grpcAddr))
+	}
+
 	<-done
 	log.Info("stopping server")
 
 	ctx, cancel := context.WithTimeout(context.Background(), cfg.HTTPServer.ShutdownTimeout)
 	defer cancel()
 
+	if grpcSrv != nil {
+		stopped := make(chan struct{})
+
+		go func() {
+			grpcSrv.GracefulStop()
+			close(stopped)
+		}()
+
+		select {
+		case <-stopped:
+		case <-ctx.Done():
+			grpcSrv.Stop()
+		}
+
+		log.Info("grpc server stopped")
+	}
+
 	if err := srv.Shutdown(ctx); err != nil {
 		log.Error("failed to stop server", slog.String("error", err.Error()))
 
-		os.Exit(1)
+		return 1
 	}
 
 	log.Info("server stopped")
+
+	return 0
+}
+
+func newAliasProvider(cfg *config.Config, store *storage.Storage, wordsPrvr *words.AliasProvider) (url.AliasProvider, error) {
+	switch cfg.Alias.Provider {
+	case config.AliasProviderRandom:
+		return rand.NewAliasProvider(cfg.Alias.Charset, cfg.Alias.Length), nil
+	case config.AliasProviderSequence:
+		var counter sequence.Counter
+
+		switch {
+		case store.DB != nil:
+			counter = postgres.NewAliasSequence(store.DB)
+		case cfg.PersistenceType == config.PersistenceTypeInmemory && cfg.Inmemory.Dir == "":
+			counter = sequence.NewAtomicCounter(0)
+		default:
+			// an in-memory counter would start over on restart and collide with the stored aliases
+			return nil, fmt.Errorf("sequence aliases aren't supported by %s persistence", cfg.PersistenceType)
+		}
+
+		return sequence.NewAliasProvider(counter, cfg.Alias.Charset, cfg.Alias.Length, cfg.Alias.SequenceKey), nil
+	case config.AliasProviderWords:
+		return wordsPrvr, nil
+	default:
+		return nil, fmt.Errorf("invalid alias provider %q", cfg.Alias.Provider)
+	}
+}
+
+// newBlocklist returns the alias blocklist read from the configured file or the embedded one.
+func newBlocklist(cfg *config.Config, log *slog.Logger) (*blocklist.List, error) {
+	bl := cfg.Alias.Blocklist
+
+	if bl.Path == "" {
+		return blocklist.NewList(blocklist.Default().WithPrefixes(bl.ReservedPrefixes...), log), nil
+	}
+
+	return blocklist.OpenList(bl.Path, bl.ReservedPrefixes, bl.ReloadInterval, log)
 }
 
 func newLogger(env string) *slog.Logger {
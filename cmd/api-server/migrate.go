@@ -0,0 +1,81 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/kodeyeen/shortify/internal/persistence/postgres"
+	"github.com/kodeyeen/shortify/migrations"
+)
+
+const migrateUsage = "usage: api-server migrate [up | down [N | all] | status]"
+
+var errNoDB = errors.New("migrations are only supported by postgres persistence")
+
+// runMigrate runs the migrate subcommand against the embedded migrations.
+func runMigrate(ctx context.Context, dbpool *pgxpool.Pool, args []string, out io.Writer) error {
+	if dbpool == nil {
+		return errNoDB
+	}
+
+	migrator := postgres.NewMigrator(dbpool, migrations.FS)
+
+	cmd := "up"
+	if len(args) > 0 {
+		cmd = args[0]
+	}
+
+	switch {
+	case cmd == "up" && len(args) <= 1:
+		applied, err := migrator.Up(ctx)
+
+		for _, mig := range applied {
+			fmt.Fprintf(out, "applied %d_%s\n", mig.Version, mig.Name)
+		}
+
+		return err
+	case cmd == "down" && len(args) <= 2:
+		steps := 1
+
+		if len(args) == 2 && args[1] == "all" {
+			steps = 0
+		} else if len(args) == 2 {
+			n, err := strconv.Atoi(args[1])
+			if err != nil || n < 1 {
+				return fmt.Errorf("invalid number of steps %q\n%s", args[1], migrateUsage)
+			}
+
+			steps = n
+		}
+
+		reverted, err := migrator.Down(ctx, steps)
+
+		for _, mig := range reverted {
+			fmt.Fprintf(out, "reverted %d_%s\n", mig.Version, mig.Name)
+		}
+
+		return err
+	case cmd == "status" && len(args) == 1:
+		statuses, err := migrator.Status(ctx)
+		if err != nil {
+			return err
+		}
+
+		for _, st := range statuses {
+			state := "pending"
+			if st.Applied {
+				state = "applied"
+			}
+
+			fmt.Fprintf(out, "%-8s %d_%s\n", state, st.Version, st.Name)
+		}
+
+		return nil
+	default:
+		return errors.New(migrateUsage)
+	}
+}
@@ -0,0 +1,311 @@
+// Command shortify-admin runs maintenance tasks directly against the configured storage.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"os/signal"
+	"syscall"
+	"text/tabwriter"
+	"time"
+
+	"github.com/kodeyeen/shortify/internal/admin"
+	"github.com/kodeyeen/shortify/internal/config"
+	"github.com/kodeyeen/shortify/internal/generation/words"
+	"github.com/kodeyeen/shortify/internal/persistence/postgres"
+	"github.com/kodeyeen/shortify/internal/storage"
+	"github.com/kodeyeen/shortify/migrations"
+)
+
+// Exit codes.
+const (
+	exitOK         = 0
+	exitError      = 1
+	exitUsage      = 2
+	exitViolations = 3
+)
+
+const usage = `Usage: shortify-admin [flags] <command> [args]
+
+Commands:
+  migrate          apply pending schema migrations (postgres)
+  reindex          rebuild the storage indexes
+  verify-aliases   check that generated aliases match the alias config
+  stats            compute statistics of the stored links
+  purge            delete the links in the trash for good
+
+Flags:
+`
+
+var errUsage = errors.New("invalid usage")
+
+// errViolations reports aliases not matching the alias config.
+var errViolations = errors.New("invalid aliases found")
+
+type app struct {
+	cfg   *config.Config
+	store *storage.Storage
+
+	stdout io.Writer
+	stderr io.Writer
+}
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	os.Exit(run(ctx, os.Args[1:], os.Stdout, os.Stderr))
+}
+
+func run(ctx context.Context, args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("shortify-admin", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() {
+		fmt.Fprint(stderr, usage)
+		fs.PrintDefaults()
+	}
+
+	configPath := fs.String("config", os.Getenv("CONFIG_PATH"), "config file (env CONFIG_PATH)")
+
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return exitOK
+		}
+
+		return exitUsage
+	}
+
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return exitUsage
+	}
+
+	a := &app{
+		stdout: stdout,
+		stderr: stderr,
+	}
+
+	commands := map[string]func(ctx context.Context, args []string) error{
+		"migrate":        a.migrate,
+		"reindex":        a.reindex,
+		"verify-aliases": a.verifyAliases,
+		"stats":          a.stats,
+		"purge":          a.purge,
+	}
+
+	name := fs.Arg(0)
+
+	cmd, ok := commands[name]
+	if !ok {
+		fmt.Fprintf(stderr, "shortify-admin: unknown command %q\n", name)
+		fs.Usage()
+		return exitUsage
+	}
+
+	if *configPath == "" {
+		fmt.Fprintln(stderr, "shortify-admin: config file is not set")
+		return exitUsage
+	}
+
+	cfg, err := config.Load(*configPath)
+	if err != nil {
+		fmt.Fprintf(stderr, "shortify-admin: %s\n", err)
+		return exitUsage
+	}
+
+	store, err := storage.Open(ctx, cfg)
+	if err != nil {
+		fmt.Fprintf(stderr, "shortify-admin: failed to open storage: %s\n", err)
+		return exitError
+	}
+	defer store.Close()
+
+	a.cfg = cfg
+	a.store = store
+
+	err = cmd(ctx, fs.Args()[1:])
+	if err != nil {
+		fmt.Fprintf(stderr, "shortify-admin: %s\n", err)
+
+		switch {
+		case errors.Is(err, errUsage):
+			return exitUsage
+		case errors.Is(err, errViolations):
+			return exitViolations
+		default:
+			return exitError
+		}
+	}
+
+	return exitOK
+}
+
+func (a *app) migrate(ctx context.Context, args []string) error {
+	flags := flag.NewFlagSet("migrate", flag.ContinueOnError)
+	flags.SetOutput(a.stderr)
+
+	dir := flags.String("dir", "", "migrations directory, the embedded migrations by default")
+
+	if err := flags.Parse(args); err != nil {
+		return fmt.Errorf("%w: %s", errUsage, err)
+	}
+
+	if a.store.DB == nil {
+		fmt.Fprintf(a.stdout, "nothing to migrate for %s persistence\n", a.cfg.PersistenceType)
+		return nil
+	}
+
+	var fsys fs.FS = migrations.FS
+	if *dir != "" {
+		fsys = os.DirFS(*dir)
+	}
+
+	migrator := postgres.NewMigrator(a.store.DB, fsys)
+
+	applied, err := migrator.Up(ctx)
+
+	for _, mig := range applied {
+		fmt.Fprintf(a.stdout, "applied %d_%s\n", mig.Version, mig.Name)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	version, _, err := migrator.Version(ctx)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(a.stdout, "schema is at version %d\n", version)
+
+	return nil
+}
+
+func (a *app) reindex(ctx context.Context, args []string) error {
+	if len(args) > 0 {
+		return fmt.Errorf("%w: reindex takes no arguments", errUsage)
+	}
+
+	err := admin.Reindex(ctx, a.store.URLs)
+	if err != nil {
+		return fmt.Errorf("failed to reindex: %w", err)
+	}
+
+	fmt.Fprintln(a.stdout, "reindexed")
+
+	return nil
+}
+
+func (a *app) verifyAliases(ctx context.Context, args []string) error {
+	if len(args) > 0 {
+		return fmt.Errorf("%w: verify-aliases takes no arguments", errUsage)
+	}
+
+	// words aliases can be requested regardless of the configured provider
+	wordsFormat, err := words.NewAliasProvider(words.DefaultDictionary(), a.cfg.Alias.Words.Count, a.cfg.Alias.Words.Separator)
+	if err != nil {
+		return fmt.Errorf("failed to create words alias provider: %w", err)
+	}
+
+	violations, checked, err := admin.VerifyAliases(ctx, a.store.URLs, a.cfg.Alias.Charset, a.cfg.Alias.Length, wordsFormat)
+	if err != nil {
+		return err
+	}
+
+	if len(violations) > 0 {
+		tw := tabwriter.NewWriter(a.stdout, 0, 0, 2, ' ', 0)
+
+		fmt.Fprintln(tw, "ALIAS\tORIGINAL\tREASON")
+
+		for _, v := range violations {
+			fmt.Fprintf(tw, "%s\t%s\t%s\n", v.Alias, v.Original, v.Reason)
+		}
+
+		if err := tw.Flush(); err != nil {
+			return err
+		}
+	}
+
+	fmt.Fprintf(a.stdout, "checked %d aliases, %d invalid\n", checked, len(violations))
+
+	if len(violations) > 0 {
+		return errViolations
+	}
+
+	return nil
+}
+
+func (a *app) stats(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("stats", flag.ContinueOnError)
+	fs.SetOutput(a.stderr)
+
+	top := fs.Int("top", 10, "number of the most clicked links to show")
+
+	if err := fs.Parse(args); err != nil {
+		return fmt.Errorf("%w: %s", errUsage, err)
+	}
+
+	stats, err := admin.ComputeStats(ctx, a.store.URLs, *top)
+	if err != nil {
+		return err
+	}
+
+	tw := tabwriter.NewWriter(a.stdout, 0, 0, 2, ' ', 0)
+
+	fmt.Fprintf(tw, "Links:\t%d\n", stats.URLs)
+	fmt.Fprintf(tw, "Clicks:\t%d\n", stats.Clicks)
+	fmt.Fprintf(tw, "Never clicked:\t%d\n", stats.NeverClicked)
+	fmt.Fprintf(tw, "With Open Graph:\t%d\n", stats.OpenGraphURLs)
+	fmt.Fprintf(tw, "Tags:\t%d\n", len(stats.URLsByTag))
+
+	if len(stats.MostClicked) > 0 {
+		fmt.Fprintln(tw, "\nMOST CLICKED\tCLICKS")
+
+		for _, u := range stats.MostClicked {
+			fmt.Fprintf(tw, "%s (%s)\t%d\n", u.Alias, u.Original, u.Clicks)
+		}
+	}
+
+	return tw.Flush()
+}
+
+func (a *app) purge(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("purge", flag.ContinueOnError)
+	fs.SetOutput(a.stderr)
+
+	before := fs.String("before", "", "purge the links deleted before the RFC 3339 time, trash.retention ago by default")
+
+	if err := fs.Parse(args); err != nil {
+		return fmt.Errorf("%w: %s", errUsage, err)
+	}
+
+	if fs.NArg() > 0 {
+		return fmt.Errorf("%w: purge takes no arguments", errUsage)
+	}
+
+	deletedBefore := time.Now().Add(-a.cfg.Trash.Retention)
+
+	if *before != "" {
+		var err error
+
+		deletedBefore, err = time.Parse(time.RFC3339, *before)
+		if err != nil {
+			return fmt.Errorf("%w: invalid -before: %s", errUsage, err)
+		}
+	}
+
+	purged, err := a.store.URLs.Purge(ctx, deletedBefore)
+	if err != nil {
+		return fmt.Errorf("failed to purge: %w", err)
+	}
+
+	fmt.Fprintf(a.stdout, "purged %d links deleted before %s\n", purged, deletedBefore.Format(time.RFC3339))
+
+	return nil
+}
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRun(t *testing.T) {
+	config := writeConfig(t)
+
+	tests := []struct {
+		Name string
+
+		GivenArgs []string
+
+		ExpectedCode   int
+		ExpectedStdout string
+	}{
+		{
+			Name:           "migrate inmemory",
+			GivenArgs:      []string{"-config", config, "migrate"},
+			ExpectedCode:   exitOK,
+			ExpectedStdout: "nothing to migrate for inmemory persistence\n",
+		},
+		{
+			Name:           "reindex",
+			GivenArgs:      []string{"-config", config, "reindex"},
+			ExpectedCode:   exitOK,
+			ExpectedStdout: "reindexed\n",
+		},
+		{
+			Name:           "verify aliases",
+			GivenArgs:      []string{"-config", config, "verify-aliases"},
+			ExpectedCode:   exitOK,
+			ExpectedStdout: "checked 0 aliases, 0 invalid\n",
+		},
+		{
+			Name:         "stats",
+			GivenArgs:    []string{"-config", config, "stats", "-top", "5"},
+			ExpectedCode: exitOK,
+		},
+		{
+			Name:           "purge",
+			GivenArgs:      []string{"-config", config, "purge", "-before", "2025-03-01T12:00:00Z"},
+			ExpectedCode:   exitOK,
+			ExpectedStdout: "purged 0 links deleted before 2025-03-01T12:00:00Z\n",
+		},
+		{
+			Name:         "purge with invalid time",
+			GivenArgs:    []string{"-config", config, "purge", "-before", "yesterday"},
+			ExpectedCode: exitUsage,
+		},
+		{
+			Name:         "no command",
+			GivenArgs:    []string{"-config", config},
+			ExpectedCode: exitUsage,
+		},
+		{
+			Name:         "unknown command",
+			GivenArgs:    []string{"-config", config, "vacuum"},
+			ExpectedCode: exitUsage,
+		},
+		{
+			Name:         "unexpected argument",
+			GivenArgs:    []string{"-config", config, "reindex", "urls"},
+			ExpectedCode: exitUsage,
+		},
+		{
+			Name:         "missing config",
+			GivenArgs:    []string{"-config", filepath.Join(t.TempDir(), "missing.yaml"), "stats"},
+			ExpectedCode: exitUsage,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+
+			code := run(context.Background(), tt.GivenArgs, &stdout, &stderr)
+
+			require.Equal(t, tt.ExpectedCode, code, stderr.String())
+
+			if tt.ExpectedStdout != "" {
+				require.Equal(t, tt.ExpectedStdout, stdout.String())
+			}
+		})
+	}
+}
+
+func writeConfig(t *testing.T) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "config.yaml")
+
+	err := os.WriteFile(path, []byte(`persistence_type: inmemory
+alias:
+  length: 10
+  charset: abcdefghijklmnopqrstuvwxyz
+http_server:
+  port: 8080
+`), 0o600)
+	require.NoError(t, err)
+
+	return path
+}
@@ -0,0 +1,268 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/kodeyeen/shortify/v1"
+)
+
+func (a *app) create(ctx context.Context, args []string) error {
+	fs := a.flagSet("create <url>")
+
+	var (
+		req  shortify.CreateURLRequest
+		tags stringsFlag
+		meta metadataFlag
+	)
+
+	fs.StringVar(&req.QueryMode, "query-mode", "", "query parameters handling: ignore, merge or override")
+	fs.BoolVar(&req.Preview, "preview", false, "show the preview page instead of redirecting")
+	fs.StringVar(&req.Title, "title", "", "link title")
+	fs.StringVar(&req.Description, "description", "", "link description")
+	fs.Var(&tags, "tag", "link tag, may be repeated")
+	fs.Var(&meta, "meta", "link metadata as key=value, may be repeated")
+
+	original, err := parseOneArg(fs, args, "url")
+	if err != nil {
+		return err
+	}
+
+	req.Original = original
+	req.Tags = tags
+	req.Metadata = meta
+
+	resp, err := a.client.CreateURL(ctx, req)
+	if err != nil {
+		return err
+	}
+
+	return a.out.print(resp, func(tw *tabwriter.Writer) {
+		fmt.Fprintf(tw, "Alias:\t%s\n", resp.Alias)
+		fmt.Fprintf(tw, "Original:\t%s\n", resp.Original)
+		fmt.Fprintf(tw, "Query mode:\t%s\n", resp.QueryMode)
+		fmt.Fprintf(tw, "Created:\t%s\n", formatTime(resp.CreatedAt))
+	})
+}
+
+func (a *app) get(ctx context.Context, args []string) error {
+	fs := a.flagSet("get <alias>")
+
+	alias, err := parseOneArg(fs, args, "alias")
+	if err != nil {
+		return err
+	}
+
+	resp, err := a.client.GetURL(ctx, alias)
+	if err != nil {
+		return err
+	}
+
+	return a.out.print(resp, func(tw *tabwriter.Writer) {
+		fmt.Fprintf(tw, "Alias:\t%s\n", resp.Alias)
+		fmt.Fprintf(tw, "Original:\t%s\n", resp.Original)
+		fmt.Fprintf(tw, "Query mode:\t%s\n", resp.QueryMode)
+		fmt.Fprintf(tw, "Preview:\t%t\n", resp.Preview)
+		fmt.Fprintf(tw, "Title:\t%s\n", orDash(resp.Title))
+		fmt.Fprintf(tw, "Description:\t%s\n", orDash(resp.Description))
+		fmt.Fprintf(tw, "Tags:\t%s\n", formatTags(resp.Tags))
+		fmt.Fprintf(tw, "Metadata:\t%s\n", formatMetadata(resp.Metadata))
+		fmt.Fprintf(tw, "Clicks:\t%d\n", resp.Clicks)
+		fmt.Fprintf(tw, "Created:\t%s\n", formatTime(resp.CreatedAt))
+	})
+}
+
+func (a *app) list(ctx context.Context, args []string) error {
+	fs := a.flagSet("list")
+
+	var (
+		req  shortify.ListURLsRequest
+		meta metadataFlag
+	)
+
+	fs.StringVar(&req.Tag, "tag", "", "list links with the tag")
+	fs.Var(&meta, "meta", "list links with the metadata key=value, may be repeated")
+	fs.IntVar(&req.Limit, "limit", 0, "maximum number of links")
+	fs.IntVar(&req.Offset, "offset", 0, "number of links to skip")
+
+	err := parseNoArgs(fs, args)
+	if err != nil {
+		return err
+	}
+
+	req.Metadata = meta
+
+	resp, err := a.client.ListURLs(ctx, req)
+	if err != nil {
+		return err
+	}
+
+	return a.out.print(resp, func(tw *tabwriter.Writer) {
+		fmt.Fprintln(tw, "ALIAS\tORIGINAL\tCLICKS\tTAGS\tCREATED")
+
+		for _, u := range resp.URLs {
+			fmt.Fprintf(tw, "%s\t%s\t%d\t%s\t%s\n", u.Alias, u.Original, u.Clicks, formatTags(u.Tags), formatTime(u.CreatedAt))
+		}
+	})
+}
+
+func (a *app) delete(ctx context.Context, args []string) error {
+	fs := a.flagSet("delete <alias>")
+
+	alias, err := parseOneArg(fs, args, "alias")
+	if err != nil {
+		return err
+	}
+
+	err = a.client.DeleteURL(ctx, alias)
+	if err != nil {
+		return err
+	}
+
+	resp := struct {
+		Alias   string `json:"alias"`
+		Deleted bool   `json:"deleted"`
+	}{
+		Alias:   alias,
+		Deleted: true,
+	}
+
+	return a.out.print(resp, func(tw *tabwriter.Writer) {
+		fmt.Fprintf(tw, "Deleted %s\n", alias)
+	})
+}
+
+func (a *app) restore(ctx context.Context, args []string) error {
+	fs := a.flagSet("restore <alias>")
+
+	alias, err := parseOneArg(fs, args, "alias")
+	if err != nil {
+		return err
+	}
+
+	resp, err := a.client.RestoreURL(ctx, alias)
+	if err != nil {
+		return err
+	}
+
+	return a.out.print(resp, func(tw *tabwriter.Writer) {
+		fmt.Fprintf(tw, "Restored %s\n", resp.Alias)
+	})
+}
+
+func (a *app) stats(ctx context.Context, args []string) error {
+	fs := a.flagSet("stats <alias>")
+
+	alias, err := parseOneArg(fs, args, "alias")
+	if err != nil {
+		return err
+	}
+
+	u, err := a.client.GetURL(ctx, alias)
+	if err != nil {
+		return err
+	}
+
+	resp := struct {
+		Alias     string    `json:"alias"`
+		Original  string    `json:"original"`
+		Clicks    int64     `json:"clicks"`
+		CreatedAt time.Time `json:"created_at"`
+	}{
+		Alias:     u.Alias,
+		Original:  u.Original,
+		Clicks:    u.Clicks,
+		CreatedAt: u.CreatedAt,
+	}
+
+	return a.out.print(resp, func(tw *tabwriter.Writer) {
+		fmt.Fprintf(tw, "Alias:\t%s\n", u.Alias)
+		fmt.Fprintf(tw, "Original:\t%s\n", u.Original)
+		fmt.Fprintf(tw, "Clicks:\t%d\n", u.Clicks)
+		fmt.Fprintf(tw, "Created:\t%s\n", formatTime(u.CreatedAt))
+	})
+}
+
+func (a *app) flagSet(usage string) *flag.FlagSet {
+	fs := flag.NewFlagSet(usage, flag.ContinueOnError)
+	fs.SetOutput(a.stderr)
+	fs.Usage = func() {
+		fmt.Fprintf(a.stderr, "Usage: shortify %s\n", usage)
+		fs.PrintDefaults()
+	}
+
+	return fs
+}
+
+func parseOneArg(fs *flag.FlagSet, args []string, name string) (string, error) {
+	if err := fs.Parse(args); err != nil {
+		return "", parseErr(err)
+	}
+
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return "", fmt.Errorf("%w: expected exactly one %s", errUsage, name)
+	}
+
+	return fs.Arg(0), nil
+}
+
+func parseNoArgs(fs *flag.FlagSet, args []string) error {
+	if err := fs.Parse(args); err != nil {
+		return parseErr(err)
+	}
+
+	if fs.NArg() != 0 {
+		fs.Usage()
+		return fmt.Errorf("%w: unexpected arguments %s", errUsage, strings.Join(fs.Args(), " "))
+	}
+
+	return nil
+}
+
+func parseErr(err error) error {
+	if errors.Is(err, flag.ErrHelp) {
+		return err
+	}
+
+	return fmt.Errorf("%w: %s", errUsage, err)
+}
+
+// stringsFlag collects the values of a repeated flag.
+type stringsFlag []string
+
+func (f *stringsFlag) String() string {
+	return strings.Join(*f, ",")
+}
+
+func (f *stringsFlag) Set(value string) error {
+	*f = append(*f, value)
+	return nil
+}
+
+// metadataFlag collects key=value pairs of a repeated flag.
+type metadataFlag map[string]string
+
+func (f *metadataFlag) String() string {
+	return formatMetadata(*f)
+}
+
+func (f *metadataFlag) Set(value string) error {
+	key, val, ok := strings.Cut(value, "=")
+	if !ok || key == "" {
+		return fmt.Errorf("metadata must be key=value, got %q", value)
+	}
+
+	if *f == nil {
+		*f = metadataFlag{}
+	}
+
+	(*f)[key] = val
+
+	return nil
+}
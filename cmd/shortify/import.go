@@ -0,0 +1,202 @@
+package main
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/kodeyeen/shortify/v1"
+)
+
+// errImportFailed reports that some of the imported links weren't created.
+var errImportFailed = errors.New("some links were not imported")
+
+// importColumns are the columns of the imported CSV. Tags are separated
+// by ';' and metadata is given as key=value pairs separated by ';'.
+var importColumns = []string{"original", "query_mode", "preview", "title", "description", "tags", "metadata"}
+
+type importResult struct {
+	Line     int    `json:"line"`
+	Original string `json:"original"`
+	Alias    string `json:"alias,omitempty"`
+	Error    string `json:"error,omitempty"`
+}
+
+func (a *app) importCSV(ctx context.Context, args []string) error {
+	fs := a.flagSet("import [file]")
+	fs.Usage = func() {
+		fmt.Fprintf(a.stderr, "Usage: shortify import [file]\n\n"+
+			"Creates links from the CSV file or stdin when the file is omitted or \"-\".\n"+
+			"The header names the columns among: %s.\n"+
+			"Without the header every line holds a single URL.\n", strings.Join(importColumns, ", "))
+	}
+
+	if err := fs.Parse(args); err != nil {
+		return parseErr(err)
+	}
+
+	if fs.NArg() > 1 {
+		fs.Usage()
+		return fmt.Errorf("%w: expected at most one file", errUsage)
+	}
+
+	in := a.stdin
+
+	if name := fs.Arg(0); name != "" && name != "-" {
+		f, err := os.Open(name)
+		if err != nil {
+			return fmt.Errorf("failed to open file: %w", err)
+		}
+		defer f.Close()
+
+		in = f
+	}
+
+	results, err := a.importRows(ctx, csv.NewReader(in))
+	if err != nil {
+		return err
+	}
+
+	err = a.out.print(results, func(tw *tabwriter.Writer) {
+		fmt.Fprintln(tw, "LINE\tORIGINAL\tALIAS\tERROR")
+
+		for _, r := range results {
+			fmt.Fprintf(tw, "%d\t%s\t%s\t%s\n", r.Line, r.Original, orDash(r.Alias), orDash(r.Error))
+		}
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, r := range results {
+		if r.Error != "" {
+			return errImportFailed
+		}
+	}
+
+	return nil
+}
+
+func (a *app) importRows(ctx context.Context, r *csv.Reader) ([]importResult, error) {
+	r.FieldsPerRecord = -1
+	r.TrimLeadingSpace = true
+
+	var (
+		columns = []string{"original"}
+		results = []importResult{}
+	)
+
+	for first := true; ; first = false {
+		record, err := r.Read()
+		if errors.Is(err, io.EOF) {
+			return results, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CSV: %w", err)
+		}
+
+		line, _ := r.FieldPos(0)
+
+		if first && slices.Contains(record, "original") {
+			columns = record
+
+			for _, column := range columns {
+				if !slices.Contains(importColumns, column) {
+					return nil, fmt.Errorf("%w: unknown column %q", errUsage, column)
+				}
+			}
+
+			continue
+		}
+
+		result := importResult{Line: line}
+
+		req, err := newImportRequest(columns, record)
+		if err != nil {
+			result.Error = err.Error()
+			results = append(results, result)
+			continue
+		}
+
+		result.Original = req.Original
+
+		resp, err := a.client.CreateURL(ctx, req)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil, err
+			}
+
+			var apiErr *shortify.Error
+			if errors.As(err, &apiErr) {
+				result.Error = apiErr.Message
+			} else {
+				result.Error = err.Error()
+			}
+		} else {
+			result.Alias = resp.Alias
+		}
+
+		results = append(results, result)
+	}
+}
+
+func newImportRequest(columns, record []string) (shortify.CreateURLRequest, error) {
+	var req shortify.CreateURLRequest
+
+	if len(record) > len(columns) {
+		return req, fmt.Errorf("expected at most %d fields, got %d", len(columns), len(record))
+	}
+
+	for i, value := range record {
+		switch columns[i] {
+		case "original":
+			req.Original = value
+		case "query_mode":
+			req.QueryMode = value
+		case "preview":
+			if value == "" {
+				continue
+			}
+
+			preview, err := strconv.ParseBool(value)
+			if err != nil {
+				return req, fmt.Errorf("invalid preview %q", value)
+			}
+
+			req.Preview = preview
+		case "title":
+			req.Title = value
+		case "description":
+			req.Description = value
+		case "tags":
+			for tag := range strings.SplitSeq(value, ";") {
+				if tag = strings.TrimSpace(tag); tag != "" {
+					req.Tags = append(req.Tags, tag)
+				}
+			}
+		case "metadata":
+			var meta metadataFlag
+
+			for pair := range strings.SplitSeq(value, ";") {
+				if pair = strings.TrimSpace(pair); pair == "" {
+					continue
+				}
+
+				if err := meta.Set(pair); err != nil {
+					return req, err
+				}
+			}
+
+			req.Metadata = meta
+		}
+	}
+
+	return req, nil
+}
@@ -0,0 +1,167 @@
+// Command shortify manages links through the Shortify API.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	neturl "net/url"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/kodeyeen/shortify/v1"
+)
+
+// Exit codes.
+const (
+	exitOK          = 0
+	exitError       = 1
+	exitUsage       = 2
+	exitNotFound    = 3
+	exitConflict    = 4
+	exitUnavailable = 5
+)
+
+const usage = `Usage: shortify [flags] <command> [args]
+
+Commands:
+  create <url>        create a short link
+  get <alias>         show a link
+  list                list links
+  delete <alias>      delete a link
+  restore <alias>     restore a deleted link
+  stats <alias>       show click statistics of a link
+  import [file]       create links from a CSV file or stdin
+
+Flags:
+`
+
+// errUsage reports invalid command line arguments.
+var errUsage = errors.New("invalid usage")
+
+type app struct {
+	client *shortify.Client
+	out    printer
+
+	stdin  io.Reader
+	stdout io.Writer
+	stderr io.Writer
+}
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	os.Exit(run(ctx, os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+func run(ctx context.Context, args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("shortify", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() {
+		fmt.Fprint(stderr, usage)
+		fs.PrintDefaults()
+	}
+
+	var flags settings
+
+	fs.StringVar(&flags.BaseURL, "url", "", "API base URL (env SHORTIFY_URL)")
+	fs.StringVar(&flags.APIKey, "api-key", "", "API key (env SHORTIFY_API_KEY)")
+	fs.StringVar(&flags.Output, "o", "", "output format: table or json (env SHORTIFY_OUTPUT)")
+	configPath := fs.String("config", "", "config file (env SHORTIFY_CONFIG, default ~/.config/shortify/config.yaml)")
+
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return exitOK
+		}
+
+		return exitUsage
+	}
+
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return exitUsage
+	}
+
+	cfg, err := loadSettings(*configPath, flags)
+	if err != nil {
+		fmt.Fprintf(stderr, "shortify: %s\n", err)
+		return exitUsage
+	}
+
+	out, err := newPrinter(cfg.Output, stdout)
+	if err != nil {
+		fmt.Fprintf(stderr, "shortify: %s\n", err)
+		return exitUsage
+	}
+
+	opts := []shortify.ClientOption{}
+	if cfg.APIKey != "" {
+		opts = append(opts, shortify.WithHeader("Authorization", "Bearer "+cfg.APIKey))
+	}
+
+	a := &app{
+		client: shortify.NewClient(cfg.BaseURL, opts...),
+		out:    out,
+
+		stdin:  stdin,
+		stdout: stdout,
+		stderr: stderr,
+	}
+
+	commands := map[string]func(ctx context.Context, args []string) error{
+		"create":  a.create,
+		"get":     a.get,
+		"list":    a.list,
+		"delete":  a.delete,
+		"restore": a.restore,
+		"stats":   a.stats,
+		"import":  a.importCSV,
+	}
+
+	name := fs.Arg(0)
+
+	cmd, ok := commands[name]
+	if !ok {
+		fmt.Fprintf(stderr, "shortify: unknown command %q\n", name)
+		fs.Usage()
+		return exitUsage
+	}
+
+	err = cmd(ctx, fs.Args()[1:])
+	if err != nil {
+		if !errors.Is(err, flag.ErrHelp) {
+			fmt.Fprintf(stderr, "shortify: %s\n", err)
+		}
+
+		return exitCode(err)
+	}
+
+	return exitOK
+}
+
+func exitCode(err error) int {
+	switch {
+	case errors.Is(err, flag.ErrHelp):
+		return exitOK
+	case errors.Is(err, errUsage), errors.Is(err, shortify.ErrBadRequest):
+		return exitUsage
+	case errors.Is(err, shortify.ErrNotFound), errors.Is(err, shortify.ErrDeleted):
+		return exitNotFound
+	case errors.Is(err, shortify.ErrAlreadyExists):
+		return exitConflict
+	case errors.Is(err, shortify.ErrServer), errors.Is(err, shortify.ErrRateLimited):
+		return exitUnavailable
+	}
+
+	// the request didn't reach the server
+	var urlErr *neturl.Error
+	if errors.As(err, &urlErr) {
+		return exitUnavailable
+	}
+
+	return exitError
+}
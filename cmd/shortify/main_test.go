@@ -0,0 +1,244 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	httpdel "github.com/kodeyeen/shortify/internal/delivery/http/v1"
+	"github.com/kodeyeen/shortify/internal/generation/rand"
+	"github.com/kodeyeen/shortify/internal/persistence/inmemory"
+	"github.com/kodeyeen/shortify/internal/url"
+	"github.com/kodeyeen/shortify/internal/webhook"
+	"github.com/kodeyeen/shortify/v1"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRun(t *testing.T) {
+	// Given
+	srv := newServer(t)
+
+	// When
+	code, stdout, _ := runCLI(t, "", "-url", srv.URL, "-o", "json", "create", "-tag", "promo", "-meta", "campaign=spring", "https://example.com/long")
+
+	// Then
+	require.Equal(t, exitOK, code)
+
+	var created shortify.CreateURLResponse
+	require.NoError(t, json.Unmarshal([]byte(stdout), &created))
+	require.Equal(t, []string{"promo"}, created.Tags)
+	require.Equal(t, map[string]string{"campaign": "spring"}, created.Metadata)
+
+	code, stdout, _ = runCLI(t, "", "-url", srv.URL, "get", created.Alias)
+	require.Equal(t, exitOK, code)
+	require.Contains(t, stdout, "https://example.com/long")
+	require.Contains(t, stdout, "campaign=spring")
+
+	code, stdout, _ = runCLI(t, "", "-url", srv.URL, "list", "-tag", "promo")
+	require.Equal(t, exitOK, code)
+	require.Contains(t, stdout, "ALIAS")
+	require.Contains(t, stdout, created.Alias)
+
+	code, stdout, _ = runCLI(t, "", "-url", srv.URL, "stats", created.Alias)
+	require.Equal(t, exitOK, code)
+	require.Contains(t, stdout, "Clicks:")
+
+	code, _, _ = runCLI(t, "", "-url", srv.URL, "create", "https://example.com/long")
+	require.Equal(t, exitConflict, code)
+
+	code, _, _ = runCLI(t, "", "-url", srv.URL, "delete", created.Alias)
+	require.Equal(t, exitOK, code)
+
+	code, _, stderr := runCLI(t, "", "-url", srv.URL, "get", created.Alias)
+	require.Equal(t, exitNotFound, code)
+	require.Contains(t, stderr, "Gone")
+
+	code, stdout, _ = runCLI(t, "", "-url", srv.URL, "restore", created.Alias)
+	require.Equal(t, exitOK, code)
+	require.Contains(t, stdout, "Restored "+created.Alias)
+
+	code, _, _ = runCLI(t, "", "-url", srv.URL, "get", created.Alias)
+	require.Equal(t, exitOK, code)
+
+	code, _, stderr = runCLI(t, "", "-url", srv.URL, "get", "missing")
+	require.Equal(t, exitNotFound, code)
+	require.Contains(t, stderr, "Not Found")
+}
+
+func TestRun_ExitCodes(t *testing.T) {
+	srv := newServer(t)
+
+	testCases := map[string]struct {
+		args []string
+		code int
+	}{
+		"No command": {
+			args: []string{"-url", srv.URL},
+			code: exitUsage,
+		},
+		"Unknown command": {
+			args: []string{"-url", srv.URL, "shorten"},
+			code: exitUsage,
+		},
+		"Missing argument": {
+			args: []string{"-url", srv.URL, "get"},
+			code: exitUsage,
+		},
+		"Invalid URL": {
+			args: []string{"-url", srv.URL, "create", "invalidurl"},
+			code: exitUsage,
+		},
+		"Unknown output": {
+			args: []string{"-url", srv.URL, "-o", "yaml", "list"},
+			code: exitUsage,
+		},
+		"Help": {
+			args: []string{"-url", srv.URL, "list", "-h"},
+			code: exitOK,
+		},
+		"Unavailable": {
+			args: []string{"-url", "http://127.0.0.1:1", "list"},
+			code: exitUnavailable,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			// When
+			code, _, _ := runCLI(t, "", tc.args...)
+
+			// Then
+			require.Equal(t, tc.code, code)
+		})
+	}
+}
+
+func TestRun_Import(t *testing.T) {
+	// Given
+	srv := newServer(t)
+
+	input := strings.Join([]string{
+		"original,title,tags,metadata",
+		"https://example.com/1,First,promo;spring,campaign=spring",
+		"invalidurl,Invalid,,",
+		"https://example.com/2,Second,,",
+	}, "\n")
+
+	// When
+	code, stdout, _ := runCLI(t, input, "-url", srv.URL, "-o", "json", "import")
+
+	// Then
+	require.Equal(t, exitError, code)
+
+	var results []importResult
+	require.NoError(t, json.Unmarshal([]byte(stdout), &results))
+	require.Len(t, results, 3)
+
+	require.Equal(t, 2, results[0].Line)
+	require.NotEmpty(t, results[0].Alias)
+	require.Empty(t, results[0].Error)
+
+	require.Equal(t, 3, results[1].Line)
+	require.Equal(t, "Field 'original' is not a valid URL", results[1].Error)
+
+	require.NotEmpty(t, results[2].Alias)
+
+	code, stdout, _ = runCLI(t, "", "-url", srv.URL, "-o", "json", "get", results[0].Alias)
+	require.Equal(t, exitOK, code)
+
+	var got shortify.GetURLByAliasResponse
+	require.NoError(t, json.Unmarshal([]byte(stdout), &got))
+	require.Equal(t, "First", got.Title)
+	require.Equal(t, []string{"promo", "spring"}, got.Tags)
+	require.Equal(t, map[string]string{"campaign": "spring"}, got.Metadata)
+}
+
+func TestRun_Import_WithoutHeader(t *testing.T) {
+	// Given
+	srv := newServer(t)
+
+	path := filepath.Join(t.TempDir(), "urls.csv")
+	require.NoError(t, os.WriteFile(path, []byte("https://example.com/1\nhttps://example.com/2\n"), 0o600))
+
+	// When
+	code, stdout, _ := runCLI(t, "", "-url", srv.URL, "import", path)
+
+	// Then
+	require.Equal(t, exitOK, code)
+	require.Contains(t, stdout, "https://example.com/1")
+	require.Contains(t, stdout, "https://example.com/2")
+}
+
+func TestLoadSettings(t *testing.T) {
+	// Given
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("url: http://file\napi_key: file-key\noutput: json\n"), 0o600))
+
+	t.Setenv("SHORTIFY_URL", "http://env")
+	t.Setenv("SHORTIFY_API_KEY", "")
+	t.Setenv("SHORTIFY_OUTPUT", "")
+
+	// When
+	cfg, err := loadSettings(path, settings{Output: "table"})
+
+	// Then
+	require.NoError(t, err)
+	require.Equal(t, settings{
+		BaseURL: "http://env",
+		APIKey:  "file-key",
+		Output:  "table",
+	}, cfg)
+
+	_, err = loadSettings(filepath.Join(t.TempDir(), "missing.yaml"), settings{})
+	require.Error(t, err)
+}
+
+func runCLI(t *testing.T, stdin string, args ...string) (int, string, string) {
+	t.Helper()
+
+	// isolate from the user config
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(path, nil, 0o600))
+
+	t.Setenv("SHORTIFY_CONFIG", path)
+	t.Setenv("SHORTIFY_URL", "")
+	t.Setenv("SHORTIFY_API_KEY", "")
+	t.Setenv("SHORTIFY_OUTPUT", "")
+
+	var stdout, stderr bytes.Buffer
+
+	code := run(context.Background(), args, strings.NewReader(stdin), &stdout, &stderr)
+
+	return code, stdout.String(), stderr.String()
+}
+
+func newServer(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	log := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	urlSvc := url.NewService(
+		inmemory.NewURLRepository(),
+		rand.NewAliasProvider("abcdefghijklmnopqrstuvwxyz0123456789", 10),
+		log,
+	)
+	webhookSvc := webhook.NewService(inmemory.NewWebhookRepository(), log)
+
+	router := httpdel.NewRouter(
+		httpdel.NewURLController(urlSvc, log),
+		httpdel.NewWebhookController(webhookSvc, log),
+		log,
+	)
+
+	srv := httptest.NewServer(router)
+	t.Cleanup(srv.Close)
+
+	return srv
+}
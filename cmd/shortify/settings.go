@@ -0,0 +1,90 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	defaultBaseURL = "http://localhost:8080"
+	defaultOutput  = outputTable
+)
+
+// settings are taken from flags, then environment variables, then the config file.
+type settings struct {
+	BaseURL string `yaml:"url"`
+	APIKey  string `yaml:"api_key"`
+	Output  string `yaml:"output"`
+}
+
+func loadSettings(path string, flags settings) (settings, error) {
+	env := settings{
+		BaseURL: os.Getenv("SHORTIFY_URL"),
+		APIKey:  os.Getenv("SHORTIFY_API_KEY"),
+		Output:  os.Getenv("SHORTIFY_OUTPUT"),
+	}
+
+	file, err := readSettings(path)
+	if err != nil {
+		return settings{}, err
+	}
+
+	return settings{
+		BaseURL: firstNonEmpty(flags.BaseURL, env.BaseURL, file.BaseURL, defaultBaseURL),
+		APIKey:  firstNonEmpty(flags.APIKey, env.APIKey, file.APIKey),
+		Output:  firstNonEmpty(flags.Output, env.Output, file.Output, defaultOutput),
+	}, nil
+}
+
+// readSettings reads the config file. The default file is optional.
+func readSettings(path string) (settings, error) {
+	explicit := true
+
+	if path == "" {
+		path = os.Getenv("SHORTIFY_CONFIG")
+	}
+
+	if path == "" {
+		explicit = false
+
+		dir, err := os.UserConfigDir()
+		if err != nil {
+			return settings{}, nil
+		}
+
+		path = filepath.Join(dir, "shortify", "config.yaml")
+	}
+
+	b, err := os.ReadFile(path)
+	if err != nil {
+		if !explicit && errors.Is(err, fs.ErrNotExist) {
+			return settings{}, nil
+		}
+
+		return settings{}, fmt.Errorf("failed to read config: %w", err)
+	}
+
+	var s settings
+
+	err = yaml.Unmarshal(b, &s)
+	if err != nil {
+		return settings{}, fmt.Errorf("failed to parse config %s: %w", path, err)
+	}
+
+	return s, nil
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+
+	return ""
+}
@@ -0,0 +1,209 @@
+// Package admin implements maintenance tasks run directly against the storage.
+package admin
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/kodeyeen/shortify/internal/domain"
+	"github.com/kodeyeen/shortify/internal/persistence"
+	"github.com/kodeyeen/shortify/internal/url"
+)
+
+// ErrNotSupported is returned when the storage doesn't support the task.
+var ErrNotSupported = errors.New("not supported by the storage")
+
+// pageSize is the number of URLs read at once while walking the storage.
+const pageSize = url.MaxListLimit
+
+// Lister lists stored URLs.
+type Lister interface {
+	List(ctx context.Context, filter persistence.URLFilter) ([]*domain.URL, error)
+}
+
+// Reindexer rebuilds the indexes of the stored URLs.
+type Reindexer interface {
+	Reindex(ctx context.Context) error
+}
+
+// Reindex rebuilds the indexes of urls when the storage supports it.
+func Reindex(ctx context.Context, urls any) error {
+	r, ok := urls.(Reindexer)
+	if !ok {
+		return ErrNotSupported
+	}
+
+	return r.Reindex(ctx)
+}
+
+// AliasLister lists stored URLs and the aliases they were renamed from.
+type AliasLister interface {
+	Lister
+	ListRetiredAliases(ctx context.Context, urlID int64) ([]*domain.RetiredAlias, error)
+}
+
+// AliasFormat is the form of the aliases of an alias provider not limited to the charset,
+// such as the words provider.
+type AliasFormat interface {
+	Matches(alias string) bool
+}
+
+// Violation is an alias not matching the alias config.
+type Violation struct {
+	Alias    string
+	Original string
+	Reason   string
+}
+
+// VerifyAliases checks that every alias consists of the charset characters
+// and has the configured length unless it matches one of formats.
+// The aliases of renamed URLs were chosen by users, so they aren't checked.
+// Neither are the custom aliases of imported URLs told apart from generated ones,
+// so they're reported when they don't match the config.
+func VerifyAliases(ctx context.Context, urls AliasLister, charset string, length int, formats ...AliasFormat) ([]Violation, int, error) {
+	// candidate is a violation unless the URL was renamed
+	type candidate struct {
+		urlID     int64
+		violation Violation
+	}
+
+	var (
+		candidates []candidate
+		checked    int
+	)
+
+	err := walk(ctx, urls, func(u *domain.URL) {
+		checked++
+
+		if slices.ContainsFunc(formats, func(f AliasFormat) bool { return f.Matches(u.Alias) }) {
+			return
+		}
+
+		var reasons []string
+
+		if n := len(u.Alias); n != length {
+			reasons = append(reasons, fmt.Sprintf("length %d instead of %d", n, length))
+		}
+
+		var invalid []string
+
+		for _, r := range u.Alias {
+			if !strings.ContainsRune(charset, r) && !slices.Contains(invalid, string(r)) {
+				invalid = append(invalid, string(r))
+			}
+		}
+
+		if len(invalid) > 0 {
+			reasons = append(reasons, fmt.Sprintf("characters %q not in charset", strings.Join(invalid, "")))
+		}
+
+		if len(reasons) > 0 {
+			candidates = append(candidates, candidate{
+				urlID: u.ID,
+				violation: Violation{
+					Alias:    u.Alias,
+					Original: u.Original,
+					Reason:   strings.Join(reasons, ", "),
+				},
+			})
+		}
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var violations []Violation
+
+	// only the candidates are looked up in the alias history rather than every URL
+	for _, c := range candidates {
+		retired, err := urls.ListRetiredAliases(ctx, c.urlID)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to list retired aliases: %w", err)
+		}
+
+		if len(retired) == 0 {
+			violations = append(violations, c.violation)
+		}
+	}
+
+	return violations, checked, nil
+}
+
+// Stats are statistics computed from the stored URLs.
+type Stats struct {
+	URLs          int
+	Clicks        int64
+	NeverClicked  int
+	URLsByTag     map[string]int
+	MostClicked   []*domain.URL
+	OpenGraphURLs int
+}
+
+// ComputeStats walks the stored URLs computing their statistics.
+// MostClicked holds up to top URLs ordered by clicks.
+func ComputeStats(ctx context.Context, urls Lister, top int) (*Stats, error) {
+	stats := &Stats{
+		URLsByTag: map[string]int{},
+	}
+
+	err := walk(ctx, urls, func(u *domain.URL) {
+		stats.URLs++
+		stats.Clicks += u.Clicks
+
+		if u.Clicks == 0 {
+			stats.NeverClicked++
+		}
+
+		if u.OpenGraph != nil {
+			stats.OpenGraphURLs++
+		}
+
+		for _, tag := range u.Tags {
+			stats.URLsByTag[tag]++
+		}
+
+		if top <= 0 || u.Clicks == 0 {
+			return
+		}
+
+		stats.MostClicked = append(stats.MostClicked, u)
+
+		slices.SortStableFunc(stats.MostClicked, func(a, b *domain.URL) int {
+			return cmp.Compare(b.Clicks, a.Clicks)
+		})
+
+		if len(stats.MostClicked) > top {
+			stats.MostClicked = stats.MostClicked[:top]
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return stats, nil
+}
+
+// walk calls fn for every stored URL in the order of creation.
+func walk(ctx context.Context, urls Lister, fn func(u *domain.URL)) error {
+	for offset := 0; ; offset += pageSize {
+		page, err := urls.List(ctx, persistence.URLFilter{
+			Limit:  pageSize,
+			Offset: offset,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to list URLs: %w", err)
+		}
+
+		for _, u := range page {
+			fn(u)
+		}
+
+		if len(page) < pageSize {
+			return nil
+		}
+	}
+}
@@ -0,0 +1,121 @@
+package admin_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/kodeyeen/shortify/internal/admin"
+	"github.com/kodeyeen/shortify/internal/domain"
+	"github.com/kodeyeen/shortify/internal/generation/words"
+	"github.com/kodeyeen/shortify/internal/persistence"
+	"github.com/kodeyeen/shortify/internal/persistence/inmemory"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVerifyAliases(t *testing.T) {
+	// Given
+	ctx := context.Background()
+
+	urls := inmemory.NewURLRepository()
+
+	for i, alias := range []string{"abcd", "ab_d", "abc", "ABCD", "brave-tiger", "bad-tiger", "dcba"} {
+		_, err := urls.Add(ctx, &domain.URL{
+			Original: fmt.Sprintf("https://example.com/%d", i),
+			Alias:    alias,
+		})
+		require.NoError(t, err)
+	}
+
+	// the alias of a renamed URL was chosen by the user
+	_, err := urls.Rename(ctx, "dcba", &domain.URL{Original: "https://example.com/6", Alias: "my-alias"}, time.Now())
+	require.NoError(t, err)
+
+	wordsFormat, err := words.NewAliasProvider(words.Dictionary{
+		Adjectives: []string{"brave"},
+		Nouns:      []string{"tiger"},
+	}, 2, "-")
+	require.NoError(t, err)
+
+	// When
+	violations, checked, err := admin.VerifyAliases(ctx, urls, "abcd", 4, wordsFormat)
+
+	// Then
+	require.NoError(t, err)
+	require.Equal(t, 7, checked)
+	require.Equal(t, []admin.Violation{
+		{Alias: "ab_d", Original: "https://example.com/1", Reason: `characters "_" not in charset`},
+		{Alias: "abc", Original: "https://example.com/2", Reason: "length 3 instead of 4"},
+		{Alias: "ABCD", Original: "https://example.com/3", Reason: `characters "ABCD" not in charset`},
+		{Alias: "bad-tiger", Original: "https://example.com/5", Reason: `length 9 instead of 4, characters "-tiger" not in charset`},
+	}, violations)
+}
+
+func TestComputeStats(t *testing.T) {
+	// Given
+	ctx := context.Background()
+
+	urls := inmemory.NewURLRepository()
+
+	// more URLs than fit a single page
+	for i := range 1500 {
+		u := &domain.URL{
+			Original: fmt.Sprintf("https://example.com/%d", i),
+			Alias:    fmt.Sprintf("alias%d", i),
+		}
+
+		if i%500 == 0 {
+			u.Tags = []string{"promo"}
+		}
+
+		_, err := urls.Add(ctx, u)
+		require.NoError(t, err)
+	}
+
+	for range 3 {
+		_, err := urls.IncrementClicks(ctx, "alias7")
+		require.NoError(t, err)
+	}
+
+	_, err := urls.IncrementClicks(ctx, "alias1200")
+	require.NoError(t, err)
+
+	// When
+	stats, err := admin.ComputeStats(ctx, urls, 5)
+
+	// Then
+	require.NoError(t, err)
+	require.Equal(t, 1500, stats.URLs)
+	require.Equal(t, int64(4), stats.Clicks)
+	require.Equal(t, 1498, stats.NeverClicked)
+	require.Equal(t, map[string]int{"promo": 3}, stats.URLsByTag)
+	require.Len(t, stats.MostClicked, 2)
+	require.Equal(t, "alias7", stats.MostClicked[0].Alias)
+	require.Equal(t, "alias1200", stats.MostClicked[1].Alias)
+}
+
+func TestReindex(t *testing.T) {
+	// Given
+	ctx := context.Background()
+
+	urls := inmemory.NewURLRepository()
+
+	_, err := urls.Add(ctx, &domain.URL{Original: "https://example.com/1", Alias: "alias1", Tags: []string{"promo"}})
+	require.NoError(t, err)
+
+	// When
+	err = admin.Reindex(ctx, urls)
+
+	// Then
+	require.NoError(t, err)
+
+	_, err = urls.Add(ctx, &domain.URL{Original: "https://example.com/1", Alias: "alias2"})
+	require.ErrorIs(t, err, persistence.ErrURLAlreadyExists)
+
+	tagged, err := urls.List(ctx, persistence.URLFilter{Tag: "promo"})
+	require.NoError(t, err)
+	require.Len(t, tagged, 1)
+
+	require.ErrorIs(t, admin.Reindex(ctx, struct{}{}), admin.ErrNotSupported)
+}
@@ -0,0 +1,24 @@
+// Package audit carries who makes a request through the context
+// so the changes it makes can be attributed.
+package audit
+
+import "context"
+
+// Origin describes who made the request.
+type Origin struct {
+	Actor     string
+	RequestID string
+}
+
+type originCtxKey struct{}
+
+// NewContext returns a copy of ctx carrying the origin.
+func NewContext(ctx context.Context, o Origin) context.Context {
+	return context.WithValue(ctx, originCtxKey{}, o)
+}
+
+// FromContext returns the origin carried by ctx, it's zero when there is none.
+func FromContext(ctx context.Context) Origin {
+	o, _ := ctx.Value(originCtxKey{}).(Origin)
+	return o
+}
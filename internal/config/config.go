@@ -1,11 +1,14 @@
 package config
 
 import (
+	"errors"
+	"fmt"
 	"log"
 	"os"
 	"time"
 
 	"github.com/ilyakaznacheev/cleanenv"
+	"github.com/kodeyeen/shortify/internal/generation/keyspace"
 )
 
 const (
@@ -17,6 +20,8 @@ const (
 const (
 	PersistenceTypeInmemory = "inmemory"
 	PersistenceTypePostgres = "postgres"
+	PersistenceTypeSQLite   = "sqlite"
+	PersistenceTypeBolt     = "bolt"
 )
 
 type Config struct {
@@ -24,12 +29,63 @@ type Config struct {
 	PersistenceType string           `yaml:"persistence_type" env:"PERSISTENCE_TYPE"`
 	Alias           AliasConfig      `yaml:"alias"`
 	HTTPServer      HTTPServerConfig `yaml:"http_server"`
+	GRPCServer      GRPCServerConfig `yaml:"grpc_server"`
 	Postgres        PostgresConfig   `yaml:"postgres"`
+	SQLite          SQLiteConfig     `yaml:"sqlite"`
+	Bolt            BoltConfig       `yaml:"bolt"`
+	Inmemory        InmemoryConfig   `yaml:"inmemory"`
+	OpenGraph       OpenGraphConfig  `yaml:"open_graph"`
+	Webhooks        WebhooksConfig   `yaml:"webhooks"`
+	Trash           TrashConfig      `yaml:"trash"`
 }
 
+const (
+	AliasProviderRandom   = "random"
+	AliasProviderSequence = "sequence"
+	AliasProviderWords    = "words"
+)
+
 type AliasConfig struct {
-	Length  int    `yaml:"length" env:"LENGTH" env-default:"10"`
-	Charset string `yaml:"charset" env:"CHARSET" env-required:"true"`
+	Length   int    `yaml:"length" env:"LENGTH" env-default:"10"`
+	Charset  string `yaml:"charset" env:"CHARSET" env-required:"true"`
+	Provider string `yaml:"provider" env:"ALIAS_PROVIDER" env-default:"random"`
+	// CaseInsensitive lowers the charset and makes alias lookups ignore case.
+	CaseInsensitive bool `yaml:"case_insensitive" env:"ALIAS_CASE_INSENSITIVE" env-default:"false"`
+	// Unambiguous drops the characters easily confused with others, such as 0 and O, from the charset.
+	Unambiguous bool `yaml:"unambiguous" env:"ALIAS_UNAMBIGUOUS" env-default:"false"`
+	// SequenceKey scrambles sequential aliases so they can't be enumerated.
+	SequenceKey uint64 `yaml:"sequence_key" env:"ALIAS_SEQUENCE_KEY"`
+	// Quarantine is how long retired aliases can't be taken by other URLs.
+	Quarantine time.Duration `yaml:"quarantine" env:"ALIAS_QUARANTINE" env-default:"720h"`
+	// RedirectRetired makes retired aliases redirect to the current alias with 301.
+	RedirectRetired bool `yaml:"redirect_retired" env:"ALIAS_REDIRECT_RETIRED" env-default:"false"`
+
+	Pool      AliasPoolConfig      `yaml:"pool"`
+	Words     AliasWordsConfig     `yaml:"words"`
+	Blocklist AliasBlocklistConfig `yaml:"blocklist"`
+}
+
+type AliasBlocklistConfig struct {
+	Enabled bool `yaml:"enabled" env:"ALIAS_BLOCKLIST_ENABLED" env-default:"true"`
+	// Path is the file of the denied words and reserved prefixes,
+	// the embedded list of offensive words is used when it's empty.
+	Path             string        `yaml:"path" env:"ALIAS_BLOCKLIST_PATH"`
+	ReservedPrefixes []string      `yaml:"reserved_prefixes" env:"ALIAS_RESERVED_PREFIXES" env-separator:"," env-default:"api,swagger,admin,static,health,metrics"`
+	ReloadInterval   time.Duration `yaml:"reload_interval" env:"ALIAS_BLOCKLIST_RELOAD_INTERVAL" env-default:"30s"`
+}
+
+type AliasWordsConfig struct {
+	Count     int    `yaml:"count" env:"ALIAS_WORDS_COUNT" env-default:"3"`
+	Separator string `yaml:"separator" env:"ALIAS_WORDS_SEPARATOR" env-default:"-"`
+}
+
+type AliasPoolConfig struct {
+	Enabled        bool          `yaml:"enabled" env:"ALIAS_POOL_ENABLED" env-default:"false"`
+	Size           int           `yaml:"size" env:"ALIAS_POOL_SIZE" env-default:"1000"`
+	LowWater       int           `yaml:"low_water" env:"ALIAS_POOL_LOW_WATER" env-default:"250"`
+	RefillInterval time.Duration `yaml:"refill_interval" env:"ALIAS_POOL_REFILL_INTERVAL" env-default:"10s"`
+	TTL            time.Duration `yaml:"ttl" env:"ALIAS_POOL_TTL" env-default:"24h"`
+	Timeout        time.Duration `yaml:"timeout" env:"ALIAS_POOL_TIMEOUT" env-default:"5s"`
 }
 
 type HTTPServerConfig struct {
@@ -40,11 +96,101 @@ type HTTPServerConfig struct {
 	ShutdownTimeout time.Duration `yaml:"shutdown_timeout" env:"HTTP_SERVER_SHUTDOWN_TIMEOUT" env-default:"10s"`
 }
 
+type GRPCServerConfig struct {
+	Enabled bool `yaml:"enabled" env:"GRPC_SERVER_ENABLED" env-default:"false"`
+	Port    int  `yaml:"port" env:"GRPC_SERVER_PORT" env-default:"9090"`
+}
+
 type PostgresConfig struct {
 	Host     string `yaml:"host" env:"POSTGRES_HOST"`
 	Database string `yaml:"database" env:"POSTGRES_DB"`
 	Username string `yaml:"username" env:"POSTGRES_USER"`
 	Password string `yaml:"password" env:"POSTGRES_PASSWORD"`
+
+	// AutoMigrate makes the api server apply the embedded migrations on startup.
+	AutoMigrate bool `yaml:"auto_migrate" env:"POSTGRES_AUTO_MIGRATE" env-default:"false"`
+}
+
+type SQLiteConfig struct {
+	Path string `yaml:"path" env:"SQLITE_PATH" env-default:"shortify.db"`
+}
+
+type BoltConfig struct {
+	Path string `yaml:"path" env:"BOLT_PATH" env-default:"shortify.bolt"`
+}
+
+type InmemoryConfig struct {
+	// Dir enables the write-ahead log and snapshots stored in the directory.
+	// The data is kept in memory only when it's empty.
+	Dir           string        `yaml:"dir" env:"INMEMORY_DIR"`
+	Fsync         string        `yaml:"fsync" env:"INMEMORY_FSYNC" env-default:"always"`
+	FsyncInterval time.Duration `yaml:"fsync_interval" env:"INMEMORY_FSYNC_INTERVAL" env-default:"1s"`
+	SnapshotEvery int           `yaml:"snapshot_every" env:"INMEMORY_SNAPSHOT_EVERY" env-default:"10000"`
+}
+
+type OpenGraphConfig struct {
+	Enabled              bool          `yaml:"enabled" env:"OPEN_GRAPH_ENABLED" env-default:"false"`
+	Workers              int           `yaml:"workers" env:"OPEN_GRAPH_WORKERS" env-default:"4"`
+	QueueSize            int           `yaml:"queue_size" env:"OPEN_GRAPH_QUEUE_SIZE" env-default:"256"`
+	Timeout              time.Duration `yaml:"timeout" env:"OPEN_GRAPH_TIMEOUT" env-default:"5s"`
+	MaxBodySize          int64         `yaml:"max_body_size" env:"OPEN_GRAPH_MAX_BODY_SIZE" env-default:"1048576"`
+	AllowPrivateNetworks bool          `yaml:"allow_private_networks" env:"OPEN_GRAPH_ALLOW_PRIVATE_NETWORKS" env-default:"false"`
+}
+
+type WebhooksConfig struct {
+	Enabled              bool          `yaml:"enabled" env:"WEBHOOKS_ENABLED" env-default:"false"`
+	PollInterval         time.Duration `yaml:"poll_interval" env:"WEBHOOKS_POLL_INTERVAL" env-default:"1s"`
+	Timeout              time.Duration `yaml:"timeout" env:"WEBHOOKS_TIMEOUT" env-default:"5s"`
+	BatchSize            int           `yaml:"batch_size" env:"WEBHOOKS_BATCH_SIZE" env-default:"64"`
+	Workers              int           `yaml:"workers" env:"WEBHOOKS_WORKERS" env-default:"4"`
+	MaxAttempts          int           `yaml:"max_attempts" env:"WEBHOOKS_MAX_ATTEMPTS" env-default:"8"`
+	InitialBackoff       time.Duration `yaml:"initial_backoff" env:"WEBHOOKS_INITIAL_BACKOFF" env-default:"10s"`
+	MaxBackoff           time.Duration `yaml:"max_backoff" env:"WEBHOOKS_MAX_BACKOFF" env-default:"1h"`
+	ClickThresholds      []int64       `yaml:"click_thresholds" env:"WEBHOOKS_CLICK_THRESHOLDS" env-separator:","`
+	AllowPrivateNetworks bool          `yaml:"allow_private_networks" env:"WEBHOOKS_ALLOW_PRIVATE_NETWORKS" env-default:"false"`
+}
+
+type TrashConfig struct {
+	// Retention is how long deleted URLs can be restored before they are deleted permanently.
+	Retention     time.Duration `yaml:"retention" env:"TRASH_RETENTION" env-default:"720h"`
+	PurgeInterval time.Duration `yaml:"purge_interval" env:"TRASH_PURGE_INTERVAL" env-default:"1h"`
+}
+
+// validate checks the reload interval which is only used for a blocklist file.
+func (c AliasBlocklistConfig) validate() error {
+	if c.Enabled && c.Path != "" && c.ReloadInterval <= 0 {
+		return errors.New("reload interval must be positive")
+	}
+
+	return nil
+}
+
+func (c AliasPoolConfig) validate() error {
+	if c.Enabled && c.RefillInterval <= 0 {
+		return errors.New("refill interval must be positive")
+	}
+
+	return nil
+}
+
+func (c WebhooksConfig) validate() error {
+	if c.Enabled && c.PollInterval <= 0 {
+		return errors.New("poll interval must be positive")
+	}
+
+	return nil
+}
+
+func (c TrashConfig) validate() error {
+	if c.Retention <= 0 {
+		return errors.New("retention must be positive")
+	}
+
+	if c.PurgeInterval <= 0 {
+		return errors.New("purge interval must be positive")
+	}
+
+	return nil
 }
 
 func MustLoad() *Config {
@@ -53,16 +199,59 @@ func MustLoad() *Config {
 		log.Fatal("CONFIG_PATH is not set")
 	}
 
-	if _, err := os.Stat(cfgPath); os.IsNotExist(err) {
-		log.Fatalf("config file does not exist: %s", cfgPath)
+	cfg, err := Load(cfgPath)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	return cfg
+}
+
+// Load reads the config file at path overriding it with environment variables.
+func Load(path string) (*Config, error) {
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return nil, fmt.Errorf("config file does not exist: %s", path)
 	}
 
 	var cfg Config
 
-	err := cleanenv.ReadConfig(cfgPath, &cfg)
+	err := cleanenv.ReadConfig(path, &cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config: %w", err)
+	}
+
+	if cfg.Alias.CaseInsensitive {
+		cfg.Alias.Charset = keyspace.Fold(cfg.Alias.Charset)
+	}
+
+	if cfg.Alias.Unambiguous {
+		cfg.Alias.Charset = keyspace.Unambiguous(cfg.Alias.Charset)
+	}
+
+	err = keyspace.Validate(cfg.Alias.Charset, cfg.Alias.Length)
+	if err != nil {
+		return nil, fmt.Errorf("invalid alias config: %w", err)
+	}
+
+	err = cfg.Alias.Blocklist.validate()
+	if err != nil {
+		return nil, fmt.Errorf("invalid alias blocklist config: %w", err)
+	}
+
+	err = cfg.Alias.Pool.validate()
+	if err != nil {
+		return nil, fmt.Errorf("invalid alias pool config: %w", err)
+	}
+
+	err = cfg.Webhooks.validate()
+	if err != nil {
+		return nil, fmt.Errorf("invalid webhooks config: %w", err)
+	}
+
+	err = cfg.Trash.validate()
 	if err != nil {
-		log.Fatalf("failed to read config: %s", err)
+		return nil, fmt.Errorf("invalid trash config: %w", err)
 	}
 
-	return &cfg
+	return &cfg, nil
 }
@@ -0,0 +1,79 @@
+package config_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/kodeyeen/shortify/internal/config"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoad_Intervals(t *testing.T) {
+	testCases := map[string]struct {
+		env    map[string]string
+		errMsg string
+	}{
+		"Defaults": {},
+		"Zero reload interval": {
+			env:    map[string]string{"ALIAS_BLOCKLIST_PATH": "blocklist.txt", "ALIAS_BLOCKLIST_RELOAD_INTERVAL": "0s"},
+			errMsg: "invalid alias blocklist config: reload interval must be positive",
+		},
+		"Zero reload interval without file": {
+			env: map[string]string{"ALIAS_BLOCKLIST_RELOAD_INTERVAL": "0s"},
+		},
+		"Zero refill interval": {
+			env:    map[string]string{"ALIAS_POOL_ENABLED": "true", "ALIAS_POOL_REFILL_INTERVAL": "0s"},
+			errMsg: "invalid alias pool config: refill interval must be positive",
+		},
+		"Zero poll interval": {
+			env:    map[string]string{"WEBHOOKS_ENABLED": "true", "WEBHOOKS_POLL_INTERVAL": "0s"},
+			errMsg: "invalid webhooks config: poll interval must be positive",
+		},
+		"Zero poll interval with disabled webhooks": {
+			env: map[string]string{"WEBHOOKS_ENABLED": "false", "WEBHOOKS_POLL_INTERVAL": "0s"},
+		},
+		"Zero purge interval": {
+			env:    map[string]string{"TRASH_PURGE_INTERVAL": "0s"},
+			errMsg: "invalid trash config: purge interval must be positive",
+		},
+		"Negative purge interval": {
+			env:    map[string]string{"TRASH_PURGE_INTERVAL": "-1h"},
+			errMsg: "invalid trash config: purge interval must be positive",
+		},
+		"Zero retention": {
+			env:    map[string]string{"TRASH_RETENTION": "0s"},
+			errMsg: "invalid trash config: retention must be positive",
+		},
+		"Negative retention": {
+			env:    map[string]string{"TRASH_RETENTION": "-1h"},
+			errMsg: "invalid trash config: retention must be positive",
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			// Given
+			path := filepath.Join(t.TempDir(), "config.yaml")
+
+			err := os.WriteFile(path, []byte("alias:\n  length: 10\n  charset: abcdefghijklmnopqrstuvwxyz0123456789\nhttp_server:\n  port: 8080\n"), 0o600)
+			require.NoError(t, err)
+
+			for key, value := range tc.env {
+				t.Setenv(key, value)
+			}
+
+			// When
+			cfg, err := config.Load(path)
+
+			// Then
+			if tc.errMsg != "" {
+				require.EqualError(t, err, tc.errMsg)
+				return
+			}
+
+			require.NoError(t, err)
+			require.NotNil(t, cfg)
+		})
+	}
+}
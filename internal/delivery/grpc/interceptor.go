@@ -0,0 +1,216 @@
+package grpc
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/kodeyeen/shortify/internal/audit"
+	"github.com/kodeyeen/shortify/internal/url"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// RequestIDKey is the metadata key carrying the request ID.
+// It's taken from the incoming metadata when present and sent back in the header.
+const RequestIDKey = "x-request-id"
+
+// ActorKey is the metadata key naming who makes the request in the audit log of the changed URLs.
+const ActorKey = "x-actor"
+
+type requestIDCtxKey struct{}
+
+// RequestID returns the request ID stored in ctx by the request ID interceptors.
+func RequestID(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDCtxKey{}).(string)
+	return id
+}
+
+// UnaryRequestID assigns an ID to every request.
+func UnaryRequestID() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		return handler(withRequestID(ctx), req)
+	}
+}
+
+// StreamRequestID assigns an ID to every stream.
+func StreamRequestID() grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		return handler(srv, &serverStream{ServerStream: ss, ctx: withRequestID(ss.Context())})
+	}
+}
+
+// UnaryAudit attributes the changes made by the request to the actor from the ActorKey
+// metadata and the request ID. It must run after UnaryRequestID.
+func UnaryAudit() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		return handler(withOrigin(ctx), req)
+	}
+}
+
+// StreamAudit attributes the changes made by the stream to the actor from the ActorKey
+// metadata and the request ID. It must run after StreamRequestID.
+func StreamAudit() grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		return handler(srv, &serverStream{ServerStream: ss, ctx: withOrigin(ss.Context())})
+	}
+}
+
+// UnaryLogger logs processed requests.
+func UnaryLogger(log *slog.Logger) grpc.UnaryServerInterceptor {
+	log = log.With(slog.String("component", "interceptor/logger"))
+
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		t1 := time.Now()
+
+		resp, err := handler(ctx, req)
+
+		logRequest(ctx, log, info.FullMethod, err, time.Since(t1))
+
+		return resp, err
+	}
+}
+
+// StreamLogger logs processed streams.
+func StreamLogger(log *slog.Logger) grpc.StreamServerInterceptor {
+	log = log.With(slog.String("component", "interceptor/logger"))
+
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		t1 := time.Now()
+
+		err := handler(srv, ss)
+
+		logRequest(ss.Context(), log, info.FullMethod, err, time.Since(t1))
+
+		return err
+	}
+}
+
+// UnaryErrors maps service errors to gRPC statuses logging unexpected ones.
+func UnaryErrors(log *slog.Logger) grpc.UnaryServerInterceptor {
+	log = log.With(slog.String("component", "interceptor/errors"))
+
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		resp, err := handler(ctx, req)
+		if err != nil {
+			return nil, mapError(ctx, log, info.FullMethod, err)
+		}
+
+		return resp, nil
+	}
+}
+
+// StreamErrors maps service errors to gRPC statuses logging unexpected ones.
+func StreamErrors(log *slog.Logger) grpc.StreamServerInterceptor {
+	log = log.With(slog.String("component", "interceptor/errors"))
+
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		err := handler(srv, ss)
+		if err != nil {
+			return mapError(ss.Context(), log, info.FullMethod, err)
+		}
+
+		return nil
+	}
+}
+
+func mapError(ctx context.Context, log *slog.Logger, method string, err error) error {
+	st := toStatus(err)
+
+	if st.Code() == codes.Internal {
+		log.Error("request failed",
+			slog.String("method", method),
+			slog.String("request_id", RequestID(ctx)),
+			slog.String("error", err.Error()),
+		)
+	}
+
+	return st.Err()
+}
+
+// toStatus converts err to a status hiding the details of unexpected errors.
+func toStatus(err error) *status.Status {
+	if st, ok := status.FromError(err); ok {
+		return st
+	}
+
+	switch {
+	case errors.Is(err, url.ErrNotFound):
+		return status.New(codes.NotFound, "URL not found")
+	case errors.Is(err, url.ErrDeleted):
+		return status.New(codes.NotFound, "URL is deleted")
+	case errors.Is(err, url.ErrAlreadyExists):
+		return status.New(codes.AlreadyExists, "URL already exists")
+	case errors.Is(err, context.Canceled):
+		return status.New(codes.Canceled, err.Error())
+	case errors.Is(err, context.DeadlineExceeded):
+		return status.New(codes.DeadlineExceeded, err.Error())
+	default:
+		return status.New(codes.Internal, "internal error")
+	}
+}
+
+func withRequestID(ctx context.Context) context.Context {
+	var id string
+
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get(RequestIDKey); len(values) > 0 {
+			id = values[0]
+		}
+	}
+
+	if id == "" {
+		id = newRequestID()
+	}
+
+	_ = grpc.SetHeader(ctx, metadata.Pairs(RequestIDKey, id))
+
+	return context.WithValue(ctx, requestIDCtxKey{}, id)
+}
+
+func withOrigin(ctx context.Context) context.Context {
+	var actor string
+
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get(ActorKey); len(values) > 0 {
+			actor = values[0]
+		}
+	}
+
+	return audit.NewContext(ctx, audit.Origin{
+		Actor:     actor,
+		RequestID: RequestID(ctx),
+	})
+}
+
+func newRequestID() string {
+	b := make([]byte, 12)
+	_, _ = rand.Read(b)
+
+	return hex.EncodeToString(b)
+}
+
+func logRequest(ctx context.Context, log *slog.Logger, method string, err error, duration time.Duration) {
+	log.Info("request processed",
+		slog.String("method", method),
+		slog.String("request_id", RequestID(ctx)),
+		slog.String("code", status.Code(err).String()),
+		slog.String("duration", duration.String()),
+	)
+}
+
+// serverStream overrides the context of the wrapped stream.
+type serverStream struct {
+	grpc.ServerStream
+
+	ctx context.Context
+}
+
+func (s *serverStream) Context() context.Context {
+	return s.ctx
+}
@@ -0,0 +1,33 @@
+package grpc
+
+import (
+	"log/slog"
+
+	"github.com/kodeyeen/shortify/v1/shortifypb"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/reflection"
+)
+
+// NewServer creates a gRPC server serving urls.
+// Errors are mapped to statuses before requests are logged.
+func NewServer(urls *URLServer, log *slog.Logger) *grpc.Server {
+	srv := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(
+			UnaryRequestID(),
+			UnaryAudit(),
+			UnaryLogger(log),
+			UnaryErrors(log),
+		),
+		grpc.ChainStreamInterceptor(
+			StreamRequestID(),
+			StreamAudit(),
+			StreamLogger(log),
+			StreamErrors(log),
+		),
+	)
+
+	shortifypb.RegisterURLServiceServer(srv, urls)
+	reflection.Register(srv)
+
+	return srv
+}
@@ -0,0 +1,172 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/kodeyeen/shortify/internal/dto"
+	"github.com/kodeyeen/shortify/v1"
+	"github.com/kodeyeen/shortify/v1/shortifypb"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+type URLService interface {
+	Create(ctx context.Context, req *dto.CreateURLRequest) (*dto.CreateURLResponse, error)
+	GetByAlias(ctx context.Context, req *dto.GetURLByAliasRequest) (*dto.GetURLByAliasResponse, error)
+}
+
+type URLServer struct {
+	shortifypb.UnimplementedURLServiceServer
+
+	urls URLService
+
+	log *slog.Logger
+}
+
+func NewURLServer(urls URLService, log *slog.Logger) *URLServer {
+	return &URLServer{
+		urls: urls,
+
+		log: log,
+	}
+}
+
+// Create creates new URL and generates an alias for it
+func (s *URLServer) Create(ctx context.Context, req *shortifypb.CreateURLRequest) (*shortifypb.URL, error) {
+	return s.create(ctx, req)
+}
+
+// GetByAlias gets URL by its alias
+func (s *URLServer) GetByAlias(ctx context.Context, req *shortifypb.GetURLByAliasRequest) (*shortifypb.URL, error) {
+	if req.GetAlias() == "" {
+		return nil, status.Error(codes.InvalidArgument, "Alias is empty")
+	}
+
+	out, err := s.urls.GetByAlias(ctx, &dto.GetURLByAliasRequest{
+		Alias: req.GetAlias(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &shortifypb.URL{
+		Original:    out.Original,
+		Alias:       out.Alias,
+		QueryMode:   out.QueryMode,
+		Preview:     out.Preview,
+		Title:       out.Title,
+		Description: out.Description,
+		Tags:        out.Tags,
+		Metadata:    out.Metadata,
+		Clicks:      out.Clicks,
+		CreatedAt:   timestamppb.New(out.CreatedAt),
+	}
+
+	if out.OpenGraph != nil {
+		resp.OpenGraph = &shortifypb.OpenGraph{
+			Title:       out.OpenGraph.Title,
+			Description: out.OpenGraph.Description,
+			Image:       out.OpenGraph.Image,
+			FetchedAt:   timestamppb.New(out.OpenGraph.FetchedAt),
+		}
+	}
+
+	return resp, nil
+}
+
+// BatchCreate creates URLs as they are received responding to each of them in order.
+// Failures are reported in the responses and don't abort the stream.
+func (s *URLServer) BatchCreate(stream shortifypb.URLService_BatchCreateServer) error {
+	ctx := stream.Context()
+
+	for index := int64(0); ; index++ {
+		req, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		resp := &shortifypb.BatchCreateURLResponse{
+			Index: index,
+		}
+
+		u, err := s.create(ctx, req)
+		if err != nil {
+			st := toStatus(err)
+
+			if st.Code() == codes.Internal {
+				s.log.Error("failed to create URL",
+					slog.String("request_id", RequestID(ctx)),
+					slog.Int64("index", index),
+					slog.String("error", err.Error()),
+				)
+			}
+
+			resp.Result = &shortifypb.BatchCreateURLResponse_Error{
+				Error: &shortifypb.Error{
+					Code:    int32(st.Code()),
+					Message: st.Message(),
+				},
+			}
+		} else {
+			resp.Result = &shortifypb.BatchCreateURLResponse_Url{
+				Url: u,
+			}
+		}
+
+		err = stream.Send(resp)
+		if err != nil {
+			return err
+		}
+	}
+}
+
+func (s *URLServer) create(ctx context.Context, req *shortifypb.CreateURLRequest) (*shortifypb.URL, error) {
+	in := shortify.CreateURLRequest{
+		Original:    req.GetOriginal(),
+		QueryMode:   req.GetQueryMode(),
+		Preview:     req.GetPreview(),
+		Title:       req.GetTitle(),
+		Description: req.GetDescription(),
+		Tags:        req.GetTags(),
+		Metadata:    req.GetMetadata(),
+	}
+
+	if err := validate.Struct(in); err != nil {
+		validatorErrs := err.(validator.ValidationErrors)
+
+		return nil, status.Error(codes.InvalidArgument, formatErrs(validatorErrs))
+	}
+
+	out, err := s.urls.Create(ctx, &dto.CreateURLRequest{
+		Original:    in.Original,
+		QueryMode:   in.QueryMode,
+		Preview:     in.Preview,
+		Title:       in.Title,
+		Description: in.Description,
+		Tags:        in.Tags,
+		Metadata:    in.Metadata,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &shortifypb.URL{
+		Original:    out.Original,
+		Alias:       out.Alias,
+		QueryMode:   out.QueryMode,
+		Preview:     out.Preview,
+		Title:       out.Title,
+		Description: out.Description,
+		Tags:        out.Tags,
+		Metadata:    out.Metadata,
+		CreatedAt:   timestamppb.New(out.CreatedAt),
+	}, nil
+}
@@ -0,0 +1,337 @@
+package grpc_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net"
+	"testing"
+
+	"github.com/kodeyeen/shortify/internal/audit"
+	grpcdel "github.com/kodeyeen/shortify/internal/delivery/grpc"
+	"github.com/kodeyeen/shortify/internal/dto"
+	"github.com/kodeyeen/shortify/internal/url"
+	"github.com/kodeyeen/shortify/internal/urlmock"
+	"github.com/kodeyeen/shortify/v1/shortifypb"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+func TestURLServer_Create(t *testing.T) {
+	type Given struct {
+		req *shortifypb.CreateURLRequest
+
+		svcReq  *dto.CreateURLRequest
+		svcResp *dto.CreateURLResponse
+		svcErr  error
+	}
+
+	type Expected struct {
+		resp    *shortifypb.URL
+		code    codes.Code
+		message string
+	}
+
+	testCases := map[string]struct {
+		given    Given
+		expected Expected
+	}{
+		"Success": {
+			Given{
+				req: &shortifypb.CreateURLRequest{
+					Original: "https://example.com/long",
+					Tags:     []string{"promo"},
+				},
+
+				svcReq: &dto.CreateURLRequest{
+					Original: "https://example.com/long",
+					Tags:     []string{"promo"},
+				},
+				svcResp: &dto.CreateURLResponse{
+					Original:  "https://example.com/long",
+					Alias:     "fjda89fadb",
+					QueryMode: "ignore",
+					Tags:      []string{"promo"},
+				},
+			},
+			Expected{
+				resp: &shortifypb.URL{
+					Original:  "https://example.com/long",
+					Alias:     "fjda89fadb",
+					QueryMode: "ignore",
+					Tags:      []string{"promo"},
+				},
+				code: codes.OK,
+			},
+		},
+		"Invalid original": {
+			Given{
+				req: &shortifypb.CreateURLRequest{
+					Original: "invalidurl",
+				},
+			},
+			Expected{
+				code:    codes.InvalidArgument,
+				message: "Field 'original' is not a valid URL",
+			},
+		},
+		"Already exists": {
+			Given{
+				req: &shortifypb.CreateURLRequest{
+					Original: "https://example.com/long",
+				},
+
+				svcReq: &dto.CreateURLRequest{
+					Original: "https://example.com/long",
+				},
+				svcErr: url.ErrAlreadyExists,
+			},
+			Expected{
+				code:    codes.AlreadyExists,
+				message: "URL already exists",
+			},
+		},
+		"Other error": {
+			Given{
+				req: &shortifypb.CreateURLRequest{
+					Original: "https://example.com/long",
+				},
+
+				svcReq: &dto.CreateURLRequest{
+					Original: "https://example.com/long",
+				},
+				svcErr: errors.New("svc error"),
+			},
+			Expected{
+				code:    codes.Internal,
+				message: "internal error",
+			},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			// Given
+			svc := urlmock.NewService(t)
+
+			if tc.given.svcResp != nil || tc.given.svcErr != nil {
+				svc.On("Create", mock.Anything, tc.given.svcReq).
+					Return(tc.given.svcResp, tc.given.svcErr).
+					Once()
+			}
+
+			client := newClient(t, svc)
+
+			// When
+			resp, err := client.Create(context.Background(), tc.given.req)
+
+			// Then
+			require.Equal(t, tc.expected.code, status.Code(err))
+
+			if tc.expected.code != codes.OK {
+				require.Equal(t, tc.expected.message, status.Convert(err).Message())
+				return
+			}
+
+			require.Equal(t, tc.expected.resp.GetAlias(), resp.GetAlias())
+			require.Equal(t, tc.expected.resp.GetOriginal(), resp.GetOriginal())
+			require.Equal(t, tc.expected.resp.GetQueryMode(), resp.GetQueryMode())
+			require.Equal(t, tc.expected.resp.GetTags(), resp.GetTags())
+		})
+	}
+}
+
+func TestURLServer_GetByAlias(t *testing.T) {
+	testCases := map[string]struct {
+		alias  string
+		svcErr error
+		code   codes.Code
+	}{
+		"Success": {
+			alias: "fjda89fadb",
+			code:  codes.OK,
+		},
+		"Empty alias": {
+			alias: "",
+			code:  codes.InvalidArgument,
+		},
+		"Not found": {
+			alias:  "fjda89fadb",
+			svcErr: url.ErrNotFound,
+			code:   codes.NotFound,
+		},
+		"Deleted": {
+			alias:  "fjda89fadb",
+			svcErr: url.ErrDeleted,
+			code:   codes.NotFound,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			// Given
+			svc := urlmock.NewService(t)
+
+			if tc.alias != "" {
+				var svcResp *dto.GetURLByAliasResponse
+				if tc.svcErr == nil {
+					svcResp = &dto.GetURLByAliasResponse{
+						Original: "https://example.com/long",
+						Alias:    tc.alias,
+						Clicks:   3,
+					}
+				}
+
+				svc.On("GetByAlias", mock.Anything, &dto.GetURLByAliasRequest{Alias: tc.alias}).
+					Return(svcResp, tc.svcErr).
+					Once()
+			}
+
+			client := newClient(t, svc)
+
+			// When
+			resp, err := client.GetByAlias(context.Background(), &shortifypb.GetURLByAliasRequest{Alias: tc.alias})
+
+			// Then
+			require.Equal(t, tc.code, status.Code(err))
+
+			if tc.code == codes.OK {
+				require.Equal(t, "https://example.com/long", resp.GetOriginal())
+				require.Equal(t, int64(3), resp.GetClicks())
+			}
+		})
+	}
+}
+
+func TestURLServer_BatchCreate(t *testing.T) {
+	// Given
+	svc := urlmock.NewService(t)
+	svc.On("Create", mock.Anything, &dto.CreateURLRequest{Original: "https://example.com/1"}).
+		Return(&dto.CreateURLResponse{Original: "https://example.com/1", Alias: "alias00001"}, nil).
+		Once()
+	svc.On("Create", mock.Anything, &dto.CreateURLRequest{Original: "https://example.com/2"}).
+		Return(nil, url.ErrAlreadyExists).
+		Once()
+
+	client := newClient(t, svc)
+
+	stream, err := client.BatchCreate(context.Background())
+	require.NoError(t, err)
+
+	// When
+	for _, original := range []string{"https://example.com/1", "https://example.com/2", "invalidurl"} {
+		err := stream.Send(&shortifypb.CreateURLRequest{Original: original})
+		require.NoError(t, err)
+	}
+
+	require.NoError(t, stream.CloseSend())
+
+	var resps []*shortifypb.BatchCreateURLResponse
+
+	for {
+		resp, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		require.NoError(t, err)
+
+		resps = append(resps, resp)
+	}
+
+	// Then
+	require.Len(t, resps, 3)
+
+	require.Equal(t, int64(0), resps[0].GetIndex())
+	require.Equal(t, "alias00001", resps[0].GetUrl().GetAlias())
+
+	require.Equal(t, int64(1), resps[1].GetIndex())
+	require.Equal(t, int32(codes.AlreadyExists), resps[1].GetError().GetCode())
+
+	require.Equal(t, int64(2), resps[2].GetIndex())
+	require.Equal(t, int32(codes.InvalidArgument), resps[2].GetError().GetCode())
+}
+
+func TestRequestID(t *testing.T) {
+	// Given
+	svc := urlmock.NewService(t)
+	svc.On("GetByAlias", mock.Anything, mock.Anything).
+		Return(nil, url.ErrNotFound).
+		Once()
+
+	client := newClient(t, svc)
+
+	ctx := metadata.AppendToOutgoingContext(context.Background(), grpcdel.RequestIDKey, "test-request-id")
+
+	var header metadata.MD
+
+	// When
+	_, err := client.GetByAlias(ctx, &shortifypb.GetURLByAliasRequest{Alias: "fjda89fadb"}, grpc.Header(&header))
+
+	// Then
+	require.Equal(t, codes.NotFound, status.Code(err))
+	require.Equal(t, []string{"test-request-id"}, header.Get(grpcdel.RequestIDKey))
+}
+
+func TestAudit(t *testing.T) {
+	// Given
+	svc := urlmock.NewService(t)
+	svc.On("GetByAlias", mock.MatchedBy(func(ctx context.Context) bool {
+		return audit.FromContext(ctx) == audit.Origin{Actor: "alice", RequestID: "test-request-id"}
+	}), mock.Anything).
+		Return(nil, url.ErrNotFound).
+		Once()
+
+	client := newClient(t, svc)
+
+	ctx := metadata.AppendToOutgoingContext(context.Background(),
+		grpcdel.RequestIDKey, "test-request-id",
+		grpcdel.ActorKey, "alice",
+	)
+
+	// When
+	_, err := client.GetByAlias(ctx, &shortifypb.GetURLByAliasRequest{Alias: "fjda89fadb"})
+
+	// Then
+	require.Equal(t, codes.NotFound, status.Code(err))
+}
+
+func newClient(t *testing.T, svc grpcdel.URLService) shortifypb.URLServiceClient {
+	t.Helper()
+
+	log := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	lis := bufconn.Listen(1 << 20)
+
+	srv := grpcdel.NewServer(grpcdel.NewURLServer(svc, log), log)
+
+	go func() {
+		_ = srv.Serve(lis)
+	}()
+
+	t.Cleanup(srv.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+
+	t.Cleanup(func() {
+		_ = conn.Close()
+	})
+
+	return shortifypb.NewURLServiceClient(conn)
+}
@@ -0,0 +1,51 @@
+package grpc
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+)
+
+var validate = newValidator()
+
+// newValidator creates a validator reporting fields by their JSON names
+// which match the protobuf field names.
+func newValidator() *validator.Validate {
+	v := validator.New()
+
+	v.RegisterTagNameFunc(func(field reflect.StructField) string {
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			return ""
+		}
+
+		return name
+	})
+
+	return v
+}
+
+func formatErrs(errs validator.ValidationErrors) string {
+	msgs := make([]string, 0, len(errs))
+
+	for _, err := range errs {
+		field := err.Field()
+
+		switch err.ActualTag() {
+		case "required":
+			msgs = append(msgs, fmt.Sprintf("Field '%s' is missing", field))
+		case "url":
+			msgs = append(msgs, fmt.Sprintf("Field '%s' is not a valid URL", field))
+		case "max":
+			msgs = append(msgs, fmt.Sprintf("Field '%s' must not exceed %s", field, err.Param()))
+		case "oneof":
+			msgs = append(msgs, fmt.Sprintf("Field '%s' must be one of: %s", field, err.Param()))
+		default:
+			msgs = append(msgs, fmt.Sprintf("Field '%s' is not valid", field))
+		}
+	}
+
+	return strings.Join(msgs, ", ")
+}
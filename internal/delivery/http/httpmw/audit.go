@@ -0,0 +1,24 @@
+package httpmw
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/kodeyeen/shortify/internal/audit"
+	"github.com/kodeyeen/shortify/v1"
+)
+
+// Audit attributes the changes made by the request to the actor from the shortify.ActorHeader
+// header and the request ID. The request ID middleware must run before it.
+func Audit(next http.Handler) http.Handler {
+	fn := func(w http.ResponseWriter, r *http.Request) {
+		ctx := audit.NewContext(r.Context(), audit.Origin{
+			Actor:     r.Header.Get(shortify.ActorHeader),
+			RequestID: middleware.GetReqID(r.Context()),
+		})
+
+		next.ServeHTTP(w, r.WithContext(ctx))
+	}
+
+	return http.HandlerFunc(fn)
+}
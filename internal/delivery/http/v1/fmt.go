@@ -11,13 +11,21 @@ func formatErrs(errs validator.ValidationErrors) string {
 	msgs := make([]string, 0, len(errs))
 
 	for _, err := range errs {
-		field := strings.ToLower(err.Field())
+		field := err.Field()
 
 		switch err.ActualTag() {
 		case "required":
 			msgs = append(msgs, fmt.Sprintf("Field '%s' is missing", field))
 		case "url":
 			msgs = append(msgs, fmt.Sprintf("Field '%s' is not a valid URL", field))
+		case "min":
+			msgs = append(msgs, fmt.Sprintf("Field '%s' must be at least %s", field, err.Param()))
+		case "max":
+			msgs = append(msgs, fmt.Sprintf("Field '%s' must not exceed %s", field, err.Param()))
+		case "oneof":
+			msgs = append(msgs, fmt.Sprintf("Field '%s' must be one of: %s", field, err.Param()))
+		case "alias":
+			msgs = append(msgs, fmt.Sprintf("Field '%s' may only contain letters, digits, '-' and '_'", field))
 		default:
 			msgs = append(msgs, fmt.Sprintf("Field '%s' is not valid", field))
 		}
@@ -0,0 +1,48 @@
+package http
+
+import (
+	neturl "net/url"
+	"strconv"
+	"strings"
+)
+
+// metadataParamPrefix prefixes query parameters filtering by metadata, e.g. meta.campaign=spring
+const metadataParamPrefix = "meta."
+
+// intParam parses an optional integer query parameter returning 0 when it's absent.
+func intParam(query neturl.Values, name string) (int, error) {
+	value := query.Get(name)
+	if value == "" {
+		return 0, nil
+	}
+
+	return strconv.Atoi(value)
+}
+
+func boolParam(query neturl.Values, name string) (bool, error) {
+	value := query.Get(name)
+	if value == "" {
+		return false, nil
+	}
+
+	return strconv.ParseBool(value)
+}
+
+func metadataParams(query neturl.Values) map[string]string {
+	var metadata map[string]string
+
+	for name, values := range query {
+		key, ok := strings.CutPrefix(name, metadataParamPrefix)
+		if !ok || key == "" {
+			continue
+		}
+
+		if metadata == nil {
+			metadata = map[string]string{}
+		}
+
+		metadata[key] = values[0]
+	}
+
+	return metadata
+}
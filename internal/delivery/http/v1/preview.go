@@ -0,0 +1,38 @@
+package http
+
+import (
+	"embed"
+	"html/template"
+	"net/http"
+	neturl "net/url"
+	"time"
+)
+
+// PreviewSuffix appended to an alias requests the preview page instead of the redirect.
+const PreviewSuffix = "+"
+
+//go:embed templates/preview.html
+var templatesFS embed.FS
+
+var previewTmpl = template.Must(template.ParseFS(templatesFS, "templates/preview.html"))
+
+type previewData struct {
+	Alias     string
+	Host      string
+	Location  string
+	CreatedAt time.Time
+}
+
+// renderPreview renders the page showing where the alias leads to.
+func renderPreview(w http.ResponseWriter, data previewData) error {
+	if u, err := neturl.Parse(data.Location); err == nil {
+		data.Host = u.Host
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set("Referrer-Policy", "no-referrer")
+	w.WriteHeader(http.StatusOK)
+
+	return previewTmpl.Execute(w, data)
+}
@@ -0,0 +1,388 @@
+package http
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/kodeyeen/shortify/v1"
+)
+
+// recordColumns are the columns of the exported CSV. The imported CSV may have any of them
+// in any order, every line holds a single URL when it has no header. The tags and metadata
+// columns hold a JSON array and a JSON object so any tag or metadata value survives a round trip.
+var recordColumns = []string{
+	"alias", "original", "query_mode", "preview", "title", "description", "tags", "metadata", "clicks", "created_at",
+}
+
+// maxRecordLine limits the length of a line of the imported JSONL.
+const maxRecordLine = 1 << 20
+
+var errNoImportFile = errors.New("no file in the form")
+
+// rowError is a malformed row of the imported file, the following rows can still be read.
+type rowError struct {
+	msg string
+}
+
+func (e *rowError) Error() string {
+	return e.msg
+}
+
+// recordEncoder writes the exported URLs.
+type recordEncoder interface {
+	Encode(rec *shortify.URLRecord) error
+	Flush() error
+}
+
+// recordDecoder reads the imported URLs. Decode returns io.EOF after the last row,
+// *rowError for a malformed row and any other error when the rest of the file can't be read.
+type recordDecoder interface {
+	Decode() (rec *shortify.URLRecord, line int, err error)
+}
+
+func validFormat(format string) bool {
+	return format == shortify.FormatCSV || format == shortify.FormatJSONL
+}
+
+// fileFormat guesses the format of the file by its media type and name, JSONL is the default one.
+func fileFormat(mediaType, name string) string {
+	if mediaType == "text/csv" || strings.EqualFold(path.Ext(name), ".csv") {
+		return shortify.FormatCSV
+	}
+
+	return shortify.FormatJSONL
+}
+
+// importFile returns the uploaded file and its format. The file is either
+// the request body or the "file" field of a multipart form, which is read
+// as a stream instead of being parsed up front.
+func importFile(r *http.Request) (io.Reader, string, error) {
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if mediaType != "multipart/form-data" {
+		return r.Body, fileFormat(mediaType, ""), nil
+	}
+
+	mr, err := r.MultipartReader()
+	if err != nil {
+		return nil, "", err
+	}
+
+	for {
+		part, err := mr.NextPart()
+		if errors.Is(err, io.EOF) {
+			return nil, "", errNoImportFile
+		}
+		if err != nil {
+			return nil, "", err
+		}
+
+		if part.FormName() == "file" {
+			partType, _, _ := mime.ParseMediaType(part.Header.Get("Content-Type"))
+
+			return part, fileFormat(partType, part.FileName()), nil
+		}
+	}
+}
+
+func setExportHeaders(w http.ResponseWriter, format string) {
+	contentType := "application/x-ndjson"
+	if format == shortify.FormatCSV {
+		contentType = "text/csv; charset=utf-8"
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="urls.%s"`, format))
+}
+
+func newRecordEncoder(w io.Writer, format string) recordEncoder {
+	if format == shortify.FormatCSV {
+		return &csvEncoder{w: csv.NewWriter(w)}
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+
+	return &jsonlEncoder{enc: enc}
+}
+
+func newRecordDecoder(r io.Reader, format string) (recordDecoder, error) {
+	if format == shortify.FormatCSV {
+		return newCSVDecoder(r)
+	}
+
+	sc := bufio.NewScanner(r)
+	sc.Buffer(nil, maxRecordLine)
+
+	return &jsonlDecoder{sc: sc}, nil
+}
+
+type jsonlEncoder struct {
+	enc *json.Encoder
+}
+
+func (e *jsonlEncoder) Encode(rec *shortify.URLRecord) error {
+	return e.enc.Encode(rec)
+}
+
+func (e *jsonlEncoder) Flush() error {
+	return nil
+}
+
+type jsonlDecoder struct {
+	sc   *bufio.Scanner
+	line int
+}
+
+func (d *jsonlDecoder) Decode() (*shortify.URLRecord, int, error) {
+	for d.sc.Scan() {
+		d.line++
+
+		data := bytes.TrimSpace(d.sc.Bytes())
+		if len(data) == 0 {
+			continue
+		}
+
+		var rec shortify.URLRecord
+
+		err := json.Unmarshal(data, &rec)
+		if err != nil {
+			return nil, d.line, &rowError{msg: "Invalid JSON"}
+		}
+
+		return &rec, d.line, nil
+	}
+
+	if err := d.sc.Err(); err != nil {
+		return nil, d.line + 1, err
+	}
+
+	return nil, d.line, io.EOF
+}
+
+type csvEncoder struct {
+	w             *csv.Writer
+	headerWritten bool
+}
+
+func (e *csvEncoder) Encode(rec *shortify.URLRecord) error {
+	err := e.writeHeader()
+	if err != nil {
+		return err
+	}
+
+	var tags, metadata string
+
+	if len(rec.Tags) > 0 {
+		tags, err = jsonCell(rec.Tags)
+		if err != nil {
+			return err
+		}
+	}
+
+	if len(rec.Metadata) > 0 {
+		metadata, err = jsonCell(rec.Metadata)
+		if err != nil {
+			return err
+		}
+	}
+
+	return e.w.Write([]string{
+		rec.Alias,
+		rec.Original,
+		rec.QueryMode,
+		strconv.FormatBool(rec.Preview),
+		rec.Title,
+		rec.Description,
+		tags,
+		metadata,
+		strconv.FormatInt(rec.Clicks, 10),
+		rec.CreatedAt.UTC().Format(time.RFC3339Nano),
+	})
+}
+
+// Flush writes the buffered rows and the header when there were no rows.
+func (e *csvEncoder) Flush() error {
+	err := e.writeHeader()
+	if err != nil {
+		return err
+	}
+
+	e.w.Flush()
+
+	return e.w.Error()
+}
+
+// jsonCell encodes v as a CSV cell, maps are encoded with sorted keys.
+func jsonCell(v any) (string, error) {
+	var buf bytes.Buffer
+
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+
+	err := enc.Encode(v)
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimSuffix(buf.String(), "\n"), nil
+}
+
+func (e *csvEncoder) writeHeader() error {
+	if e.headerWritten {
+		return nil
+	}
+
+	e.headerWritten = true
+
+	return e.w.Write(recordColumns)
+}
+
+type csvDecoder struct {
+	r       *csv.Reader
+	columns []string
+
+	// pending is the first row when the file has no header.
+	pending     []string
+	pendingLine int
+}
+
+// newCSVDecoder reads the header of the CSV, it fails on unknown columns.
+func newCSVDecoder(r io.Reader) (*csvDecoder, error) {
+	d := &csvDecoder{
+		r:       csv.NewReader(r),
+		columns: []string{"original"},
+	}
+
+	d.r.FieldsPerRecord = -1
+	d.r.TrimLeadingSpace = true
+
+	record, err := d.r.Read()
+	if errors.Is(err, io.EOF) {
+		return d, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if !slices.Contains(record, "original") {
+		d.pending = record
+		d.pendingLine, _ = d.r.FieldPos(0)
+
+		return d, nil
+	}
+
+	for _, column := range record {
+		if !slices.Contains(recordColumns, column) {
+			return nil, &rowError{msg: fmt.Sprintf("Unknown column '%s'", column)}
+		}
+	}
+
+	d.columns = record
+
+	return d, nil
+}
+
+func (d *csvDecoder) Decode() (*shortify.URLRecord, int, error) {
+	record, line := d.pending, d.pendingLine
+	d.pending = nil
+
+	if record == nil {
+		var err error
+
+		record, err = d.r.Read()
+		if err != nil {
+			var parseErr *csv.ParseError
+			if errors.As(err, &parseErr) {
+				return nil, parseErr.StartLine, err
+			}
+
+			return nil, 0, err
+		}
+
+		line, _ = d.r.FieldPos(0)
+	}
+
+	rec, err := newCSVRecord(d.columns, record)
+	if err != nil {
+		return nil, line, err
+	}
+
+	return rec, line, nil
+}
+
+func newCSVRecord(columns, record []string) (*shortify.URLRecord, error) {
+	if len(record) > len(columns) {
+		return nil, &rowError{msg: fmt.Sprintf("Expected at most %d fields, got %d", len(columns), len(record))}
+	}
+
+	var rec shortify.URLRecord
+
+	for i, value := range record {
+		switch columns[i] {
+		case "alias":
+			rec.Alias = value
+		case "original":
+			rec.Original = value
+		case "query_mode":
+			rec.QueryMode = value
+		case "preview":
+			if value == "" {
+				continue
+			}
+
+			preview, err := strconv.ParseBool(value)
+			if err != nil {
+				return nil, &rowError{msg: "Field 'preview' must be a boolean"}
+			}
+
+			rec.Preview = preview
+		case "title":
+			rec.Title = value
+		case "description":
+			rec.Description = value
+		case "tags":
+			if value == "" {
+				continue
+			}
+
+			err := json.Unmarshal([]byte(value), &rec.Tags)
+			if err != nil {
+				return nil, &rowError{msg: "Field 'tags' must be a JSON array of strings"}
+			}
+		case "metadata":
+			if value == "" {
+				continue
+			}
+
+			err := json.Unmarshal([]byte(value), &rec.Metadata)
+			if err != nil {
+				return nil, &rowError{msg: "Field 'metadata' must be a JSON object of strings"}
+			}
+		case "created_at":
+			if value == "" {
+				continue
+			}
+
+			createdAt, err := time.Parse(time.RFC3339Nano, value)
+			if err != nil {
+				return nil, &rowError{msg: "Field 'created_at' must be an RFC 3339 time"}
+			}
+
+			rec.CreatedAt = createdAt
+		}
+	}
+
+	return &rec, nil
+}
@@ -0,0 +1,46 @@
+package http
+
+import (
+	"log/slog"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/kodeyeen/shortify/internal/delivery/http/httpmw"
+)
+
+// NewRouter creates the router serving the API and the redirects.
+func NewRouter(urls *URLController, webhooks *WebhookController, log *slog.Logger) *chi.Mux {
+	router := chi.NewRouter()
+	router.Use(middleware.RequestID)
+	router.Use(middleware.Recoverer)
+	router.Use(middleware.URLFormat)
+	router.Use(httpmw.NewLogger(log))
+	router.Use(httpmw.Audit)
+
+	router.Route("/api/v1", func(r chi.Router) {
+		r.Post("/urls", urls.Create)
+		r.Get("/urls", urls.List)
+		r.Get("/urls/export", urls.Export)
+		r.Post("/urls/import", urls.Import)
+		r.Get("/urls/{alias}", urls.GetByAlias)
+		r.Patch("/urls/{alias}", urls.Update)
+		r.Delete("/urls/{alias}", urls.Delete)
+		r.Post("/urls/{alias}/restore", urls.Restore)
+		r.Get("/urls/{alias}/aliases", urls.ListAliases)
+		r.Get("/urls/{alias}/history", urls.History)
+		r.Post("/urls/{alias}/rollback", urls.Rollback)
+
+		r.Get("/aliases/capacity", urls.Capacity)
+
+		r.Post("/webhooks", webhooks.Create)
+		r.Get("/webhooks", webhooks.List)
+		r.Get("/webhooks/{id}", webhooks.GetByID)
+		r.Delete("/webhooks/{id}", webhooks.Delete)
+		r.Get("/webhooks/{id}/deliveries", webhooks.ListDeliveries)
+	})
+
+	router.Get("/{alias}", urls.Redirect)
+	router.Get("/{alias}/*", urls.Redirect)
+
+	return router
+}
@@ -3,8 +3,11 @@ package http
 import (
 	"context"
 	"errors"
+	"fmt"
+	"io"
 	"log/slog"
 	"net/http"
+	"strings"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
@@ -18,6 +21,17 @@ import (
 type URLService interface {
 	Create(ctx context.Context, req *dto.CreateURLRequest) (*dto.CreateURLResponse, error)
 	GetByAlias(ctx context.Context, req *dto.GetURLByAliasRequest) (*dto.GetURLByAliasResponse, error)
+	List(ctx context.Context, req *dto.ListURLsRequest) (*dto.ListURLsResponse, error)
+	Update(ctx context.Context, req *dto.UpdateURLRequest) (*dto.GetURLByAliasResponse, error)
+	Delete(ctx context.Context, req *dto.DeleteURLRequest) error
+	Resolve(ctx context.Context, req *dto.ResolveURLRequest) (*dto.ResolveURLResponse, error)
+	Capacity(ctx context.Context) (*dto.CapacityResponse, error)
+	ListAliases(ctx context.Context, req *dto.ListAliasesRequest) (*dto.ListAliasesResponse, error)
+	History(ctx context.Context, req *dto.URLHistoryRequest) (*dto.URLHistoryResponse, error)
+	Rollback(ctx context.Context, req *dto.RollbackURLRequest) (*dto.GetURLByAliasResponse, error)
+	Restore(ctx context.Context, req *dto.RestoreURLRequest) (*dto.GetURLByAliasResponse, error)
+	Export(ctx context.Context, req *dto.ExportURLsRequest, fn func(u *dto.GetURLByAliasResponse) error) error
+	Import(ctx context.Context, req *dto.ImportURLRequest) (*dto.CreateURLResponse, error)
 }
 
 type URLController struct {
@@ -70,7 +84,7 @@ func (c *URLController) Create(w http.ResponseWriter, r *http.Request) {
 
 	log.Info("request body decoded", slog.Any("request", req))
 
-	if err := validator.New().Struct(req); err != nil {
+	if err := validate.Struct(req); err != nil {
 		log.Error("invalid request", slog.String("error", err.Error()))
 
 		validatorErrs := err.(validator.ValidationErrors)
@@ -83,8 +97,29 @@ func (c *URLController) Create(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	out, err := c.urls.Create(ctx, &dto.CreateURLRequest{Original: req.Original})
+	out, err := c.urls.Create(ctx, &dto.CreateURLRequest{
+		Original:    req.Original,
+		QueryMode:   req.QueryMode,
+		Preview:     req.Preview,
+		Title:       req.Title,
+		Description: req.Description,
+		Tags:        req.Tags,
+		Metadata:    req.Metadata,
+
+		AliasProvider: req.AliasProvider,
+	})
 	if err != nil {
+		if errors.Is(err, url.ErrUnknownAliasProvider) {
+			log.Info("unknown alias provider", slog.String("alias_provider", req.AliasProvider))
+
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, shortify.ErrorResponse{
+				Status:  http.StatusBadRequest,
+				Message: "Unknown alias provider",
+			})
+			return
+		}
+
 		if errors.Is(err, url.ErrAlreadyExists) {
 			log.Info("url already exists", slog.String("url", req.Original))
 
@@ -110,8 +145,15 @@ func (c *URLController) Create(w http.ResponseWriter, r *http.Request) {
 
 	render.Status(r, http.StatusCreated)
 	render.JSON(w, r, shortify.CreateURLResponse{
-		Original: out.Original,
-		Alias:    out.Alias,
+		Original:    out.Original,
+		Alias:       out.Alias,
+		QueryMode:   out.QueryMode,
+		Preview:     out.Preview,
+		Title:       out.Title,
+		Description: out.Description,
+		Tags:        out.Tags,
+		Metadata:    out.Metadata,
+		CreatedAt:   out.CreatedAt,
 	})
 }
 
@@ -126,6 +168,7 @@ func (c *URLController) Create(w http.ResponseWriter, r *http.Request) {
 //	@Success		200		{object}	shortify.GetURLByAliasResponse
 //	@Failure		400		{object}	shortify.ErrorResponse
 //	@Failure		404		{object}	shortify.ErrorResponse
+//	@Failure		410		{object}	shortify.ErrorResponse
 //	@Failure		500		{object}	shortify.ErrorResponse
 //	@Router			/api/v1/urls/{alias} [get]
 func (c *URLController) GetByAlias(w http.ResponseWriter, r *http.Request) {
@@ -162,6 +205,17 @@ func (c *URLController) GetByAlias(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 
+		if errors.Is(err, url.ErrDeleted) {
+			log.Info("URL is deleted", "alias", alias)
+
+			render.Status(r, http.StatusGone)
+			render.JSON(w, r, shortify.ErrorResponse{
+				Status:  http.StatusGone,
+				Message: "URL is deleted",
+			})
+			return
+		}
+
 		log.Error("failed to get URL by alias", slog.String("error", err.Error()))
 
 		render.Status(r, http.StatusInternalServerError)
@@ -175,9 +229,1133 @@ func (c *URLController) GetByAlias(w http.ResponseWriter, r *http.Request) {
 	log.Info("got URL by alias", slog.String("url", out.Original))
 
 	render.Status(r, http.StatusOK)
-	render.JSON(w, r, shortify.GetURLByAliasResponse{
-		Original: out.Original,
-		Alias:    out.Alias,
+	render.JSON(w, r, newGetURLByAliasResponse(out))
+}
+
+// List lists URLs
+//
+//	@Summary		List URLs
+//	@Description	List lists URLs ordered by creation optionally filtered by tag and metadata
+//	@Tags			urls
+//	@Produce		json
+//	@Param			tag		query		string	false	"Tag the URLs have"
+//	@Param			meta	query		string	false	"Metadata the URLs have as meta.<key>=<value> parameters"
+//	@Param			limit	query		int		false	"Maximum number of URLs"	default(50)	maximum(1000)
+//	@Param			offset	query		int		false	"Number of URLs to skip"
+//	@Success		200		{object}	shortify.ListURLsResponse
+//	@Failure		400		{object}	shortify.ErrorResponse
+//	@Failure		500		{object}	shortify.ErrorResponse
+//	@Router			/api/v1/urls [get]
+func (c *URLController) List(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	log := c.log.With(
+		slog.String("handler", "List"),
+		slog.String("request_id", middleware.GetReqID(ctx)),
+	)
+
+	query := r.URL.Query()
+
+	limit, err := intParam(query, "limit")
+	if err != nil || limit < 0 || limit > url.MaxListLimit {
+		log.Info("invalid limit", slog.String("limit", query.Get("limit")))
+
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, shortify.ErrorResponse{
+			Status:  http.StatusBadRequest,
+			Message: fmt.Sprintf("Parameter 'limit' must be an integer between 0 and %d", url.MaxListLimit),
+		})
+		return
+	}
+
+	offset, err := intParam(query, "offset")
+	if err != nil || offset < 0 {
+		log.Info("invalid offset", slog.String("offset", query.Get("offset")))
+
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, shortify.ErrorResponse{
+			Status:  http.StatusBadRequest,
+			Message: "Parameter 'offset' must be a non-negative integer",
+		})
+		return
+	}
+
+	out, err := c.urls.List(ctx, &dto.ListURLsRequest{
+		Tag:      query.Get("tag"),
+		Metadata: metadataParams(query),
+		Limit:    limit,
+		Offset:   offset,
+	})
+	if err != nil {
+		log.Error("failed to list URLs", slog.String("error", err.Error()))
+
+		render.Status(r, http.StatusInternalServerError)
+		render.JSON(w, r, shortify.ErrorResponse{
+			Status:  http.StatusInternalServerError,
+			Message: http.StatusText(http.StatusInternalServerError),
+		})
+		return
+	}
+
+	log.Info("listed URLs", slog.Int("count", len(out.URLs)))
+
+	resp := shortify.ListURLsResponse{
+		URLs: make([]shortify.GetURLByAliasResponse, 0, len(out.URLs)),
+	}
+
+	for _, u := range out.URLs {
+		resp.URLs = append(resp.URLs, newGetURLByAliasResponse(u))
+	}
+
+	render.Status(r, http.StatusOK)
+	render.JSON(w, r, resp)
+}
+
+// Export streams the URLs as a CSV or JSONL file
+//
+//	@Summary		Export URLs
+//	@Description	Export streams the URLs ordered by creation as a CSV or JSONL file, deleted URLs are skipped
+//	@Tags			urls
+//	@Produce		text/csv,application/x-ndjson
+//	@Param			format	query		string	false	"File format"	Enums(csv, jsonl)	default(jsonl)
+//	@Param			tag		query		string	false	"Tag the URLs have"
+//	@Param			meta	query		string	false	"Metadata the URLs have as meta.<key>=<value> parameters"
+//	@Success		200		{array}		shortify.URLRecord
+//	@Failure		400		{object}	shortify.ErrorResponse
+//	@Failure		500		{object}	shortify.ErrorResponse
+//	@Router			/api/v1/urls/export [get]
+func (c *URLController) Export(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	log := c.log.With(
+		slog.String("handler", "Export"),
+		slog.String("request_id", middleware.GetReqID(ctx)),
+	)
+
+	query := r.URL.Query()
+
+	format := query.Get("format")
+	if format == "" {
+		format = shortify.FormatJSONL
+	}
+
+	if !validFormat(format) {
+		log.Info("invalid format", slog.String("format", format))
+
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, shortify.ErrorResponse{
+			Status:  http.StatusBadRequest,
+			Message: "Parameter 'format' must be one of: csv jsonl",
+		})
+		return
+	}
+
+	var (
+		enc     = newRecordEncoder(w, format)
+		written = 0
+	)
+
+	// the headers are sent with the first URL so a failure before it still gets an error response
+	err := c.urls.Export(ctx, &dto.ExportURLsRequest{
+		Tag:      query.Get("tag"),
+		Metadata: metadataParams(query),
+	}, func(u *dto.GetURLByAliasResponse) error {
+		if written == 0 {
+			setExportHeaders(w, format)
+		}
+
+		written++
+
+		rec := newURLRecord(u)
+
+		return enc.Encode(&rec)
+	})
+	if err != nil {
+		if written > 0 {
+			log.Error("export interrupted", slog.Int("count", written), slog.String("error", err.Error()))
+			return
+		}
+
+		log.Error("failed to export URLs", slog.String("error", err.Error()))
+
+		render.Status(r, http.StatusInternalServerError)
+		render.JSON(w, r, shortify.ErrorResponse{
+			Status:  http.StatusInternalServerError,
+			Message: http.StatusText(http.StatusInternalServerError),
+		})
+		return
+	}
+
+	if written == 0 {
+		setExportHeaders(w, format)
+	}
+
+	if err := enc.Flush(); err != nil {
+		log.Error("failed to write export", slog.String("error", err.Error()))
+		return
+	}
+
+	log.Info("exported URLs", slog.Int("count", written))
+}
+
+// Import creates URLs from a CSV or JSONL file keeping their aliases
+//
+//	@Summary		Import URLs
+//	@Description	Import creates URLs from a CSV or JSONL file read as a stream, rows without an alias get a generated one.
+//	@Description	The file is the request body or the "file" field of a multipart form. Rows that conflict with stored URLs
+//	@Description	or are invalid are reported with their line numbers while the other rows are imported.
+//	@Tags			urls
+//	@Accept			text/csv,application/x-ndjson,mpfd
+//	@Produce		json
+//	@Param			format	query		string	false	"File format, guessed by the content type or the file name by default"	Enums(csv, jsonl)
+//	@Param			dry_run	query		bool	false	"Check the rows for conflicts without creating URLs"
+//	@Param			file	formData	file	false	"Imported file"
+//	@Success		200		{object}	shortify.ImportURLsResponse
+//	@Failure		400		{object}	shortify.ErrorResponse
+//	@Router			/api/v1/urls/import [post]
+func (c *URLController) Import(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	log := c.log.With(
+		slog.String("handler", "Import"),
+		slog.String("request_id", middleware.GetReqID(ctx)),
+	)
+
+	query := r.URL.Query()
+
+	dryRun, err := boolParam(query, "dry_run")
+	if err != nil {
+		log.Info("invalid dry run", slog.String("dry_run", query.Get("dry_run")))
+
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, shortify.ErrorResponse{
+			Status:  http.StatusBadRequest,
+			Message: "Parameter 'dry_run' must be a boolean",
+		})
+		return
+	}
+
+	format := query.Get("format")
+	if format != "" && !validFormat(format) {
+		log.Info("invalid format", slog.String("format", format))
+
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, shortify.ErrorResponse{
+			Status:  http.StatusBadRequest,
+			Message: "Parameter 'format' must be one of: csv jsonl",
+		})
+		return
+	}
+
+	file, detected, err := importFile(r)
+	if err != nil {
+		log.Info("failed to read file", slog.String("error", err.Error()))
+
+		msg := "Invalid request body"
+		if errors.Is(err, errNoImportFile) {
+			msg = "Field 'file' is missing"
+		}
+
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, shortify.ErrorResponse{
+			Status:  http.StatusBadRequest,
+			Message: msg,
+		})
+		return
+	}
+
+	if format == "" {
+		format = detected
+	}
+
+	dec, err := newRecordDecoder(file, format)
+	if err != nil {
+		log.Info("failed to read file header", slog.String("error", err.Error()))
+
+		msg := "Invalid CSV"
+
+		var rowErr *rowError
+		if errors.As(err, &rowErr) {
+			msg = rowErr.msg
+		}
+
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, shortify.ErrorResponse{
+			Status:  http.StatusBadRequest,
+			Message: msg,
+		})
+		return
+	}
+
+	resp := shortify.ImportURLsResponse{
+		DryRun: dryRun,
+		Errors: []shortify.ImportError{},
+	}
+
+	var run *dto.ImportDryRun
+	if dryRun {
+		run = &dto.ImportDryRun{}
+	}
+
+	for {
+		rec, line, err := dec.Decode()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		var rowErr *rowError
+
+		if errors.As(err, &rowErr) {
+			resp.Errors = append(resp.Errors, shortify.ImportError{
+				Line:    line,
+				Status:  http.StatusBadRequest,
+				Message: rowErr.msg,
+			})
+			continue
+		} else if err != nil {
+			log.Info("failed to read file", slog.Int("line", line), slog.String("error", err.Error()))
+
+			resp.Errors = append(resp.Errors, shortify.ImportError{
+				Line:    line,
+				Status:  http.StatusBadRequest,
+				Message: "File can't be read from this line on",
+			})
+			break
+		}
+
+		status, msg := c.importRecord(ctx, log, rec, run)
+		if status == 0 {
+			resp.Imported++
+			continue
+		}
+
+		resp.Errors = append(resp.Errors, shortify.ImportError{
+			Line:     line,
+			Alias:    rec.Alias,
+			Original: rec.Original,
+			Status:   status,
+			Message:  msg,
+		})
+
+		if status == http.StatusInternalServerError {
+			break
+		}
+	}
+
+	resp.Failed = len(resp.Errors)
+
+	log.Info("imported URLs",
+		slog.Bool("dry_run", dryRun),
+		slog.Int("imported", resp.Imported),
+		slog.Int("failed", resp.Failed),
+	)
+
+	render.Status(r, http.StatusOK)
+	render.JSON(w, r, resp)
+}
+
+// importRecord imports the URL and returns the status and the message of the failure,
+// the status is 0 when the URL is imported.
+func (c *URLController) importRecord(ctx context.Context, log *slog.Logger, rec *shortify.URLRecord, run *dto.ImportDryRun) (int, string) {
+	if err := validate.Struct(rec); err != nil {
+		return http.StatusBadRequest, formatErrs(err.(validator.ValidationErrors))
+	}
+
+	_, err := c.urls.Import(ctx, &dto.ImportURLRequest{
+		Alias:       rec.Alias,
+		Original:    rec.Original,
+		QueryMode:   rec.QueryMode,
+		Preview:     rec.Preview,
+		Title:       rec.Title,
+		Description: rec.Description,
+		Tags:        rec.Tags,
+		Metadata:    rec.Metadata,
+		CreatedAt:   rec.CreatedAt,
+		DryRun:      run,
+	})
+
+	switch {
+	case err == nil:
+		return 0, ""
+	case errors.Is(err, url.ErrAlreadyExists):
+		return http.StatusConflict, "URL already exists"
+	case errors.Is(err, url.ErrAliasTaken), errors.Is(err, url.ErrAliasQuarantined):
+		return http.StatusConflict, "Alias is not available"
+	default:
+		log.Error("failed to import URL", slog.String("original", rec.Original), slog.String("error", err.Error()))
+
+		return http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError)
+	}
+}
+
+// Capacity reports the alias keyspace capacity
+//
+//	@Summary		Alias keyspace capacity
+//	@Description	Capacity reports the keyspace size of the default alias provider, the number of stored URLs and the estimated chance of alias collisions
+//	@Tags			urls
+//	@Produce		json
+//	@Success		200	{object}	shortify.AliasCapacityResponse
+//	@Failure		500	{object}	shortify.ErrorResponse
+//	@Router			/api/v1/aliases/capacity [get]
+func (c *URLController) Capacity(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	log := c.log.With(
+		slog.String("handler", "Capacity"),
+		slog.String("request_id", middleware.GetReqID(ctx)),
+	)
+
+	out, err := c.urls.Capacity(ctx)
+	if err != nil {
+		log.Error("failed to get alias capacity", slog.String("error", err.Error()))
+
+		render.Status(r, http.StatusInternalServerError)
+		render.JSON(w, r, shortify.ErrorResponse{
+			Status:  http.StatusInternalServerError,
+			Message: http.StatusText(http.StatusInternalServerError),
+		})
+		return
+	}
+
+	render.Status(r, http.StatusOK)
+	render.JSON(w, r, shortify.AliasCapacityResponse{
+		Provider:             out.Provider,
+		KeyspaceSize:         out.KeyspaceSize,
+		KeyspaceBits:         out.KeyspaceBits,
+		Occupied:             out.Occupied,
+		Occupancy:            out.Occupancy,
+		CollisionProbability: out.CollisionProbability,
+		FailureProbability:   out.FailureProbability,
+	})
+}
+
+// ListAliases lists the retired aliases of the URL
+//
+//	@Summary		List URL aliases
+//	@Description	ListAliases lists the aliases the URL had before its current one, the most recently retired first. Retired aliases keep resolving to the URL
+//	@Tags			urls
+//	@Produce		json
+//	@Param			alias	path		string	true	"Current or retired URL alias"
+//	@Success		200		{object}	shortify.AliasHistoryResponse
+//	@Failure		400		{object}	shortify.ErrorResponse
+//	@Failure		404		{object}	shortify.ErrorResponse
+//	@Failure		410		{object}	shortify.ErrorResponse
+//	@Failure		500		{object}	shortify.ErrorResponse
+//	@Router			/api/v1/urls/{alias}/aliases [get]
+func (c *URLController) ListAliases(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	log := c.log.With(
+		slog.String("handler", "ListAliases"),
+		slog.String("request_id", middleware.GetReqID(ctx)),
+	)
+
+	alias := chi.URLParam(r, "alias")
+	if alias == "" {
+		log.Info("alias is empty")
+
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, shortify.ErrorResponse{
+			Status:  http.StatusBadRequest,
+			Message: "Alias is empty",
+		})
+		return
+	}
+
+	out, err := c.urls.ListAliases(ctx, &dto.ListAliasesRequest{
+		Alias: alias,
 	})
-	// http.Redirect(w, r, resp.Original, http.StatusFound)
+	if err != nil {
+		if errors.Is(err, url.ErrNotFound) {
+			log.Info("URL not found", "alias", alias)
+
+			render.Status(r, http.StatusNotFound)
+			render.JSON(w, r, shortify.ErrorResponse{
+				Status:  http.StatusNotFound,
+				Message: http.StatusText(http.StatusNotFound),
+			})
+			return
+		}
+
+		if errors.Is(err, url.ErrDeleted) {
+			log.Info("URL is deleted", "alias", alias)
+
+			render.Status(r, http.StatusGone)
+			render.JSON(w, r, shortify.ErrorResponse{
+				Status:  http.StatusGone,
+				Message: "URL is deleted",
+			})
+			return
+		}
+
+		log.Error("failed to list URL aliases", slog.String("error", err.Error()))
+
+		render.Status(r, http.StatusInternalServerError)
+		render.JSON(w, r, shortify.ErrorResponse{
+			Status:  http.StatusInternalServerError,
+			Message: http.StatusText(http.StatusInternalServerError),
+		})
+		return
+	}
+
+	resp := shortify.AliasHistoryResponse{
+		Alias:   out.Alias,
+		Retired: make([]shortify.RetiredAlias, 0, len(out.Retired)),
+	}
+
+	for _, ra := range out.Retired {
+		resp.Retired = append(resp.Retired, shortify.RetiredAlias{
+			Alias:            ra.Alias,
+			RetiredAt:        ra.RetiredAt,
+			QuarantinedUntil: ra.QuarantinedUntil,
+		})
+	}
+
+	render.Status(r, http.StatusOK)
+	render.JSON(w, r, resp)
+}
+
+// History lists the changes to the URL
+//
+//	@Summary		URL history
+//	@Description	History lists the changes to the URL, the oldest first, with who made them, the request ID and the changed fields. Set the X-Actor header to attribute changes
+//	@Tags			urls
+//	@Produce		json
+//	@Param			alias	path		string	true	"Current or retired URL alias"
+//	@Success		200		{object}	shortify.URLHistoryResponse
+//	@Failure		400		{object}	shortify.ErrorResponse
+//	@Failure		404		{object}	shortify.ErrorResponse
+//	@Failure		500		{object}	shortify.ErrorResponse
+//	@Router			/api/v1/urls/{alias}/history [get]
+func (c *URLController) History(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	log := c.log.With(
+		slog.String("handler", "History"),
+		slog.String("request_id", middleware.GetReqID(ctx)),
+	)
+
+	alias := chi.URLParam(r, "alias")
+	if alias == "" {
+		log.Info("alias is empty")
+
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, shortify.ErrorResponse{
+			Status:  http.StatusBadRequest,
+			Message: "Alias is empty",
+		})
+		return
+	}
+
+	out, err := c.urls.History(ctx, &dto.URLHistoryRequest{
+		Alias: alias,
+	})
+	if err != nil {
+		if errors.Is(err, url.ErrNotFound) {
+			log.Info("URL not found", "alias", alias)
+
+			render.Status(r, http.StatusNotFound)
+			render.JSON(w, r, shortify.ErrorResponse{
+				Status:  http.StatusNotFound,
+				Message: http.StatusText(http.StatusNotFound),
+			})
+			return
+		}
+
+		log.Error("failed to get URL history", slog.String("error", err.Error()))
+
+		render.Status(r, http.StatusInternalServerError)
+		render.JSON(w, r, shortify.ErrorResponse{
+			Status:  http.StatusInternalServerError,
+			Message: http.StatusText(http.StatusInternalServerError),
+		})
+		return
+	}
+
+	resp := shortify.URLHistoryResponse{
+		Alias:    out.Alias,
+		Versions: make([]shortify.URLVersion, 0, len(out.Versions)),
+	}
+
+	for _, v := range out.Versions {
+		changes := make([]shortify.FieldChange, 0, len(v.Changes))
+
+		for _, ch := range v.Changes {
+			changes = append(changes, shortify.FieldChange{
+				Field:  ch.Field,
+				Before: ch.Before,
+				After:  ch.After,
+			})
+		}
+
+		resp.Versions = append(resp.Versions, shortify.URLVersion{
+			Version:   v.Version,
+			Action:    v.Action,
+			Actor:     v.Actor,
+			RequestID: v.RequestID,
+			CreatedAt: v.CreatedAt,
+			Changes:   changes,
+		})
+	}
+
+	render.Status(r, http.StatusOK)
+	render.JSON(w, r, resp)
+}
+
+// Rollback restores the URL to a prior version
+//
+//	@Summary		Roll back URL
+//	@Description	Rollback restores the URL fields to the state after the version from the URL history. The rollback is recorded as a new version
+//	@Tags			urls
+//	@Accept			json
+//	@Produce		json
+//	@Param			alias	path		string						true	"URL alias"
+//	@Param			version	body		shortify.RollbackURLRequest	true	"Version to roll back to"
+//	@Success		200		{object}	shortify.GetURLByAliasResponse
+//	@Failure		400		{object}	shortify.ErrorResponse
+//	@Failure		404		{object}	shortify.ErrorResponse
+//	@Failure		410		{object}	shortify.ErrorResponse
+//	@Failure		409		{object}	shortify.ErrorResponse
+//	@Failure		500		{object}	shortify.ErrorResponse
+//	@Router			/api/v1/urls/{alias}/rollback [post]
+func (c *URLController) Rollback(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	log := c.log.With(
+		slog.String("handler", "Rollback"),
+		slog.String("request_id", middleware.GetReqID(ctx)),
+	)
+
+	alias := chi.URLParam(r, "alias")
+	if alias == "" {
+		log.Info("alias is empty")
+
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, shortify.ErrorResponse{
+			Status:  http.StatusBadRequest,
+			Message: "Alias is empty",
+		})
+		return
+	}
+
+	var req shortify.RollbackURLRequest
+
+	err := render.DecodeJSON(r.Body, &req)
+	if err != nil {
+		log.Error("failed to decode request body", slog.String("error", err.Error()))
+
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, shortify.ErrorResponse{
+			Status:  http.StatusBadRequest,
+			Message: "Invalid request body",
+		})
+		return
+	}
+
+	if err := validate.Struct(req); err != nil {
+		log.Error("invalid request", slog.String("error", err.Error()))
+
+		validatorErrs := err.(validator.ValidationErrors)
+
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, shortify.ErrorResponse{
+			Status:  http.StatusBadRequest,
+			Message: formatErrs(validatorErrs),
+		})
+		return
+	}
+
+	out, err := c.urls.Rollback(ctx, &dto.RollbackURLRequest{
+		Alias:   alias,
+		Version: req.Version,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, url.ErrNotFound):
+			log.Info("URL not found", "alias", alias)
+
+			render.Status(r, http.StatusNotFound)
+			render.JSON(w, r, shortify.ErrorResponse{
+				Status:  http.StatusNotFound,
+				Message: http.StatusText(http.StatusNotFound),
+			})
+		case errors.Is(err, url.ErrDeleted):
+			log.Info("URL is deleted", "alias", alias)
+
+			render.Status(r, http.StatusGone)
+			render.JSON(w, r, shortify.ErrorResponse{
+				Status:  http.StatusGone,
+				Message: "URL is deleted",
+			})
+		case errors.Is(err, url.ErrVersionNotFound):
+			log.Info("version not found", slog.String("alias", alias), slog.Int("version", req.Version))
+
+			render.Status(r, http.StatusNotFound)
+			render.JSON(w, r, shortify.ErrorResponse{
+				Status:  http.StatusNotFound,
+				Message: "Version not found",
+			})
+		case errors.Is(err, url.ErrAlreadyExists),
+			errors.Is(err, url.ErrAliasTaken),
+			errors.Is(err, url.ErrAliasQuarantined):
+			log.Info("version conflicts with another URL", slog.String("error", err.Error()))
+
+			render.Status(r, http.StatusConflict)
+			render.JSON(w, r, shortify.ErrorResponse{
+				Status:  http.StatusConflict,
+				Message: "Version conflicts with another URL",
+			})
+		default:
+			log.Error("failed to roll back URL", slog.String("error", err.Error()))
+
+			render.Status(r, http.StatusInternalServerError)
+			render.JSON(w, r, shortify.ErrorResponse{
+				Status:  http.StatusInternalServerError,
+				Message: http.StatusText(http.StatusInternalServerError),
+			})
+		}
+		return
+	}
+
+	log.Info("URL rolled back", slog.String("alias", out.Alias), slog.Int("version", req.Version))
+
+	render.Status(r, http.StatusOK)
+	render.JSON(w, r, newGetURLByAliasResponse(out))
+}
+
+// Update updates URL by its alias
+//
+//	@Summary		Update URL
+//	@Description	Update changes the URL fields present in the request. Tags and metadata are replaced as a whole. The old alias of a renamed URL keeps resolving to it
+//	@Tags			urls
+//	@Accept			json
+//	@Produce		json
+//	@Param			alias	path		string						true	"URL alias"
+//	@Param			URL		body		shortify.UpdateURLRequest	true	"Update URL"
+//	@Success		200		{object}	shortify.GetURLByAliasResponse
+//	@Failure		400		{object}	shortify.ErrorResponse
+//	@Failure		404		{object}	shortify.ErrorResponse
+//	@Failure		410		{object}	shortify.ErrorResponse
+//	@Failure		409		{object}	shortify.ErrorResponse
+//	@Failure		500		{object}	shortify.ErrorResponse
+//	@Router			/api/v1/urls/{alias} [patch]
+func (c *URLController) Update(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	log := c.log.With(
+		slog.String("handler", "Update"),
+		slog.String("request_id", middleware.GetReqID(ctx)),
+	)
+
+	alias := chi.URLParam(r, "alias")
+	if alias == "" {
+		log.Info("alias is empty")
+
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, shortify.ErrorResponse{
+			Status:  http.StatusBadRequest,
+			Message: "Alias is empty",
+		})
+		return
+	}
+
+	var req shortify.UpdateURLRequest
+
+	err := render.DecodeJSON(r.Body, &req)
+	if err != nil {
+		log.Error("failed to decode request body", slog.String("error", err.Error()))
+
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, shortify.ErrorResponse{
+			Status:  http.StatusBadRequest,
+			Message: "Invalid request body",
+		})
+		return
+	}
+
+	if err := validate.Struct(req); err != nil {
+		log.Error("invalid request", slog.String("error", err.Error()))
+
+		validatorErrs := err.(validator.ValidationErrors)
+
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, shortify.ErrorResponse{
+			Status:  http.StatusBadRequest,
+			Message: formatErrs(validatorErrs),
+		})
+		return
+	}
+
+	out, err := c.urls.Update(ctx, &dto.UpdateURLRequest{
+		Alias:       alias,
+		NewAlias:    req.Alias,
+		Original:    req.Original,
+		QueryMode:   req.QueryMode,
+		Preview:     req.Preview,
+		Title:       req.Title,
+		Description: req.Description,
+		Tags:        req.Tags,
+		Metadata:    req.Metadata,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, url.ErrNotFound):
+			log.Info("URL not found", "alias", alias)
+
+			render.Status(r, http.StatusNotFound)
+			render.JSON(w, r, shortify.ErrorResponse{
+				Status:  http.StatusNotFound,
+				Message: http.StatusText(http.StatusNotFound),
+			})
+		case errors.Is(err, url.ErrDeleted):
+			log.Info("URL is deleted", "alias", alias)
+
+			render.Status(r, http.StatusGone)
+			render.JSON(w, r, shortify.ErrorResponse{
+				Status:  http.StatusGone,
+				Message: "URL is deleted",
+			})
+		case errors.Is(err, url.ErrAlreadyExists):
+			log.Info("url already exists", slog.String("url", *req.Original))
+
+			render.Status(r, http.StatusConflict)
+			render.JSON(w, r, shortify.ErrorResponse{
+				Status:  http.StatusConflict,
+				Message: "URL already exists",
+			})
+		case errors.Is(err, url.ErrAliasTaken), errors.Is(err, url.ErrAliasQuarantined):
+			log.Info("alias is not available", slog.String("alias", *req.Alias), slog.String("error", err.Error()))
+
+			render.Status(r, http.StatusConflict)
+			render.JSON(w, r, shortify.ErrorResponse{
+				Status:  http.StatusConflict,
+				Message: "Alias is not available",
+			})
+		default:
+			log.Error("failed to update URL", slog.String("error", err.Error()))
+
+			render.Status(r, http.StatusInternalServerError)
+			render.JSON(w, r, shortify.ErrorResponse{
+				Status:  http.StatusInternalServerError,
+				Message: http.StatusText(http.StatusInternalServerError),
+			})
+		}
+		return
+	}
+
+	log.Info("URL updated", slog.String("alias", out.Alias))
+
+	render.Status(r, http.StatusOK)
+	render.JSON(w, r, newGetURLByAliasResponse(out))
+}
+
+// Delete moves URL to the trash by its alias
+//
+//	@Summary		Delete URL
+//	@Description	Delete moves URL to the trash by its alias. Deleted URLs respond with 410 and can be restored until the trash retention period passes, their aliases aren't reused until then
+//	@Tags			urls
+//	@Produce		json
+//	@Param			alias	path	string	true	"URL alias"
+//	@Success		204
+//	@Failure		400	{object}	shortify.ErrorResponse
+//	@Failure		404	{object}	shortify.ErrorResponse
+//	@Failure		410	{object}	shortify.ErrorResponse
+//	@Failure		500	{object}	shortify.ErrorResponse
+//	@Router			/api/v1/urls/{alias} [delete]
+func (c *URLController) Delete(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	log := c.log.With(
+		slog.String("handler", "Delete"),
+		slog.String("request_id", middleware.GetReqID(ctx)),
+	)
+
+	alias := chi.URLParam(r, "alias")
+	if alias == "" {
+		log.Info("alias is empty")
+
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, shortify.ErrorResponse{
+			Status:  http.StatusBadRequest,
+			Message: "Alias is empty",
+		})
+		return
+	}
+
+	err := c.urls.Delete(ctx, &dto.DeleteURLRequest{
+		Alias: alias,
+	})
+	if err != nil {
+		if errors.Is(err, url.ErrNotFound) {
+			log.Info("URL not found", "alias", alias)
+
+			render.Status(r, http.StatusNotFound)
+			render.JSON(w, r, shortify.ErrorResponse{
+				Status:  http.StatusNotFound,
+				Message: http.StatusText(http.StatusNotFound),
+			})
+			return
+		}
+
+		if errors.Is(err, url.ErrDeleted) {
+			log.Info("URL is deleted", "alias", alias)
+
+			render.Status(r, http.StatusGone)
+			render.JSON(w, r, shortify.ErrorResponse{
+				Status:  http.StatusGone,
+				Message: "URL is deleted",
+			})
+			return
+		}
+
+		log.Error("failed to delete URL", slog.String("error", err.Error()))
+
+		render.Status(r, http.StatusInternalServerError)
+		render.JSON(w, r, shortify.ErrorResponse{
+			Status:  http.StatusInternalServerError,
+			Message: http.StatusText(http.StatusInternalServerError),
+		})
+		return
+	}
+
+	log.Info("URL deleted", slog.String("alias", alias))
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Restore takes the deleted URL out of the trash
+//
+//	@Summary		Restore URL
+//	@Description	Restore takes the deleted URL out of the trash
+//	@Tags			urls
+//	@Produce		json
+//	@Param			alias	path		string	true	"URL alias"
+//	@Success		200		{object}	shortify.GetURLByAliasResponse
+//	@Failure		400		{object}	shortify.ErrorResponse
+//	@Failure		404		{object}	shortify.ErrorResponse
+//	@Failure		409		{object}	shortify.ErrorResponse
+//	@Failure		500		{object}	shortify.ErrorResponse
+//	@Router			/api/v1/urls/{alias}/restore [post]
+func (c *URLController) Restore(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	log := c.log.With(
+		slog.String("handler", "Restore"),
+		slog.String("request_id", middleware.GetReqID(ctx)),
+	)
+
+	alias := chi.URLParam(r, "alias")
+	if alias == "" {
+		log.Info("alias is empty")
+
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, shortify.ErrorResponse{
+			Status:  http.StatusBadRequest,
+			Message: "Alias is empty",
+		})
+		return
+	}
+
+	out, err := c.urls.Restore(ctx, &dto.RestoreURLRequest{
+		Alias: alias,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, url.ErrNotFound):
+			log.Info("URL not found", "alias", alias)
+
+			render.Status(r, http.StatusNotFound)
+			render.JSON(w, r, shortify.ErrorResponse{
+				Status:  http.StatusNotFound,
+				Message: http.StatusText(http.StatusNotFound),
+			})
+		case errors.Is(err, url.ErrNotDeleted):
+			log.Info("URL is not deleted", "alias", alias)
+
+			render.Status(r, http.StatusConflict)
+			render.JSON(w, r, shortify.ErrorResponse{
+				Status:  http.StatusConflict,
+				Message: "URL is not deleted",
+			})
+		default:
+			log.Error("failed to restore URL", slog.String("error", err.Error()))
+
+			render.Status(r, http.StatusInternalServerError)
+			render.JSON(w, r, shortify.ErrorResponse{
+				Status:  http.StatusInternalServerError,
+				Message: http.StatusText(http.StatusInternalServerError),
+			})
+		}
+		return
+	}
+
+	log.Info("URL restored", slog.String("alias", out.Alias))
+
+	render.Status(r, http.StatusOK)
+	render.JSON(w, r, newGetURLByAliasResponse(out))
+}
+
+// Redirect redirects to the URL behind the alias
+// or renders the preview page for preview URLs and aliases ending with PreviewSuffix
+//
+//	@Summary		Redirect to URL
+//	@Description	Redirect redirects to the URL behind the alias. The path following the alias is substituted into templated URLs and the query string is forwarded according to the URL query mode. Preview URLs and aliases followed by "+" render an HTML page showing the destination instead. Retired aliases redirect to the current alias when configured so
+//	@Tags			urls
+//	@Produce		json,html
+//	@Param			alias	path		string	true	"URL alias"
+//	@Success		200
+//	@Success		301
+//	@Success		302
+//	@Failure		400	{object}	shortify.ErrorResponse
+//	@Failure		404	{object}	shortify.ErrorResponse
+//	@Failure		410	{object}	shortify.ErrorResponse
+//	@Failure		500	{object}	shortify.ErrorResponse
+//	@Router			/{alias} [get]
+func (c *URLController) Redirect(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	log := c.log.With(
+		slog.String("handler", "Redirect"),
+		slog.String("request_id", middleware.GetReqID(ctx)),
+	)
+
+	alias := chi.URLParam(r, "alias")
+	if alias == "" {
+		log.Info("alias is empty")
+
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, shortify.ErrorResponse{
+			Status:  http.StatusBadRequest,
+			Message: "Alias is empty",
+		})
+		return
+	}
+
+	path := tailPath(r, alias)
+
+	alias, preview := strings.CutSuffix(alias, PreviewSuffix)
+
+	out, err := c.urls.Resolve(ctx, &dto.ResolveURLRequest{
+		Alias: alias,
+		Path:  path,
+		Query: r.URL.Query(),
+	})
+	if err != nil {
+		if errors.Is(err, url.ErrNotFound) {
+			log.Info("URL not found", "alias", alias)
+
+			render.Status(r, http.StatusNotFound)
+			render.JSON(w, r, shortify.ErrorResponse{
+				Status:  http.StatusNotFound,
+				Message: http.StatusText(http.StatusNotFound),
+			})
+			return
+		}
+
+		if errors.Is(err, url.ErrDeleted) {
+			log.Info("URL is deleted", "alias", alias)
+
+			render.Status(r, http.StatusGone)
+			render.JSON(w, r, shortify.ErrorResponse{
+				Status:  http.StatusGone,
+				Message: "URL is deleted",
+			})
+			return
+		}
+
+		log.Error("failed to resolve URL", slog.String("error", err.Error()))
+
+		render.Status(r, http.StatusInternalServerError)
+		render.JSON(w, r, shortify.ErrorResponse{
+			Status:  http.StatusInternalServerError,
+			Message: http.StatusText(http.StatusInternalServerError),
+		})
+		return
+	}
+
+	if out.Moved {
+		location := movedLocation(r, out.Alias, preview, path)
+
+		log.Info("redirecting to current alias", slog.String("location", location))
+
+		http.Redirect(w, r, location, http.StatusMovedPermanently)
+		return
+	}
+
+	if preview || out.Preview {
+		log.Info("rendering preview", slog.String("location", out.Location))
+
+		err := renderPreview(w, previewData{
+			Alias:     out.Alias,
+			Location:  out.Location,
+			CreatedAt: out.CreatedAt,
+		})
+		if err != nil {
+			log.Error("failed to render preview", slog.String("error", err.Error()))
+		}
+		return
+	}
+
+	log.Info("redirecting", slog.String("location", out.Location))
+
+	http.Redirect(w, r, out.Location, http.StatusFound)
+}
+
+func newURLRecord(out *dto.GetURLByAliasResponse) shortify.URLRecord {
+	return shortify.URLRecord{
+		Alias:       out.Alias,
+		Original:    out.Original,
+		QueryMode:   out.QueryMode,
+		Preview:     out.Preview,
+		Title:       out.Title,
+		Description: out.Description,
+		Tags:        out.Tags,
+		Metadata:    out.Metadata,
+		Clicks:      out.Clicks,
+		CreatedAt:   out.CreatedAt,
+	}
+}
+
+func newGetURLByAliasResponse(out *dto.GetURLByAliasResponse) shortify.GetURLByAliasResponse {
+	resp := shortify.GetURLByAliasResponse{
+		Original:    out.Original,
+		Alias:       out.Alias,
+		QueryMode:   out.QueryMode,
+		Preview:     out.Preview,
+		Title:       out.Title,
+		Description: out.Description,
+		Tags:        out.Tags,
+		Metadata:    out.Metadata,
+		Clicks:      out.Clicks,
+		CreatedAt:   out.CreatedAt,
+	}
+
+	if out.OpenGraph != nil {
+		resp.OpenGraph = &shortify.OpenGraph{
+			Title:       out.OpenGraph.Title,
+			Description: out.OpenGraph.Description,
+			Image:       out.OpenGraph.Image,
+			FetchedAt:   out.OpenGraph.FetchedAt,
+		}
+	}
+
+	return resp
+}
+
+// tailPath returns the escaped path following the alias segment.
+// It is taken from the request URL rather than the route context because
+// the URLFormat middleware strips extensions from the routing path.
+func tailPath(r *http.Request, alias string) string {
+	path := strings.TrimPrefix(r.URL.EscapedPath(), "/")
+	path = strings.TrimPrefix(path, alias)
+
+	return strings.TrimPrefix(path, "/")
+}
+
+// movedLocation is the location of the request under the current alias of the URL.
+func movedLocation(r *http.Request, alias string, preview bool, path string) string {
+	location := "/" + alias
+
+	if preview {
+		location += PreviewSuffix
+	}
+
+	if path != "" {
+		location += "/" + path
+	}
+
+	if r.URL.RawQuery != "" {
+		location += "?" + r.URL.RawQuery
+	}
+
+	return location
 }
@@ -8,9 +8,12 @@ import (
 	"fmt"
 	"io"
 	"log/slog"
+	"mime/multipart"
 	"net/http"
 	"net/http/httptest"
+	neturl "net/url"
 	"testing"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 	httpdel "github.com/kodeyeen/shortify/internal/delivery/http/v1"
@@ -19,6 +22,7 @@ import (
 	"github.com/kodeyeen/shortify/internal/urlmock"
 	"github.com/kodeyeen/shortify/v1"
 
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 )
 
@@ -114,6 +118,23 @@ func TestURLController_Create(t *testing.T) {
 				},
 			},
 		},
+		"Invalid query mode": {
+			Given{
+				reqBody: []byte(`{"original": "https://example.com/longlonglonglonglonglonglonglong", "query_mode": "append"}`),
+
+				svcReq:  nil,
+				svcResp: nil,
+				svcErr:  nil,
+			},
+			Expected{
+				statusCode:  http.StatusBadRequest,
+				successResp: nil,
+				errResp: &shortify.ErrorResponse{
+					Status:  http.StatusBadRequest,
+					Message: "Field 'query_mode' must be one of: ignore merge override",
+				},
+			},
+		},
 		"URL already exists": {
 			Given{
 				reqBody: []byte(`{"original": "https://example.com/longlonglonglonglonglonglonglong"}`),
@@ -133,6 +154,26 @@ func TestURLController_Create(t *testing.T) {
 				},
 			},
 		},
+		"Unknown alias provider": {
+			Given{
+				reqBody: []byte(`{"original": "https://example.com/longlonglonglonglonglonglonglong", "alias_provider": "emoji"}`),
+
+				svcReq: &dto.CreateURLRequest{
+					Original:      "https://example.com/longlonglonglonglonglonglonglong",
+					AliasProvider: "emoji",
+				},
+				svcResp: nil,
+				svcErr:  url.ErrUnknownAliasProvider,
+			},
+			Expected{
+				statusCode:  http.StatusBadRequest,
+				successResp: nil,
+				errResp: &shortify.ErrorResponse{
+					Status:  http.StatusBadRequest,
+					Message: "Unknown alias provider",
+				},
+			},
+		},
 		"Other": {
 			Given{
 				reqBody: []byte(`{"original": "https://example.com/longlonglonglonglonglonglonglong"}`),
@@ -353,3 +394,1858 @@ func TestURLController_GetByAlias(t *testing.T) {
 		})
 	}
 }
+
+func TestURLController_Redirect(t *testing.T) {
+	type Given struct {
+		alias  string
+		target string
+
+		svcReq  *dto.ResolveURLRequest
+		svcResp *dto.ResolveURLResponse
+		svcErr  error
+	}
+
+	type Expected struct {
+		statusCode int
+		location   string
+		page       []string
+		errResp    *shortify.ErrorResponse
+	}
+
+	testCases := map[string]struct {
+		given    Given
+		expected Expected
+	}{
+		"Success": {
+			Given{
+				alias:  "fjsido39jf",
+				target: "/fjsido39jf",
+
+				svcReq: &dto.ResolveURLRequest{
+					Alias: "fjsido39jf",
+					Path:  "",
+					Query: neturl.Values{},
+				},
+				svcResp: &dto.ResolveURLResponse{
+					Location: "https://example.com/longlonglonglonglonglonglonglong",
+				},
+				svcErr: nil,
+			},
+			Expected{
+				statusCode: http.StatusFound,
+				location:   "https://example.com/longlonglonglonglonglonglonglong",
+				errResp:    nil,
+			},
+		},
+		"Path and query": {
+			Given{
+				alias:  "fjsido39jf",
+				target: "/fjsido39jf/products/page.html?ref=twitter",
+
+				svcReq: &dto.ResolveURLRequest{
+					Alias: "fjsido39jf",
+					Path:  "products/page.html",
+					Query: neturl.Values{"ref": {"twitter"}},
+				},
+				svcResp: &dto.ResolveURLResponse{
+					Location: "https://example.com/p/products/page.html?ref=twitter",
+				},
+				svcErr: nil,
+			},
+			Expected{
+				statusCode: http.StatusFound,
+				location:   "https://example.com/p/products/page.html?ref=twitter",
+				errResp:    nil,
+			},
+		},
+		"Preview suffix": {
+			Given{
+				alias:  "fjsido39jf+",
+				target: "/fjsido39jf+",
+
+				svcReq: &dto.ResolveURLRequest{
+					Alias: "fjsido39jf",
+					Path:  "",
+					Query: neturl.Values{},
+				},
+				svcResp: &dto.ResolveURLResponse{
+					Location:  "https://example.com/long?a=1&b=2",
+					Alias:     "fjsido39jf",
+					CreatedAt: time.Date(2025, time.March, 1, 12, 0, 0, 0, time.UTC),
+				},
+				svcErr: nil,
+			},
+			Expected{
+				statusCode: http.StatusOK,
+				page: []string{
+					`<p class="host">example.com</p>`,
+					`<p class="url">https://example.com/long?a=1&amp;b=2</p>`,
+					`href="https://example.com/long?a=1&amp;b=2"`,
+					`Created March 1, 2025 12:00 UTC`,
+				},
+				errResp: nil,
+			},
+		},
+		"Preview URL": {
+			Given{
+				alias:  "fjsido39jf",
+				target: "/fjsido39jf",
+
+				svcReq: &dto.ResolveURLRequest{
+					Alias: "fjsido39jf",
+					Path:  "",
+					Query: neturl.Values{},
+				},
+				svcResp: &dto.ResolveURLResponse{
+					Location:  "https://example.com/long",
+					Alias:     "fjsido39jf",
+					Preview:   true,
+					CreatedAt: time.Date(2025, time.March, 1, 12, 0, 0, 0, time.UTC),
+				},
+				svcErr: nil,
+			},
+			Expected{
+				statusCode: http.StatusOK,
+				page: []string{
+					`<p class="host">example.com</p>`,
+					`href="https://example.com/long"`,
+				},
+				errResp: nil,
+			},
+		},
+		"Retired alias": {
+			Given{
+				alias:  "fjsido39jf+",
+				target: "/fjsido39jf+/products/42?ref=twitter",
+
+				svcReq: &dto.ResolveURLRequest{
+					Alias: "fjsido39jf",
+					Path:  "products/42",
+					Query: neturl.Values{"ref": {"twitter"}},
+				},
+				svcResp: &dto.ResolveURLResponse{
+					Alias: "spring-sale",
+					Moved: true,
+				},
+				svcErr: nil,
+			},
+			Expected{
+				statusCode: http.StatusMovedPermanently,
+				location:   "/spring-sale+/products/42?ref=twitter",
+				errResp:    nil,
+			},
+		},
+		"Empty alias": {
+			Given{
+				alias:  "",
+				target: "/",
+
+				svcReq:  nil,
+				svcResp: nil,
+				svcErr:  nil,
+			},
+			Expected{
+				statusCode: http.StatusBadRequest,
+				errResp: &shortify.ErrorResponse{
+					Status:  http.StatusBadRequest,
+					Message: "Alias is empty",
+				},
+			},
+		},
+		"Not found": {
+			Given{
+				alias:  "fjsido39jf",
+				target: "/fjsido39jf",
+
+				svcReq: &dto.ResolveURLRequest{
+					Alias: "fjsido39jf",
+					Path:  "",
+					Query: neturl.Values{},
+				},
+				svcResp: nil,
+				svcErr:  url.ErrNotFound,
+			},
+			Expected{
+				statusCode: http.StatusNotFound,
+				errResp: &shortify.ErrorResponse{
+					Status:  http.StatusNotFound,
+					Message: http.StatusText(http.StatusNotFound),
+				},
+			},
+		},
+		"Other": {
+			Given{
+				alias:  "fjsido39jf",
+				target: "/fjsido39jf",
+
+				svcReq: &dto.ResolveURLRequest{
+					Alias: "fjsido39jf",
+					Path:  "",
+					Query: neturl.Values{},
+				},
+				svcResp: nil,
+				svcErr:  errors.New("svc error"),
+			},
+			Expected{
+				statusCode: http.StatusInternalServerError,
+				errResp: &shortify.ErrorResponse{
+					Status:  http.StatusInternalServerError,
+					Message: http.StatusText(http.StatusInternalServerError),
+				},
+			},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			// Given
+			rr := httptest.NewRecorder()
+
+			req, err := http.NewRequest(http.MethodGet, tc.given.target, nil)
+			require.NoError(t, err)
+
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("alias", tc.given.alias)
+
+			ctx := context.WithValue(req.Context(), chi.RouteCtxKey, rctx)
+			req = req.WithContext(ctx)
+
+			svc := urlmock.NewService(t)
+
+			if tc.given.svcResp != nil || tc.given.svcErr != nil {
+				svc.On("Resolve", ctx, tc.given.svcReq).
+					Return(tc.given.svcResp, tc.given.svcErr).
+					Once()
+			}
+
+			log := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+			clr := httpdel.NewURLController(svc, log)
+
+			// When
+			clr.Redirect(rr, req)
+
+			// Then
+			require.Equal(t, tc.expected.statusCode, rr.Code)
+
+			if tc.expected.errResp != nil {
+				var resp shortify.ErrorResponse
+
+				err = json.NewDecoder(rr.Body).Decode(&resp)
+				require.NoError(t, err)
+
+				require.Equal(t, tc.expected.errResp, &resp)
+			} else if tc.expected.page != nil {
+				require.Equal(t, "text/html; charset=utf-8", rr.Header().Get("Content-Type"))
+
+				for _, fragment := range tc.expected.page {
+					require.Contains(t, rr.Body.String(), fragment)
+				}
+			} else {
+				require.Equal(t, tc.expected.location, rr.Header().Get("Location"))
+			}
+		})
+	}
+}
+
+func TestURLController_List(t *testing.T) {
+	type Given struct {
+		target string
+
+		svcReq  *dto.ListURLsRequest
+		svcResp *dto.ListURLsResponse
+		svcErr  error
+	}
+
+	type Expected struct {
+		statusCode  int
+		successResp *shortify.ListURLsResponse
+		errResp     *shortify.ErrorResponse
+	}
+
+	testCases := map[string]struct {
+		given    Given
+		expected Expected
+	}{
+		"Success": {
+			Given{
+				target: "/api/v1/urls?tag=podcast&meta.campaign=spring&limit=10&offset=20",
+
+				svcReq: &dto.ListURLsRequest{
+					Tag:      "podcast",
+					Metadata: map[string]string{"campaign": "spring"},
+					Limit:    10,
+					Offset:   20,
+				},
+				svcResp: &dto.ListURLsResponse{
+					URLs: []*dto.GetURLByAliasResponse{
+						{
+							Original: "https://example.com/longlonglonglonglonglonglonglong",
+							Alias:    "fjsido39jf",
+							Tags:     []string{"podcast"},
+							Metadata: map[string]string{"campaign": "spring"},
+						},
+					},
+				},
+				svcErr: nil,
+			},
+			Expected{
+				statusCode: http.StatusOK,
+				successResp: &shortify.ListURLsResponse{
+					URLs: []shortify.GetURLByAliasResponse{
+						{
+							Original: "https://example.com/longlonglonglonglonglonglonglong",
+							Alias:    "fjsido39jf",
+							Tags:     []string{"podcast"},
+							Metadata: map[string]string{"campaign": "spring"},
+						},
+					},
+				},
+				errResp: nil,
+			},
+		},
+		"Invalid limit": {
+			Given{
+				target: "/api/v1/urls?limit=many",
+
+				svcReq:  nil,
+				svcResp: nil,
+				svcErr:  nil,
+			},
+			Expected{
+				statusCode:  http.StatusBadRequest,
+				successResp: nil,
+				errResp: &shortify.ErrorResponse{
+					Status:  http.StatusBadRequest,
+					Message: "Parameter 'limit' must be an integer between 0 and 1000",
+				},
+			},
+		},
+		"Invalid offset": {
+			Given{
+				target: "/api/v1/urls?offset=-1",
+
+				svcReq:  nil,
+				svcResp: nil,
+				svcErr:  nil,
+			},
+			Expected{
+				statusCode:  http.StatusBadRequest,
+				successResp: nil,
+				errResp: &shortify.ErrorResponse{
+					Status:  http.StatusBadRequest,
+					Message: "Parameter 'offset' must be a non-negative integer",
+				},
+			},
+		},
+		"Other": {
+			Given{
+				target: "/api/v1/urls",
+
+				svcReq:  &dto.ListURLsRequest{},
+				svcResp: nil,
+				svcErr:  errors.New("svc error"),
+			},
+			Expected{
+				statusCode:  http.StatusInternalServerError,
+				successResp: nil,
+				errResp: &shortify.ErrorResponse{
+					Status:  http.StatusInternalServerError,
+					Message: http.StatusText(http.StatusInternalServerError),
+				},
+			},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			// Given
+			rr := httptest.NewRecorder()
+
+			req, err := http.NewRequest(http.MethodGet, tc.given.target, nil)
+			require.NoError(t, err)
+
+			ctx := req.Context()
+
+			svc := urlmock.NewService(t)
+
+			if tc.given.svcResp != nil || tc.given.svcErr != nil {
+				svc.On("List", ctx, tc.given.svcReq).
+					Return(tc.given.svcResp, tc.given.svcErr).
+					Once()
+			}
+
+			log := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+			clr := httpdel.NewURLController(svc, log)
+
+			// When
+			clr.List(rr, req)
+
+			// Then
+			require.Equal(t, tc.expected.statusCode, rr.Code)
+
+			if tc.expected.errResp != nil {
+				var resp shortify.ErrorResponse
+
+				err = json.NewDecoder(rr.Body).Decode(&resp)
+				require.NoError(t, err)
+
+				require.Equal(t, tc.expected.errResp, &resp)
+			} else {
+				var resp shortify.ListURLsResponse
+
+				err = json.NewDecoder(rr.Body).Decode(&resp)
+				require.NoError(t, err)
+
+				require.Equal(t, tc.expected.successResp, &resp)
+			}
+		})
+	}
+}
+
+func TestURLController_Update(t *testing.T) {
+	type Given struct {
+		alias   string
+		reqBody []byte
+
+		svcReq  *dto.UpdateURLRequest
+		svcResp *dto.GetURLByAliasResponse
+		svcErr  error
+	}
+
+	type Expected struct {
+		statusCode  int
+		successResp *shortify.GetURLByAliasResponse
+		errResp     *shortify.ErrorResponse
+	}
+
+	title := "Episode 42"
+	tags := []string{"podcast"}
+	original := "https://example.com/taken"
+	newAlias := "spring-sale"
+
+	testCases := map[string]struct {
+		given    Given
+		expected Expected
+	}{
+		"Success": {
+			Given{
+				alias:   "fjsido39jf",
+				reqBody: []byte(`{"title": "Episode 42", "tags": ["podcast"]}`),
+
+				svcReq: &dto.UpdateURLRequest{
+					Alias: "fjsido39jf",
+					Title: &title,
+					Tags:  &tags,
+				},
+				svcResp: &dto.GetURLByAliasResponse{
+					Original: "https://example.com/longlonglonglonglonglonglonglong",
+					Alias:    "fjsido39jf",
+					Title:    "Episode 42",
+					Tags:     []string{"podcast"},
+				},
+				svcErr: nil,
+			},
+			Expected{
+				statusCode: http.StatusOK,
+				successResp: &shortify.GetURLByAliasResponse{
+					Original: "https://example.com/longlonglonglonglonglonglonglong",
+					Alias:    "fjsido39jf",
+					Title:    "Episode 42",
+					Tags:     []string{"podcast"},
+				},
+				errResp: nil,
+			},
+		},
+		"Invalid request body": {
+			Given{
+				alias:   "fjsido39jf",
+				reqBody: []byte(`not json`),
+
+				svcReq:  nil,
+				svcResp: nil,
+				svcErr:  nil,
+			},
+			Expected{
+				statusCode:  http.StatusBadRequest,
+				successResp: nil,
+				errResp: &shortify.ErrorResponse{
+					Status:  http.StatusBadRequest,
+					Message: "Invalid request body",
+				},
+			},
+		},
+		"Invalid Original": {
+			Given{
+				alias:   "fjsido39jf",
+				reqBody: []byte(`{"original": "invalidurl"}`),
+
+				svcReq:  nil,
+				svcResp: nil,
+				svcErr:  nil,
+			},
+			Expected{
+				statusCode:  http.StatusBadRequest,
+				successResp: nil,
+				errResp: &shortify.ErrorResponse{
+					Status:  http.StatusBadRequest,
+					Message: "Field 'original' is not a valid URL",
+				},
+			},
+		},
+		"Not found": {
+			Given{
+				alias:   "fjsido39jf",
+				reqBody: []byte(`{"title": "Episode 42"}`),
+
+				svcReq: &dto.UpdateURLRequest{
+					Alias: "fjsido39jf",
+					Title: &title,
+				},
+				svcResp: nil,
+				svcErr:  url.ErrNotFound,
+			},
+			Expected{
+				statusCode:  http.StatusNotFound,
+				successResp: nil,
+				errResp: &shortify.ErrorResponse{
+					Status:  http.StatusNotFound,
+					Message: http.StatusText(http.StatusNotFound),
+				},
+			},
+		},
+		"Alias changed": {
+			Given{
+				alias:   "fjsido39jf",
+				reqBody: []byte(`{"alias": "spring-sale"}`),
+
+				svcReq: &dto.UpdateURLRequest{
+					Alias:    "fjsido39jf",
+					NewAlias: &newAlias,
+				},
+				svcResp: &dto.GetURLByAliasResponse{
+					Original: "https://example.com/longlonglonglonglonglonglonglong",
+					Alias:    "spring-sale",
+				},
+				svcErr: nil,
+			},
+			Expected{
+				statusCode: http.StatusOK,
+				successResp: &shortify.GetURLByAliasResponse{
+					Original: "https://example.com/longlonglonglonglonglonglonglong",
+					Alias:    "spring-sale",
+				},
+				errResp: nil,
+			},
+		},
+		"Invalid alias": {
+			Given{
+				alias:   "fjsido39jf",
+				reqBody: []byte(`{"alias": "spring/sale"}`),
+
+				svcReq:  nil,
+				svcResp: nil,
+				svcErr:  nil,
+			},
+			Expected{
+				statusCode:  http.StatusBadRequest,
+				successResp: nil,
+				errResp: &shortify.ErrorResponse{
+					Status:  http.StatusBadRequest,
+					Message: "Field 'alias' may only contain letters, digits, '-' and '_'",
+				},
+			},
+		},
+		"Alias quarantined": {
+			Given{
+				alias:   "fjsido39jf",
+				reqBody: []byte(`{"alias": "spring-sale"}`),
+
+				svcReq: &dto.UpdateURLRequest{
+					Alias:    "fjsido39jf",
+					NewAlias: &newAlias,
+				},
+				svcResp: nil,
+				svcErr:  url.ErrAliasQuarantined,
+			},
+			Expected{
+				statusCode:  http.StatusConflict,
+				successResp: nil,
+				errResp: &shortify.ErrorResponse{
+					Status:  http.StatusConflict,
+					Message: "Alias is not available",
+				},
+			},
+		},
+		"URL already exists": {
+			Given{
+				alias:   "fjsido39jf",
+				reqBody: []byte(`{"original": "https://example.com/taken"}`),
+
+				svcReq: &dto.UpdateURLRequest{
+					Alias:    "fjsido39jf",
+					Original: &original,
+				},
+				svcResp: nil,
+				svcErr:  url.ErrAlreadyExists,
+			},
+			Expected{
+				statusCode:  http.StatusConflict,
+				successResp: nil,
+				errResp: &shortify.ErrorResponse{
+					Status:  http.StatusConflict,
+					Message: "URL already exists",
+				},
+			},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			// Given
+			rr := httptest.NewRecorder()
+
+			req, err := http.NewRequest(http.MethodPatch, fmt.Sprintf("/api/v1/urls/%s", tc.given.alias), bytes.NewReader(tc.given.reqBody))
+			require.NoError(t, err)
+
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("alias", tc.given.alias)
+
+			ctx := context.WithValue(req.Context(), chi.RouteCtxKey, rctx)
+			req = req.WithContext(ctx)
+
+			svc := urlmock.NewService(t)
+
+			if tc.given.svcResp != nil || tc.given.svcErr != nil {
+				svc.On("Update", ctx, tc.given.svcReq).
+					Return(tc.given.svcResp, tc.given.svcErr).
+					Once()
+			}
+
+			log := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+			clr := httpdel.NewURLController(svc, log)
+
+			// When
+			clr.Update(rr, req)
+
+			// Then
+			require.Equal(t, tc.expected.statusCode, rr.Code)
+
+			if tc.expected.errResp != nil {
+				var resp shortify.ErrorResponse
+
+				err = json.NewDecoder(rr.Body).Decode(&resp)
+				require.NoError(t, err)
+
+				require.Equal(t, tc.expected.errResp, &resp)
+			} else {
+				var resp shortify.GetURLByAliasResponse
+
+				err = json.NewDecoder(rr.Body).Decode(&resp)
+				require.NoError(t, err)
+
+				require.Equal(t, tc.expected.successResp, &resp)
+			}
+		})
+	}
+}
+
+func TestURLController_Delete(t *testing.T) {
+	type Given struct {
+		alias string
+
+		svcReq *dto.DeleteURLRequest
+		svcErr error
+		called bool
+	}
+
+	type Expected struct {
+		statusCode int
+		errResp    *shortify.ErrorResponse
+	}
+
+	testCases := map[string]struct {
+		given    Given
+		expected Expected
+	}{
+		"Success": {
+			Given{
+				alias: "fjsido39jf",
+
+				svcReq: &dto.DeleteURLRequest{
+					Alias: "fjsido39jf",
+				},
+				svcErr: nil,
+				called: true,
+			},
+			Expected{
+				statusCode: http.StatusNoContent,
+				errResp:    nil,
+			},
+		},
+		"Empty alias": {
+			Given{
+				alias: "",
+
+				svcReq: nil,
+				svcErr: nil,
+				called: false,
+			},
+			Expected{
+				statusCode: http.StatusBadRequest,
+				errResp: &shortify.ErrorResponse{
+					Status:  http.StatusBadRequest,
+					Message: "Alias is empty",
+				},
+			},
+		},
+		"Not found": {
+			Given{
+				alias: "fjsido39jf",
+
+				svcReq: &dto.DeleteURLRequest{
+					Alias: "fjsido39jf",
+				},
+				svcErr: url.ErrNotFound,
+				called: true,
+			},
+			Expected{
+				statusCode: http.StatusNotFound,
+				errResp: &shortify.ErrorResponse{
+					Status:  http.StatusNotFound,
+					Message: http.StatusText(http.StatusNotFound),
+				},
+			},
+		},
+		"Already deleted": {
+			Given{
+				alias: "fjsido39jf",
+
+				svcReq: &dto.DeleteURLRequest{
+					Alias: "fjsido39jf",
+				},
+				svcErr: url.ErrDeleted,
+				called: true,
+			},
+			Expected{
+				statusCode: http.StatusGone,
+				errResp: &shortify.ErrorResponse{
+					Status:  http.StatusGone,
+					Message: "URL is deleted",
+				},
+			},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			// Given
+			rr := httptest.NewRecorder()
+
+			req, err := http.NewRequest(http.MethodDelete, fmt.Sprintf("/api/v1/urls/%s", tc.given.alias), nil)
+			require.NoError(t, err)
+
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("alias", tc.given.alias)
+
+			ctx := context.WithValue(req.Context(), chi.RouteCtxKey, rctx)
+			req = req.WithContext(ctx)
+
+			svc := urlmock.NewService(t)
+
+			if tc.given.called {
+				svc.On("Delete", ctx, tc.given.svcReq).
+					Return(tc.given.svcErr).
+					Once()
+			}
+
+			log := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+			clr := httpdel.NewURLController(svc, log)
+
+			// When
+			clr.Delete(rr, req)
+
+			// Then
+			require.Equal(t, tc.expected.statusCode, rr.Code)
+
+			if tc.expected.errResp != nil {
+				var resp shortify.ErrorResponse
+
+				err = json.NewDecoder(rr.Body).Decode(&resp)
+				require.NoError(t, err)
+
+				require.Equal(t, tc.expected.errResp, &resp)
+			}
+		})
+	}
+}
+
+func TestURLController_Capacity(t *testing.T) {
+	type Given struct {
+		svcResp *dto.CapacityResponse
+		svcErr  error
+	}
+
+	type Expected struct {
+		statusCode  int
+		successResp *shortify.AliasCapacityResponse
+		errResp     *shortify.ErrorResponse
+	}
+
+	testCases := map[string]struct {
+		given    Given
+		expected Expected
+	}{
+		"Success": {
+			Given{
+				svcResp: &dto.CapacityResponse{
+					Provider:             "random",
+					KeyspaceSize:         "1000",
+					KeyspaceBits:         9.965784284662087,
+					Occupied:             500,
+					Occupancy:            0.5,
+					CollisionProbability: 0.5,
+					FailureProbability:   0.0009765625,
+				},
+			},
+			Expected{
+				statusCode: http.StatusOK,
+				successResp: &shortify.AliasCapacityResponse{
+					Provider:             "random",
+					KeyspaceSize:         "1000",
+					KeyspaceBits:         9.965784284662087,
+					Occupied:             500,
+					Occupancy:            0.5,
+					CollisionProbability: 0.5,
+					FailureProbability:   0.0009765625,
+				},
+			},
+		},
+		"Other": {
+			Given{
+				svcErr: errors.New("svc error"),
+			},
+			Expected{
+				statusCode: http.StatusInternalServerError,
+				errResp: &shortify.ErrorResponse{
+					Status:  http.StatusInternalServerError,
+					Message: http.StatusText(http.StatusInternalServerError),
+				},
+			},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			// Given
+			rr := httptest.NewRecorder()
+
+			req, err := http.NewRequest(http.MethodGet, "/api/v1/aliases/capacity", nil)
+			require.NoError(t, err)
+
+			ctx := req.Context()
+
+			svc := urlmock.NewService(t)
+			svc.On("Capacity", ctx).
+				Return(tc.given.svcResp, tc.given.svcErr).
+				Once()
+
+			log := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+			clr := httpdel.NewURLController(svc, log)
+
+			// When
+			clr.Capacity(rr, req)
+
+			// Then
+			require.Equal(t, tc.expected.statusCode, rr.Code)
+
+			if tc.expected.successResp != nil {
+				var resp shortify.AliasCapacityResponse
+
+				err = json.NewDecoder(rr.Body).Decode(&resp)
+				require.NoError(t, err)
+
+				require.Equal(t, tc.expected.successResp, &resp)
+			}
+
+			if tc.expected.errResp != nil {
+				var resp shortify.ErrorResponse
+
+				err = json.NewDecoder(rr.Body).Decode(&resp)
+				require.NoError(t, err)
+
+				require.Equal(t, tc.expected.errResp, &resp)
+			}
+		})
+	}
+}
+
+func TestURLController_ListAliases(t *testing.T) {
+	type Given struct {
+		alias string
+
+		svcResp *dto.ListAliasesResponse
+		svcErr  error
+	}
+
+	type Expected struct {
+		statusCode  int
+		successResp *shortify.AliasHistoryResponse
+		errResp     *shortify.ErrorResponse
+	}
+
+	retiredAt := time.Date(2025, time.March, 1, 12, 0, 0, 0, time.UTC)
+
+	testCases := map[string]struct {
+		given    Given
+		expected Expected
+	}{
+		"Success": {
+			Given{
+				alias: "fjsido39jf",
+
+				svcResp: &dto.ListAliasesResponse{
+					Alias: "spring-sale",
+					Retired: []*dto.RetiredAliasResponse{
+						{Alias: "fjsido39jf", RetiredAt: retiredAt, QuarantinedUntil: retiredAt.Add(720 * time.Hour)},
+					},
+				},
+			},
+			Expected{
+				statusCode: http.StatusOK,
+				successResp: &shortify.AliasHistoryResponse{
+					Alias: "spring-sale",
+					Retired: []shortify.RetiredAlias{
+						{Alias: "fjsido39jf", RetiredAt: retiredAt, QuarantinedUntil: retiredAt.Add(720 * time.Hour)},
+					},
+				},
+			},
+		},
+		"Not found": {
+			Given{
+				alias:  "fjsido39jf",
+				svcErr: url.ErrNotFound,
+			},
+			Expected{
+				statusCode: http.StatusNotFound,
+				errResp: &shortify.ErrorResponse{
+					Status:  http.StatusNotFound,
+					Message: http.StatusText(http.StatusNotFound),
+				},
+			},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			// Given
+			rr := httptest.NewRecorder()
+
+			req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("/api/v1/urls/%s/aliases", tc.given.alias), nil)
+			require.NoError(t, err)
+
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("alias", tc.given.alias)
+
+			ctx := context.WithValue(req.Context(), chi.RouteCtxKey, rctx)
+			req = req.WithContext(ctx)
+
+			svc := urlmock.NewService(t)
+			svc.On("ListAliases", ctx, &dto.ListAliasesRequest{Alias: tc.given.alias}).
+				Return(tc.given.svcResp, tc.given.svcErr).
+				Once()
+
+			log := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+			clr := httpdel.NewURLController(svc, log)
+
+			// When
+			clr.ListAliases(rr, req)
+
+			// Then
+			require.Equal(t, tc.expected.statusCode, rr.Code)
+
+			if tc.expected.successResp != nil {
+				var resp shortify.AliasHistoryResponse
+
+				err = json.NewDecoder(rr.Body).Decode(&resp)
+				require.NoError(t, err)
+
+				require.Equal(t, tc.expected.successResp, &resp)
+			}
+
+			if tc.expected.errResp != nil {
+				var resp shortify.ErrorResponse
+
+				err = json.NewDecoder(rr.Body).Decode(&resp)
+				require.NoError(t, err)
+
+				require.Equal(t, tc.expected.errResp, &resp)
+			}
+		})
+	}
+}
+
+func TestURLController_History(t *testing.T) {
+	// Given
+	createdAt := time.Date(2025, time.March, 1, 12, 0, 0, 0, time.UTC)
+
+	rr := httptest.NewRecorder()
+
+	req, err := http.NewRequest(http.MethodGet, "/api/v1/urls/fjsido39jf/history", nil)
+	require.NoError(t, err)
+
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("alias", "fjsido39jf")
+
+	ctx := context.WithValue(req.Context(), chi.RouteCtxKey, rctx)
+	req = req.WithContext(ctx)
+
+	svc := urlmock.NewService(t)
+	svc.On("History", ctx, &dto.URLHistoryRequest{Alias: "fjsido39jf"}).
+		Return(&dto.URLHistoryResponse{
+			Alias: "fjsido39jf",
+			Versions: []*dto.URLVersionResponse{
+				{
+					Version:   2,
+					Action:    "updated",
+					Actor:     "alice",
+					RequestID: "req-2",
+					CreatedAt: createdAt,
+					Changes: []*dto.FieldChange{
+						{Field: "original", Before: "https://example.com/old", After: "https://example.com/new"},
+					},
+				},
+			},
+		}, nil).
+		Once()
+
+	log := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	clr := httpdel.NewURLController(svc, log)
+
+	// When
+	clr.History(rr, req)
+
+	// Then
+	require.Equal(t, http.StatusOK, rr.Code)
+
+	var resp shortify.URLHistoryResponse
+
+	err = json.NewDecoder(rr.Body).Decode(&resp)
+	require.NoError(t, err)
+
+	require.Equal(t, shortify.URLHistoryResponse{
+		Alias: "fjsido39jf",
+		Versions: []shortify.URLVersion{
+			{
+				Version:   2,
+				Action:    "updated",
+				Actor:     "alice",
+				RequestID: "req-2",
+				CreatedAt: createdAt,
+				Changes: []shortify.FieldChange{
+					{Field: "original", Before: "https://example.com/old", After: "https://example.com/new"},
+				},
+			},
+		},
+	}, resp)
+}
+
+func TestURLController_Rollback(t *testing.T) {
+	type Given struct {
+		reqBody []byte
+
+		svcReq  *dto.RollbackURLRequest
+		svcResp *dto.GetURLByAliasResponse
+		svcErr  error
+	}
+
+	type Expected struct {
+		statusCode  int
+		successResp *shortify.GetURLByAliasResponse
+		errResp     *shortify.ErrorResponse
+	}
+
+	testCases := map[string]struct {
+		given    Given
+		expected Expected
+	}{
+		"Success": {
+			Given{
+				reqBody: []byte(`{"version": 1}`),
+
+				svcReq: &dto.RollbackURLRequest{Alias: "fjsido39jf", Version: 1},
+				svcResp: &dto.GetURLByAliasResponse{
+					Original: "https://example.com/old",
+					Alias:    "fjsido39jf",
+				},
+			},
+			Expected{
+				statusCode: http.StatusOK,
+				successResp: &shortify.GetURLByAliasResponse{
+					Original: "https://example.com/old",
+					Alias:    "fjsido39jf",
+				},
+			},
+		},
+		"Missing version": {
+			Given{
+				reqBody: []byte(`{}`),
+			},
+			Expected{
+				statusCode: http.StatusBadRequest,
+				errResp: &shortify.ErrorResponse{
+					Status:  http.StatusBadRequest,
+					Message: "Field 'version' is missing",
+				},
+			},
+		},
+		"Version not found": {
+			Given{
+				reqBody: []byte(`{"version": 7}`),
+
+				svcReq: &dto.RollbackURLRequest{Alias: "fjsido39jf", Version: 7},
+				svcErr: url.ErrVersionNotFound,
+			},
+			Expected{
+				statusCode: http.StatusNotFound,
+				errResp: &shortify.ErrorResponse{
+					Status:  http.StatusNotFound,
+					Message: "Version not found",
+				},
+			},
+		},
+		"Conflict": {
+			Given{
+				reqBody: []byte(`{"version": 1}`),
+
+				svcReq: &dto.RollbackURLRequest{Alias: "fjsido39jf", Version: 1},
+				svcErr: url.ErrAliasTaken,
+			},
+			Expected{
+				statusCode: http.StatusConflict,
+				errResp: &shortify.ErrorResponse{
+					Status:  http.StatusConflict,
+					Message: "Version conflicts with another URL",
+				},
+			},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			// Given
+			rr := httptest.NewRecorder()
+
+			req, err := http.NewRequest(http.MethodPost, "/api/v1/urls/fjsido39jf/rollback", bytes.NewReader(tc.given.reqBody))
+			require.NoError(t, err)
+
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("alias", "fjsido39jf")
+
+			ctx := context.WithValue(req.Context(), chi.RouteCtxKey, rctx)
+			req = req.WithContext(ctx)
+
+			svc := urlmock.NewService(t)
+
+			if tc.given.svcReq != nil {
+				svc.On("Rollback", ctx, tc.given.svcReq).
+					Return(tc.given.svcResp, tc.given.svcErr).
+					Once()
+			}
+
+			log := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+			clr := httpdel.NewURLController(svc, log)
+
+			// When
+			clr.Rollback(rr, req)
+
+			// Then
+			require.Equal(t, tc.expected.statusCode, rr.Code)
+
+			if tc.expected.successResp != nil {
+				var resp shortify.GetURLByAliasResponse
+
+				err = json.NewDecoder(rr.Body).Decode(&resp)
+				require.NoError(t, err)
+
+				require.Equal(t, tc.expected.successResp, &resp)
+			}
+
+			if tc.expected.errResp != nil {
+				var resp shortify.ErrorResponse
+
+				err = json.NewDecoder(rr.Body).Decode(&resp)
+				require.NoError(t, err)
+
+				require.Equal(t, tc.expected.errResp, &resp)
+			}
+		})
+	}
+}
+
+func TestURLController_Restore(t *testing.T) {
+	type Given struct {
+		svcResp *dto.GetURLByAliasResponse
+		svcErr  error
+	}
+
+	type Expected struct {
+		statusCode  int
+		successResp *shortify.GetURLByAliasResponse
+		errResp     *shortify.ErrorResponse
+	}
+
+	testCases := map[string]struct {
+		given    Given
+		expected Expected
+	}{
+		"Success": {
+			Given{
+				svcResp: &dto.GetURLByAliasResponse{
+					Original: "https://example.com/long",
+					Alias:    "fjsido39jf",
+				},
+			},
+			Expected{
+				statusCode: http.StatusOK,
+				successResp: &shortify.GetURLByAliasResponse{
+					Original: "https://example.com/long",
+					Alias:    "fjsido39jf",
+				},
+			},
+		},
+		"Not found": {
+			Given{
+				svcErr: url.ErrNotFound,
+			},
+			Expected{
+				statusCode: http.StatusNotFound,
+				errResp: &shortify.ErrorResponse{
+					Status:  http.StatusNotFound,
+					Message: http.StatusText(http.StatusNotFound),
+				},
+			},
+		},
+		"Not deleted": {
+			Given{
+				svcErr: url.ErrNotDeleted,
+			},
+			Expected{
+				statusCode: http.StatusConflict,
+				errResp: &shortify.ErrorResponse{
+					Status:  http.StatusConflict,
+					Message: "URL is not deleted",
+				},
+			},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			// Given
+			rr := httptest.NewRecorder()
+
+			req, err := http.NewRequest(http.MethodPost, "/api/v1/urls/fjsido39jf/restore", nil)
+			require.NoError(t, err)
+
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("alias", "fjsido39jf")
+
+			ctx := context.WithValue(req.Context(), chi.RouteCtxKey, rctx)
+			req = req.WithContext(ctx)
+
+			svc := urlmock.NewService(t)
+			svc.On("Restore", ctx, &dto.RestoreURLRequest{Alias: "fjsido39jf"}).
+				Return(tc.given.svcResp, tc.given.svcErr).
+				Once()
+
+			log := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+			clr := httpdel.NewURLController(svc, log)
+
+			// When
+			clr.Restore(rr, req)
+
+			// Then
+			require.Equal(t, tc.expected.statusCode, rr.Code)
+
+			if tc.expected.successResp != nil {
+				var resp shortify.GetURLByAliasResponse
+
+				err = json.NewDecoder(rr.Body).Decode(&resp)
+				require.NoError(t, err)
+
+				require.Equal(t, tc.expected.successResp, &resp)
+			}
+
+			if tc.expected.errResp != nil {
+				var resp shortify.ErrorResponse
+
+				err = json.NewDecoder(rr.Body).Decode(&resp)
+				require.NoError(t, err)
+
+				require.Equal(t, tc.expected.errResp, &resp)
+			}
+		})
+	}
+}
+
+func TestURLController_Export(t *testing.T) {
+	createdAt := time.Date(2025, time.March, 1, 12, 0, 0, 0, time.UTC)
+
+	urls := []*dto.GetURLByAliasResponse{
+		{
+			Original:  "https://example.com/1?a=1&b=2",
+			Alias:     "promo",
+			QueryMode: "merge",
+			Tags:      []string{"spring", "sale"},
+			Metadata:  map[string]string{"team": "a", "env": "prod"},
+			Clicks:    3,
+			CreatedAt: createdAt,
+		},
+		{
+			Original:  "https://example.com/2",
+			Alias:     "fjsido39jf",
+			QueryMode: "ignore",
+			Title:     "Title, with a comma",
+			CreatedAt: createdAt,
+		},
+	}
+
+	type Given struct {
+		query string
+
+		urls   []*dto.GetURLByAliasResponse
+		svcErr error
+	}
+
+	type Expected struct {
+		statusCode  int
+		contentType string
+		body        string
+		errResp     *shortify.ErrorResponse
+	}
+
+	testCases := map[string]struct {
+		given    Given
+		expected Expected
+	}{
+		"JSONL": {
+			Given{
+				urls: urls,
+			},
+			Expected{
+				statusCode:  http.StatusOK,
+				contentType: "application/x-ndjson",
+				body: `{"alias":"promo","original":"https://example.com/1?a=1&b=2","query_mode":"merge","tags":["spring","sale"],"metadata":{"env":"prod","team":"a"},"clicks":3,"created_at":"2025-03-01T12:00:00Z"}` + "\n" +
+					`{"alias":"fjsido39jf","original":"https://example.com/2","query_mode":"ignore","title":"Title, with a comma","created_at":"2025-03-01T12:00:00Z"}` + "\n",
+			},
+		},
+		"CSV": {
+			Given{
+				query: "format=csv",
+				urls:  urls,
+			},
+			Expected{
+				statusCode:  http.StatusOK,
+				contentType: "text/csv; charset=utf-8",
+				body: "alias,original,query_mode,preview,title,description,tags,metadata,clicks,created_at\n" +
+					"promo,https://example.com/1?a=1&b=2,merge,false,,,\"[\"\"spring\"\",\"\"sale\"\"]\",\"{\"\"env\"\":\"\"prod\"\",\"\"team\"\":\"\"a\"\"}\",3,2025-03-01T12:00:00Z\n" +
+					"fjsido39jf,https://example.com/2,ignore,false,\"Title, with a comma\",,,,0,2025-03-01T12:00:00Z\n",
+			},
+		},
+		"Empty CSV": {
+			Given{
+				query: "format=csv",
+			},
+			Expected{
+				statusCode:  http.StatusOK,
+				contentType: "text/csv; charset=utf-8",
+				body:        "alias,original,query_mode,preview,title,description,tags,metadata,clicks,created_at\n",
+			},
+		},
+		"Invalid format": {
+			Given{
+				query: "format=xml",
+			},
+			Expected{
+				statusCode: http.StatusBadRequest,
+				errResp: &shortify.ErrorResponse{
+					Status:  http.StatusBadRequest,
+					Message: "Parameter 'format' must be one of: csv jsonl",
+				},
+			},
+		},
+		"Service error": {
+			Given{
+				svcErr: errors.New("unexpected error"),
+			},
+			Expected{
+				statusCode: http.StatusInternalServerError,
+				errResp: &shortify.ErrorResponse{
+					Status:  http.StatusInternalServerError,
+					Message: http.StatusText(http.StatusInternalServerError),
+				},
+			},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			// Given
+			rr := httptest.NewRecorder()
+
+			req, err := http.NewRequest(http.MethodGet, "/api/v1/urls/export?tag=spring&"+tc.given.query, nil)
+			require.NoError(t, err)
+
+			svc := urlmock.NewService(t)
+
+			if tc.expected.errResp == nil || tc.given.svcErr != nil {
+				svc.On("Export", req.Context(), &dto.ExportURLsRequest{Tag: "spring"}, mock.Anything).
+					Run(func(args mock.Arguments) {
+						fn := args.Get(2).(func(u *dto.GetURLByAliasResponse) error)
+
+						for _, u := range tc.given.urls {
+							require.NoError(t, fn(u))
+						}
+					}).
+					Return(tc.given.svcErr).
+					Once()
+			}
+
+			log := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+			clr := httpdel.NewURLController(svc, log)
+
+			// When
+			clr.Export(rr, req)
+
+			// Then
+			require.Equal(t, tc.expected.statusCode, rr.Code)
+
+			if tc.expected.errResp != nil {
+				var errResp shortify.ErrorResponse
+
+				err := json.NewDecoder(rr.Body).Decode(&errResp)
+				require.NoError(t, err)
+				require.Equal(t, *tc.expected.errResp, errResp)
+			} else {
+				require.Equal(t, tc.expected.contentType, rr.Header().Get("Content-Type"))
+				require.Equal(t, tc.expected.body, rr.Body.String())
+			}
+		})
+	}
+}
+
+func TestURLController_ExportImport_CSV(t *testing.T) {
+	// Given
+	exported := &dto.GetURLByAliasResponse{
+		Original:  "https://example.com/1",
+		Alias:     "promo",
+		QueryMode: "ignore",
+		Title:     "a;b=c, \"quoted\"",
+		Tags:      []string{"a;b", "c=d", `"e"`},
+		Metadata:  map[string]string{"k;1": "v=1;v=2", "k=2": "", `"k3"`: "[1]"},
+		CreatedAt: time.Date(2025, time.March, 1, 12, 0, 0, 0, time.UTC),
+	}
+
+	svc := urlmock.NewService(t)
+	svc.On("Export", mock.Anything, &dto.ExportURLsRequest{}, mock.Anything).
+		Run(func(args mock.Arguments) {
+			fn := args.Get(2).(func(u *dto.GetURLByAliasResponse) error)
+			require.NoError(t, fn(exported))
+		}).
+		Return(nil).
+		Once()
+	svc.On("Import", mock.Anything, &dto.ImportURLRequest{
+		Alias:     exported.Alias,
+		Original:  exported.Original,
+		QueryMode: exported.QueryMode,
+		Title:     exported.Title,
+		Tags:      exported.Tags,
+		Metadata:  exported.Metadata,
+		CreatedAt: exported.CreatedAt,
+	}).
+		Return(&dto.CreateURLResponse{}, nil).
+		Once()
+
+	log := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	clr := httpdel.NewURLController(svc, log)
+
+	exportRR := httptest.NewRecorder()
+
+	exportReq, err := http.NewRequest(http.MethodGet, "/api/v1/urls/export?format=csv", nil)
+	require.NoError(t, err)
+
+	clr.Export(exportRR, exportReq)
+	require.Equal(t, http.StatusOK, exportRR.Code)
+
+	importRR := httptest.NewRecorder()
+
+	importReq, err := http.NewRequest(http.MethodPost, "/api/v1/urls/import", exportRR.Body)
+	require.NoError(t, err)
+
+	importReq.Header.Set("Content-Type", "text/csv")
+
+	// When
+	clr.Import(importRR, importReq)
+
+	// Then
+	require.Equal(t, http.StatusOK, importRR.Code)
+
+	var resp shortify.ImportURLsResponse
+
+	err = json.NewDecoder(importRR.Body).Decode(&resp)
+	require.NoError(t, err)
+	require.Equal(t, shortify.ImportURLsResponse{Imported: 1, Errors: []shortify.ImportError{}}, resp)
+}
+
+func TestURLController_Import(t *testing.T) {
+	createdAt := time.Date(2025, time.March, 1, 12, 0, 0, 0, time.UTC)
+
+	type importCall struct {
+		req    *dto.ImportURLRequest
+		svcErr error
+	}
+
+	type Given struct {
+		query       string
+		contentType string
+		body        func(t *testing.T) (io.Reader, string)
+
+		calls []importCall
+	}
+
+	type Expected struct {
+		statusCode  int
+		successResp *shortify.ImportURLsResponse
+		errResp     *shortify.ErrorResponse
+	}
+
+	raw := func(contentType, body string) func(t *testing.T) (io.Reader, string) {
+		return func(t *testing.T) (io.Reader, string) {
+			return bytes.NewBufferString(body), contentType
+		}
+	}
+
+	form := func(field, filename, body string) func(t *testing.T) (io.Reader, string) {
+		return func(t *testing.T) (io.Reader, string) {
+			var buf bytes.Buffer
+
+			mw := multipart.NewWriter(&buf)
+
+			require.NoError(t, mw.WriteField("note", "first field"))
+
+			fw, err := mw.CreateFormFile(field, filename)
+			require.NoError(t, err)
+
+			_, err = io.WriteString(fw, body)
+			require.NoError(t, err)
+			require.NoError(t, mw.Close())
+
+			return &buf, mw.FormDataContentType()
+		}
+	}
+
+	testCases := map[string]struct {
+		given    Given
+		expected Expected
+	}{
+		"JSONL": {
+			Given{
+				body: raw("application/x-ndjson",
+					`{"alias":"promo","original":"https://example.com/1","tags":["spring"],"clicks":3,"created_at":"2025-03-01T12:00:00Z"}`+"\n"+
+						`{"alias":"taken","original":"https://example.com/2"}`+"\n"+
+						"\n"+
+						`{"original":`+"\n"+
+						`{"original":"https://example.com/3"}`+"\n"),
+				calls: []importCall{
+					{
+						req: &dto.ImportURLRequest{
+							Alias:     "promo",
+							Original:  "https://example.com/1",
+							Tags:      []string{"spring"},
+							CreatedAt: createdAt,
+						},
+					},
+					{
+						req:    &dto.ImportURLRequest{Alias: "taken", Original: "https://example.com/2"},
+						svcErr: url.ErrAliasTaken,
+					},
+					{
+						req: &dto.ImportURLRequest{Original: "https://example.com/3"},
+					},
+				},
+			},
+			Expected{
+				statusCode: http.StatusOK,
+				successResp: &shortify.ImportURLsResponse{
+					Imported: 2,
+					Failed:   2,
+					Errors: []shortify.ImportError{
+						{Line: 2, Alias: "taken", Original: "https://example.com/2", Status: http.StatusConflict, Message: "Alias is not available"},
+						{Line: 4, Status: http.StatusBadRequest, Message: "Invalid JSON"},
+					},
+				},
+			},
+		},
+		"CSV": {
+			Given{
+				query: "format=csv",
+				body: raw("text/plain",
+					"original,alias,tags,metadata,preview,created_at\n"+
+						`https://example.com/1,promo,"[""spring"",""sale""]","{""team"":""a"",""env"":""prod""}",true,2025-03-01T12:00:00Z`+"\n"+
+						"https://example.com/2,exists\n"+
+						"not a url,invalid\n"+
+						"https://example.com/3,,,,maybe\n"+
+						"https://example.com/4,,spring;sale\n"),
+				calls: []importCall{
+					{
+						req: &dto.ImportURLRequest{
+							Alias:     "promo",
+							Original:  "https://example.com/1",
+							Preview:   true,
+							Tags:      []string{"spring", "sale"},
+							Metadata:  map[string]string{"team": "a", "env": "prod"},
+							CreatedAt: createdAt,
+						},
+					},
+					{
+						req:    &dto.ImportURLRequest{Alias: "exists", Original: "https://example.com/2"},
+						svcErr: url.ErrAlreadyExists,
+					},
+				},
+			},
+			Expected{
+				statusCode: http.StatusOK,
+				successResp: &shortify.ImportURLsResponse{
+					Imported: 1,
+					Failed:   4,
+					Errors: []shortify.ImportError{
+						{Line: 3, Alias: "exists", Original: "https://example.com/2", Status: http.StatusConflict, Message: "URL already exists"},
+						{Line: 4, Alias: "invalid", Original: "not a url", Status: http.StatusBadRequest, Message: "Field 'original' is not a valid URL"},
+						{Line: 5, Status: http.StatusBadRequest, Message: "Field 'preview' must be a boolean"},
+						{Line: 6, Status: http.StatusBadRequest, Message: "Field 'tags' must be a JSON array of strings"},
+					},
+				},
+			},
+		},
+		"CSV without header": {
+			Given{
+				body: raw("text/csv", "https://example.com/1\nhttps://example.com/2\n"),
+				calls: []importCall{
+					{req: &dto.ImportURLRequest{Original: "https://example.com/1"}},
+					{req: &dto.ImportURLRequest{Original: "https://example.com/2"}},
+				},
+			},
+			Expected{
+				statusCode: http.StatusOK,
+				successResp: &shortify.ImportURLsResponse{
+					Imported: 2,
+					Errors:   []shortify.ImportError{},
+				},
+			},
+		},
+		"Multipart form": {
+			Given{
+				body: form("file", "urls.csv", "alias,original\npromo,https://example.com/1\n"),
+				calls: []importCall{
+					{req: &dto.ImportURLRequest{Alias: "promo", Original: "https://example.com/1"}},
+				},
+			},
+			Expected{
+				statusCode: http.StatusOK,
+				successResp: &shortify.ImportURLsResponse{
+					Imported: 1,
+					Errors:   []shortify.ImportError{},
+				},
+			},
+		},
+		"Dry run": {
+			Given{
+				query: "dry_run=true",
+				body:  raw("application/x-ndjson", `{"alias":"promo","original":"https://example.com/1"}`),
+				calls: []importCall{
+					{
+						req:    &dto.ImportURLRequest{Alias: "promo", Original: "https://example.com/1", DryRun: &dto.ImportDryRun{}},
+						svcErr: url.ErrAliasQuarantined,
+					},
+				},
+			},
+			Expected{
+				statusCode: http.StatusOK,
+				successResp: &shortify.ImportURLsResponse{
+					DryRun: true,
+					Failed: 1,
+					Errors: []shortify.ImportError{
+						{Line: 1, Alias: "promo", Original: "https://example.com/1", Status: http.StatusConflict, Message: "Alias is not available"},
+					},
+				},
+			},
+		},
+		"Service error stops import": {
+			Given{
+				body: raw("application/x-ndjson", `{"original":"https://example.com/1"}`+"\n"+`{"original":"https://example.com/2"}`),
+				calls: []importCall{
+					{
+						req:    &dto.ImportURLRequest{Original: "https://example.com/1"},
+						svcErr: errors.New("unexpected error"),
+					},
+				},
+			},
+			Expected{
+				statusCode: http.StatusOK,
+				successResp: &shortify.ImportURLsResponse{
+					Failed: 1,
+					Errors: []shortify.ImportError{
+						{Line: 1, Original: "https://example.com/1", Status: http.StatusInternalServerError, Message: http.StatusText(http.StatusInternalServerError)},
+					},
+				},
+			},
+		},
+		"Unreadable CSV stops import": {
+			Given{
+				body: raw("text/csv", "alias,original\npromo,https://example.com/1\nbad,\"https://example.com/\"2\"\nnext,https://example.com/3\n"),
+				calls: []importCall{
+					{req: &dto.ImportURLRequest{Alias: "promo", Original: "https://example.com/1"}},
+				},
+			},
+			Expected{
+				statusCode: http.StatusOK,
+				successResp: &shortify.ImportURLsResponse{
+					Imported: 1,
+					Failed:   1,
+					Errors: []shortify.ImportError{
+						{Line: 3, Status: http.StatusBadRequest, Message: "File can't be read from this line on"},
+					},
+				},
+			},
+		},
+		"Unknown column": {
+			Given{
+				body: raw("text/csv", "original,clicks,owner\nhttps://example.com/1,0,me\n"),
+			},
+			Expected{
+				statusCode: http.StatusBadRequest,
+				errResp: &shortify.ErrorResponse{
+					Status:  http.StatusBadRequest,
+					Message: "Unknown column 'owner'",
+				},
+			},
+		},
+		"Missing file": {
+			Given{
+				body: form("upload", "urls.csv", "https://example.com/1\n"),
+			},
+			Expected{
+				statusCode: http.StatusBadRequest,
+				errResp: &shortify.ErrorResponse{
+					Status:  http.StatusBadRequest,
+					Message: "Field 'file' is missing",
+				},
+			},
+		},
+		"Invalid format": {
+			Given{
+				query: "format=xml",
+				body:  raw("text/csv", ""),
+			},
+			Expected{
+				statusCode: http.StatusBadRequest,
+				errResp: &shortify.ErrorResponse{
+					Status:  http.StatusBadRequest,
+					Message: "Parameter 'format' must be one of: csv jsonl",
+				},
+			},
+		},
+		"Invalid dry run": {
+			Given{
+				query: "dry_run=maybe",
+				body:  raw("text/csv", ""),
+			},
+			Expected{
+				statusCode: http.StatusBadRequest,
+				errResp: &shortify.ErrorResponse{
+					Status:  http.StatusBadRequest,
+					Message: "Parameter 'dry_run' must be a boolean",
+				},
+			},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			// Given
+			rr := httptest.NewRecorder()
+
+			body, contentType := tc.given.body(t)
+
+			req, err := http.NewRequest(http.MethodPost, "/api/v1/urls/import?"+tc.given.query, body)
+			require.NoError(t, err)
+
+			req.Header.Set("Content-Type", contentType)
+
+			svc := urlmock.NewService(t)
+
+			for _, call := range tc.given.calls {
+				svc.On("Import", req.Context(), call.req).
+					Return(&dto.CreateURLResponse{}, call.svcErr).
+					Once()
+			}
+
+			log := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+			clr := httpdel.NewURLController(svc, log)
+
+			// When
+			clr.Import(rr, req)
+
+			// Then
+			require.Equal(t, tc.expected.statusCode, rr.Code)
+
+			if tc.expected.successResp != nil {
+				var resp shortify.ImportURLsResponse
+
+				err := json.NewDecoder(rr.Body).Decode(&resp)
+				require.NoError(t, err)
+				require.Equal(t, *tc.expected.successResp, resp)
+			}
+
+			if tc.expected.errResp != nil {
+				var errResp shortify.ErrorResponse
+
+				err := json.NewDecoder(rr.Body).Decode(&errResp)
+				require.NoError(t, err)
+				require.Equal(t, *tc.expected.errResp, errResp)
+			}
+		})
+	}
+}
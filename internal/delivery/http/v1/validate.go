@@ -0,0 +1,34 @@
+package http
+
+import (
+	"reflect"
+	"regexp"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+)
+
+var validate = newValidator()
+
+// aliasPattern matches the aliases a URL may be renamed to.
+var aliasPattern = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+// newValidator creates a validator reporting fields by their JSON names.
+func newValidator() *validator.Validate {
+	v := validator.New()
+
+	v.RegisterTagNameFunc(func(field reflect.StructField) string {
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			return ""
+		}
+
+		return name
+	})
+
+	v.RegisterValidation("alias", func(fl validator.FieldLevel) bool {
+		return aliasPattern.MatchString(fl.Field().String())
+	})
+
+	return v
+}
@@ -0,0 +1,381 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+	"github.com/go-playground/validator/v10"
+	"github.com/kodeyeen/shortify/internal/dto"
+	"github.com/kodeyeen/shortify/internal/webhook"
+	"github.com/kodeyeen/shortify/v1"
+)
+
+// MaxDeliveriesLimit caps the number of deliveries listed at once
+const MaxDeliveriesLimit = 100
+
+type WebhookService interface {
+	Create(ctx context.Context, req *dto.CreateWebhookRequest) (*dto.WebhookResponse, error)
+	GetByID(ctx context.Context, req *dto.GetWebhookRequest) (*dto.WebhookResponse, error)
+	List(ctx context.Context) (*dto.ListWebhooksResponse, error)
+	Delete(ctx context.Context, req *dto.DeleteWebhookRequest) error
+	ListDeliveries(ctx context.Context, req *dto.ListDeliveriesRequest) (*dto.ListDeliveriesResponse, error)
+}
+
+type WebhookController struct {
+	webhooks WebhookService
+
+	log *slog.Logger
+}
+
+func NewWebhookController(webhooks WebhookService, log *slog.Logger) *WebhookController {
+	return &WebhookController{
+		webhooks: webhooks,
+
+		log: log,
+	}
+}
+
+// Create registers new webhook
+//
+//	@Summary		Create a webhook
+//	@Description	Create registers new webhook notified about the subscribed link events. The secret signing the deliveries is generated unless provided and returned only in this response
+//	@Tags			webhooks
+//	@Accept			json
+//	@Produce		json
+//	@Param			webhook	body		shortify.CreateWebhookRequest	true	"Create webhook"
+//	@Success		201		{object}	shortify.WebhookResponse
+//	@Failure		400		{object}	shortify.ErrorResponse
+//	@Failure		500		{object}	shortify.ErrorResponse
+//	@Router			/api/v1/webhooks [post]
+func (c *WebhookController) Create(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	log := c.log.With(
+		slog.String("handler", "CreateWebhook"),
+		slog.String("request_id", middleware.GetReqID(ctx)),
+	)
+
+	var req shortify.CreateWebhookRequest
+
+	err := render.DecodeJSON(r.Body, &req)
+	if err != nil {
+		log.Error("failed to decode request body", slog.String("error", err.Error()))
+
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, shortify.ErrorResponse{
+			Status:  http.StatusBadRequest,
+			Message: "Invalid request body",
+		})
+		return
+	}
+
+	if err := validate.Struct(req); err != nil {
+		log.Error("invalid request", slog.String("error", err.Error()))
+
+		validatorErrs := err.(validator.ValidationErrors)
+
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, shortify.ErrorResponse{
+			Status:  http.StatusBadRequest,
+			Message: formatErrs(validatorErrs),
+		})
+		return
+	}
+
+	out, err := c.webhooks.Create(ctx, &dto.CreateWebhookRequest{
+		URL:    req.URL,
+		Secret: req.Secret,
+		Events: req.Events,
+	})
+	if err != nil {
+		log.Error("failed to create webhook", slog.String("error", err.Error()))
+
+		render.Status(r, http.StatusInternalServerError)
+		render.JSON(w, r, shortify.ErrorResponse{
+			Status:  http.StatusInternalServerError,
+			Message: http.StatusText(http.StatusInternalServerError),
+		})
+		return
+	}
+
+	log.Info("webhook created", slog.Int64("id", out.ID))
+
+	render.Status(r, http.StatusCreated)
+	render.JSON(w, r, newWebhookResponse(out))
+}
+
+// GetByID gets webhook by its ID
+//
+//	@Summary		Get webhook
+//	@Description	Get webhook by its ID
+//	@Tags			webhooks
+//	@Produce		json
+//	@Param			id	path		int	true	"Webhook ID"
+//	@Success		200	{object}	shortify.WebhookResponse
+//	@Failure		400	{object}	shortify.ErrorResponse
+//	@Failure		404	{object}	shortify.ErrorResponse
+//	@Failure		500	{object}	shortify.ErrorResponse
+//	@Router			/api/v1/webhooks/{id} [get]
+func (c *WebhookController) GetByID(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	log := c.log.With(
+		slog.String("handler", "GetWebhookByID"),
+		slog.String("request_id", middleware.GetReqID(ctx)),
+	)
+
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		log.Info("invalid webhook id", slog.String("id", chi.URLParam(r, "id")))
+
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, shortify.ErrorResponse{
+			Status:  http.StatusBadRequest,
+			Message: "Invalid webhook ID",
+		})
+		return
+	}
+
+	out, err := c.webhooks.GetByID(ctx, &dto.GetWebhookRequest{
+		ID: id,
+	})
+	if err != nil {
+		if errors.Is(err, webhook.ErrNotFound) {
+			log.Info("webhook not found", slog.Int64("id", id))
+
+			render.Status(r, http.StatusNotFound)
+			render.JSON(w, r, shortify.ErrorResponse{
+				Status:  http.StatusNotFound,
+				Message: http.StatusText(http.StatusNotFound),
+			})
+			return
+		}
+
+		log.Error("failed to get webhook", slog.String("error", err.Error()))
+
+		render.Status(r, http.StatusInternalServerError)
+		render.JSON(w, r, shortify.ErrorResponse{
+			Status:  http.StatusInternalServerError,
+			Message: http.StatusText(http.StatusInternalServerError),
+		})
+		return
+	}
+
+	render.Status(r, http.StatusOK)
+	render.JSON(w, r, newWebhookResponse(out))
+}
+
+// List lists webhooks
+//
+//	@Summary		List webhooks
+//	@Description	List lists all webhooks
+//	@Tags			webhooks
+//	@Produce		json
+//	@Success		200	{object}	shortify.ListWebhooksResponse
+//	@Failure		500	{object}	shortify.ErrorResponse
+//	@Router			/api/v1/webhooks [get]
+func (c *WebhookController) List(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	log := c.log.With(
+		slog.String("handler", "ListWebhooks"),
+		slog.String("request_id", middleware.GetReqID(ctx)),
+	)
+
+	out, err := c.webhooks.List(ctx)
+	if err != nil {
+		log.Error("failed to list webhooks", slog.String("error", err.Error()))
+
+		render.Status(r, http.StatusInternalServerError)
+		render.JSON(w, r, shortify.ErrorResponse{
+			Status:  http.StatusInternalServerError,
+			Message: http.StatusText(http.StatusInternalServerError),
+		})
+		return
+	}
+
+	resp := shortify.ListWebhooksResponse{
+		Webhooks: make([]shortify.WebhookResponse, 0, len(out.Webhooks)),
+	}
+
+	for _, wh := range out.Webhooks {
+		resp.Webhooks = append(resp.Webhooks, newWebhookResponse(wh))
+	}
+
+	render.Status(r, http.StatusOK)
+	render.JSON(w, r, resp)
+}
+
+// Delete deletes webhook by its ID
+//
+//	@Summary		Delete webhook
+//	@Description	Delete deletes webhook with its pending deliveries
+//	@Tags			webhooks
+//	@Produce		json
+//	@Param			id	path	int	true	"Webhook ID"
+//	@Success		204
+//	@Failure		400	{object}	shortify.ErrorResponse
+//	@Failure		404	{object}	shortify.ErrorResponse
+//	@Failure		500	{object}	shortify.ErrorResponse
+//	@Router			/api/v1/webhooks/{id} [delete]
+func (c *WebhookController) Delete(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	log := c.log.With(
+		slog.String("handler", "DeleteWebhook"),
+		slog.String("request_id", middleware.GetReqID(ctx)),
+	)
+
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		log.Info("invalid webhook id", slog.String("id", chi.URLParam(r, "id")))
+
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, shortify.ErrorResponse{
+			Status:  http.StatusBadRequest,
+			Message: "Invalid webhook ID",
+		})
+		return
+	}
+
+	err = c.webhooks.Delete(ctx, &dto.DeleteWebhookRequest{
+		ID: id,
+	})
+	if err != nil {
+		if errors.Is(err, webhook.ErrNotFound) {
+			log.Info("webhook not found", slog.Int64("id", id))
+
+			render.Status(r, http.StatusNotFound)
+			render.JSON(w, r, shortify.ErrorResponse{
+				Status:  http.StatusNotFound,
+				Message: http.StatusText(http.StatusNotFound),
+			})
+			return
+		}
+
+		log.Error("failed to delete webhook", slog.String("error", err.Error()))
+
+		render.Status(r, http.StatusInternalServerError)
+		render.JSON(w, r, shortify.ErrorResponse{
+			Status:  http.StatusInternalServerError,
+			Message: http.StatusText(http.StatusInternalServerError),
+		})
+		return
+	}
+
+	log.Info("webhook deleted", slog.Int64("id", id))
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ListDeliveries lists recent deliveries of the webhook
+//
+//	@Summary		List webhook deliveries
+//	@Description	ListDeliveries lists the most recent deliveries of the webhook with their status
+//	@Tags			webhooks
+//	@Produce		json
+//	@Param			id		path		int	true	"Webhook ID"
+//	@Param			limit	query		int	false	"Maximum number of deliveries"	default(100)	maximum(100)
+//	@Success		200		{object}	shortify.ListDeliveriesResponse
+//	@Failure		400		{object}	shortify.ErrorResponse
+//	@Failure		404		{object}	shortify.ErrorResponse
+//	@Failure		500		{object}	shortify.ErrorResponse
+//	@Router			/api/v1/webhooks/{id}/deliveries [get]
+func (c *WebhookController) ListDeliveries(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	log := c.log.With(
+		slog.String("handler", "ListDeliveries"),
+		slog.String("request_id", middleware.GetReqID(ctx)),
+	)
+
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		log.Info("invalid webhook id", slog.String("id", chi.URLParam(r, "id")))
+
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, shortify.ErrorResponse{
+			Status:  http.StatusBadRequest,
+			Message: "Invalid webhook ID",
+		})
+		return
+	}
+
+	query := r.URL.Query()
+
+	limit, err := intParam(query, "limit")
+	if err != nil || limit < 0 || limit > MaxDeliveriesLimit {
+		log.Info("invalid limit", slog.String("limit", query.Get("limit")))
+
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, shortify.ErrorResponse{
+			Status:  http.StatusBadRequest,
+			Message: fmt.Sprintf("Parameter 'limit' must be an integer between 0 and %d", MaxDeliveriesLimit),
+		})
+		return
+	}
+
+	if limit == 0 {
+		limit = MaxDeliveriesLimit
+	}
+
+	out, err := c.webhooks.ListDeliveries(ctx, &dto.ListDeliveriesRequest{
+		WebhookID: id,
+		Limit:     limit,
+	})
+	if err != nil {
+		if errors.Is(err, webhook.ErrNotFound) {
+			log.Info("webhook not found", slog.Int64("id", id))
+
+			render.Status(r, http.StatusNotFound)
+			render.JSON(w, r, shortify.ErrorResponse{
+				Status:  http.StatusNotFound,
+				Message: http.StatusText(http.StatusNotFound),
+			})
+			return
+		}
+
+		log.Error("failed to list deliveries", slog.String("error", err.Error()))
+
+		render.Status(r, http.StatusInternalServerError)
+		render.JSON(w, r, shortify.ErrorResponse{
+			Status:  http.StatusInternalServerError,
+			Message: http.StatusText(http.StatusInternalServerError),
+		})
+		return
+	}
+
+	resp := shortify.ListDeliveriesResponse{
+		Deliveries: make([]shortify.DeliveryResponse, 0, len(out.Deliveries)),
+	}
+
+	for _, d := range out.Deliveries {
+		resp.Deliveries = append(resp.Deliveries, shortify.DeliveryResponse{
+			ID:             d.ID,
+			Event:          d.Event,
+			Payload:        d.Payload,
+			Status:         d.Status,
+			Attempts:       d.Attempts,
+			NextAttemptAt:  d.NextAttemptAt,
+			LastError:      d.LastError,
+			ResponseStatus: d.ResponseStatus,
+			CreatedAt:      d.CreatedAt,
+			DeliveredAt:    d.DeliveredAt,
+		})
+	}
+
+	render.Status(r, http.StatusOK)
+	render.JSON(w, r, resp)
+}
+
+func newWebhookResponse(out *dto.WebhookResponse) shortify.WebhookResponse {
+	return shortify.WebhookResponse{
+		ID:        out.ID,
+		URL:       out.URL,
+		Secret:    out.Secret,
+		Events:    out.Events,
+		CreatedAt: out.CreatedAt,
+	}
+}
@@ -0,0 +1,299 @@
+package http_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	httpdel "github.com/kodeyeen/shortify/internal/delivery/http/v1"
+	"github.com/kodeyeen/shortify/internal/dto"
+	"github.com/kodeyeen/shortify/internal/webhook"
+	"github.com/kodeyeen/shortify/internal/webhookmock"
+	"github.com/kodeyeen/shortify/v1"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWebhookController_Create(t *testing.T) {
+	type Given struct {
+		reqBody []byte
+
+		svcReq  *dto.CreateWebhookRequest
+		svcResp *dto.WebhookResponse
+		svcErr  error
+	}
+
+	type Expected struct {
+		statusCode  int
+		successResp *shortify.WebhookResponse
+		errResp     *shortify.ErrorResponse
+	}
+
+	testCases := map[string]struct {
+		given    Given
+		expected Expected
+	}{
+		"Success": {
+			Given{
+				reqBody: []byte(`{"url": "https://example.com/hook", "events": ["url.created"]}`),
+
+				svcReq: &dto.CreateWebhookRequest{
+					URL:    "https://example.com/hook",
+					Events: []string{"url.created"},
+				},
+				svcResp: &dto.WebhookResponse{
+					ID:     1,
+					URL:    "https://example.com/hook",
+					Secret: "generatedsecret0",
+					Events: []string{"url.created"},
+				},
+				svcErr: nil,
+			},
+			Expected{
+				statusCode: http.StatusCreated,
+				successResp: &shortify.WebhookResponse{
+					ID:     1,
+					URL:    "https://example.com/hook",
+					Secret: "generatedsecret0",
+					Events: []string{"url.created"},
+				},
+				errResp: nil,
+			},
+		},
+		"Invalid request body": {
+			Given{
+				reqBody: []byte(`not json`),
+			},
+			Expected{
+				statusCode: http.StatusBadRequest,
+				errResp: &shortify.ErrorResponse{
+					Status:  http.StatusBadRequest,
+					Message: "Invalid request body",
+				},
+			},
+		},
+		"Empty events": {
+			Given{
+				reqBody: []byte(`{"url": "https://example.com/hook"}`),
+			},
+			Expected{
+				statusCode: http.StatusBadRequest,
+				errResp: &shortify.ErrorResponse{
+					Status:  http.StatusBadRequest,
+					Message: "Field 'events' is missing",
+				},
+			},
+		},
+		"Unknown event": {
+			Given{
+				reqBody: []byte(`{"url": "https://example.com/hook", "events": ["url.expired"]}`),
+			},
+			Expected{
+				statusCode: http.StatusBadRequest,
+				errResp: &shortify.ErrorResponse{
+					Status:  http.StatusBadRequest,
+					Message: "Field 'events[0]' must be one of: url.created url.updated url.deleted url.restored url.click_threshold",
+				},
+			},
+		},
+		"Short secret": {
+			Given{
+				reqBody: []byte(`{"url": "https://example.com/hook", "secret": "short", "events": ["url.created"]}`),
+			},
+			Expected{
+				statusCode: http.StatusBadRequest,
+				errResp: &shortify.ErrorResponse{
+					Status:  http.StatusBadRequest,
+					Message: "Field 'secret' must be at least 16",
+				},
+			},
+		},
+		"Other error": {
+			Given{
+				reqBody: []byte(`{"url": "https://example.com/hook", "events": ["url.created"]}`),
+
+				svcReq: &dto.CreateWebhookRequest{
+					URL:    "https://example.com/hook",
+					Events: []string{"url.created"},
+				},
+				svcResp: nil,
+				svcErr:  errors.New("svc error"),
+			},
+			Expected{
+				statusCode: http.StatusInternalServerError,
+				errResp: &shortify.ErrorResponse{
+					Status:  http.StatusInternalServerError,
+					Message: http.StatusText(http.StatusInternalServerError),
+				},
+			},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			// Given
+			rr := httptest.NewRecorder()
+
+			req, err := http.NewRequest(http.MethodPost, "/api/v1/webhooks", bytes.NewReader(tc.given.reqBody))
+			require.NoError(t, err)
+
+			ctx := req.Context()
+
+			svc := webhookmock.NewService(t)
+
+			if tc.given.svcResp != nil || tc.given.svcErr != nil {
+				svc.On("Create", ctx, tc.given.svcReq).
+					Return(tc.given.svcResp, tc.given.svcErr).
+					Once()
+			}
+
+			log := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+			clr := httpdel.NewWebhookController(svc, log)
+
+			// When
+			clr.Create(rr, req)
+
+			// Then
+			require.Equal(t, tc.expected.statusCode, rr.Code)
+
+			if tc.expected.errResp != nil {
+				var resp shortify.ErrorResponse
+
+				err = json.NewDecoder(rr.Body).Decode(&resp)
+				require.NoError(t, err)
+
+				require.Equal(t, tc.expected.errResp, &resp)
+			} else {
+				var resp shortify.WebhookResponse
+
+				err = json.NewDecoder(rr.Body).Decode(&resp)
+				require.NoError(t, err)
+
+				require.Equal(t, tc.expected.successResp, &resp)
+			}
+		})
+	}
+}
+
+func TestWebhookController_Delete(t *testing.T) {
+	type Given struct {
+		id string
+
+		svcReq *dto.DeleteWebhookRequest
+		svcErr error
+		called bool
+	}
+
+	type Expected struct {
+		statusCode int
+		errResp    *shortify.ErrorResponse
+	}
+
+	testCases := map[string]struct {
+		given    Given
+		expected Expected
+	}{
+		"Success": {
+			Given{
+				id: "1",
+
+				svcReq: &dto.DeleteWebhookRequest{
+					ID: 1,
+				},
+				svcErr: nil,
+				called: true,
+			},
+			Expected{
+				statusCode: http.StatusNoContent,
+				errResp:    nil,
+			},
+		},
+		"Invalid ID": {
+			Given{
+				id: "abc",
+
+				called: false,
+			},
+			Expected{
+				statusCode: http.StatusBadRequest,
+				errResp: &shortify.ErrorResponse{
+					Status:  http.StatusBadRequest,
+					Message: "Invalid webhook ID",
+				},
+			},
+		},
+		"Not found": {
+			Given{
+				id: "1",
+
+				svcReq: &dto.DeleteWebhookRequest{
+					ID: 1,
+				},
+				svcErr: webhook.ErrNotFound,
+				called: true,
+			},
+			Expected{
+				statusCode: http.StatusNotFound,
+				errResp: &shortify.ErrorResponse{
+					Status:  http.StatusNotFound,
+					Message: http.StatusText(http.StatusNotFound),
+				},
+			},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			// Given
+			rr := httptest.NewRecorder()
+
+			req, err := http.NewRequest(http.MethodDelete, "/api/v1/webhooks/"+tc.given.id, nil)
+			require.NoError(t, err)
+
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("id", tc.given.id)
+
+			ctx := context.WithValue(req.Context(), chi.RouteCtxKey, rctx)
+			req = req.WithContext(ctx)
+
+			svc := webhookmock.NewService(t)
+
+			if tc.given.called {
+				svc.On("Delete", ctx, tc.given.svcReq).
+					Return(tc.given.svcErr).
+					Once()
+			}
+
+			log := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+			clr := httpdel.NewWebhookController(svc, log)
+
+			// When
+			clr.Delete(rr, req)
+
+			// Then
+			require.Equal(t, tc.expected.statusCode, rr.Code)
+
+			if tc.expected.errResp != nil {
+				var resp shortify.ErrorResponse
+
+				err = json.NewDecoder(rr.Body).Decode(&resp)
+				require.NoError(t, err)
+
+				require.Equal(t, tc.expected.errResp, &resp)
+			}
+		})
+	}
+}
@@ -0,0 +1,52 @@
+package domain
+
+import "time"
+
+// Audit actions.
+const (
+	AuditActionCreated    = "created"
+	AuditActionUpdated    = "updated"
+	AuditActionDeleted    = "deleted"
+	AuditActionRestored   = "restored"
+	AuditActionRolledBack = "rolled_back"
+)
+
+// AuditEntry is an immutable record of a change to a URL.
+type AuditEntry struct {
+	ID        int64
+	URLID     int64
+	Action    string
+	Actor     string
+	RequestID string
+	// Before is the URL state before the change, nil for creation and restoration.
+	Before *URLState
+	// After is the URL state after the change, nil for deletion.
+	After     *URLState
+	CreatedAt time.Time
+}
+
+// URLState is the state of the editable URL fields.
+type URLState struct {
+	Original    string
+	Alias       string
+	QueryMode   string
+	Preview     bool
+	Title       string
+	Description string
+	Tags        []string
+	Metadata    map[string]string
+}
+
+// NewURLState returns the state of the editable fields of u.
+func NewURLState(u *URL) *URLState {
+	return &URLState{
+		Original:    u.Original,
+		Alias:       u.Alias,
+		QueryMode:   u.QueryMode,
+		Preview:     u.Preview,
+		Title:       u.Title,
+		Description: u.Description,
+		Tags:        u.Tags,
+		Metadata:    u.Metadata,
+	}
+}
@@ -1,7 +1,51 @@
 package domain
 
+import "time"
+
+// Query modes define what happens to the query string of an incoming
+// redirect request.
+const (
+	// QueryModeIgnore drops incoming query parameters.
+	QueryModeIgnore = "ignore"
+	// QueryModeMerge adds incoming query parameters the destination doesn't have.
+	QueryModeMerge = "merge"
+	// QueryModeOverride adds incoming query parameters replacing the destination ones.
+	QueryModeOverride = "override"
+)
+
+// PathPlaceholder is substituted with the path that follows the alias
+// in the redirect request, e.g. https://example.com/p/{path}.
+const PathPlaceholder = "{path}"
+
 type URL struct {
-	ID       int64
-	Original string
-	Alias    string
+	ID          int64
+	Original    string
+	Alias       string
+	QueryMode   string
+	Preview     bool
+	Title       string
+	Description string
+	Tags        []string
+	Metadata    map[string]string
+	Clicks      int64
+	CreatedAt   time.Time
+	OpenGraph   *OpenGraph
+	// DeletedAt is set while the URL is in the trash
+	DeletedAt *time.Time
+}
+
+// RetiredAlias is an alias the URL had before it was renamed.
+// Retired aliases keep resolving to the URL.
+type RetiredAlias struct {
+	Alias     string
+	URLID     int64
+	RetiredAt time.Time
+}
+
+// OpenGraph is the preview metadata of the page behind the URL.
+type OpenGraph struct {
+	Title       string
+	Description string
+	Image       string
+	FetchedAt   time.Time
 }
@@ -0,0 +1,54 @@
+package domain
+
+import "time"
+
+// Events webhooks can subscribe to.
+const (
+	EventURLCreated        = "url.created"
+	EventURLUpdated        = "url.updated"
+	EventURLDeleted        = "url.deleted"
+	EventURLRestored       = "url.restored"
+	EventURLClickThreshold = "url.click_threshold"
+)
+
+// Events lists all the events webhooks can subscribe to.
+var Events = []string{
+	EventURLCreated,
+	EventURLUpdated,
+	EventURLDeleted,
+	EventURLRestored,
+	EventURLClickThreshold,
+}
+
+// Delivery statuses.
+const (
+	// DeliveryStatusPending deliveries are waiting for the next attempt.
+	DeliveryStatusPending = "pending"
+	// DeliveryStatusDelivered deliveries were acknowledged by the receiver.
+	DeliveryStatusDelivered = "delivered"
+	// DeliveryStatusDead deliveries ran out of attempts and won't be retried.
+	DeliveryStatusDead = "dead"
+)
+
+type Webhook struct {
+	ID        int64
+	URL       string
+	Secret    string
+	Events    []string
+	CreatedAt time.Time
+}
+
+// Delivery is a webhook notification about a single event.
+type Delivery struct {
+	ID             int64
+	WebhookID      int64
+	Event          string
+	Payload        []byte
+	Status         string
+	Attempts       int
+	NextAttemptAt  time.Time
+	LastError      string
+	ResponseStatus int
+	CreatedAt      time.Time
+	DeliveredAt    *time.Time
+}
@@ -1,13 +1,33 @@
 package dto
 
+import (
+	"net/url"
+	"time"
+)
+
 type CreateURLRequest struct {
-	Original string `json:"original" validate:"required,url"`
+	Original    string            `json:"original" validate:"required,url"`
+	QueryMode   string            `json:"query_mode" validate:"omitempty,oneof=ignore merge override"`
+	Preview     bool              `json:"preview"`
+	Title       string            `json:"title"`
+	Description string            `json:"description"`
+	Tags        []string          `json:"tags"`
+	Metadata    map[string]string `json:"metadata"`
+	// AliasProvider chooses the alias provider by name, the default one is used when it's empty.
+	AliasProvider string `json:"alias_provider"`
 }
 
 type CreateURLResponse struct {
-	ID       int64  `json:"-"`
-	Original string `json:"original"`
-	Alias    string `json:"alias"`
+	ID          int64             `json:"-"`
+	Original    string            `json:"original"`
+	Alias       string            `json:"alias"`
+	QueryMode   string            `json:"query_mode"`
+	Preview     bool              `json:"preview"`
+	Title       string            `json:"title"`
+	Description string            `json:"description"`
+	Tags        []string          `json:"tags"`
+	Metadata    map[string]string `json:"metadata"`
+	CreatedAt   time.Time         `json:"created_at"`
 }
 
 type GetURLByAliasRequest struct {
@@ -15,6 +35,156 @@ type GetURLByAliasRequest struct {
 }
 
 type GetURLByAliasResponse struct {
-	Original string `json:"original"`
-	Alias    string `json:"alias"`
+	Original    string             `json:"original"`
+	Alias       string             `json:"alias"`
+	QueryMode   string             `json:"query_mode"`
+	Preview     bool               `json:"preview"`
+	Title       string             `json:"title"`
+	Description string             `json:"description"`
+	Tags        []string           `json:"tags"`
+	Metadata    map[string]string  `json:"metadata"`
+	Clicks      int64              `json:"clicks"`
+	CreatedAt   time.Time          `json:"created_at"`
+	OpenGraph   *OpenGraphResponse `json:"open_graph"`
+}
+
+type OpenGraphResponse struct {
+	Title       string    `json:"title"`
+	Description string    `json:"description"`
+	Image       string    `json:"image"`
+	FetchedAt   time.Time `json:"fetched_at"`
+}
+
+// UpdateURLRequest changes the fields that are not nil.
+type UpdateURLRequest struct {
+	Alias       string             `json:"-"`
+	NewAlias    *string            `json:"alias"`
+	Original    *string            `json:"original"`
+	QueryMode   *string            `json:"query_mode"`
+	Preview     *bool              `json:"preview"`
+	Title       *string            `json:"title"`
+	Description *string            `json:"description"`
+	Tags        *[]string          `json:"tags"`
+	Metadata    *map[string]string `json:"metadata"`
+}
+
+type DeleteURLRequest struct {
+	Alias string `json:"alias"`
+}
+
+type RestoreURLRequest struct {
+	Alias string `json:"alias"`
+}
+
+type ListURLsRequest struct {
+	Tag      string
+	Metadata map[string]string
+	Limit    int
+	Offset   int
+}
+
+type ListURLsResponse struct {
+	URLs []*GetURLByAliasResponse `json:"urls"`
+}
+
+type CapacityResponse struct {
+	Provider             string
+	KeyspaceSize         string
+	KeyspaceBits         float64
+	Occupied             int64
+	Occupancy            float64
+	CollisionProbability float64
+	FailureProbability   float64
+}
+
+type ListAliasesRequest struct {
+	Alias string
+}
+
+type ListAliasesResponse struct {
+	Alias   string
+	Retired []*RetiredAliasResponse
+}
+
+type RetiredAliasResponse struct {
+	Alias            string
+	RetiredAt        time.Time
+	QuarantinedUntil time.Time
+}
+
+type URLHistoryRequest struct {
+	Alias string
+}
+
+type URLHistoryResponse struct {
+	Alias    string
+	Versions []*URLVersionResponse
+}
+
+// URLVersionResponse is a change to the URL. Versions are numbered from 1 in the order of changes.
+type URLVersionResponse struct {
+	Version   int
+	Action    string
+	Actor     string
+	RequestID string
+	CreatedAt time.Time
+	Changes   []*FieldChange
+}
+
+// FieldChange is a changed URL field, the missing side of a creation or deletion is nil.
+type FieldChange struct {
+	Field  string
+	Before any
+	After  any
+}
+
+type RollbackURLRequest struct {
+	Alias   string
+	Version int
+}
+
+type ResolveURLRequest struct {
+	Alias string
+	Path  string
+	Query url.Values
+}
+
+type ResolveURLResponse struct {
+	Location  string
+	Alias     string
+	Preview   bool
+	CreatedAt time.Time
+	// Moved is set when the request used a retired alias and the client
+	// should be redirected to Alias instead of Location
+	Moved bool
+}
+
+type ExportURLsRequest struct {
+	Tag      string
+	Metadata map[string]string
+}
+
+// ImportURLRequest is a URL read from an import file.
+type ImportURLRequest struct {
+	// Alias is kept as is, a generated one is used when it's empty.
+	Alias       string
+	Original    string
+	QueryMode   string
+	Preview     bool
+	Title       string
+	Description string
+	Tags        []string
+	Metadata    map[string]string
+	// CreatedAt is the current time when it's zero.
+	CreatedAt time.Time
+	// DryRun checks the URL for conflicts without adding it when it's set.
+	// The same DryRun is passed for every URL of the file.
+	DryRun *ImportDryRun
+}
+
+// ImportDryRun holds the URLs of the file checked by a dry run so the later
+// URLs of the file conflicting with them are reported as well.
+type ImportDryRun struct {
+	Aliases   map[string]struct{}
+	Originals map[string]struct{}
 }
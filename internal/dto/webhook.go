@@ -0,0 +1,54 @@
+package dto
+
+import (
+	"encoding/json"
+	"time"
+)
+
+type CreateWebhookRequest struct {
+	URL    string   `json:"url"`
+	Secret string   `json:"secret"`
+	Events []string `json:"events"`
+}
+
+type WebhookResponse struct {
+	ID        int64     `json:"id"`
+	URL       string    `json:"url"`
+	Secret    string    `json:"secret,omitempty"`
+	Events    []string  `json:"events"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type GetWebhookRequest struct {
+	ID int64 `json:"id"`
+}
+
+type ListWebhooksResponse struct {
+	Webhooks []*WebhookResponse `json:"webhooks"`
+}
+
+type DeleteWebhookRequest struct {
+	ID int64 `json:"id"`
+}
+
+type ListDeliveriesRequest struct {
+	WebhookID int64 `json:"webhook_id"`
+	Limit     int   `json:"limit"`
+}
+
+type DeliveryResponse struct {
+	ID             int64           `json:"id"`
+	Event          string          `json:"event"`
+	Payload        json.RawMessage `json:"payload"`
+	Status         string          `json:"status"`
+	Attempts       int             `json:"attempts"`
+	NextAttemptAt  time.Time       `json:"next_attempt_at"`
+	LastError      string          `json:"last_error"`
+	ResponseStatus int             `json:"response_status"`
+	CreatedAt      time.Time       `json:"created_at"`
+	DeliveredAt    *time.Time      `json:"delivered_at"`
+}
+
+type ListDeliveriesResponse struct {
+	Deliveries []*DeliveryResponse `json:"deliveries"`
+}
@@ -0,0 +1,320 @@
+// Package blocklist filters generated aliases containing offensive words
+// or starting with reserved route prefixes.
+package blocklist
+
+import (
+	"bufio"
+	"context"
+	_ "embed"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// MaxAttempts limits generating another alias when the previous one is blocked.
+const MaxAttempts = 100
+
+//go:embed default.txt
+var defaultList string
+
+var ErrBlocked = errors.New("only blocked aliases were generated")
+
+// Generator generates aliases.
+type Generator interface {
+	Generate(ctx context.Context, original string) (string, error)
+}
+
+// Rules are the words aliases may not contain and the prefixes they may not start with.
+type Rules struct {
+	words    []string
+	prefixes []string
+}
+
+// Parse reads the rules, one per line. Lines starting with ^ are reserved prefixes,
+// other lines are denied words. Empty lines and lines starting with # are skipped.
+func Parse(r io.Reader) (*Rules, error) {
+	rules := &Rules{}
+
+	sc := bufio.NewScanner(r)
+	for sc.Scan() {
+		line := strings.TrimSpace(sc.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		if prefix, ok := strings.CutPrefix(line, "^"); ok {
+			if prefix = strings.ToLower(strings.TrimSpace(prefix)); prefix != "" {
+				rules.prefixes = append(rules.prefixes, prefix)
+			}
+
+			continue
+		}
+
+		if word := normalize(line); word != "" {
+			rules.words = append(rules.words, word)
+		}
+	}
+
+	if err := sc.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read blocklist: %w", err)
+	}
+
+	return rules, nil
+}
+
+// Default returns the embedded rules.
+func Default() *Rules {
+	rules, err := Parse(strings.NewReader(defaultList))
+	if err != nil {
+		panic(err)
+	}
+
+	return rules
+}
+
+// WithPrefixes returns the rules with the reserved prefixes added.
+func (r *Rules) WithPrefixes(prefixes ...string) *Rules {
+	res := &Rules{
+		words:    r.words,
+		prefixes: append([]string(nil), r.prefixes...),
+	}
+
+	for _, prefix := range prefixes {
+		if prefix = strings.ToLower(strings.TrimSpace(prefix)); prefix != "" {
+			res.prefixes = append(res.prefixes, prefix)
+		}
+	}
+
+	return res
+}
+
+// Words returns the denied words normalized to lowercase letters.
+func (r *Rules) Words() []string {
+	return slices.Clone(r.words)
+}
+
+// Blocked reports whether alias is blocked. Words match case-insensitively anywhere
+// in the alias, ignoring separators and leetspeak substitutions. Prefixes match
+// case-insensitively at the start of the alias.
+func (r *Rules) Blocked(alias string) bool {
+	lower := strings.ToLower(alias)
+
+	for _, prefix := range r.prefixes {
+		if strings.HasPrefix(lower, prefix) {
+			return true
+		}
+	}
+
+	normalized := normalize(alias)
+
+	for _, word := range r.words {
+		if strings.Contains(normalized, word) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// leet maps the characters used in leetspeak to the letters they stand for.
+// The letters easily confused with others are mapped to one of them.
+var leet = map[rune]rune{
+	'0': 'o',
+	'1': 'i',
+	'l': 'i',
+	'!': 'i',
+	'|': 'i',
+	'3': 'e',
+	'4': 'a',
+	'@': 'a',
+	'5': 's',
+	'$': 's',
+	'7': 't',
+	'+': 't',
+	'8': 'b',
+	'9': 'g',
+}
+
+// normalize lowers s, undoes leetspeak and drops everything but letters.
+func normalize(s string) string {
+	var b strings.Builder
+
+	for _, r := range strings.ToLower(s) {
+		if l, ok := leet[r]; ok {
+			r = l
+		}
+
+		if r >= 'a' && r <= 'z' {
+			b.WriteRune(r)
+		}
+	}
+
+	return b.String()
+}
+
+// List holds the rules reloading them when their file changes.
+type List struct {
+	rules atomic.Pointer[Rules]
+
+	// path is the file the rules are reloaded from, empty when they're fixed
+	path     string
+	prefixes []string
+	interval time.Duration
+	modTime  time.Time
+	size     int64
+
+	stop     chan struct{}
+	done     chan struct{}
+	stopOnce sync.Once
+
+	log *slog.Logger
+}
+
+// NewList returns a list of fixed rules.
+func NewList(rules *Rules, log *slog.Logger) *List {
+	l := &List{
+		stop: make(chan struct{}),
+		done: make(chan struct{}),
+
+		log: log.With(slog.String("component", "generation/blocklist")),
+	}
+
+	l.rules.Store(rules)
+
+	return l
+}
+
+// OpenList returns a list of the rules read from the file at path with the reserved prefixes added.
+// The file is checked for changes every interval once the list is started.
+func OpenList(path string, prefixes []string, interval time.Duration, log *slog.Logger) (*List, error) {
+	l := NewList(&Rules{}, log)
+	l.path = path
+	l.prefixes = prefixes
+	l.interval = interval
+
+	_, err := l.reload()
+	if err != nil {
+		return nil, err
+	}
+
+	return l, nil
+}
+
+// Rules returns the current rules.
+func (l *List) Rules() *Rules {
+	return l.rules.Load()
+}
+
+// Start reloads the rules in the background when the file changes
+// until Stop is called or ctx is done.
+func (l *List) Start(ctx context.Context) {
+	if l.path == "" {
+		close(l.done)
+		return
+	}
+
+	go l.run(ctx)
+}
+
+// Stop stops reloading the rules.
+func (l *List) Stop() {
+	l.stopOnce.Do(func() {
+		close(l.stop)
+	})
+
+	<-l.done
+}
+
+func (l *List) run(ctx context.Context) {
+	defer close(l.done)
+
+	ticker := time.NewTicker(l.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-l.stop:
+			return
+		case <-ticker.C:
+		}
+
+		reloaded, err := l.reload()
+		if err != nil {
+			// the previous rules stay in effect
+			l.log.Error("failed to reload blocklist", slog.String("error", err.Error()))
+			continue
+		}
+
+		if reloaded {
+			l.log.Info("blocklist reloaded", slog.String("path", l.path))
+		}
+	}
+}
+
+// reload reads the file when it has changed since the last read.
+func (l *List) reload() (bool, error) {
+	info, err := os.Stat(l.path)
+	if err != nil {
+		return false, fmt.Errorf("failed to stat blocklist: %w", err)
+	}
+
+	if info.ModTime().Equal(l.modTime) && info.Size() == l.size {
+		return false, nil
+	}
+
+	file, err := os.Open(l.path)
+	if err != nil {
+		return false, fmt.Errorf("failed to open blocklist: %w", err)
+	}
+	defer file.Close()
+
+	rules, err := Parse(file)
+	if err != nil {
+		return false, err
+	}
+
+	l.rules.Store(rules.WithPrefixes(l.prefixes...))
+	l.modTime = info.ModTime()
+	l.size = info.Size()
+
+	return true, nil
+}
+
+// Filter implements url.AliasProvider regenerating the aliases the list blocks.
+type Filter struct {
+	gen  Generator
+	list *List
+}
+
+func NewFilter(gen Generator, list *List) *Filter {
+	return &Filter{
+		gen:  gen,
+		list: list,
+	}
+}
+
+func (f *Filter) Generate(ctx context.Context, original string) (string, error) {
+	rules := f.list.Rules()
+
+	for range MaxAttempts {
+		alias, err := f.gen.Generate(ctx, original)
+		if err != nil {
+			return "", err
+		}
+
+		if !rules.Blocked(alias) {
+			return alias, nil
+		}
+	}
+
+	return "", ErrBlocked
+}
@@ -0,0 +1,149 @@
+package blocklist_test
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kodeyeen/shortify/internal/generation/blocklist"
+	"github.com/stretchr/testify/require"
+)
+
+type sliceGenerator struct {
+	aliases []string
+}
+
+func (g *sliceGenerator) Generate(ctx context.Context, original string) (string, error) {
+	alias := g.aliases[0]
+	g.aliases = append(g.aliases[1:], alias)
+
+	return alias, nil
+}
+
+func TestRules_Blocked(t *testing.T) {
+	rules, err := blocklist.Parse(strings.NewReader(`
+# offensive words
+badword
+Sex
+
+# reserved routes
+^api
+^Swagger
+`))
+	require.NoError(t, err)
+
+	testCases := map[string]struct {
+		alias    string
+		expected bool
+	}{
+		"Clean":              {alias: "xK3pQ9vL2m", expected: false},
+		"Word":               {alias: "xxbadwordx", expected: true},
+		"Upper case":         {alias: "xxBADWORDx", expected: true},
+		"Leetspeak":          {alias: "b4dw0rd123", expected: true},
+		"Separated":          {alias: "s_e_x", expected: true},
+		"Leetspeak digits":   {alias: "x5ex", expected: true},
+		"Prefix":             {alias: "api1234567", expected: true},
+		"Prefix upper case":  {alias: "SWAGGERabc", expected: true},
+		"Prefix inside":      {alias: "xapi123456", expected: false},
+		"Word split by case": {alias: "BadWord", expected: true},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			require.Equal(t, tc.expected, rules.Blocked(tc.alias))
+		})
+	}
+}
+
+func TestDefault(t *testing.T) {
+	rules := blocklist.Default().WithPrefixes("api")
+
+	require.True(t, rules.Blocked("xxsh1txx"))
+	require.True(t, rules.Blocked("api"))
+	require.False(t, rules.Blocked("brave-orange-tiger"))
+	require.Contains(t, rules.Words(), "shit")
+}
+
+func TestFilter_Generate(t *testing.T) {
+	testCases := map[string]struct {
+		aliases []string
+
+		expectedAlias string
+		expectedErr   error
+	}{
+		"Blocked aliases are regenerated": {
+			aliases:       []string{"xxbadword", "api123", "clean"},
+			expectedAlias: "clean",
+		},
+		"Only blocked aliases": {
+			aliases:     []string{"xxbadword", "api123"},
+			expectedErr: blocklist.ErrBlocked,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			// Given
+			rules, err := blocklist.Parse(strings.NewReader("badword\n^api"))
+			require.NoError(t, err)
+
+			log := slog.New(slog.NewTextHandler(io.Discard, nil))
+			filter := blocklist.NewFilter(&sliceGenerator{aliases: tc.aliases}, blocklist.NewList(rules, log))
+
+			// When
+			alias, err := filter.Generate(context.Background(), "https://example.com")
+
+			// Then
+			require.ErrorIs(t, err, tc.expectedErr)
+			require.Equal(t, tc.expectedAlias, alias)
+		})
+	}
+}
+
+func TestList_Reload(t *testing.T) {
+	// Given
+	path := filepath.Join(t.TempDir(), "blocklist.txt")
+	require.NoError(t, os.WriteFile(path, []byte("badword\n"), 0o600))
+
+	log := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	list, err := blocklist.OpenList(path, []string{"api"}, time.Millisecond, log)
+	require.NoError(t, err)
+
+	list.Start(context.Background())
+	t.Cleanup(list.Stop)
+
+	require.True(t, list.Rules().Blocked("badword"))
+	require.True(t, list.Rules().Blocked("api123"))
+	require.False(t, list.Rules().Blocked("newword"))
+
+	// When
+	require.NoError(t, os.WriteFile(path, []byte("newword\n"), 0o600))
+	require.NoError(t, os.Chtimes(path, time.Now(), time.Now().Add(time.Hour)))
+
+	// Then
+	require.Eventually(t, func() bool { return list.Rules().Blocked("newword") }, time.Second, time.Millisecond)
+	require.False(t, list.Rules().Blocked("badword"))
+	require.True(t, list.Rules().Blocked("api123"))
+
+	// invalid updates keep the previous rules
+	require.NoError(t, os.Remove(path))
+	time.Sleep(10 * time.Millisecond)
+	require.True(t, list.Rules().Blocked("newword"))
+}
+
+func TestOpenList_Missing(t *testing.T) {
+	log := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	_, err := blocklist.OpenList(filepath.Join(t.TempDir(), "missing.txt"), nil, time.Second, log)
+	require.Error(t, err)
+}
@@ -0,0 +1,173 @@
+// Package keyspace estimates how many aliases a provider can generate
+// and how likely new aliases are to collide with the stored ones.
+package keyspace
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"strings"
+)
+
+var (
+	ErrEmptyCharset  = errors.New("alias charset is empty")
+	ErrInvalidLength = errors.New("alias length must be positive")
+)
+
+// unreserved are the characters RFC 3986 allows in URLs without escaping.
+const unreserved = "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789-._~"
+
+// ambiguous are the characters easily confused with others when a link
+// is read aloud or retyped. The underscore hides under link underlines.
+const ambiguous = "0Oo1lI_"
+
+// Validate checks that aliases can be generated from charset and length.
+func Validate(charset string, length int) error {
+	if charset == "" {
+		return ErrEmptyCharset
+	}
+
+	if length <= 0 {
+		return ErrInvalidLength
+	}
+
+	return nil
+}
+
+// Check returns the problems of charset that don't prevent generating aliases.
+func Check(charset string) []string {
+	var unsafe, confusing, duplicates []rune
+
+	seen := map[rune]bool{}
+
+	for _, r := range charset {
+		if seen[r] {
+			duplicates = appendUnique(duplicates, r)
+			continue
+		}
+
+		seen[r] = true
+
+		if !strings.ContainsRune(unreserved, r) {
+			unsafe = append(unsafe, r)
+		}
+
+		if strings.ContainsRune(ambiguous, r) {
+			confusing = append(confusing, r)
+		}
+	}
+
+	var warnings []string
+
+	if len(unsafe) > 0 {
+		warnings = append(warnings, fmt.Sprintf("alias charset contains URL-unsafe characters %q", string(unsafe)))
+	}
+
+	if len(confusing) > 0 {
+		warnings = append(warnings, fmt.Sprintf("alias charset contains ambiguous characters %q", string(confusing)))
+	}
+
+	if len(duplicates) > 0 {
+		warnings = append(warnings, fmt.Sprintf("alias charset contains duplicate characters %q", string(duplicates)))
+	}
+
+	return warnings
+}
+
+// Fold returns charset with upper case letters turned into lower case ones
+// and duplicates dropped, so aliases generated from it have a single case.
+func Fold(charset string) string {
+	return dedup(strings.ToLower(charset))
+}
+
+// Unambiguous returns charset without the characters easily confused with others.
+func Unambiguous(charset string) string {
+	return strings.Map(func(r rune) rune {
+		if strings.ContainsRune(ambiguous, r) {
+			return -1
+		}
+
+		return r
+	}, charset)
+}
+
+// Size returns the number of distinct aliases of length characters of charset.
+func Size(charset string, length int) *big.Int {
+	distinct := map[rune]bool{}
+	for _, r := range charset {
+		distinct[r] = true
+	}
+
+	return new(big.Int).Exp(big.NewInt(int64(len(distinct))), big.NewInt(int64(length)), nil)
+}
+
+// Capacity describes how much of a keyspace is occupied.
+type Capacity struct {
+	// Size is the number of distinct aliases.
+	Size *big.Int
+	// Bits is the binary logarithm of Size.
+	Bits float64
+	// Occupied is the number of stored aliases, it may include aliases outside the keyspace.
+	Occupied int64
+	// Occupancy is the share of the keyspace occupied.
+	Occupancy float64
+	// CollisionProbability is the chance a random alias is taken.
+	CollisionProbability float64
+	// FailureProbability is the chance all of attempts random aliases are taken.
+	FailureProbability float64
+}
+
+// Estimate estimates the capacity of a keyspace of size with occupied stored aliases
+// generating up to attempts aliases per URL.
+func Estimate(size *big.Int, occupied int64, attempts int) Capacity {
+	c := Capacity{
+		Size:     size,
+		Bits:     bits(size),
+		Occupied: occupied,
+	}
+
+	if size.Sign() > 0 {
+		c.Occupancy, _ = new(big.Rat).SetFrac(big.NewInt(occupied), size).Float64()
+	}
+
+	// every stored URL is counted, including the aliases outside the keyspace
+	c.Occupancy = min(c.Occupancy, 1)
+	c.CollisionProbability = c.Occupancy
+	c.FailureProbability = math.Pow(c.Occupancy, float64(attempts))
+
+	return c
+}
+
+func bits(size *big.Int) float64 {
+	if size.Sign() <= 0 {
+		return 0
+	}
+
+	// the size may overflow float64, so the exponent is taken separately
+	mant := new(big.Float).SetInt(size)
+	exp := mant.MantExp(mant)
+	f, _ := mant.Float64()
+
+	return math.Log2(f) + float64(exp)
+}
+
+func dedup(s string) string {
+	var b strings.Builder
+
+	for _, r := range s {
+		if !strings.ContainsRune(b.String(), r) {
+			b.WriteRune(r)
+		}
+	}
+
+	return b.String()
+}
+
+func appendUnique(rs []rune, r rune) []rune {
+	if strings.ContainsRune(string(rs), r) {
+		return rs
+	}
+
+	return append(rs, r)
+}
@@ -0,0 +1,176 @@
+package keyspace_test
+
+import (
+	"math"
+	"math/big"
+	"testing"
+
+	"github.com/kodeyeen/shortify/internal/generation/keyspace"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidate(t *testing.T) {
+	testCases := map[string]struct {
+		charset string
+		length  int
+
+		expectedErr error
+	}{
+		"Valid": {
+			charset: "abc",
+			length:  5,
+		},
+		"Empty charset": {
+			charset:     "",
+			length:      5,
+			expectedErr: keyspace.ErrEmptyCharset,
+		},
+		"Zero length": {
+			charset:     "abc",
+			length:      0,
+			expectedErr: keyspace.ErrInvalidLength,
+		},
+		"Negative length": {
+			charset:     "abc",
+			length:      -1,
+			expectedErr: keyspace.ErrInvalidLength,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			// When
+			err := keyspace.Validate(tc.charset, tc.length)
+
+			// Then
+			require.ErrorIs(t, err, tc.expectedErr)
+		})
+	}
+}
+
+func TestCheck(t *testing.T) {
+	testCases := map[string]struct {
+		charset string
+
+		expected []string
+	}{
+		"Clean": {
+			charset: "abcdefghjkmnpqrstuvwxyz23456789",
+		},
+		"Default charset": {
+			charset: "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789_",
+			expected: []string{
+				`alias charset contains ambiguous characters "loIO01_"`,
+			},
+		},
+		"Unsafe and duplicate": {
+			charset: "ab/c?a/",
+			expected: []string{
+				`alias charset contains URL-unsafe characters "/?"`,
+				`alias charset contains duplicate characters "a/"`,
+			},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			// When
+			warnings := keyspace.Check(tc.charset)
+
+			// Then
+			require.Equal(t, tc.expected, warnings)
+		})
+	}
+}
+
+func TestFold(t *testing.T) {
+	require.Equal(t, "abc12_", keyspace.Fold("aBcABC12_"))
+}
+
+func TestUnambiguous(t *testing.T) {
+	require.Equal(t, "abcdefghijkmnpqrstuvwxyzABCDEFGHJKLMNPQRSTUVWXYZ23456789",
+		keyspace.Unambiguous("abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789_"))
+	require.Empty(t, keyspace.Check(keyspace.Unambiguous("abcdefghijklmnopqrstuvwxyz0123456789_")))
+}
+
+func TestSize(t *testing.T) {
+	require.Equal(t, big.NewInt(1000), keyspace.Size("0123456789", 3))
+	require.Equal(t, big.NewInt(8), keyspace.Size("abba", 3))
+
+	expected, ok := new(big.Int).SetString("984930291881790849", 10)
+	require.True(t, ok)
+	require.Equal(t, expected, keyspace.Size("abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789_", 10))
+}
+
+func TestEstimate(t *testing.T) {
+	testCases := map[string]struct {
+		size     *big.Int
+		occupied int64
+
+		expected keyspace.Capacity
+	}{
+		"Half occupied": {
+			size:     big.NewInt(1000),
+			occupied: 500,
+			expected: keyspace.Capacity{
+				Size:                 big.NewInt(1000),
+				Bits:                 math.Log2(1000),
+				Occupied:             500,
+				Occupancy:            0.5,
+				CollisionProbability: 0.5,
+				FailureProbability:   0.25,
+			},
+		},
+		"Empty": {
+			size:     big.NewInt(1000),
+			occupied: 0,
+			expected: keyspace.Capacity{
+				Size: big.NewInt(1000),
+				Bits: math.Log2(1000),
+			},
+		},
+		"More URLs than aliases": {
+			size:     big.NewInt(10),
+			occupied: 20,
+			expected: keyspace.Capacity{
+				Size:                 big.NewInt(10),
+				Bits:                 math.Log2(10),
+				Occupied:             20,
+				Occupancy:            1,
+				CollisionProbability: 1,
+				FailureProbability:   1,
+			},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			// When
+			c := keyspace.Estimate(tc.size, tc.occupied, 2)
+
+			// Then
+			require.InDelta(t, tc.expected.Bits, c.Bits, 1e-9)
+
+			c.Bits = tc.expected.Bits
+			require.Equal(t, tc.expected, c)
+		})
+	}
+}
+
+func TestEstimate_HugeKeyspace(t *testing.T) {
+	// Given
+	size := new(big.Int).Lsh(big.NewInt(1), 2000)
+
+	// When
+	c := keyspace.Estimate(size, 1, 10)
+
+	// Then
+	require.InDelta(t, 2000, c.Bits, 1e-9)
+	require.Zero(t, c.FailureProbability)
+}
@@ -0,0 +1,254 @@
+// Package pool hands out aliases reserved in advance so creating
+// a URL doesn't wait for alias generation and collision checks.
+package pool
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// Generator generates alias candidates.
+type Generator interface {
+	Generate(ctx context.Context, original string) (string, error)
+}
+
+// Store keeps the reservations of the pooled aliases.
+type Store interface {
+	// Reserve reserves the aliases that are neither used by URLs nor
+	// reserved already, and returns them. Reservations made before
+	// expiredBefore are dropped.
+	Reserve(ctx context.Context, aliases []string, expiredBefore time.Time) ([]string, error)
+	// Release drops the reservations of the aliases.
+	Release(ctx context.Context, aliases []string) error
+}
+
+type Config struct {
+	// Size is the number of aliases kept reserved.
+	Size int
+	// LowWater is the number of aliases below which the pool is refilled right away.
+	LowWater int
+	// RefillInterval is the period of refills when the pool isn't running low.
+	RefillInterval time.Duration
+	// TTL is how long a reservation lives. Older aliases aren't handed out
+	// since their reservations may be dropped by another replica.
+	TTL time.Duration
+	// Timeout limits a single refill.
+	Timeout time.Duration
+}
+
+type reserved struct {
+	alias string
+	at    time.Time
+}
+
+// Pool implements url.AliasProvider. It falls back to the generator
+// when it runs out of aliases, leaving collisions to the caller.
+type Pool struct {
+	gen   Generator
+	store Store
+	cfg   Config
+
+	aliases chan reserved
+	refill  chan struct{}
+
+	// handedOut are the aliases whose reservations are released on the next refill,
+	// by then the URLs are stored and the aliases can't be reserved again
+	handedOut []string
+	mu        sync.Mutex
+
+	stop     chan struct{}
+	done     chan struct{}
+	stopOnce sync.Once
+
+	now func() time.Time
+	log *slog.Logger
+}
+
+func New(gen Generator, store Store, cfg Config, log *slog.Logger) *Pool {
+	return &Pool{
+		gen:   gen,
+		store: store,
+		cfg:   cfg,
+
+		aliases: make(chan reserved, cfg.Size),
+		refill:  make(chan struct{}, 1),
+
+		stop: make(chan struct{}),
+		done: make(chan struct{}),
+
+		now: time.Now,
+		log: log.With(slog.String("component", "generation/pool")),
+	}
+}
+
+// Start fills the pool in the background until Stop is called or ctx is done.
+func (p *Pool) Start(ctx context.Context) {
+	go p.run(ctx)
+}
+
+// Stop stops refilling and releases the reservations of the aliases
+// left in the pool. Calling it again has no effect.
+func (p *Pool) Stop(ctx context.Context) {
+	p.stopOnce.Do(func() {
+		close(p.stop)
+	})
+
+	<-p.done
+
+	var unused []string
+
+drain:
+	for {
+		select {
+		case r := <-p.aliases:
+			unused = append(unused, r.alias)
+		default:
+			break drain
+		}
+	}
+
+	p.mu.Lock()
+	unused = append(unused, p.handedOut...)
+	p.handedOut = nil
+	p.mu.Unlock()
+
+	if len(unused) == 0 {
+		return
+	}
+
+	err := p.store.Release(ctx, unused)
+	if err != nil {
+		p.log.Error("failed to release reserved aliases", slog.String("error", err.Error()))
+	}
+}
+
+// Generate returns a reserved alias.
+func (p *Pool) Generate(ctx context.Context, original string) (string, error) {
+	defer p.requestRefill()
+
+	for {
+		select {
+		case r := <-p.aliases:
+			p.mu.Lock()
+			p.handedOut = append(p.handedOut, r.alias)
+			p.mu.Unlock()
+
+			if p.now().Sub(r.at) >= p.cfg.TTL {
+				continue
+			}
+
+			return r.alias, nil
+		default:
+			p.log.Warn("alias pool is empty, generating alias inline")
+
+			return p.gen.Generate(ctx, original)
+		}
+	}
+}
+
+// Len returns the number of aliases in the pool.
+func (p *Pool) Len() int {
+	return len(p.aliases)
+}
+
+func (p *Pool) requestRefill() {
+	if len(p.aliases) >= p.cfg.LowWater {
+		return
+	}
+
+	select {
+	case p.refill <- struct{}{}:
+	default:
+	}
+}
+
+func (p *Pool) run(ctx context.Context) {
+	defer close(p.done)
+
+	ticker := time.NewTicker(p.cfg.RefillInterval)
+	defer ticker.Stop()
+
+	for {
+		p.fill(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-p.stop:
+			return
+		case <-ticker.C:
+		case <-p.refill:
+		}
+	}
+}
+
+// fill tops the pool up to its size and releases the handed out aliases.
+func (p *Pool) fill(ctx context.Context) {
+	ctx, cancel := context.WithTimeout(ctx, p.cfg.Timeout)
+	defer cancel()
+
+	p.mu.Lock()
+	handedOut := p.handedOut
+	p.handedOut = nil
+	p.mu.Unlock()
+
+	if len(handedOut) > 0 {
+		err := p.store.Release(ctx, handedOut)
+		if err != nil {
+			p.log.Error("failed to release handed out aliases", slog.String("error", err.Error()))
+
+			p.mu.Lock()
+			p.handedOut = append(p.handedOut, handedOut...)
+			p.mu.Unlock()
+		}
+	}
+
+	// a few rounds make up for candidates that turn out to be taken
+	for range 3 {
+		missing := p.cfg.Size - len(p.aliases)
+		if missing <= 0 {
+			return
+		}
+
+		candidates, err := p.candidates(ctx, missing)
+		if err != nil {
+			p.log.Error("failed to generate aliases", slog.String("error", err.Error()))
+			return
+		}
+
+		now := p.now()
+
+		aliases, err := p.store.Reserve(ctx, candidates, now.Add(-p.cfg.TTL))
+		if err != nil {
+			p.log.Error("failed to reserve aliases", slog.String("error", err.Error()))
+			return
+		}
+
+		for _, alias := range aliases {
+			p.aliases <- reserved{alias: alias, at: now}
+		}
+	}
+}
+
+func (p *Pool) candidates(ctx context.Context, n int) ([]string, error) {
+	seen := make(map[string]struct{}, n)
+	res := make([]string, 0, n)
+
+	for range n {
+		alias, err := p.gen.Generate(ctx, "")
+		if err != nil {
+			return nil, err
+		}
+
+		if _, ok := seen[alias]; ok {
+			continue
+		}
+
+		seen[alias] = struct{}{}
+		res = append(res, alias)
+	}
+
+	return res, nil
+}
@@ -0,0 +1,162 @@
+package pool_test
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/kodeyeen/shortify/internal/domain"
+	"github.com/kodeyeen/shortify/internal/generation/pool"
+	"github.com/kodeyeen/shortify/internal/persistence/inmemory"
+	"github.com/stretchr/testify/require"
+)
+
+type countingGenerator struct {
+	n atomic.Int64
+}
+
+func (g *countingGenerator) Generate(ctx context.Context, original string) (string, error) {
+	return fmt.Sprintf("a%d", g.n.Add(1)), nil
+}
+
+func newPool(t *testing.T, urls pool.AliasFinder, cfg pool.Config) (*pool.Pool, *pool.MemoryStore) {
+	t.Helper()
+
+	store := pool.NewMemoryStore(urls)
+	log := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	return pool.New(&countingGenerator{}, store, cfg, log), store
+}
+
+func testConfig() pool.Config {
+	return pool.Config{
+		Size:           10,
+		LowWater:       0,
+		RefillInterval: time.Hour,
+		TTL:            time.Hour,
+		Timeout:        time.Second,
+	}
+}
+
+func TestPool_Generate(t *testing.T) {
+	ctx := context.Background()
+
+	// Given
+	urls := inmemory.NewURLRepository()
+	_, err := urls.Add(ctx, &domain.URL{Original: "https://example.com", Alias: "a1"})
+	require.NoError(t, err)
+
+	p, store := newPool(t, urls, testConfig())
+
+	p.Start(ctx)
+	t.Cleanup(func() { p.Stop(ctx) })
+
+	require.Eventually(t, func() bool { return p.Len() == 10 }, time.Second, time.Millisecond)
+
+	// When
+	seen := map[string]bool{}
+
+	for range 10 {
+		alias, err := p.Generate(ctx, "https://example.org")
+		require.NoError(t, err)
+
+		// Then
+		require.NotEqual(t, "a1", alias)
+		require.False(t, seen[alias], "duplicate alias %q", alias)
+
+		seen[alias] = true
+	}
+
+	require.Equal(t, 0, p.Len())
+	require.Equal(t, 10, store.Len())
+}
+
+func TestPool_Generate_Empty(t *testing.T) {
+	ctx := context.Background()
+
+	// Given
+	p, _ := newPool(t, inmemory.NewURLRepository(), testConfig())
+
+	// When
+	alias, err := p.Generate(ctx, "https://example.com")
+
+	// Then
+	require.NoError(t, err)
+	require.Equal(t, "a1", alias)
+}
+
+func TestPool_Generate_Expired(t *testing.T) {
+	ctx := context.Background()
+
+	// Given
+	cfg := testConfig()
+	cfg.TTL = time.Nanosecond
+
+	p, _ := newPool(t, inmemory.NewURLRepository(), cfg)
+
+	p.Start(ctx)
+	t.Cleanup(func() { p.Stop(ctx) })
+
+	require.Eventually(t, func() bool { return p.Len() == 10 }, time.Second, time.Millisecond)
+
+	// When
+	alias, err := p.Generate(ctx, "https://example.com")
+
+	// Then
+	require.NoError(t, err)
+	require.Equal(t, "a11", alias)
+	require.Equal(t, 0, p.Len())
+}
+
+func TestPool_Stop(t *testing.T) {
+	ctx := context.Background()
+
+	// Given
+	p, store := newPool(t, inmemory.NewURLRepository(), testConfig())
+
+	p.Start(ctx)
+	require.Eventually(t, func() bool { return p.Len() == 10 }, time.Second, time.Millisecond)
+
+	_, err := p.Generate(ctx, "https://example.com")
+	require.NoError(t, err)
+
+	// When
+	p.Stop(ctx)
+
+	// Then
+	require.Equal(t, 0, store.Len())
+
+	// stopping again doesn't panic
+	require.NotPanics(t, func() { p.Stop(ctx) })
+}
+
+func TestPool_Refill(t *testing.T) {
+	ctx := context.Background()
+
+	// Given
+	cfg := testConfig()
+	cfg.LowWater = 5
+
+	p, store := newPool(t, inmemory.NewURLRepository(), cfg)
+
+	p.Start(ctx)
+	t.Cleanup(func() { p.Stop(ctx) })
+
+	require.Eventually(t, func() bool { return p.Len() == 10 }, time.Second, time.Millisecond)
+
+	// When
+	for range 6 {
+		_, err := p.Generate(ctx, "https://example.com")
+		require.NoError(t, err)
+	}
+
+	// Then
+	require.Eventually(t, func() bool { return p.Len() == 10 }, time.Second, time.Millisecond)
+
+	// the handed out aliases are released on refill
+	require.Eventually(t, func() bool { return store.Len() == 10 }, time.Second, time.Millisecond)
+}
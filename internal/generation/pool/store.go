@@ -0,0 +1,87 @@
+package pool
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/kodeyeen/shortify/internal/domain"
+	"github.com/kodeyeen/shortify/internal/persistence"
+)
+
+// AliasFinder looks up URLs by alias.
+type AliasFinder interface {
+	FindByAlias(ctx context.Context, alias string) (*domain.URL, error)
+}
+
+// MemoryStore keeps the reservations in memory. It suits a single replica only.
+type MemoryStore struct {
+	urls     AliasFinder
+	reserved map[string]time.Time
+
+	now func() time.Time
+	mu  sync.Mutex
+}
+
+func NewMemoryStore(urls AliasFinder) *MemoryStore {
+	return &MemoryStore{
+		urls:     urls,
+		reserved: map[string]time.Time{},
+
+		now: time.Now,
+	}
+}
+
+func (s *MemoryStore) Reserve(ctx context.Context, aliases []string, expiredBefore time.Time) ([]string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for alias, at := range s.reserved {
+		if at.Before(expiredBefore) {
+			delete(s.reserved, alias)
+		}
+	}
+
+	res := make([]string, 0, len(aliases))
+	now := s.now()
+
+	for _, alias := range aliases {
+		if _, ok := s.reserved[alias]; ok {
+			continue
+		}
+
+		_, err := s.urls.FindByAlias(ctx, alias)
+		if err == nil {
+			continue
+		}
+
+		if !errors.Is(err, persistence.ErrURLNotFound) {
+			return nil, err
+		}
+
+		s.reserved[alias] = now
+		res = append(res, alias)
+	}
+
+	return res, nil
+}
+
+func (s *MemoryStore) Release(ctx context.Context, aliases []string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, alias := range aliases {
+		delete(s.reserved, alias)
+	}
+
+	return nil
+}
+
+// Len returns the number of reserved aliases.
+func (s *MemoryStore) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return len(s.reserved)
+}
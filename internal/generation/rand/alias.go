@@ -5,6 +5,8 @@ import (
 	"crypto/rand"
 	"fmt"
 	"math/big"
+
+	"github.com/kodeyeen/shortify/internal/generation/keyspace"
 )
 
 type AliasProvider struct {
@@ -20,6 +22,11 @@ func NewAliasProvider(charset string, length int) *AliasProvider {
 }
 
 func (p *AliasProvider) Generate(ctx context.Context, original string) (string, error) {
+	err := keyspace.Validate(p.charset, p.len)
+	if err != nil {
+		return "", err
+	}
+
 	charsetLen := len(p.charset)
 	res := make([]byte, p.len)
 
@@ -0,0 +1,160 @@
+// Package sequence generates aliases from a counter so they are unique by construction.
+package sequence
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"math/bits"
+	"sync/atomic"
+)
+
+// ErrExhausted is returned when the counter runs past the number of possible aliases.
+var ErrExhausted = errors.New("alias space exhausted")
+
+// rounds of the Feistel network, four make a pseudorandom permutation.
+const rounds = 4
+
+// Counter hands out increasing values that are never repeated.
+type Counter interface {
+	Next(ctx context.Context) (uint64, error)
+}
+
+// AliasProvider maps counter values to aliases of the fixed length through
+// a keyed permutation of all the possible aliases. Consecutive values give
+// unrelated aliases so they can't be enumerated without the key, which is
+// obfuscation rather than encryption.
+type AliasProvider struct {
+	counter Counter
+	charset string
+	len     int
+	perm    permutation
+}
+
+func NewAliasProvider(counter Counter, charset string, length int, key uint64) *AliasProvider {
+	return &AliasProvider{
+		counter: counter,
+		charset: charset,
+		len:     length,
+		perm:    newPermutation(capacity(len(charset), length), key),
+	}
+}
+
+func (p *AliasProvider) Generate(ctx context.Context, original string) (string, error) {
+	n, err := p.counter.Next(ctx)
+	if err != nil {
+		return "", fmt.Errorf("failed to get next counter value: %w", err)
+	}
+
+	if n >= p.perm.n {
+		return "", ErrExhausted
+	}
+
+	return p.encode(p.perm.apply(n)), nil
+}
+
+// encode writes n in the base of the charset padded to the alias length.
+func (p *AliasProvider) encode(n uint64) string {
+	base := uint64(len(p.charset))
+	res := make([]byte, p.len)
+
+	for i := p.len - 1; i >= 0; i-- {
+		res[i] = p.charset[n%base]
+		n /= base
+	}
+
+	return string(res)
+}
+
+// AtomicCounter is an in-memory Counter, it starts over on restart.
+type AtomicCounter struct {
+	last atomic.Uint64
+}
+
+// NewAtomicCounter returns a counter whose first value follows start.
+func NewAtomicCounter(start uint64) *AtomicCounter {
+	c := &AtomicCounter{}
+	c.last.Store(start)
+
+	return c
+}
+
+func (c *AtomicCounter) Next(ctx context.Context) (uint64, error) {
+	return c.last.Add(1), nil
+}
+
+// capacity returns the number of aliases capped at the uint64 range.
+func capacity(base, length int) uint64 {
+	n := uint64(1)
+
+	for range length {
+		hi, lo := bits.Mul64(n, uint64(base))
+		if hi != 0 {
+			return math.MaxUint64
+		}
+
+		n = lo
+	}
+
+	return n
+}
+
+// permutation is a bijection of [0, n) made of a balanced Feistel network
+// over the smallest even number of bits covering n and cycle walking
+// until the result falls into the range.
+type permutation struct {
+	n    uint64
+	half uint
+	mask uint64
+	keys [rounds]uint64
+}
+
+func newPermutation(n uint64, key uint64) permutation {
+	width := uint(max(bits.Len64(n-1), 2))
+	width += width % 2
+
+	p := permutation{
+		n:    n,
+		half: width / 2,
+		mask: 1<<(width/2) - 1,
+	}
+
+	for i := range p.keys {
+		p.keys[i] = mix(key + uint64(i)*0x9e3779b97f4a7c15)
+	}
+
+	return p
+}
+
+func (p permutation) apply(x uint64) uint64 {
+	// the network permutes a range at most 4 times larger than n,
+	// so walking takes a few steps on average
+	for {
+		x = p.feistel(x)
+		if x < p.n {
+			return x
+		}
+	}
+}
+
+func (p permutation) feistel(x uint64) uint64 {
+	l, r := x>>p.half, x&p.mask
+
+	for _, k := range p.keys {
+		l, r = r, l^(mix(r^k)&p.mask)
+	}
+
+	return l<<p.half | r
+}
+
+// mix is the SplitMix64 finalizer.
+func mix(x uint64) uint64 {
+	x ^= x >> 30
+	x *= 0xbf58476d1ce4e5b9
+	x ^= x >> 27
+	x *= 0x94d049bb133111eb
+	x ^= x >> 31
+
+	return x
+}
@@ -0,0 +1,117 @@
+package sequence_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/kodeyeen/shortify/internal/generation/sequence"
+	"github.com/stretchr/testify/require"
+)
+
+type failingCounter struct{}
+
+func (failingCounter) Next(ctx context.Context) (uint64, error) {
+	return 0, errors.New("counter failed")
+}
+
+func TestAliasProvider_Generate_Unique(t *testing.T) {
+	testCases := map[string]struct {
+		charset string
+		length  int
+	}{
+		"Even number of bits": {
+			charset: "ab",
+			length:  8,
+		},
+		"Odd number of bits": {
+			charset: "abc",
+			length:  5,
+		},
+		"Single character": {
+			charset: "abcdefghij",
+			length:  1,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := context.Background()
+
+			// Given
+			total := 1
+			for range tc.length {
+				total *= len(tc.charset)
+			}
+
+			provider := sequence.NewAliasProvider(sequence.NewAtomicCounter(0), tc.charset, tc.length, 42)
+
+			// When
+			seen := map[string]bool{}
+
+			// the counter starts at 1 so the zero value is never used
+			for range total - 1 {
+				alias, err := provider.Generate(ctx, "")
+				require.NoError(t, err)
+
+				// Then
+				require.Len(t, alias, tc.length)
+				require.Empty(t, strings.Trim(alias, tc.charset))
+				require.False(t, seen[alias], "alias %q generated twice", alias)
+
+				seen[alias] = true
+			}
+
+			_, err := provider.Generate(ctx, "")
+			require.ErrorIs(t, err, sequence.ErrExhausted)
+		})
+	}
+}
+
+func TestAliasProvider_Generate_Key(t *testing.T) {
+	ctx := context.Background()
+	charset := "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789_"
+
+	generate := func(key uint64, start uint64) string {
+		provider := sequence.NewAliasProvider(sequence.NewAtomicCounter(start), charset, 10, key)
+
+		alias, err := provider.Generate(ctx, "")
+		require.NoError(t, err)
+
+		return alias
+	}
+
+	// same key and counter give the same alias
+	require.Equal(t, generate(1, 0), generate(1, 0))
+
+	// another key gives another alias
+	require.NotEqual(t, generate(1, 0), generate(2, 0))
+
+	// consecutive counter values don't give similar aliases
+	first, second := generate(1, 0), generate(1, 1)
+	require.NotEqual(t, first[:9], second[:9])
+	require.NotEqual(t, first[1:], second[1:])
+}
+
+func TestAliasProvider_Generate_LargeSpace(t *testing.T) {
+	ctx := context.Background()
+
+	// 62^20 doesn't fit into uint64
+	provider := sequence.NewAliasProvider(sequence.NewAtomicCounter(0), "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789", 20, 7)
+
+	alias, err := provider.Generate(ctx, "")
+
+	require.NoError(t, err)
+	require.Len(t, alias, 20)
+}
+
+func TestAliasProvider_Generate_CounterError(t *testing.T) {
+	provider := sequence.NewAliasProvider(failingCounter{}, "ab", 8, 1)
+
+	_, err := provider.Generate(context.Background(), "")
+
+	require.Error(t, err)
+}
@@ -0,0 +1,251 @@
+// Package words generates human-readable aliases such as brave-orange-tiger.
+package words
+
+import (
+	"bufio"
+	"context"
+	"crypto/rand"
+	_ "embed"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"slices"
+	"strings"
+
+	"github.com/kodeyeen/shortify/internal/generation/blocklist"
+)
+
+// MaxWords limits the number of words in an alias.
+const MaxWords = 8
+
+// maxAttempts limits generating another alias when the previous one is blocked.
+const maxAttempts = 10
+
+// separators are the characters allowed in a separator, they don't need escaping in URL paths.
+const separators = "-_.~"
+
+var (
+	//go:embed adjectives.txt
+	adjectives string
+	//go:embed nouns.txt
+	nouns string
+)
+
+var ErrBlocked = errors.New("only blocked aliases were generated")
+
+// Dictionary is the source of the alias words. Aliases consist of adjectives
+// followed by a noun.
+type Dictionary struct {
+	Adjectives []string
+	Nouns      []string
+	// Blocklist are the words that never appear in aliases,
+	// neither as dictionary words nor across word boundaries.
+	Blocklist []string
+}
+
+// DefaultDictionary returns the embedded dictionary blocking the words of the default blocklist.
+func DefaultDictionary() Dictionary {
+	return Dictionary{
+		Adjectives: lines(adjectives),
+		Nouns:      lines(nouns),
+		Blocklist:  blocklist.Default().Words(),
+	}
+}
+
+type AliasProvider struct {
+	adjectives []string
+	nouns      []string
+	blocklist  []string
+
+	count     int
+	separator string
+}
+
+// NewAliasProvider returns a provider of aliases made of count words joined by separator.
+// The dictionary words found in the blocklist are dropped.
+func NewAliasProvider(dict Dictionary, count int, separator string) (*AliasProvider, error) {
+	if count < 1 || count > MaxWords {
+		return nil, fmt.Errorf("word count must be between 1 and %d", MaxWords)
+	}
+
+	if strings.Trim(separator, separators) != "" {
+		return nil, fmt.Errorf("separator may only contain %q", separators)
+	}
+
+	blocked := normalize(dict.Blocklist)
+
+	p := &AliasProvider{
+		adjectives: filter(normalize(dict.Adjectives), blocked),
+		nouns:      filter(normalize(dict.Nouns), blocked),
+		blocklist:  blocked,
+
+		count:     count,
+		separator: separator,
+	}
+
+	if len(p.nouns) == 0 || (count > 1 && len(p.adjectives) == 0) {
+		return nil, errors.New("dictionary has no words")
+	}
+
+	return p, nil
+}
+
+func (p *AliasProvider) Generate(ctx context.Context, original string) (string, error) {
+	for range maxAttempts {
+		words := make([]string, p.count)
+
+		for i := range p.count - 1 {
+			word, err := pick(p.adjectives)
+			if err != nil {
+				return "", err
+			}
+
+			words[i] = word
+		}
+
+		word, err := pick(p.nouns)
+		if err != nil {
+			return "", err
+		}
+
+		words[p.count-1] = word
+
+		alias := strings.Join(words, p.separator)
+		if !p.blocked(alias, words) {
+			return alias, nil
+		}
+	}
+
+	return "", ErrBlocked
+}
+
+// Keyspace returns the number of distinct aliases.
+func (p *AliasProvider) Keyspace() *big.Int {
+	res := big.NewInt(int64(len(p.nouns)))
+	adjs := big.NewInt(int64(len(p.adjectives)))
+
+	for range p.count - 1 {
+		res.Mul(res, adjs)
+	}
+
+	return res
+}
+
+// Entropy returns the entropy of an alias in bits.
+func (p *AliasProvider) Entropy() float64 {
+	return float64(p.count-1)*math.Log2(float64(len(p.adjectives))) + math.Log2(float64(len(p.nouns)))
+}
+
+// Matches reports whether alias has the form of the generated aliases: count dictionary words,
+// adjectives followed by a noun, joined by the separator.
+func (p *AliasProvider) Matches(alias string) bool {
+	return p.matches(alias, 0)
+}
+
+// matches reports whether rest is made of the dictionary words starting with the i-th word.
+func (p *AliasProvider) matches(rest string, i int) bool {
+	if i == p.count-1 {
+		return slices.Contains(p.nouns, rest)
+	}
+
+	for _, adj := range p.adjectives {
+		after, ok := strings.CutPrefix(rest, adj+p.separator)
+		if ok && p.matches(after, i+1) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// blocked reports whether a blocked word spans several words of the alias.
+// The words themselves are filtered out of the dictionary already.
+func (p *AliasProvider) blocked(alias string, words []string) bool {
+	if p.count == 1 {
+		return false
+	}
+
+	// ends[i] is the offset of the end of the i-th word
+	ends := make([]int, len(words))
+	end := 0
+
+	for i, word := range words {
+		end += len(word)
+		ends[i] = end
+		end += len(p.separator)
+	}
+
+	for _, b := range p.blocklist {
+		for from := 0; ; {
+			i := strings.Index(alias[from:], b)
+			if i < 0 {
+				break
+			}
+
+			start := from + i
+			if !withinWord(start, start+len(b), ends, len(p.separator)) {
+				return true
+			}
+
+			from = start + 1
+		}
+	}
+
+	return false
+}
+
+func withinWord(start, end int, ends []int, sepLen int) bool {
+	wordStart := 0
+
+	for _, wordEnd := range ends {
+		if start >= wordStart && end <= wordEnd {
+			return true
+		}
+
+		wordStart = wordEnd + sepLen
+	}
+
+	return false
+}
+
+func pick(words []string) (string, error) {
+	num, err := rand.Int(rand.Reader, big.NewInt(int64(len(words))))
+	if err != nil {
+		return "", fmt.Errorf("failed to pick random word: %w", err)
+	}
+
+	return words[num.Int64()], nil
+}
+
+func lines(s string) []string {
+	var res []string
+
+	sc := bufio.NewScanner(strings.NewReader(s))
+	for sc.Scan() {
+		if line := strings.TrimSpace(sc.Text()); line != "" && !strings.HasPrefix(line, "#") {
+			res = append(res, line)
+		}
+	}
+
+	return res
+}
+
+func normalize(words []string) []string {
+	res := make([]string, 0, len(words))
+
+	for _, word := range words {
+		word = strings.ToLower(strings.TrimSpace(word))
+		if word != "" && !slices.Contains(res, word) {
+			res = append(res, word)
+		}
+	}
+
+	return res
+}
+
+func filter(words, blocked []string) []string {
+	return slices.DeleteFunc(words, func(word string) bool {
+		return slices.Contains(blocked, word)
+	})
+}
@@ -0,0 +1,219 @@
+package words_test
+
+import (
+	"context"
+	"math"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/kodeyeen/shortify/internal/generation/words"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAliasProvider_Generate(t *testing.T) {
+	testCases := map[string]struct {
+		dict      words.Dictionary
+		count     int
+		separator string
+		want      []string
+	}{
+		"Adjectives and noun": {
+			dict: words.Dictionary{
+				Adjectives: []string{"brave", "orange"},
+				Nouns:      []string{"tiger"},
+			},
+			count:     3,
+			separator: "-",
+			want:      []string{"brave-brave-tiger", "brave-orange-tiger", "orange-brave-tiger", "orange-orange-tiger"},
+		},
+		"Single word": {
+			dict: words.Dictionary{
+				Nouns: []string{"Tiger"},
+			},
+			count: 1,
+			want:  []string{"tiger"},
+		},
+		"Blocked dictionary word": {
+			dict: words.Dictionary{
+				Adjectives: []string{"bad", "good"},
+				Nouns:      []string{"fox"},
+				Blocklist:  []string{"BAD"},
+			},
+			count:     2,
+			separator: "_",
+			want:      []string{"good_fox"},
+		},
+		"Blocked across words": {
+			dict: words.Dictionary{
+				Adjectives: []string{"sup"},
+				Nouns:      []string{"erb", "ace"},
+				Blocklist:  []string{"perb"},
+			},
+			count: 2,
+			want:  []string{"supace"},
+		},
+		"Blocked inside word": {
+			dict: words.Dictionary{
+				Adjectives: []string{"classic"},
+				Nouns:      []string{"fox"},
+				Blocklist:  []string{"ass"},
+			},
+			count:     2,
+			separator: ".",
+			want:      []string{"classic.fox"},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			// Given
+			provider, err := words.NewAliasProvider(tc.dict, tc.count, tc.separator)
+			require.NoError(t, err)
+
+			for range 50 {
+				// When
+				alias, err := provider.Generate(context.Background(), "https://example.com")
+
+				// Then
+				require.NoError(t, err)
+				require.Contains(t, tc.want, alias)
+			}
+		})
+	}
+}
+
+func TestAliasProvider_Generate_Blocked(t *testing.T) {
+	// Given
+	provider, err := words.NewAliasProvider(words.Dictionary{
+		Adjectives: []string{"sup"},
+		Nouns:      []string{"erb"},
+		Blocklist:  []string{"perb"},
+	}, 2, "")
+	require.NoError(t, err)
+
+	// When
+	_, err = provider.Generate(context.Background(), "https://example.com")
+
+	// Then
+	require.ErrorIs(t, err, words.ErrBlocked)
+}
+
+func TestNewAliasProvider_Invalid(t *testing.T) {
+	testCases := map[string]struct {
+		dict      words.Dictionary
+		count     int
+		separator string
+	}{
+		"Zero words": {
+			dict:  words.DefaultDictionary(),
+			count: 0,
+		},
+		"Too many words": {
+			dict:  words.DefaultDictionary(),
+			count: words.MaxWords + 1,
+		},
+		"Slash separator": {
+			dict:      words.DefaultDictionary(),
+			count:     3,
+			separator: "/",
+		},
+		"No adjectives": {
+			dict:  words.Dictionary{Nouns: []string{"fox"}},
+			count: 2,
+		},
+		"Only blocked nouns": {
+			dict:  words.Dictionary{Nouns: []string{"fox"}, Blocklist: []string{"fox"}},
+			count: 1,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			// When
+			_, err := words.NewAliasProvider(tc.dict, tc.count, tc.separator)
+
+			// Then
+			require.Error(t, err)
+		})
+	}
+}
+
+func TestAliasProvider_Entropy(t *testing.T) {
+	// Given
+	provider, err := words.NewAliasProvider(words.Dictionary{
+		Adjectives: []string{"a", "b", "c", "d", "e", "f", "g", "h", "i", "j"},
+		Nouns:      []string{"k", "l", "m", "n", "o", "p", "q", "r", "s", "t", "u", "v", "w", "x", "y", "z", "kk", "ll", "mm", "nn"},
+	}, 3, "-")
+	require.NoError(t, err)
+
+	// When
+	keyspace := provider.Keyspace()
+	entropy := provider.Entropy()
+
+	// Then
+	require.Equal(t, int64(2000), keyspace.Int64())
+	require.InDelta(t, math.Log2(2000), entropy, 1e-9)
+}
+
+func TestAliasProvider_Matches(t *testing.T) {
+	testCases := map[string]struct {
+		separator string
+		alias     string
+		want      bool
+	}{
+		"Generated":        {separator: "-", alias: "brave-orange-tiger", want: true},
+		"Noun first":       {separator: "-", alias: "tiger-brave-orange", want: false},
+		"Too few words":    {separator: "-", alias: "brave-tiger", want: false},
+		"Unknown word":     {separator: "-", alias: "brave-purple-tiger", want: false},
+		"Other separator":  {separator: "-", alias: "brave_orange_tiger", want: false},
+		"Upper case":       {separator: "-", alias: "Brave-orange-tiger", want: false},
+		"Random alias":     {separator: "-", alias: "fjda89fadb", want: false},
+		"Empty separator":  {separator: "", alias: "braveorangetiger", want: true},
+		"Ambiguous prefix": {separator: "", alias: "orangeoranget", want: true},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			// Given
+			provider, err := words.NewAliasProvider(words.Dictionary{
+				Adjectives: []string{"brave", "orange", "orangeo"},
+				Nouns:      []string{"tiger", "ranget", "t"},
+			}, 3, tc.separator)
+			require.NoError(t, err)
+
+			// When
+			got := provider.Matches(tc.alias)
+
+			// Then
+			require.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestDefaultDictionary(t *testing.T) {
+	dict := words.DefaultDictionary()
+
+	require.NotEmpty(t, dict.Adjectives)
+	require.NotEmpty(t, dict.Nouns)
+	require.NotEmpty(t, dict.Blocklist)
+
+	for _, word := range slices.Concat(dict.Adjectives, dict.Nouns) {
+		require.Equal(t, strings.ToLower(word), word)
+		require.NotContains(t, dict.Blocklist, word)
+
+		for _, r := range word {
+			require.True(t, r >= 'a' && r <= 'z', "word %q has non-letter characters", word)
+		}
+	}
+
+	provider, err := words.NewAliasProvider(dict, 3, "-")
+	require.NoError(t, err)
+	require.Greater(t, provider.Entropy(), 20.0)
+}
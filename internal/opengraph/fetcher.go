@@ -0,0 +1,211 @@
+package opengraph
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net"
+	"net/http"
+	neturl "net/url"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/kodeyeen/shortify/internal/domain"
+	"golang.org/x/net/html"
+)
+
+var (
+	ErrUnexpectedStatus      = errors.New("unexpected response status")
+	ErrUnsupportedMediaType  = errors.New("unsupported media type")
+	ErrPrivateNetworkAddress = errors.New("private network address")
+)
+
+const maxRedirects = 5
+
+// Fetcher fetches Open Graph metadata of HTML pages.
+type Fetcher struct {
+	client      *http.Client
+	maxBodySize int64
+}
+
+func NewFetcher(client *http.Client, maxBodySize int64) *Fetcher {
+	return &Fetcher{
+		client:      client,
+		maxBodySize: maxBodySize,
+	}
+}
+
+// NewHTTPClient creates a client suitable for fetching user supplied URLs.
+// Unless allowPrivateNetworks is set it refuses to connect to loopback,
+// private and link-local addresses so links can't be used to probe the
+// internal network.
+func NewHTTPClient(timeout time.Duration, allowPrivateNetworks bool) *http.Client {
+	dialer := &net.Dialer{
+		Timeout: timeout,
+	}
+
+	if !allowPrivateNetworks {
+		dialer.Control = func(network, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+
+			ip := net.ParseIP(host)
+			if ip == nil || ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() ||
+				ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() {
+				return fmt.Errorf("%w: %s", ErrPrivateNetworkAddress, host)
+			}
+
+			return nil
+		}
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.DialContext = dialer.DialContext
+
+	return &http.Client{
+		Transport: transport,
+		Timeout:   timeout,
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if len(via) >= maxRedirects {
+				return http.ErrUseLastResponse
+			}
+
+			return nil
+		},
+	}
+}
+
+// Fetch fetches the page at rawURL and extracts its title, description and image.
+// At most maxBodySize bytes of the page are read.
+func (f *Fetcher) Fetch(ctx context.Context, rawURL string) (*domain.OpenGraph, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Accept", "text/html,application/xhtml+xml")
+	req.Header.Set("User-Agent", "ShortifyBot/1.0 (+link preview)")
+
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch page: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil, fmt.Errorf("%w: %d", ErrUnexpectedStatus, resp.StatusCode)
+	}
+
+	mediaType, _, err := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	if err != nil || (mediaType != "text/html" && mediaType != "application/xhtml+xml") {
+		return nil, fmt.Errorf("%w: %q", ErrUnsupportedMediaType, resp.Header.Get("Content-Type"))
+	}
+
+	og := parse(io.LimitReader(resp.Body, f.maxBodySize))
+
+	if og.Image != "" {
+		og.Image = resolveReference(resp.Request.URL, og.Image)
+	}
+
+	return og, nil
+}
+
+// parse extracts metadata from the document head. Open Graph properties take
+// precedence over the title element and the description meta tag.
+func parse(r io.Reader) *domain.OpenGraph {
+	var (
+		og          domain.OpenGraph
+		title       string
+		description string
+		inTitle     bool
+	)
+
+	z := html.NewTokenizer(r)
+
+	for {
+		tt := z.Next()
+
+		switch tt {
+		case html.ErrorToken:
+			return finish(&og, title, description)
+		case html.StartTagToken, html.SelfClosingTagToken:
+			tok := z.Token()
+
+			switch tok.Data {
+			case "title":
+				inTitle = tt == html.StartTagToken
+			case "meta":
+				key, content := metaAttrs(tok)
+
+				switch key {
+				case "og:title":
+					og.Title = content
+				case "og:description":
+					og.Description = content
+				case "og:image", "og:image:url":
+					if og.Image == "" {
+						og.Image = content
+					}
+				case "description":
+					description = content
+				}
+			case "body":
+				return finish(&og, title, description)
+			}
+		case html.TextToken:
+			if inTitle && title == "" {
+				title = strings.TrimSpace(string(z.Text()))
+			}
+		case html.EndTagToken:
+			tok := z.Token()
+
+			switch tok.Data {
+			case "title":
+				inTitle = false
+			case "head":
+				return finish(&og, title, description)
+			}
+		}
+	}
+}
+
+func finish(og *domain.OpenGraph, title, description string) *domain.OpenGraph {
+	if og.Title == "" {
+		og.Title = title
+	}
+
+	if og.Description == "" {
+		og.Description = description
+	}
+
+	return og
+}
+
+func metaAttrs(tok html.Token) (key, content string) {
+	for _, attr := range tok.Attr {
+		switch attr.Key {
+		case "property", "name":
+			if key == "" {
+				key = strings.ToLower(attr.Val)
+			}
+		case "content":
+			content = strings.TrimSpace(attr.Val)
+		}
+	}
+
+	return key, content
+}
+
+func resolveReference(base *neturl.URL, ref string) string {
+	u, err := neturl.Parse(ref)
+	if err != nil {
+		return ""
+	}
+
+	return base.ResolveReference(u).String()
+}
@@ -0,0 +1,182 @@
+package opengraph_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kodeyeen/shortify/internal/domain"
+	"github.com/kodeyeen/shortify/internal/opengraph"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFetcher_Fetch(t *testing.T) {
+	type Given struct {
+		contentType string
+		status      int
+		body        string
+		maxBodySize int64
+	}
+
+	type Expected struct {
+		og  *domain.OpenGraph
+		err error
+	}
+
+	testCases := map[string]struct {
+		given    Given
+		expected Expected
+	}{
+		"Open Graph tags": {
+			Given{
+				contentType: "text/html; charset=utf-8",
+				status:      http.StatusOK,
+				body: `<!DOCTYPE html><html><head>
+					<title>Page title</title>
+					<meta name="description" content="Page description">
+					<meta property="og:title" content="OG title">
+					<meta property="og:description" content="OG description">
+					<meta property="og:image" content="https://cdn.example.com/image.png">
+					</head><body></body></html>`,
+				maxBodySize: 1 << 20,
+			},
+			Expected{
+				og: &domain.OpenGraph{
+					Title:       "OG title",
+					Description: "OG description",
+					Image:       "https://cdn.example.com/image.png",
+				},
+				err: nil,
+			},
+		},
+		"Fallback to title and description": {
+			Given{
+				contentType: "text/html",
+				status:      http.StatusOK,
+				body: `<html><head>
+					<title> Page title </title>
+					<meta name="description" content="Page description">
+					</head></html>`,
+				maxBodySize: 1 << 20,
+			},
+			Expected{
+				og: &domain.OpenGraph{
+					Title:       "Page title",
+					Description: "Page description",
+				},
+				err: nil,
+			},
+		},
+		"Relative image": {
+			Given{
+				contentType: "text/html",
+				status:      http.StatusOK,
+				body:        `<html><head><meta property="og:image" content="/static/image.png"/></head></html>`,
+				maxBodySize: 1 << 20,
+			},
+			Expected{
+				og: &domain.OpenGraph{
+					Image: "{{server}}/static/image.png",
+				},
+				err: nil,
+			},
+		},
+		"Body size limit": {
+			Given{
+				contentType: "text/html",
+				status:      http.StatusOK,
+				body: `<html><head><title>Page title</title>` +
+					strings.Repeat("<!-- padding -->", 100) +
+					`<meta property="og:title" content="OG title"></head></html>`,
+				maxBodySize: 64,
+			},
+			Expected{
+				og: &domain.OpenGraph{
+					Title: "Page title",
+				},
+				err: nil,
+			},
+		},
+		"Unexpected status": {
+			Given{
+				contentType: "text/html",
+				status:      http.StatusNotFound,
+				body:        `<html></html>`,
+				maxBodySize: 1 << 20,
+			},
+			Expected{
+				og:  nil,
+				err: opengraph.ErrUnexpectedStatus,
+			},
+		},
+		"Unsupported media type": {
+			Given{
+				contentType: "application/pdf",
+				status:      http.StatusOK,
+				body:        `%PDF-1.4`,
+				maxBodySize: 1 << 20,
+			},
+			Expected{
+				og:  nil,
+				err: opengraph.ErrUnsupportedMediaType,
+			},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			// Given
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", tc.given.contentType)
+				w.WriteHeader(tc.given.status)
+				fmt.Fprint(w, tc.given.body)
+			}))
+			defer srv.Close()
+
+			fetcher := opengraph.NewFetcher(srv.Client(), tc.given.maxBodySize)
+
+			// When
+			og, err := fetcher.Fetch(context.Background(), srv.URL+"/page")
+
+			// Then
+			require.ErrorIs(t, err, tc.expected.err)
+
+			if tc.expected.og != nil {
+				tc.expected.og.Image = strings.ReplaceAll(tc.expected.og.Image, "{{server}}", srv.URL)
+			}
+
+			require.Equal(t, tc.expected.og, og)
+		})
+	}
+}
+
+func TestNewHTTPClient(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		fmt.Fprint(w, `<html><head><title>Internal</title></head></html>`)
+	}))
+	defer srv.Close()
+
+	t.Run("Private networks refused", func(t *testing.T) {
+		fetcher := opengraph.NewFetcher(opengraph.NewHTTPClient(time.Second, false), 1<<20)
+
+		_, err := fetcher.Fetch(context.Background(), srv.URL)
+
+		require.ErrorIs(t, err, opengraph.ErrPrivateNetworkAddress)
+	})
+
+	t.Run("Private networks allowed", func(t *testing.T) {
+		fetcher := opengraph.NewFetcher(opengraph.NewHTTPClient(time.Second, true), 1<<20)
+
+		og, err := fetcher.Fetch(context.Background(), srv.URL)
+
+		require.NoError(t, err)
+		require.Equal(t, "Internal", og.Title)
+	})
+}
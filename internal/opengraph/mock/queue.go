@@ -0,0 +1,79 @@
+// Code generated by mockery. DO NOT EDIT.
+
+package mock
+
+import mock "github.com/stretchr/testify/mock"
+
+// Queue is an autogenerated mock type for the MetadataQueue type
+type Queue struct {
+	mock.Mock
+}
+
+type Queue_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *Queue) EXPECT() *Queue_Expecter {
+	return &Queue_Expecter{mock: &_m.Mock}
+}
+
+// Enqueue provides a mock function with given fields: id, original
+func (_m *Queue) Enqueue(id int64, original string) bool {
+	ret := _m.Called(id, original)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Enqueue")
+	}
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func(int64, string) bool); ok {
+		r0 = rf(id, original)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	return r0
+}
+
+// Queue_Enqueue_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Enqueue'
+type Queue_Enqueue_Call struct {
+	*mock.Call
+}
+
+// Enqueue is a helper method to define mock.On call
+//   - id int64
+//   - original string
+func (_e *Queue_Expecter) Enqueue(id interface{}, original interface{}) *Queue_Enqueue_Call {
+	return &Queue_Enqueue_Call{Call: _e.mock.On("Enqueue", id, original)}
+}
+
+func (_c *Queue_Enqueue_Call) Run(run func(id int64, original string)) *Queue_Enqueue_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(int64), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *Queue_Enqueue_Call) Return(_a0 bool) *Queue_Enqueue_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *Queue_Enqueue_Call) RunAndReturn(run func(int64, string) bool) *Queue_Enqueue_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewQueue creates a new instance of Queue. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewQueue(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *Queue {
+	mock := &Queue{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,140 @@
+package opengraph
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kodeyeen/shortify/internal/domain"
+	"github.com/kodeyeen/shortify/internal/persistence"
+)
+
+type Repository interface {
+	UpdateOpenGraph(ctx context.Context, id int64, original string, og *domain.OpenGraph) error
+}
+
+type job struct {
+	id       int64
+	original string
+}
+
+// Pool fetches metadata in the background with a fixed number of workers.
+// Jobs that don't fit into the queue are dropped.
+type Pool struct {
+	fetcher *Fetcher
+	urls    Repository
+
+	workers int
+	timeout time.Duration
+	jobs    chan job
+	closed  bool
+	mu      sync.RWMutex
+	wg      sync.WaitGroup
+
+	log *slog.Logger
+}
+
+func NewPool(fetcher *Fetcher, urls Repository, workers, queueSize int, timeout time.Duration, log *slog.Logger) *Pool {
+	return &Pool{
+		fetcher: fetcher,
+		urls:    urls,
+
+		workers: workers,
+		timeout: timeout,
+		jobs:    make(chan job, queueSize),
+
+		log: log.With(slog.String("component", "opengraph/pool")),
+	}
+}
+
+// Start starts the workers. They run until Stop is called or ctx is done.
+func (p *Pool) Start(ctx context.Context) {
+	for range p.workers {
+		p.wg.Add(1)
+
+		go func() {
+			defer p.wg.Done()
+
+			p.work(ctx)
+		}()
+	}
+}
+
+// Stop stops accepting jobs and waits for the queued ones to be processed.
+func (p *Pool) Stop() {
+	p.mu.Lock()
+	if !p.closed {
+		p.closed = true
+		close(p.jobs)
+	}
+	p.mu.Unlock()
+
+	p.wg.Wait()
+}
+
+// Enqueue schedules fetching metadata for the URL with the id and the original URL.
+// The metadata is dropped if the original URL changes before it's stored.
+// It reports whether the job was queued.
+func (p *Pool) Enqueue(id int64, original string) bool {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	if p.closed {
+		return false
+	}
+
+	select {
+	case p.jobs <- job{id: id, original: original}:
+		return true
+	default:
+		p.log.Warn("queue is full, dropping job", slog.Int64("url_id", id))
+
+		return false
+	}
+}
+
+func (p *Pool) work(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case j, ok := <-p.jobs:
+			if !ok {
+				return
+			}
+
+			p.process(ctx, j)
+		}
+	}
+}
+
+func (p *Pool) process(ctx context.Context, j job) {
+	log := p.log.With(slog.Int64("url_id", j.id))
+
+	ctx, cancel := context.WithTimeout(ctx, p.timeout)
+	defer cancel()
+
+	og, err := p.fetcher.Fetch(ctx, strings.ReplaceAll(j.original, domain.PathPlaceholder, ""))
+	if err != nil {
+		log.Info("failed to fetch metadata", slog.String("error", err.Error()))
+		return
+	}
+
+	og.FetchedAt = time.Now()
+
+	err = p.urls.UpdateOpenGraph(ctx, j.id, j.original, og)
+	if errors.Is(err, persistence.ErrURLNotFound) {
+		log.Debug("url changed or deleted, dropping metadata")
+		return
+	}
+
+	if err != nil {
+		log.Error("failed to store metadata", slog.String("error", err.Error()))
+		return
+	}
+
+	log.Debug("metadata stored", slog.String("title", og.Title))
+}
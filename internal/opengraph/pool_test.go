@@ -0,0 +1,158 @@
+package opengraph_test
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/kodeyeen/shortify/internal/domain"
+	"github.com/kodeyeen/shortify/internal/opengraph"
+	"github.com/kodeyeen/shortify/internal/persistence/inmemory"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPool(t *testing.T) {
+	// Given
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		fmt.Fprintf(w, `<html><head><meta property="og:title" content="Title of %s"></head></html>`, r.URL.Path)
+	}))
+	defer srv.Close()
+
+	ctx := context.Background()
+
+	urls := inmemory.NewURLRepository()
+
+	aliases := []string{"alias1", "alias2", "alias3"}
+	ids := make([]int64, len(aliases))
+
+	for i, alias := range aliases {
+		id, err := urls.Add(ctx, &domain.URL{
+			Original: srv.URL + "/" + alias,
+			Alias:    alias,
+		})
+		require.NoError(t, err)
+
+		ids[i] = id
+	}
+
+	log := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	pool := opengraph.NewPool(opengraph.NewFetcher(srv.Client(), 1<<20), urls, 2, len(aliases), time.Second, log)
+	pool.Start(ctx)
+
+	// When
+	for i, alias := range aliases {
+		require.True(t, pool.Enqueue(ids[i], srv.URL+"/"+alias))
+	}
+
+	pool.Stop()
+
+	// Then
+	require.False(t, pool.Enqueue(ids[0]+100, srv.URL+"/alias4"))
+
+	for _, alias := range aliases {
+		u, err := urls.FindByAlias(ctx, alias)
+		require.NoError(t, err)
+		require.NotNil(t, u.OpenGraph)
+		require.Equal(t, "Title of /"+alias, u.OpenGraph.Title)
+		require.False(t, u.OpenGraph.FetchedAt.IsZero())
+	}
+}
+
+func TestPool_Enqueue_QueueFull(t *testing.T) {
+	// Given
+	log := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	pool := opengraph.NewPool(opengraph.NewFetcher(http.DefaultClient, 1<<20), inmemory.NewURLRepository(), 1, 1, time.Second, log)
+
+	// When
+	first := pool.Enqueue(1, "https://example.com/1")
+	second := pool.Enqueue(2, "https://example.com/2")
+
+	// Then
+	require.True(t, first)
+	require.False(t, second)
+}
+
+func TestPool_OriginalChanged(t *testing.T) {
+	// Given
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		fmt.Fprint(w, `<html><head><meta property="og:title" content="Old title"></head></html>`)
+	}))
+	defer srv.Close()
+
+	ctx := context.Background()
+
+	urls := inmemory.NewURLRepository()
+
+	id, err := urls.Add(ctx, &domain.URL{
+		Original: srv.URL + "/old",
+		Alias:    "alias1",
+	})
+	require.NoError(t, err)
+
+	err = urls.Update(ctx, &domain.URL{
+		Original: srv.URL + "/new",
+		Alias:    "alias1",
+	})
+	require.NoError(t, err)
+
+	log := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	pool := opengraph.NewPool(opengraph.NewFetcher(srv.Client(), 1<<20), urls, 1, 1, time.Second, log)
+	pool.Start(ctx)
+
+	// When
+	require.True(t, pool.Enqueue(id, srv.URL+"/old"))
+
+	pool.Stop()
+
+	// Then
+	u, err := urls.FindByAlias(ctx, "alias1")
+	require.NoError(t, err)
+	require.Nil(t, u.OpenGraph)
+}
+
+func TestPool_TemplatedURL(t *testing.T) {
+	// Given
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		fmt.Fprintf(w, `<html><head><meta property="og:title" content="Title of %s"></head></html>`, r.URL.Path)
+	}))
+	defer srv.Close()
+
+	ctx := context.Background()
+
+	urls := inmemory.NewURLRepository()
+
+	original := srv.URL + "/p/" + domain.PathPlaceholder
+
+	id, err := urls.Add(ctx, &domain.URL{
+		Original: original,
+		Alias:    "alias1",
+	})
+	require.NoError(t, err)
+
+	log := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	pool := opengraph.NewPool(opengraph.NewFetcher(srv.Client(), 1<<20), urls, 1, 1, time.Second, log)
+	pool.Start(ctx)
+
+	// When
+	require.True(t, pool.Enqueue(id, original))
+
+	pool.Stop()
+
+	// Then
+	u, err := urls.FindByAlias(ctx, "alias1")
+	require.NoError(t, err)
+	require.NotNil(t, u.OpenGraph)
+	require.Equal(t, "Title of /p/", u.OpenGraph.Title)
+}
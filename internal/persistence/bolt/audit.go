@@ -0,0 +1,95 @@
+package bolt
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+
+	"github.com/kodeyeen/shortify/internal/domain"
+	bolt "go.etcd.io/bbolt"
+)
+
+// AuditRepository stores the audit entries in the audit bucket
+// indexed by URL in the audit_urls bucket.
+type AuditRepository struct {
+	db *bolt.DB
+}
+
+func NewAuditRepository(db *bolt.DB) *AuditRepository {
+	return &AuditRepository{
+		db: db,
+	}
+}
+
+func (r *AuditRepository) Append(ctx context.Context, e *domain.AuditEntry) (int64, error) {
+	var id int64
+
+	err := r.db.Update(func(tx *bolt.Tx) error {
+		entries := tx.Bucket(auditBucket)
+
+		seq, err := entries.NextSequence()
+		if err != nil {
+			return err
+		}
+
+		stored := *e
+		stored.ID = int64(seq)
+
+		data, err := json.Marshal(&stored)
+		if err != nil {
+			return fmt.Errorf("failed to encode audit entry: %w", err)
+		}
+
+		err = entries.Put(idKey(stored.ID), data)
+		if err != nil {
+			return err
+		}
+
+		id = stored.ID
+
+		return tx.Bucket(auditURLsBucket).Put(append(idKey(e.URLID), idKey(id)...), nil)
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to append audit entry: %w", err)
+	}
+
+	return id, nil
+}
+
+// ListByURL returns the entries of the URL, the oldest first.
+func (r *AuditRepository) ListByURL(ctx context.Context, urlID int64) ([]*domain.AuditEntry, error) {
+	entries := []*domain.AuditEntry{}
+
+	err := r.db.View(func(tx *bolt.Tx) error {
+		prefix := idKey(urlID)
+		bucket := tx.Bucket(auditBucket)
+
+		c := tx.Bucket(auditURLsBucket).Cursor()
+		for k, _ := c.Seek(prefix); k != nil && bytes.HasPrefix(k, prefix); k, _ = c.Next() {
+			id := int64(binary.BigEndian.Uint64(k[len(prefix):]))
+
+			data := bucket.Get(idKey(id))
+			if data == nil {
+				return fmt.Errorf("audit entry %d is missing", id)
+			}
+
+			var e domain.AuditEntry
+
+			err := json.Unmarshal(data, &e)
+			if err != nil {
+				return fmt.Errorf("failed to decode audit entry: %w", err)
+			}
+
+			entries = append(entries, &e)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list audit entries: %w", err)
+	}
+
+	return entries, nil
+}
@@ -0,0 +1,59 @@
+// Package bolt stores URLs and their audit log in an embedded bbolt key-value database.
+package bolt
+
+import (
+	"fmt"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+var (
+	// urlsBucket maps aliases to the JSON encoded URLs, its sequence assigns the IDs.
+	urlsBucket = []byte("urls")
+	// originalsBucket maps original URLs to their aliases.
+	originalsBucket = []byte("originals")
+	// idsBucket maps big-endian IDs to aliases to list URLs in order.
+	idsBucket = []byte("ids")
+	// retiredBucket maps retired aliases followed by a zero byte and the big-endian URL ID
+	// to the JSON encoded retirements.
+	retiredBucket = []byte("retired")
+	// retiredURLsBucket maps big-endian URL IDs followed by the retired aliases to nothing
+	// to find the retirements of a URL.
+	retiredURLsBucket = []byte("retired_urls")
+	// auditBucket maps big-endian entry IDs to the JSON encoded audit entries,
+	// its sequence assigns the IDs.
+	auditBucket = []byte("audit")
+	// auditURLsBucket maps big-endian URL IDs followed by the big-endian entry IDs
+	// to nothing to list the entries of a URL.
+	auditURLsBucket = []byte("audit_urls")
+)
+
+// Open opens the database file at path creating the buckets.
+// It fails if another process holds the file for longer than a second.
+func Open(path string) (*bolt.DB, error) {
+	db, err := bolt.Open(path, 0o600, &bolt.Options{Timeout: time.Second})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open bolt database: %w", err)
+	}
+
+	err = db.Update(func(tx *bolt.Tx) error {
+		for _, name := range [][]byte{
+			urlsBucket, originalsBucket, idsBucket, retiredBucket, retiredURLsBucket, auditBucket, auditURLsBucket,
+		} {
+			_, err := tx.CreateBucketIfNotExists(name)
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		db.Close()
+
+		return nil, fmt.Errorf("failed to create buckets: %w", err)
+	}
+
+	return db, nil
+}
@@ -0,0 +1,704 @@
+package bolt
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/kodeyeen/shortify/internal/domain"
+	"github.com/kodeyeen/shortify/internal/persistence"
+	bolt "go.etcd.io/bbolt"
+)
+
+type URLRepository struct {
+	db *bolt.DB
+}
+
+func NewURLRepository(db *bolt.DB) *URLRepository {
+	return &URLRepository{
+		db: db,
+	}
+}
+
+func (r *URLRepository) Close() {
+	r.db.Close()
+}
+
+// Add checks both uniqueness constraints and stores the URL in one transaction.
+func (r *URLRepository) Add(ctx context.Context, u *domain.URL) (int64, error) {
+	var id int64
+
+	err := r.db.Update(func(tx *bolt.Tx) error {
+		b := buckets(tx)
+
+		if b.originals.Get([]byte(u.Original)) != nil {
+			return persistence.ErrURLAlreadyExists
+		}
+
+		if b.urls.Get([]byte(u.Alias)) != nil {
+			return persistence.ErrDuplicateAlias
+		}
+
+		// the sequence is rolled back along with a failed transaction
+		seq, err := b.urls.NextSequence()
+		if err != nil {
+			return err
+		}
+
+		stored := *u
+		stored.ID = int64(seq)
+
+		err = b.put(&stored)
+		if err != nil {
+			return err
+		}
+
+		id = stored.ID
+
+		return nil
+	})
+	if err != nil {
+		return 0, wrap(err, "failed to add url")
+	}
+
+	return id, nil
+}
+
+func (r *URLRepository) FindByID(ctx context.Context, id int64) (*domain.URL, error) {
+	var u *domain.URL
+
+	err := r.db.View(func(tx *bolt.Tx) error {
+		b := buckets(tx)
+
+		alias := b.ids.Get(idKey(id))
+		if alias == nil {
+			return persistence.ErrURLNotFound
+		}
+
+		var err error
+
+		u, err = b.get(string(alias))
+
+		return err
+	})
+	if err != nil {
+		return nil, wrap(err, "failed to find url by id")
+	}
+
+	return u, nil
+}
+
+func (r *URLRepository) FindByAlias(ctx context.Context, alias string) (*domain.URL, error) {
+	var u *domain.URL
+
+	err := r.db.View(func(tx *bolt.Tx) error {
+		var err error
+
+		u, err = buckets(tx).get(alias)
+
+		return err
+	})
+	if err != nil {
+		return nil, wrap(err, "failed to find url by alias")
+	}
+
+	return u, nil
+}
+
+func (r *URLRepository) FindByOriginal(ctx context.Context, original string) (*domain.URL, error) {
+	var u *domain.URL
+
+	err := r.db.View(func(tx *bolt.Tx) error {
+		b := buckets(tx)
+
+		alias := b.originals.Get([]byte(original))
+		if alias == nil {
+			return persistence.ErrURLNotFound
+		}
+
+		var err error
+
+		u, err = b.get(string(alias))
+
+		return err
+	})
+	if err != nil {
+		return nil, wrap(err, "failed to find url by original")
+	}
+
+	return u, nil
+}
+
+func (r *URLRepository) Count(ctx context.Context) (int64, error) {
+	var count int64
+
+	err := r.db.View(func(tx *bolt.Tx) error {
+		count = int64(buckets(tx).urls.Stats().KeyN)
+
+		return nil
+	})
+	if err != nil {
+		return 0, wrap(err, "failed to count urls")
+	}
+
+	return count, nil
+}
+
+func (r *URLRepository) List(ctx context.Context, filter persistence.URLFilter) ([]*domain.URL, error) {
+	urls := []*domain.URL{}
+
+	err := r.db.View(func(tx *bolt.Tx) error {
+		b := buckets(tx)
+		skipped := 0
+
+		c := b.ids.Cursor()
+
+		for _, alias := c.Seek(idKey(filter.AfterID + 1)); alias != nil; _, alias = c.Next() {
+			if filter.Limit > 0 && len(urls) == filter.Limit {
+				break
+			}
+
+			u, err := b.get(string(alias))
+			if err != nil {
+				return err
+			}
+
+			if u.DeletedAt != nil || !matches(u, filter) {
+				continue
+			}
+
+			if skipped < filter.Offset {
+				skipped++
+				continue
+			}
+
+			urls = append(urls, u)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, wrap(err, "failed to list urls")
+	}
+
+	return urls, nil
+}
+
+// Update updates the editable fields of the URL with the same alias.
+// Changing the original URL resets its Open Graph metadata.
+func (r *URLRepository) Update(ctx context.Context, u *domain.URL) error {
+	err := r.db.Update(func(tx *bolt.Tx) error {
+		b := buckets(tx)
+
+		old, err := b.get(u.Alias)
+		if err != nil {
+			return err
+		}
+
+		if alias := b.originals.Get([]byte(u.Original)); alias != nil && string(alias) != u.Alias {
+			return persistence.ErrURLAlreadyExists
+		}
+
+		return b.replace(old, edited(old, u))
+	})
+
+	return wrap(err, "failed to update url")
+}
+
+// UpdateOpenGraph stores the metadata of the URL with the id unless its original URL
+// is no longer original, failing with persistence.ErrURLNotFound then.
+func (r *URLRepository) UpdateOpenGraph(ctx context.Context, id int64, original string, og *domain.OpenGraph) error {
+	err := r.db.Update(func(tx *bolt.Tx) error {
+		b := buckets(tx)
+
+		alias := b.ids.Get(idKey(id))
+		if alias == nil {
+			return persistence.ErrURLNotFound
+		}
+
+		u, err := b.get(string(alias))
+		if err != nil {
+			return err
+		}
+
+		if u.Original != original {
+			return persistence.ErrURLNotFound
+		}
+
+		u.OpenGraph = og
+
+		return b.put(u)
+	})
+
+	return wrap(err, "failed to update url open graph")
+}
+
+func (r *URLRepository) IncrementClicks(ctx context.Context, alias string) (int64, error) {
+	var clicks int64
+
+	err := r.modify(alias, func(u *domain.URL) {
+		u.Clicks++
+		clicks = u.Clicks
+	})
+	if err != nil {
+		return 0, wrap(err, "failed to increment url clicks")
+	}
+
+	return clicks, nil
+}
+
+// SoftDelete moves the URL to the trash keeping its alias and original URL taken.
+func (r *URLRepository) SoftDelete(ctx context.Context, alias string, deletedAt time.Time) (*domain.URL, error) {
+	u, err := r.setDeletedAt(alias, &deletedAt)
+	if err != nil {
+		return nil, wrap(err, "failed to soft delete url")
+	}
+
+	return u, nil
+}
+
+// Restore takes the URL out of the trash.
+func (r *URLRepository) Restore(ctx context.Context, alias string) (*domain.URL, error) {
+	u, err := r.setDeletedAt(alias, nil)
+	if err != nil {
+		return nil, wrap(err, "failed to restore url")
+	}
+
+	return u, nil
+}
+
+// Purge deletes the URLs moved to the trash before deletedBefore along with their retired aliases.
+// There is no index of the trash so all URLs are scanned.
+func (r *URLRepository) Purge(ctx context.Context, deletedBefore time.Time) (int64, error) {
+	var purged int64
+
+	err := r.db.Update(func(tx *bolt.Tx) error {
+		b := buckets(tx)
+
+		var expired []*domain.URL
+
+		err := b.urls.ForEach(func(_, data []byte) error {
+			var u domain.URL
+
+			err := json.Unmarshal(data, &u)
+			if err != nil {
+				return fmt.Errorf("failed to decode url: %w", err)
+			}
+
+			if u.DeletedAt != nil && u.DeletedAt.Before(deletedBefore) {
+				expired = append(expired, &u)
+			}
+
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		// the bucket can't be modified while iterating over it
+		for _, u := range expired {
+			err := b.remove(u)
+			if err != nil {
+				return err
+			}
+		}
+
+		purged = int64(len(expired))
+
+		return nil
+	})
+	if err != nil {
+		return 0, wrap(err, "failed to purge urls")
+	}
+
+	return purged, nil
+}
+
+// setDeletedAt moves the URL to the trash or takes it out of there.
+// The URL must be in the opposite state.
+func (r *URLRepository) setDeletedAt(alias string, deletedAt *time.Time) (*domain.URL, error) {
+	var u *domain.URL
+
+	err := r.db.Update(func(tx *bolt.Tx) error {
+		b := buckets(tx)
+
+		var err error
+
+		u, err = b.get(alias)
+		if err != nil {
+			return err
+		}
+
+		if (u.DeletedAt == nil) == (deletedAt == nil) {
+			return persistence.ErrURLNotFound
+		}
+
+		u.DeletedAt = deletedAt
+
+		return b.put(u)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return u, nil
+}
+
+// Rename moves the URL with alias to the alias of u, updates its editable fields like Update
+// and records the old alias as retired, all in one transaction.
+func (r *URLRepository) Rename(ctx context.Context, alias string, u *domain.URL, retiredAt time.Time) (*domain.URL, error) {
+	var renamed *domain.URL
+
+	err := r.db.Update(func(tx *bolt.Tx) error {
+		b := buckets(tx)
+
+		old, err := b.get(alias)
+		if err != nil {
+			return err
+		}
+
+		if u.Alias != alias && b.urls.Get([]byte(u.Alias)) != nil {
+			return persistence.ErrDuplicateAlias
+		}
+
+		if other := b.originals.Get([]byte(u.Original)); other != nil && string(other) != alias {
+			return persistence.ErrURLAlreadyExists
+		}
+
+		err = b.urls.Delete([]byte(alias))
+		if err != nil {
+			return err
+		}
+
+		renamed = edited(old, u)
+		renamed.Alias = u.Alias
+
+		err = b.replace(old, renamed)
+		if err != nil {
+			return err
+		}
+
+		return b.retire(&domain.RetiredAlias{
+			Alias:     alias,
+			URLID:     old.ID,
+			RetiredAt: retiredAt,
+		})
+	})
+	if err != nil {
+		return nil, wrap(err, "failed to rename url")
+	}
+
+	return renamed, nil
+}
+
+// FindRetiredAlias returns the latest retirement of the alias.
+func (r *URLRepository) FindRetiredAlias(ctx context.Context, alias string) (*domain.RetiredAlias, error) {
+	var latest *domain.RetiredAlias
+
+	err := r.db.View(func(tx *bolt.Tx) error {
+		prefix := retiredPrefix(alias)
+		c := tx.Bucket(retiredBucket).Cursor()
+
+		for k, data := c.Seek(prefix); k != nil && bytes.HasPrefix(k, prefix); k, data = c.Next() {
+			var ra domain.RetiredAlias
+
+			err := json.Unmarshal(data, &ra)
+			if err != nil {
+				return fmt.Errorf("failed to decode retired alias: %w", err)
+			}
+
+			if latest == nil || ra.RetiredAt.After(latest.RetiredAt) {
+				latest = &ra
+			}
+		}
+
+		if latest == nil {
+			return persistence.ErrAliasNotFound
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, wrap(err, "failed to find retired alias")
+	}
+
+	return latest, nil
+}
+
+// ListRetiredAliases returns the retired aliases of the URL, the most recently retired first.
+func (r *URLRepository) ListRetiredAliases(ctx context.Context, urlID int64) ([]*domain.RetiredAlias, error) {
+	retired := []*domain.RetiredAlias{}
+
+	err := r.db.View(func(tx *bolt.Tx) error {
+		b := buckets(tx)
+		prefix := idKey(urlID)
+		c := b.retiredURLs.Cursor()
+
+		for k, _ := c.Seek(prefix); k != nil && bytes.HasPrefix(k, prefix); k, _ = c.Next() {
+			data := b.retired.Get(retiredKey(string(k[len(prefix):]), urlID))
+			if data == nil {
+				continue
+			}
+
+			var ra domain.RetiredAlias
+
+			err := json.Unmarshal(data, &ra)
+			if err != nil {
+				return fmt.Errorf("failed to decode retired alias: %w", err)
+			}
+
+			retired = append(retired, &ra)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, wrap(err, "failed to list retired aliases")
+	}
+
+	slices.SortFunc(retired, func(a, b *domain.RetiredAlias) int {
+		return b.RetiredAt.Compare(a.RetiredAt)
+	})
+
+	return retired, nil
+}
+
+// Reindex rebuilds the original URL and ID buckets from the URLs.
+func (r *URLRepository) Reindex(ctx context.Context) error {
+	err := r.db.Update(func(tx *bolt.Tx) error {
+		for _, name := range [][]byte{originalsBucket, idsBucket} {
+			err := tx.DeleteBucket(name)
+			if err != nil {
+				return err
+			}
+
+			_, err = tx.CreateBucket(name)
+			if err != nil {
+				return err
+			}
+		}
+
+		b := buckets(tx)
+
+		return b.urls.ForEach(func(_, data []byte) error {
+			var u domain.URL
+
+			err := json.Unmarshal(data, &u)
+			if err != nil {
+				return err
+			}
+
+			return b.index(&u)
+		})
+	})
+
+	return wrap(err, "failed to reindex urls")
+}
+
+// modify applies fn to the URL with the alias in a transaction.
+func (r *URLRepository) modify(alias string, fn func(u *domain.URL)) error {
+	return r.db.Update(func(tx *bolt.Tx) error {
+		b := buckets(tx)
+
+		u, err := b.get(alias)
+		if err != nil {
+			return err
+		}
+
+		fn(u)
+
+		return b.put(u)
+	})
+}
+
+type txBuckets struct {
+	urls        *bolt.Bucket
+	originals   *bolt.Bucket
+	ids         *bolt.Bucket
+	retired     *bolt.Bucket
+	retiredURLs *bolt.Bucket
+}
+
+func buckets(tx *bolt.Tx) txBuckets {
+	return txBuckets{
+		urls:        tx.Bucket(urlsBucket),
+		originals:   tx.Bucket(originalsBucket),
+		ids:         tx.Bucket(idsBucket),
+		retired:     tx.Bucket(retiredBucket),
+		retiredURLs: tx.Bucket(retiredURLsBucket),
+	}
+}
+
+func (b txBuckets) get(alias string) (*domain.URL, error) {
+	data := b.urls.Get([]byte(alias))
+	if data == nil {
+		return nil, persistence.ErrURLNotFound
+	}
+
+	var u domain.URL
+
+	err := json.Unmarshal(data, &u)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode url: %w", err)
+	}
+
+	return &u, nil
+}
+
+// put stores the URL and indexes it.
+func (b txBuckets) put(u *domain.URL) error {
+	data, err := json.Marshal(u)
+	if err != nil {
+		return fmt.Errorf("failed to encode url: %w", err)
+	}
+
+	err = b.urls.Put([]byte(u.Alias), data)
+	if err != nil {
+		return err
+	}
+
+	return b.index(u)
+}
+
+// replace stores u in place of old dropping the original URL of old from the index.
+func (b txBuckets) replace(old, u *domain.URL) error {
+	if u.Original != old.Original {
+		err := b.originals.Delete([]byte(old.Original))
+		if err != nil {
+			return err
+		}
+	}
+
+	return b.put(u)
+}
+
+// remove deletes the URL along with its indexes and retired aliases.
+func (b txBuckets) remove(u *domain.URL) error {
+	for _, del := range []func() error{
+		func() error { return b.urls.Delete([]byte(u.Alias)) },
+		func() error { return b.originals.Delete([]byte(u.Original)) },
+		func() error { return b.ids.Delete(idKey(u.ID)) },
+		func() error { return b.forget(u.ID) },
+	} {
+		if err := del(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (b txBuckets) index(u *domain.URL) error {
+	err := b.originals.Put([]byte(u.Original), []byte(u.Alias))
+	if err != nil {
+		return err
+	}
+
+	return b.ids.Put(idKey(u.ID), []byte(u.Alias))
+}
+
+// retire stores the retirement replacing the previous retirement of the alias by the same URL.
+func (b txBuckets) retire(ra *domain.RetiredAlias) error {
+	data, err := json.Marshal(ra)
+	if err != nil {
+		return fmt.Errorf("failed to encode retired alias: %w", err)
+	}
+
+	err = b.retired.Put(retiredKey(ra.Alias, ra.URLID), data)
+	if err != nil {
+		return err
+	}
+
+	return b.retiredURLs.Put(append(idKey(ra.URLID), ra.Alias...), nil)
+}
+
+// forget deletes the retired aliases of the URL.
+func (b txBuckets) forget(urlID int64) error {
+	prefix := idKey(urlID)
+
+	var keys [][]byte
+
+	c := b.retiredURLs.Cursor()
+	for k, _ := c.Seek(prefix); k != nil && bytes.HasPrefix(k, prefix); k, _ = c.Next() {
+		keys = append(keys, slices.Clone(k))
+	}
+
+	for _, k := range keys {
+		err := b.retired.Delete(retiredKey(string(k[len(prefix):]), urlID))
+		if err != nil {
+			return err
+		}
+
+		err = b.retiredURLs.Delete(k)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// edited returns a copy of old with the editable fields of u.
+// Changing the original URL resets its Open Graph metadata.
+func edited(old, u *domain.URL) *domain.URL {
+	updated := *old
+	updated.Original = u.Original
+	updated.QueryMode = u.QueryMode
+	updated.Preview = u.Preview
+	updated.Title = u.Title
+	updated.Description = u.Description
+	updated.Tags = u.Tags
+	updated.Metadata = u.Metadata
+
+	if updated.Original != old.Original {
+		updated.OpenGraph = nil
+	}
+
+	return &updated
+}
+
+// retiredPrefix is the prefix of the retiredBucket keys of the alias.
+func retiredPrefix(alias string) []byte {
+	return append([]byte(alias), 0)
+}
+
+func retiredKey(alias string, urlID int64) []byte {
+	return append(retiredPrefix(alias), idKey(urlID)...)
+}
+
+// idKey encodes the ID so that keys sort in the ID order.
+func idKey(id int64) []byte {
+	key := make([]byte, 8)
+	binary.BigEndian.PutUint64(key, uint64(id))
+
+	return key
+}
+
+func matches(u *domain.URL, filter persistence.URLFilter) bool {
+	if filter.Tag != "" && !slices.Contains(u.Tags, filter.Tag) {
+		return false
+	}
+
+	for key, value := range filter.Metadata {
+		if v, ok := u.Metadata[key]; !ok || v != value {
+			return false
+		}
+	}
+
+	return true
+}
+
+// wrap leaves persistence errors as is so callers can match them.
+func wrap(err error, msg string) error {
+	switch err {
+	case nil, persistence.ErrURLNotFound, persistence.ErrURLAlreadyExists, persistence.ErrDuplicateAlias,
+		persistence.ErrAliasNotFound:
+		return err
+	default:
+		return fmt.Errorf("%s: %w", msg, err)
+	}
+}
@@ -0,0 +1,116 @@
+package bolt_test
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/kodeyeen/shortify/internal/domain"
+	"github.com/kodeyeen/shortify/internal/persistence"
+	"github.com/kodeyeen/shortify/internal/persistence/bolt"
+	"github.com/kodeyeen/shortify/internal/persistence/repotest"
+	"github.com/stretchr/testify/require"
+)
+
+func TestURLRepository(t *testing.T) {
+	repotest.TestURLRepository(t, func(t *testing.T) repotest.URLRepository {
+		db, err := bolt.Open(filepath.Join(t.TempDir(), "shortify.bolt"))
+		require.NoError(t, err)
+
+		t.Cleanup(func() { db.Close() })
+
+		return bolt.NewURLRepository(db)
+	})
+}
+
+func TestAuditRepository(t *testing.T) {
+	repotest.TestAuditRepository(t, func(t *testing.T) repotest.AuditRepository {
+		db, err := bolt.Open(filepath.Join(t.TempDir(), "shortify.bolt"))
+		require.NoError(t, err)
+
+		t.Cleanup(func() { db.Close() })
+
+		return bolt.NewAuditRepository(db)
+	})
+}
+
+func TestURLRepository_Reopen(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "shortify.bolt")
+
+	// Given
+	db, err := bolt.Open(path)
+	require.NoError(t, err)
+
+	repo := bolt.NewURLRepository(db)
+
+	_, err = repo.Add(ctx, &domain.URL{Original: "https://example.com/1", Alias: "alias00001", Tags: []string{"promo"}})
+	require.NoError(t, err)
+
+	_, err = repo.Add(ctx, &domain.URL{Original: "https://example.com/2", Alias: "alias00002"})
+	require.NoError(t, err)
+
+	_, err = repo.SoftDelete(ctx, "alias00002", time.Now())
+	require.NoError(t, err)
+
+	_, err = repo.Purge(ctx, time.Now().Add(time.Second))
+	require.NoError(t, err)
+
+	require.NoError(t, db.Close())
+
+	// When
+	db, err = bolt.Open(path)
+	require.NoError(t, err)
+
+	defer db.Close()
+
+	repo = bolt.NewURLRepository(db)
+
+	// Then
+	u, err := repo.FindByAlias(ctx, "alias00001")
+	require.NoError(t, err)
+	require.Equal(t, int64(1), u.ID)
+
+	// IDs of deleted URLs aren't reused
+	id, err := repo.Add(ctx, &domain.URL{Original: "https://example.com/3", Alias: "alias00003"})
+	require.NoError(t, err)
+	require.Equal(t, int64(3), id)
+
+	_, err = repo.Add(ctx, &domain.URL{Original: "https://example.com/1", Alias: "alias00004"})
+	require.ErrorIs(t, err, persistence.ErrURLAlreadyExists)
+}
+
+func TestURLRepository_Reindex(t *testing.T) {
+	ctx := context.Background()
+
+	// Given
+	db, err := bolt.Open(filepath.Join(t.TempDir(), "shortify.bolt"))
+	require.NoError(t, err)
+
+	defer db.Close()
+
+	repo := bolt.NewURLRepository(db)
+
+	for _, u := range []*domain.URL{
+		{Original: "https://example.com/1", Alias: "alias00001"},
+		{Original: "https://example.com/2", Alias: "alias00002"},
+	} {
+		_, err := repo.Add(ctx, u)
+		require.NoError(t, err)
+	}
+
+	// When
+	err = repo.Reindex(ctx)
+
+	// Then
+	require.NoError(t, err)
+
+	urls, err := repo.List(ctx, persistence.URLFilter{})
+	require.NoError(t, err)
+	require.Len(t, urls, 2)
+	require.Equal(t, "alias00001", urls[0].Alias)
+
+	_, err = repo.Add(ctx, &domain.URL{Original: "https://example.com/2", Alias: "alias00003"})
+	require.ErrorIs(t, err, persistence.ErrURLAlreadyExists)
+}
@@ -0,0 +1,126 @@
+package inmemory
+
+import (
+	"context"
+	"maps"
+	"slices"
+	"sync"
+
+	"github.com/kodeyeen/shortify/internal/domain"
+)
+
+type AuditRepository struct {
+	// entries maps URL IDs to their entries in the order they were appended
+	entries map[int64][]*domain.AuditEntry
+
+	lastID int64
+
+	// wal is nil unless the repository is durable
+	wal *wal
+
+	mu *sync.RWMutex
+}
+
+func NewAuditRepository() *AuditRepository {
+	return &AuditRepository{
+		entries: map[int64][]*domain.AuditEntry{},
+
+		mu: &sync.RWMutex{},
+	}
+}
+
+// OpenAuditRepository returns an audit log that survives restarts by writing
+// every entry to a log in dir. The log is append-only so it's never compacted.
+// The previous entries are restored from dir.
+func OpenAuditRepository(dir string, cfg LogConfig) (*AuditRepository, error) {
+	r := NewAuditRepository()
+
+	apply := func(rec *record) {
+		if rec.Op == opAudit {
+			r.add(rec.Entry)
+		}
+	}
+
+	w, err := openWAL(dir, auditLogName, cfg, func(*snapshot) {}, apply)
+	if err != nil {
+		return nil, err
+	}
+
+	r.wal = w
+
+	return r, nil
+}
+
+// Close closes the log of a durable repository.
+func (r *AuditRepository) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.wal == nil {
+		return nil
+	}
+
+	err := r.wal.close()
+
+	r.wal = nil
+
+	return err
+}
+
+func (r *AuditRepository) Append(ctx context.Context, e *domain.AuditEntry) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored := cloneAuditEntry(e)
+	stored.ID = r.lastID + 1
+
+	if r.wal != nil {
+		err := r.wal.append(&record{Op: opAudit, Entry: stored})
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	r.add(stored)
+
+	return stored.ID, nil
+}
+
+// ListByURL returns the entries of the URL, the oldest first.
+func (r *AuditRepository) ListByURL(ctx context.Context, urlID int64) ([]*domain.AuditEntry, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	res := make([]*domain.AuditEntry, 0, len(r.entries[urlID]))
+
+	for _, e := range r.entries[urlID] {
+		res = append(res, cloneAuditEntry(e))
+	}
+
+	return res, nil
+}
+
+func (r *AuditRepository) add(e *domain.AuditEntry) {
+	r.entries[e.URLID] = append(r.entries[e.URLID], e)
+	r.lastID = max(r.lastID, e.ID)
+}
+
+func cloneAuditEntry(e *domain.AuditEntry) *domain.AuditEntry {
+	c := *e
+	c.Before = cloneURLState(e.Before)
+	c.After = cloneURLState(e.After)
+
+	return &c
+}
+
+func cloneURLState(s *domain.URLState) *domain.URLState {
+	if s == nil {
+		return nil
+	}
+
+	c := *s
+	c.Tags = slices.Clone(s.Tags)
+	c.Metadata = maps.Clone(s.Metadata)
+
+	return &c
+}
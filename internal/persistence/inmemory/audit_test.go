@@ -0,0 +1,67 @@
+package inmemory_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/kodeyeen/shortify/internal/domain"
+	"github.com/kodeyeen/shortify/internal/persistence/inmemory"
+	"github.com/kodeyeen/shortify/internal/persistence/repotest"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuditRepository(t *testing.T) {
+	repotest.TestAuditRepository(t, func(t *testing.T) repotest.AuditRepository {
+		return inmemory.NewAuditRepository()
+	})
+}
+
+func TestOpenAuditRepository_Conformance(t *testing.T) {
+	repotest.TestAuditRepository(t, func(t *testing.T) repotest.AuditRepository {
+		repo, err := inmemory.OpenAuditRepository(t.TempDir(), testLogConfig)
+		require.NoError(t, err)
+
+		t.Cleanup(func() { repo.Close() })
+
+		return repo
+	})
+}
+
+func TestOpenAuditRepository_Restore(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+
+	// Given
+	repo, err := inmemory.OpenAuditRepository(dir, testLogConfig)
+	require.NoError(t, err)
+
+	for _, action := range []string{domain.AuditActionCreated, domain.AuditActionUpdated, domain.AuditActionDeleted, domain.AuditActionRestored} {
+		_, err := repo.Append(ctx, &domain.AuditEntry{
+			URLID:  1,
+			Action: action,
+			After:  &domain.URLState{Alias: "alias00001", Tags: []string{"promo"}},
+		})
+		require.NoError(t, err)
+	}
+
+	require.NoError(t, repo.Close())
+
+	// When
+	repo, err = inmemory.OpenAuditRepository(dir, testLogConfig)
+	require.NoError(t, err)
+
+	defer repo.Close()
+
+	// Then
+	entries, err := repo.ListByURL(ctx, 1)
+	require.NoError(t, err)
+	require.Len(t, entries, 4)
+	require.Equal(t, int64(4), entries[3].ID)
+	require.Equal(t, domain.AuditActionRestored, entries[3].Action)
+	require.Equal(t, []string{"promo"}, entries[3].After.Tags)
+
+	// IDs go on after the restored entries
+	id, err := repo.Append(ctx, &domain.AuditEntry{URLID: 1, Action: domain.AuditActionUpdated})
+	require.NoError(t, err)
+	require.Equal(t, int64(5), id)
+}
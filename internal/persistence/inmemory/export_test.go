@@ -0,0 +1,10 @@
+package inmemory
+
+// Crash closes the log without compacting it as if the process died.
+func (r *URLRepository) Crash() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.wal.file.Close()
+	r.wal = nil
+}
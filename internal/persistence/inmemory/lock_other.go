@@ -0,0 +1,10 @@
+//go:build !unix
+
+package inmemory
+
+import "os"
+
+// lockFile is a no-op where flock isn't available.
+func lockFile(file *os.File) error {
+	return nil
+}
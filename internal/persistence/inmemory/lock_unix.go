@@ -0,0 +1,25 @@
+//go:build unix
+
+package inmemory
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"syscall"
+)
+
+// lockFile takes an exclusive lock of the log so two processes don't write it.
+// The lock is released when the file is closed.
+func lockFile(file *os.File) error {
+	err := syscall.Flock(int(file.Fd()), syscall.LOCK_EX|syscall.LOCK_NB)
+	if err != nil {
+		if errors.Is(err, syscall.EWOULDBLOCK) {
+			return ErrLocked
+		}
+
+		return fmt.Errorf("failed to lock log: %w", err)
+	}
+
+	return nil
+}
@@ -1,27 +1,135 @@
 package inmemory
 
 import (
+	"cmp"
 	"context"
+	"errors"
+	"maps"
+	"slices"
+	"strings"
 	"sync"
+	"time"
 
 	"github.com/kodeyeen/shortify/internal/domain"
 	"github.com/kodeyeen/shortify/internal/persistence"
 )
 
+// URLRepository keeps copies of the URLs so neither the URLs passed in
+// nor the returned ones share memory with the stored ones.
 type URLRepository struct {
+	idIdx map[int64]*domain.URL
+	// ids are the keys of idIdx in ascending order to page through the URLs
+	ids         []int64
 	originalIdx map[string]*domain.URL
-	aliasIdx    map[string]*domain.URL
+	// aliasIdx is keyed by key(alias)
+	aliasIdx map[string]*domain.URL
+	// tagIdx maps tags to the alias keys of URLs having them
+	tagIdx map[string]map[string]struct{}
+	// retiredIdx maps alias keys to their retirements, one per URL
+	retiredIdx map[string][]*domain.RetiredAlias
+	// retiredByURL maps URL IDs to their retired aliases
+	retiredByURL map[int64][]*domain.RetiredAlias
+
+	caseInsensitive bool
+
+	lastID int64
+
+	// wal is nil unless the repository is durable
+	wal *wal
 
 	mu *sync.RWMutex
 }
 
-func NewURLRepository() *URLRepository {
-	return &URLRepository{
+// URLOption configures a URLRepository.
+type URLOption func(r *URLRepository)
+
+// WithCaseInsensitiveAliases makes aliases match regardless of case.
+// Aliases differing only in case are duplicates then.
+func WithCaseInsensitiveAliases() URLOption {
+	return func(r *URLRepository) {
+		r.caseInsensitive = true
+	}
+}
+
+func NewURLRepository(opts ...URLOption) *URLRepository {
+	r := &URLRepository{
+		idIdx:       map[int64]*domain.URL{},
 		originalIdx: map[string]*domain.URL{},
 		aliasIdx:    map[string]*domain.URL{},
+		tagIdx:      map[string]map[string]struct{}{},
+
+		retiredIdx:   map[string][]*domain.RetiredAlias{},
+		retiredByURL: map[int64][]*domain.RetiredAlias{},
 
 		mu: &sync.RWMutex{},
 	}
+
+	for _, opt := range opts {
+		opt(r)
+	}
+
+	return r
+}
+
+// OpenURLRepository returns a repository that survives restarts by writing
+// every change to a log in dir and periodically compacting it into a snapshot.
+// The previous state is restored from dir.
+func OpenURLRepository(dir string, cfg LogConfig, opts ...URLOption) (*URLRepository, error) {
+	r := NewURLRepository(opts...)
+
+	restore := func(s *snapshot) {
+		r.lastID = s.LastID
+
+		for _, u := range s.URLs {
+			r.put(u)
+		}
+
+		for _, ra := range s.Retired {
+			r.retire(ra)
+		}
+	}
+
+	apply := func(rec *record) {
+		switch rec.Op {
+		case opPut:
+			r.put(rec.URL)
+		case opDelete:
+			if u, ok := r.aliasIdx[r.key(rec.Alias)]; ok {
+				r.remove(u)
+				r.forget(u.ID)
+			}
+		case opRename:
+			if u, ok := r.aliasIdx[r.key(rec.Alias)]; ok {
+				r.rename(u, rec.URL, rec.Retired)
+			}
+		}
+	}
+
+	w, err := openWAL(dir, urlsLogName, cfg, restore, apply)
+	if err != nil {
+		return nil, err
+	}
+
+	r.wal = w
+
+	return r, nil
+}
+
+// Close compacts the log of a durable repository and closes it.
+func (r *URLRepository) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.wal == nil {
+		return nil
+	}
+
+	snapErr := r.wal.snapshot(r.snapshot())
+	err := r.wal.close()
+
+	r.wal = nil
+
+	return errors.Join(snapErr, err)
 }
 
 func (r *URLRepository) Add(ctx context.Context, u *domain.URL) (int64, error) {
@@ -32,26 +140,582 @@ func (r *URLRepository) Add(ctx context.Context, u *domain.URL) (int64, error) {
 		return 0, persistence.ErrURLAlreadyExists
 	}
 
-	if _, ok := r.aliasIdx[u.Alias]; ok {
+	if _, ok := r.aliasIdx[r.key(u.Alias)]; ok {
 		return 0, persistence.ErrDuplicateAlias
 	}
 
-	id := int64(len(r.originalIdx) + 1)
+	stored := clone(u)
 
-	r.originalIdx[u.Original] = u
-	r.aliasIdx[u.Alias] = u
+	// IDs are never reused, even after deletion
+	stored.ID = r.lastID + 1
+
+	err := r.logPut(stored)
+	if err != nil {
+		return 0, err
+	}
 
-	return id, nil
+	r.lastID = stored.ID
+	r.insert(stored)
+
+	r.compact()
+
+	return stored.ID, nil
+}
+
+func (r *URLRepository) FindByID(ctx context.Context, id int64) (*domain.URL, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	u, ok := r.idIdx[id]
+	if !ok {
+		return nil, persistence.ErrURLNotFound
+	}
+
+	return clone(u), nil
 }
 
 func (r *URLRepository) FindByAlias(ctx context.Context, alias string) (*domain.URL, error) {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 
-	u, ok := r.aliasIdx[alias]
+	u, ok := r.aliasIdx[r.key(alias)]
+	if !ok {
+		return nil, persistence.ErrURLNotFound
+	}
+
+	return clone(u), nil
+}
+
+func (r *URLRepository) FindByOriginal(ctx context.Context, original string) (*domain.URL, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	u, ok := r.originalIdx[original]
+	if !ok {
+		return nil, persistence.ErrURLNotFound
+	}
+
+	return clone(u), nil
+}
+
+func (r *URLRepository) Count(ctx context.Context) (int64, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return int64(len(r.aliasIdx)), nil
+}
+
+func (r *URLRepository) List(ctx context.Context, filter persistence.URLFilter) ([]*domain.URL, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	tagged := r.tagIdx[filter.Tag]
+	if filter.Tag != "" && len(tagged) == 0 {
+		return []*domain.URL{}, nil
+	}
+
+	// the URLs are walked in ID order from AfterID so a page costs
+	// as much as the URLs it skips rather than all of them
+	start, _ := slices.BinarySearch(r.ids, filter.AfterID+1)
+
+	res := []*domain.URL{}
+	skip := filter.Offset
+
+	for _, id := range r.ids[start:] {
+		if filter.Limit > 0 && len(res) == filter.Limit {
+			break
+		}
+
+		u := r.idIdx[id]
+
+		if u.DeletedAt != nil || !matchesMetadata(u, filter.Metadata) {
+			continue
+		}
+
+		if _, ok := tagged[r.key(u.Alias)]; filter.Tag != "" && !ok {
+			continue
+		}
+
+		if skip > 0 {
+			skip--
+			continue
+		}
+
+		res = append(res, clone(u))
+	}
+
+	return res, nil
+}
+
+// Update updates the editable fields of the URL with the same alias.
+// Changing the original URL resets its Open Graph metadata.
+func (r *URLRepository) Update(ctx context.Context, u *domain.URL) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	old, ok := r.aliasIdx[r.key(u.Alias)]
 	if !ok {
+		return persistence.ErrURLNotFound
+	}
+
+	if other, ok := r.originalIdx[u.Original]; ok && other != old {
+		return persistence.ErrURLAlreadyExists
+	}
+
+	updated := edited(old, u)
+
+	err := r.logPut(updated)
+	if err != nil {
+		return err
+	}
+
+	r.replace(old, updated)
+	r.compact()
+
+	return nil
+}
+
+// UpdateOpenGraph stores the metadata of the URL with the id unless its original URL
+// is no longer original, failing with persistence.ErrURLNotFound then.
+func (r *URLRepository) UpdateOpenGraph(ctx context.Context, id int64, original string, og *domain.OpenGraph) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	u, ok := r.idIdx[id]
+	if !ok || u.Original != original {
+		return persistence.ErrURLNotFound
+	}
+
+	// replace rather than mutate so URLs returned earlier stay untouched
+	updated := *u
+	updated.OpenGraph = cloneOpenGraph(og)
+
+	err := r.logPut(&updated)
+	if err != nil {
+		return err
+	}
+
+	r.replace(u, &updated)
+	r.compact()
+
+	return nil
+}
+
+func (r *URLRepository) IncrementClicks(ctx context.Context, alias string) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	u, ok := r.aliasIdx[r.key(alias)]
+	if !ok {
+		return 0, persistence.ErrURLNotFound
+	}
+
+	updated := *u
+	updated.Clicks++
+
+	err := r.logPut(&updated)
+	if err != nil {
+		return 0, err
+	}
+
+	r.replace(u, &updated)
+	r.compact()
+
+	return updated.Clicks, nil
+}
+
+// SoftDelete moves the URL to the trash keeping its alias and original URL taken.
+func (r *URLRepository) SoftDelete(ctx context.Context, alias string, deletedAt time.Time) (*domain.URL, error) {
+	return r.setDeletedAt(alias, &deletedAt)
+}
+
+// Restore takes the URL out of the trash.
+func (r *URLRepository) Restore(ctx context.Context, alias string) (*domain.URL, error) {
+	return r.setDeletedAt(alias, nil)
+}
+
+// Purge deletes the URLs moved to the trash before deletedBefore along with their retired aliases.
+func (r *URLRepository) Purge(ctx context.Context, deletedBefore time.Time) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var purged int64
+
+	for _, u := range r.aliasIdx {
+		if u.DeletedAt == nil || !u.DeletedAt.Before(deletedBefore) {
+			continue
+		}
+
+		err := r.logDelete(u.Alias)
+		if err != nil {
+			return purged, err
+		}
+
+		r.remove(u)
+		r.forget(u.ID)
+
+		purged++
+	}
+
+	r.compact()
+
+	return purged, nil
+}
+
+// setDeletedAt moves the URL to the trash or takes it out of there.
+// The URL must be in the opposite state.
+func (r *URLRepository) setDeletedAt(alias string, deletedAt *time.Time) (*domain.URL, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	u, ok := r.aliasIdx[r.key(alias)]
+	if !ok || (u.DeletedAt == nil) == (deletedAt == nil) {
 		return nil, persistence.ErrURLNotFound
 	}
 
-	return u, nil
+	updated := *u
+	updated.DeletedAt = cloneTime(deletedAt)
+
+	err := r.logPut(&updated)
+	if err != nil {
+		return nil, err
+	}
+
+	r.replace(u, &updated)
+	r.compact()
+
+	return clone(&updated), nil
+}
+
+func (r *URLRepository) Rename(ctx context.Context, alias string, u *domain.URL, retiredAt time.Time) (*domain.URL, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	old, ok := r.aliasIdx[r.key(alias)]
+	if !ok {
+		return nil, persistence.ErrURLNotFound
+	}
+
+	if other, ok := r.aliasIdx[r.key(u.Alias)]; ok && other != old {
+		return nil, persistence.ErrDuplicateAlias
+	}
+
+	if other, ok := r.originalIdx[u.Original]; ok && other != old {
+		return nil, persistence.ErrURLAlreadyExists
+	}
+
+	renamed := edited(old, u)
+	renamed.Alias = u.Alias
+
+	retired := &domain.RetiredAlias{
+		Alias:     old.Alias,
+		URLID:     old.ID,
+		RetiredAt: retiredAt,
+	}
+
+	if r.wal != nil {
+		err := r.wal.append(&record{Op: opRename, Alias: old.Alias, URL: renamed, Retired: retired})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	r.rename(old, renamed, retired)
+	r.compact()
+
+	return clone(renamed), nil
+}
+
+// FindRetiredAlias returns the latest retirement of the alias.
+func (r *URLRepository) FindRetiredAlias(ctx context.Context, alias string) (*domain.RetiredAlias, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	retirements := r.retiredIdx[r.key(alias)]
+	if len(retirements) == 0 {
+		return nil, persistence.ErrAliasNotFound
+	}
+
+	latest := slices.MaxFunc(retirements, func(a, b *domain.RetiredAlias) int {
+		return a.RetiredAt.Compare(b.RetiredAt)
+	})
+
+	res := *latest
+
+	return &res, nil
+}
+
+// ListRetiredAliases returns the retired aliases of the URL, the most recently retired first.
+func (r *URLRepository) ListRetiredAliases(ctx context.Context, urlID int64) ([]*domain.RetiredAlias, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	res := make([]*domain.RetiredAlias, 0, len(r.retiredByURL[urlID]))
+
+	for _, ra := range r.retiredByURL[urlID] {
+		c := *ra
+		res = append(res, &c)
+	}
+
+	slices.SortFunc(res, func(a, b *domain.RetiredAlias) int {
+		return b.RetiredAt.Compare(a.RetiredAt)
+	})
+
+	return res, nil
+}
+
+// Reindex rebuilds the ID, original URL and tag indexes from the alias index.
+func (r *URLRepository) Reindex(ctx context.Context) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.idIdx = make(map[int64]*domain.URL, len(r.aliasIdx))
+	r.ids = make([]int64, 0, len(r.aliasIdx))
+	r.originalIdx = make(map[string]*domain.URL, len(r.aliasIdx))
+	r.tagIdx = map[string]map[string]struct{}{}
+
+	for _, u := range r.aliasIdx {
+		r.idIdx[u.ID] = u
+		r.ids = append(r.ids, u.ID)
+		r.originalIdx[u.Original] = u
+		r.indexTags(u)
+	}
+
+	slices.Sort(r.ids)
+
+	return nil
+}
+
+// put inserts u or replaces the URL with the same alias while restoring the state.
+func (r *URLRepository) put(u *domain.URL) {
+	if old, ok := r.aliasIdx[r.key(u.Alias)]; ok {
+		r.replace(old, u)
+	} else {
+		r.insert(u)
+	}
+
+	r.lastID = max(r.lastID, u.ID)
+}
+
+// rename replaces old URL with u under its new alias and records the retired alias.
+func (r *URLRepository) rename(old, u *domain.URL, retired *domain.RetiredAlias) {
+	r.remove(old)
+	r.insert(u)
+	r.retire(retired)
+}
+
+// retire records the retirement replacing the previous retirement of the alias by the same URL.
+func (r *URLRepository) retire(ra *domain.RetiredAlias) {
+	key := r.key(ra.Alias)
+	sameURL := func(other *domain.RetiredAlias) bool {
+		return other.URLID == ra.URLID
+	}
+
+	r.retiredIdx[key] = append(slices.DeleteFunc(r.retiredIdx[key], sameURL), ra)
+	r.retiredByURL[ra.URLID] = append(slices.DeleteFunc(r.retiredByURL[ra.URLID], func(other *domain.RetiredAlias) bool {
+		return r.key(other.Alias) == key
+	}), ra)
+}
+
+// forget drops the retired aliases of the URL.
+func (r *URLRepository) forget(urlID int64) {
+	for _, ra := range r.retiredByURL[urlID] {
+		key := r.key(ra.Alias)
+
+		r.retiredIdx[key] = slices.DeleteFunc(r.retiredIdx[key], func(other *domain.RetiredAlias) bool {
+			return other.URLID == urlID
+		})
+
+		if len(r.retiredIdx[key]) == 0 {
+			delete(r.retiredIdx, key)
+		}
+	}
+
+	delete(r.retiredByURL, urlID)
+}
+
+// key returns the alias index key of alias.
+func (r *URLRepository) key(alias string) string {
+	if r.caseInsensitive {
+		return strings.ToLower(alias)
+	}
+
+	return alias
+}
+
+func (r *URLRepository) insert(u *domain.URL) {
+	r.idIdx[u.ID] = u
+	r.indexID(u.ID)
+	r.originalIdx[u.Original] = u
+	r.aliasIdx[r.key(u.Alias)] = u
+	r.indexTags(u)
+}
+
+func (r *URLRepository) remove(u *domain.URL) {
+	delete(r.idIdx, u.ID)
+	r.unindexID(u.ID)
+	delete(r.originalIdx, u.Original)
+	delete(r.aliasIdx, r.key(u.Alias))
+	r.unindexTags(u)
+}
+
+func (r *URLRepository) logPut(u *domain.URL) error {
+	if r.wal == nil {
+		return nil
+	}
+
+	return r.wal.append(&record{Op: opPut, URL: u})
+}
+
+func (r *URLRepository) logDelete(alias string) error {
+	if r.wal == nil {
+		return nil
+	}
+
+	return r.wal.append(&record{Op: opDelete, Alias: alias})
+}
+
+// compact writes a snapshot once the log has grown enough.
+// A failed snapshot isn't fatal as the log still has the changes,
+// it's retried on the next change.
+func (r *URLRepository) compact() {
+	if r.wal == nil || !r.wal.needsSnapshot() {
+		return
+	}
+
+	_ = r.wal.snapshot(r.snapshot())
+}
+
+func (r *URLRepository) snapshot() *snapshot {
+	s := &snapshot{
+		LastID: r.lastID,
+		URLs:   make([]*domain.URL, 0, len(r.aliasIdx)),
+	}
+
+	for _, u := range r.aliasIdx {
+		s.URLs = append(s.URLs, u)
+	}
+
+	slices.SortFunc(s.URLs, func(a, b *domain.URL) int {
+		return cmp.Compare(a.ID, b.ID)
+	})
+
+	for _, retired := range r.retiredByURL {
+		s.Retired = append(s.Retired, retired...)
+	}
+
+	slices.SortFunc(s.Retired, func(a, b *domain.RetiredAlias) int {
+		return cmp.Or(cmp.Compare(a.URLID, b.URLID), a.RetiredAt.Compare(b.RetiredAt))
+	})
+
+	return s
+}
+
+// replace replaces old URL with u in all indexes.
+func (r *URLRepository) replace(old, u *domain.URL) {
+	u.ID = old.ID
+
+	delete(r.originalIdx, old.Original)
+	r.unindexTags(old)
+
+	r.idIdx[u.ID] = u
+	r.originalIdx[u.Original] = u
+	r.aliasIdx[r.key(u.Alias)] = u
+	r.indexTags(u)
+}
+
+// indexID adds the ID to ids, new IDs are the largest ones so it's usually appended.
+func (r *URLRepository) indexID(id int64) {
+	i, found := slices.BinarySearch(r.ids, id)
+	if !found {
+		r.ids = slices.Insert(r.ids, i, id)
+	}
+}
+
+func (r *URLRepository) unindexID(id int64) {
+	i, found := slices.BinarySearch(r.ids, id)
+	if found {
+		r.ids = slices.Delete(r.ids, i, i+1)
+	}
+}
+
+func (r *URLRepository) indexTags(u *domain.URL) {
+	for _, tag := range u.Tags {
+		aliases, ok := r.tagIdx[tag]
+		if !ok {
+			aliases = map[string]struct{}{}
+			r.tagIdx[tag] = aliases
+		}
+
+		aliases[r.key(u.Alias)] = struct{}{}
+	}
+}
+
+func (r *URLRepository) unindexTags(u *domain.URL) {
+	for _, tag := range u.Tags {
+		delete(r.tagIdx[tag], r.key(u.Alias))
+
+		if len(r.tagIdx[tag]) == 0 {
+			delete(r.tagIdx, tag)
+		}
+	}
+}
+
+func matchesMetadata(u *domain.URL, metadata map[string]string) bool {
+	for key, value := range metadata {
+		if v, ok := u.Metadata[key]; !ok || v != value {
+			return false
+		}
+	}
+
+	return true
+}
+
+// edited returns a copy of old with the editable fields of u.
+func edited(old, u *domain.URL) *domain.URL {
+	updated := *old
+	updated.Original = u.Original
+	updated.QueryMode = u.QueryMode
+	updated.Preview = u.Preview
+	updated.Title = u.Title
+	updated.Description = u.Description
+	updated.Tags = slices.Clone(u.Tags)
+	updated.Metadata = maps.Clone(u.Metadata)
+
+	if updated.Original != old.Original {
+		updated.OpenGraph = nil
+	}
+
+	return &updated
+}
+
+// clone copies u deeply so the copy shares no memory with it.
+func clone(u *domain.URL) *domain.URL {
+	c := *u
+	c.Tags = slices.Clone(u.Tags)
+	c.Metadata = maps.Clone(u.Metadata)
+	c.OpenGraph = cloneOpenGraph(u.OpenGraph)
+	c.DeletedAt = cloneTime(u.DeletedAt)
+
+	return &c
+}
+
+func cloneOpenGraph(og *domain.OpenGraph) *domain.OpenGraph {
+	if og == nil {
+		return nil
+	}
+
+	c := *og
+
+	return &c
+}
+
+func cloneTime(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+
+	c := *t
+
+	return &c
 }
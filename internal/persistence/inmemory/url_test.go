@@ -0,0 +1,20 @@
+package inmemory_test
+
+import (
+	"testing"
+
+	"github.com/kodeyeen/shortify/internal/persistence/inmemory"
+	"github.com/kodeyeen/shortify/internal/persistence/repotest"
+)
+
+func TestURLRepository(t *testing.T) {
+	repotest.TestURLRepository(t, func(t *testing.T) repotest.URLRepository {
+		return inmemory.NewURLRepository()
+	})
+}
+
+func TestURLRepository_CaseInsensitive(t *testing.T) {
+	repotest.TestCaseInsensitiveURLRepository(t, func(t *testing.T) repotest.URLRepository {
+		return inmemory.NewURLRepository(inmemory.WithCaseInsensitiveAliases())
+	})
+}
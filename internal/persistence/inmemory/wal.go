@@ -0,0 +1,468 @@
+package inmemory
+
+import (
+	"bufio"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/kodeyeen/shortify/internal/domain"
+)
+
+var (
+	// ErrCorrupted is returned when a log record or the snapshot fails the checksum.
+	ErrCorrupted = errors.New("storage file is corrupted")
+	// ErrLocked is returned when the storage is open by another process.
+	ErrLocked = errors.New("storage is locked by another process")
+)
+
+// FsyncPolicy defines when the log is flushed to disk.
+type FsyncPolicy string
+
+const (
+	// FsyncAlways flushes every record before the change is acknowledged.
+	FsyncAlways FsyncPolicy = "always"
+	// FsyncInterval flushes in the background so the last interval may be lost on a crash.
+	FsyncInterval FsyncPolicy = "interval"
+	// FsyncNever leaves flushing to the operating system.
+	FsyncNever FsyncPolicy = "never"
+)
+
+// LogConfig configures the write-ahead log of a durable repository.
+type LogConfig struct {
+	Fsync         FsyncPolicy
+	FsyncInterval time.Duration
+	// SnapshotEvery is the number of log records after which the state
+	// is written to a snapshot and the log starts over.
+	SnapshotEvery int
+}
+
+// Names of the logs, the log and the snapshot files are named after them.
+const (
+	urlsLogName  = "urls"
+	auditLogName = "audit"
+)
+
+const (
+	logFileExt      = ".wal"
+	snapshotFileExt = ".snapshot"
+
+	// frameHeaderSize is the size of the record length and its CRC-32 checksum.
+	frameHeaderSize = 8
+
+	maxRecordSize   = 16 << 20
+	maxSnapshotSize = 1 << 30
+)
+
+var crcTable = crc32.MakeTable(crc32.Castagnoli)
+
+// Record operations. Records hold the whole state of the URL
+// so replaying a record twice gives the same result.
+const (
+	opPut    = "put"
+	opDelete = "delete"
+	// opRename replaces the URL with Alias by URL retiring the alias
+	opRename = "rename"
+	// opAudit appends Entry to the audit log
+	opAudit = "audit"
+)
+
+type record struct {
+	Op      string               `json:"op"`
+	URL     *domain.URL          `json:"url,omitempty"`
+	Alias   string               `json:"alias,omitempty"`
+	Retired *domain.RetiredAlias `json:"retired,omitempty"`
+	Entry   *domain.AuditEntry   `json:"entry,omitempty"`
+}
+
+type snapshot struct {
+	LastID  int64                  `json:"last_id"`
+	URLs    []*domain.URL          `json:"urls"`
+	Retired []*domain.RetiredAlias `json:"retired,omitempty"`
+}
+
+// wal is an append-only log of URL changes. Every record is framed
+// with its length and checksum so torn writes and corruption are detected.
+type wal struct {
+	dir  string
+	name string
+	cfg  LogConfig
+	file *os.File
+
+	// records is the number of records written since the last snapshot
+	records int
+	dirty   bool
+
+	stop chan struct{}
+	done chan struct{}
+
+	mu sync.Mutex
+}
+
+func (c LogConfig) validate() error {
+	switch c.Fsync {
+	case FsyncAlways, FsyncNever:
+	case FsyncInterval:
+		if c.FsyncInterval <= 0 {
+			return errors.New("fsync interval must be positive")
+		}
+	default:
+		return fmt.Errorf("invalid fsync policy %q", c.Fsync)
+	}
+
+	if c.SnapshotEvery <= 0 {
+		return errors.New("snapshot threshold must be positive")
+	}
+
+	return nil
+}
+
+// openWAL opens the log with name in dir calling restore for the snapshot and apply for every record.
+// A record torn by a crash at the end of the log is dropped.
+func openWAL(dir, name string, cfg LogConfig, restore func(s *snapshot), apply func(rec *record)) (*wal, error) {
+	err := cfg.validate()
+	if err != nil {
+		return nil, err
+	}
+
+	err = os.MkdirAll(dir, 0o755)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create storage dir: %w", err)
+	}
+
+	file, err := os.OpenFile(filepath.Join(dir, name+logFileExt), os.O_RDWR|os.O_CREATE, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open log: %w", err)
+	}
+
+	// the snapshot is read under the lock as well since it's replaced on compaction
+	err = lockFile(file)
+	if err == nil {
+		err = readSnapshot(filepath.Join(dir, name+snapshotFileExt), restore)
+	}
+
+	if err != nil {
+		file.Close()
+
+		return nil, err
+	}
+
+	records, err := replay(file, apply)
+	if err != nil {
+		file.Close()
+
+		return nil, err
+	}
+
+	w := &wal{
+		dir:     dir,
+		name:    name,
+		cfg:     cfg,
+		file:    file,
+		records: records,
+	}
+
+	if cfg.Fsync == FsyncInterval {
+		w.stop = make(chan struct{})
+		w.done = make(chan struct{})
+
+		go w.syncLoop()
+	}
+
+	return w, nil
+}
+
+// append writes the record to the log.
+func (w *wal) append(rec *record) error {
+	payload, err := json.Marshal(rec)
+	if err != nil {
+		return fmt.Errorf("failed to encode log record: %w", err)
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	_, err = w.file.Write(frame(payload))
+	if err != nil {
+		return fmt.Errorf("failed to write log record: %w", err)
+	}
+
+	w.records++
+
+	if w.cfg.Fsync == FsyncAlways {
+		err = w.file.Sync()
+		if err != nil {
+			return fmt.Errorf("failed to sync log: %w", err)
+		}
+	} else {
+		w.dirty = true
+	}
+
+	return nil
+}
+
+// needsSnapshot tells whether the log has grown enough to be compacted.
+func (w *wal) needsSnapshot() bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return w.records >= w.cfg.SnapshotEvery
+}
+
+// snapshot atomically replaces the snapshot and empties the log.
+// The caller must prevent appends until it returns.
+func (w *wal) snapshot(s *snapshot) error {
+	payload, err := json.Marshal(s)
+	if err != nil {
+		return fmt.Errorf("failed to encode snapshot: %w", err)
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	path := filepath.Join(w.dir, w.name+snapshotFileExt)
+	tmpPath := path + ".tmp"
+
+	err = writeFileSync(tmpPath, frame(payload))
+	if err != nil {
+		return fmt.Errorf("failed to write snapshot: %w", err)
+	}
+
+	err = os.Rename(tmpPath, path)
+	if err != nil {
+		return fmt.Errorf("failed to replace snapshot: %w", err)
+	}
+
+	err = syncDir(w.dir)
+	if err != nil {
+		return err
+	}
+
+	// a crash before the truncation replays records already in the snapshot
+	// which is harmless as they are idempotent
+	err = w.file.Truncate(0)
+	if err != nil {
+		return fmt.Errorf("failed to truncate log: %w", err)
+	}
+
+	_, err = w.file.Seek(0, io.SeekStart)
+	if err != nil {
+		return fmt.Errorf("failed to rewind log: %w", err)
+	}
+
+	w.records = 0
+
+	return nil
+}
+
+// close flushes and closes the log.
+func (w *wal) close() error {
+	if w.stop != nil {
+		close(w.stop)
+		<-w.done
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	err := w.file.Sync()
+	if err != nil {
+		w.file.Close()
+
+		return fmt.Errorf("failed to sync log: %w", err)
+	}
+
+	return w.file.Close()
+}
+
+func (w *wal) syncLoop() {
+	defer close(w.done)
+
+	ticker := time.NewTicker(w.cfg.FsyncInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-w.stop:
+			return
+		case <-ticker.C:
+			w.mu.Lock()
+
+			if w.dirty {
+				// a failed sync is retried on the next tick
+				if err := w.file.Sync(); err == nil {
+					w.dirty = false
+				}
+			}
+
+			w.mu.Unlock()
+		}
+	}
+}
+
+// replay applies the records of the log returning their number.
+// The log is truncated after the last complete record.
+func replay(file *os.File, apply func(rec *record)) (int, error) {
+	r := bufio.NewReader(file)
+
+	var (
+		offset  int64
+		records int
+	)
+
+	for {
+		payload, err := readFrame(r, maxRecordSize)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		if errors.Is(err, io.ErrUnexpectedEOF) {
+			err := file.Truncate(offset)
+			if err != nil {
+				return 0, fmt.Errorf("failed to truncate torn log record: %w", err)
+			}
+
+			break
+		}
+
+		if err != nil {
+			return 0, fmt.Errorf("log record at offset %d: %w", offset, err)
+		}
+
+		var rec record
+
+		err = json.Unmarshal(payload, &rec)
+		if err != nil {
+			return 0, fmt.Errorf("log record at offset %d: %w: %s", offset, ErrCorrupted, err)
+		}
+
+		apply(&rec)
+
+		offset += int64(frameHeaderSize + len(payload))
+		records++
+	}
+
+	_, err := file.Seek(offset, io.SeekStart)
+	if err != nil {
+		return 0, fmt.Errorf("failed to seek log: %w", err)
+	}
+
+	return records, nil
+}
+
+func readSnapshot(path string, restore func(s *snapshot)) error {
+	file, err := os.Open(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+
+		return fmt.Errorf("failed to open snapshot: %w", err)
+	}
+	defer file.Close()
+
+	// snapshots are replaced atomically so a short one is corrupted too
+	payload, err := readFrame(bufio.NewReader(file), maxSnapshotSize)
+	if err != nil {
+		if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+			return fmt.Errorf("snapshot: %w: unexpected end of file", ErrCorrupted)
+		}
+
+		return fmt.Errorf("snapshot: %w", err)
+	}
+
+	var s snapshot
+
+	err = json.Unmarshal(payload, &s)
+	if err != nil {
+		return fmt.Errorf("snapshot: %w: %s", ErrCorrupted, err)
+	}
+
+	restore(&s)
+
+	return nil
+}
+
+func frame(payload []byte) []byte {
+	buf := make([]byte, frameHeaderSize, frameHeaderSize+len(payload))
+
+	binary.LittleEndian.PutUint32(buf[0:4], uint32(len(payload)))
+	binary.LittleEndian.PutUint32(buf[4:8], crc32.Checksum(payload, crcTable))
+
+	return append(buf, payload...)
+}
+
+// readFrame returns io.EOF at the end of r and io.ErrUnexpectedEOF
+// when the frame is cut short.
+func readFrame(r io.Reader, maxSize int) ([]byte, error) {
+	header := make([]byte, frameHeaderSize)
+
+	_, err := io.ReadFull(r, header)
+	if err != nil {
+		return nil, err
+	}
+
+	size := binary.LittleEndian.Uint32(header[0:4])
+	checksum := binary.LittleEndian.Uint32(header[4:8])
+
+	if size == 0 || size > uint32(maxSize) {
+		return nil, fmt.Errorf("%w: invalid record size %d", ErrCorrupted, size)
+	}
+
+	payload := make([]byte, size)
+
+	_, err = io.ReadFull(r, payload)
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, io.ErrUnexpectedEOF
+		}
+
+		return nil, err
+	}
+
+	if crc32.Checksum(payload, crcTable) != checksum {
+		return nil, fmt.Errorf("%w: checksum mismatch", ErrCorrupted)
+	}
+
+	return payload, nil
+}
+
+func writeFileSync(path string, data []byte) error {
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o644)
+	if err != nil {
+		return err
+	}
+
+	_, err = file.Write(data)
+	if err == nil {
+		err = file.Sync()
+	}
+
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+
+	return err
+}
+
+func syncDir(dir string) error {
+	d, err := os.Open(dir)
+	if err != nil {
+		return fmt.Errorf("failed to open storage dir: %w", err)
+	}
+	defer d.Close()
+
+	err = d.Sync()
+	if err != nil {
+		return fmt.Errorf("failed to sync storage dir: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,304 @@
+package inmemory_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/kodeyeen/shortify/internal/domain"
+	"github.com/kodeyeen/shortify/internal/persistence"
+	"github.com/kodeyeen/shortify/internal/persistence/inmemory"
+	"github.com/kodeyeen/shortify/internal/persistence/repotest"
+	"github.com/stretchr/testify/require"
+)
+
+var testLogConfig = inmemory.LogConfig{
+	Fsync:         inmemory.FsyncAlways,
+	SnapshotEvery: 3,
+}
+
+func TestOpenURLRepository_Conformance(t *testing.T) {
+	repotest.TestURLRepository(t, func(t *testing.T) repotest.URLRepository {
+		repo, err := inmemory.OpenURLRepository(t.TempDir(), testLogConfig)
+		require.NoError(t, err)
+
+		t.Cleanup(func() { repo.Close() })
+
+		return repo
+	})
+}
+
+func TestOpenURLRepository_Restore(t *testing.T) {
+	testCases := map[string]struct {
+		close bool
+	}{
+		"After close":   {close: true},
+		"Without close": {close: false},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := context.Background()
+			dir := t.TempDir()
+
+			// Given
+			repo, err := inmemory.OpenURLRepository(dir, testLogConfig)
+			require.NoError(t, err)
+
+			fillRepo(t, repo)
+
+			if tc.close {
+				require.NoError(t, repo.Close())
+			} else {
+				repo.Crash()
+			}
+
+			// When
+			repo, err = inmemory.OpenURLRepository(dir, testLogConfig)
+
+			// Then
+			require.NoError(t, err)
+			t.Cleanup(func() { repo.Close() })
+
+			requireFilled(t, repo)
+
+			// IDs of deleted URLs aren't reused
+			id, err := repo.Add(ctx, &domain.URL{Original: "https://example.com/4", Alias: "alias00004"})
+			require.NoError(t, err)
+			require.Equal(t, int64(4), id)
+		})
+	}
+}
+
+func TestOpenURLRepository_TornRecord(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+
+	// Given
+	repo, err := inmemory.OpenURLRepository(dir, inmemory.LogConfig{Fsync: inmemory.FsyncAlways, SnapshotEvery: 100})
+	require.NoError(t, err)
+
+	fillRepo(t, repo)
+	repo.Crash()
+
+	appendFile(t, filepath.Join(dir, "urls.wal"), []byte{42, 0, 0, 0, 1, 2})
+
+	// When
+	repo, err = inmemory.OpenURLRepository(dir, testLogConfig)
+
+	// Then
+	require.NoError(t, err)
+
+	requireFilled(t, repo)
+
+	_, err = repo.Add(ctx, &domain.URL{Original: "https://example.com/4", Alias: "alias00004"})
+	require.NoError(t, err)
+
+	repo.Crash()
+
+	// the torn record was cut off so the new one is readable
+	repo, err = inmemory.OpenURLRepository(dir, testLogConfig)
+	require.NoError(t, err)
+	t.Cleanup(func() { repo.Close() })
+
+	_, err = repo.FindByAlias(ctx, "alias00004")
+	require.NoError(t, err)
+}
+
+func TestOpenURLRepository_Corrupted(t *testing.T) {
+	testCases := map[string]struct {
+		file    string
+		corrupt func(data []byte) []byte
+	}{
+		"Log checksum": {
+			file: "urls.wal",
+			corrupt: func(data []byte) []byte {
+				data[20] ^= 0xff
+				return data
+			},
+		},
+		"Log size": {
+			file: "urls.wal",
+			corrupt: func(data []byte) []byte {
+				return append(data, 0, 0, 0, 0xff, 0, 0, 0, 0)
+			},
+		},
+		"Snapshot checksum": {
+			file: "urls.snapshot",
+			corrupt: func(data []byte) []byte {
+				data[len(data)-2] ^= 0xff
+				return data
+			},
+		},
+		"Snapshot cut short": {
+			file: "urls.snapshot",
+			corrupt: func(data []byte) []byte {
+				return data[:len(data)-1]
+			},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			dir := t.TempDir()
+
+			// Given
+			repo, err := inmemory.OpenURLRepository(dir, inmemory.LogConfig{Fsync: inmemory.FsyncAlways, SnapshotEvery: 5})
+			require.NoError(t, err)
+
+			// 5 records go to the snapshot, the rest stay in the log
+			fillRepo(t, repo)
+			repo.Crash()
+
+			path := filepath.Join(dir, tc.file)
+
+			data, err := os.ReadFile(path)
+			require.NoError(t, err)
+			require.NoError(t, os.WriteFile(path, tc.corrupt(data), 0o644))
+
+			// When
+			_, err = inmemory.OpenURLRepository(dir, testLogConfig)
+
+			// Then
+			require.ErrorIs(t, err, inmemory.ErrCorrupted)
+		})
+	}
+}
+
+func TestOpenURLRepository_FsyncInterval(t *testing.T) {
+	dir := t.TempDir()
+
+	// Given
+	repo, err := inmemory.OpenURLRepository(dir, inmemory.LogConfig{
+		Fsync:         inmemory.FsyncInterval,
+		FsyncInterval: time.Millisecond,
+		SnapshotEvery: 100,
+	})
+	require.NoError(t, err)
+
+	// When
+	fillRepo(t, repo)
+	require.NoError(t, repo.Close())
+
+	// Then
+	repo, err = inmemory.OpenURLRepository(dir, testLogConfig)
+	require.NoError(t, err)
+
+	requireFilled(t, repo)
+}
+
+func TestOpenURLRepository_InvalidConfig(t *testing.T) {
+	testCases := map[string]inmemory.LogConfig{
+		"Unknown fsync policy": {Fsync: "sometimes", SnapshotEvery: 1},
+		"Zero fsync interval":  {Fsync: inmemory.FsyncInterval, SnapshotEvery: 1},
+		"Zero snapshot":        {Fsync: inmemory.FsyncNever},
+	}
+
+	for name, cfg := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := inmemory.OpenURLRepository(t.TempDir(), cfg)
+			require.Error(t, err)
+		})
+	}
+}
+
+// fillRepo writes 8 log records.
+func fillRepo(t *testing.T, repo *inmemory.URLRepository) {
+	t.Helper()
+
+	ctx := context.Background()
+
+	for i, alias := range []string{"alias00001", "alias00002", "alias00003"} {
+		_, err := repo.Add(ctx, &domain.URL{
+			Original: "https://example.com/" + string(rune('1'+i)),
+			Alias:    alias,
+			Tags:     []string{"promo"},
+		})
+		require.NoError(t, err)
+	}
+
+	err := repo.Update(ctx, &domain.URL{Original: "https://example.com/updated", Alias: "alias00001", Tags: []string{"spring"}})
+	require.NoError(t, err)
+
+	_, err = repo.IncrementClicks(ctx, "alias00002")
+	require.NoError(t, err)
+
+	renamed := &domain.URL{Original: "https://example.com/2", Alias: "alias00005", Title: "Renamed", Tags: []string{"promo"}}
+
+	_, err = repo.Rename(ctx, "alias00002", renamed, time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+
+	_, err = repo.SoftDelete(ctx, "alias00003", time.Now())
+	require.NoError(t, err)
+
+	_, err = repo.Purge(ctx, time.Now().Add(time.Second))
+	require.NoError(t, err)
+}
+
+func requireFilled(t *testing.T, repo *inmemory.URLRepository) {
+	t.Helper()
+
+	ctx := context.Background()
+
+	u, err := repo.FindByAlias(ctx, "alias00001")
+	require.NoError(t, err)
+	require.Equal(t, int64(1), u.ID)
+	require.Equal(t, "https://example.com/updated", u.Original)
+
+	u, err = repo.FindByAlias(ctx, "alias00005")
+	require.NoError(t, err)
+	require.Equal(t, int64(1), u.Clicks)
+	require.Equal(t, "Renamed", u.Title)
+
+	retired, err := repo.FindRetiredAlias(ctx, "alias00002")
+	require.NoError(t, err)
+	require.Equal(t, u.ID, retired.URLID)
+	require.True(t, retired.RetiredAt.Equal(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)))
+
+	_, err = repo.FindByAlias(ctx, "alias00003")
+	require.ErrorIs(t, err, persistence.ErrURLNotFound)
+
+	tagged, err := repo.List(ctx, persistence.URLFilter{Tag: "promo"})
+	require.NoError(t, err)
+	require.Len(t, tagged, 1)
+	require.Equal(t, "alias00005", tagged[0].Alias)
+}
+
+func appendFile(t *testing.T, path string, data []byte) {
+	t.Helper()
+
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0o644)
+	require.NoError(t, err)
+
+	_, err = f.Write(data)
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+}
+
+func TestOpenURLRepository_Locked(t *testing.T) {
+	dir := t.TempDir()
+
+	// Given
+	repo, err := inmemory.OpenURLRepository(dir, testLogConfig)
+	require.NoError(t, err)
+
+	// When
+	_, err = inmemory.OpenURLRepository(dir, testLogConfig)
+
+	// Then
+	require.ErrorIs(t, err, inmemory.ErrLocked)
+
+	require.NoError(t, repo.Close())
+
+	repo, err = inmemory.OpenURLRepository(dir, testLogConfig)
+	require.NoError(t, err)
+	require.NoError(t, repo.Close())
+}
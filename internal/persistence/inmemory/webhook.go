@@ -0,0 +1,191 @@
+package inmemory
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/kodeyeen/shortify/internal/domain"
+	"github.com/kodeyeen/shortify/internal/persistence"
+)
+
+type WebhookRepository struct {
+	webhooks   map[int64]*domain.Webhook
+	deliveries map[int64]*domain.Delivery
+
+	lastWebhookID  int64
+	lastDeliveryID int64
+
+	mu *sync.RWMutex
+}
+
+func NewWebhookRepository() *WebhookRepository {
+	return &WebhookRepository{
+		webhooks:   map[int64]*domain.Webhook{},
+		deliveries: map[int64]*domain.Delivery{},
+
+		mu: &sync.RWMutex{},
+	}
+}
+
+func (r *WebhookRepository) Add(ctx context.Context, w *domain.Webhook) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.lastWebhookID++
+
+	stored := *w
+	stored.ID = r.lastWebhookID
+
+	r.webhooks[stored.ID] = &stored
+
+	return stored.ID, nil
+}
+
+func (r *WebhookRepository) FindByID(ctx context.Context, id int64) (*domain.Webhook, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	w, ok := r.webhooks[id]
+	if !ok {
+		return nil, persistence.ErrWebhookNotFound
+	}
+
+	found := *w
+
+	return &found, nil
+}
+
+func (r *WebhookRepository) List(ctx context.Context) ([]*domain.Webhook, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	res := make([]*domain.Webhook, 0, len(r.webhooks))
+
+	for _, w := range r.webhooks {
+		found := *w
+		res = append(res, &found)
+	}
+
+	slices.SortFunc(res, func(a, b *domain.Webhook) int {
+		return cmp.Compare(a.ID, b.ID)
+	})
+
+	return res, nil
+}
+
+func (r *WebhookRepository) Delete(ctx context.Context, id int64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.webhooks[id]; !ok {
+		return persistence.ErrWebhookNotFound
+	}
+
+	delete(r.webhooks, id)
+
+	for deliveryID, d := range r.deliveries {
+		if d.WebhookID == id {
+			delete(r.deliveries, deliveryID)
+		}
+	}
+
+	return nil
+}
+
+func (r *WebhookRepository) AddDeliveries(ctx context.Context, ds []*domain.Delivery) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, d := range ds {
+		if _, ok := r.webhooks[d.WebhookID]; !ok {
+			return persistence.ErrWebhookNotFound
+		}
+	}
+
+	for _, d := range ds {
+		r.lastDeliveryID++
+
+		stored := *d
+		stored.ID = r.lastDeliveryID
+
+		r.deliveries[stored.ID] = &stored
+		d.ID = stored.ID
+	}
+
+	return nil
+}
+
+func (r *WebhookRepository) ClaimDue(ctx context.Context, now, leaseUntil time.Time, limit int) ([]*domain.Delivery, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var due []*domain.Delivery
+
+	for _, d := range r.deliveries {
+		if d.Status == domain.DeliveryStatusPending && !d.NextAttemptAt.After(now) {
+			due = append(due, d)
+		}
+	}
+
+	slices.SortFunc(due, func(a, b *domain.Delivery) int {
+		return cmp.Or(a.NextAttemptAt.Compare(b.NextAttemptAt), cmp.Compare(a.ID, b.ID))
+	})
+
+	if len(due) > limit {
+		due = due[:limit]
+	}
+
+	res := make([]*domain.Delivery, 0, len(due))
+
+	for _, d := range due {
+		d.NextAttemptAt = leaseUntil
+
+		claimed := *d
+		res = append(res, &claimed)
+	}
+
+	return res, nil
+}
+
+func (r *WebhookRepository) UpdateDelivery(ctx context.Context, d *domain.Delivery) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.deliveries[d.ID]; !ok {
+		// the webhook and its deliveries were deleted
+		return nil
+	}
+
+	stored := *d
+	r.deliveries[d.ID] = &stored
+
+	return nil
+}
+
+func (r *WebhookRepository) ListDeliveries(ctx context.Context, webhookID int64, limit int) ([]*domain.Delivery, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var res []*domain.Delivery
+
+	for _, d := range r.deliveries {
+		if d.WebhookID == webhookID {
+			found := *d
+			res = append(res, &found)
+		}
+	}
+
+	// most recent first
+	slices.SortFunc(res, func(a, b *domain.Delivery) int {
+		return cmp.Compare(b.ID, a.ID)
+	})
+
+	if limit > 0 && len(res) > limit {
+		res = res[:limit]
+	}
+
+	return res, nil
+}
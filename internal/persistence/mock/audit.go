@@ -0,0 +1,153 @@
+// Code generated by mockery. DO NOT EDIT.
+
+package mock
+
+import (
+	context "context"
+
+	domain "github.com/kodeyeen/shortify/internal/domain"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// AuditRepository is an autogenerated mock type for the AuditLog type
+type AuditRepository struct {
+	mock.Mock
+}
+
+type AuditRepository_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *AuditRepository) EXPECT() *AuditRepository_Expecter {
+	return &AuditRepository_Expecter{mock: &_m.Mock}
+}
+
+// Append provides a mock function with given fields: ctx, e
+func (_m *AuditRepository) Append(ctx context.Context, e *domain.AuditEntry) (int64, error) {
+	ret := _m.Called(ctx, e)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Append")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.AuditEntry) (int64, error)); ok {
+		return rf(ctx, e)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.AuditEntry) int64); ok {
+		r0 = rf(ctx, e)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *domain.AuditEntry) error); ok {
+		r1 = rf(ctx, e)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// AuditRepository_Append_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Append'
+type AuditRepository_Append_Call struct {
+	*mock.Call
+}
+
+// Append is a helper method to define mock.On call
+//   - ctx context.Context
+//   - e *domain.AuditEntry
+func (_e *AuditRepository_Expecter) Append(ctx interface{}, e interface{}) *AuditRepository_Append_Call {
+	return &AuditRepository_Append_Call{Call: _e.mock.On("Append", ctx, e)}
+}
+
+func (_c *AuditRepository_Append_Call) Run(run func(ctx context.Context, e *domain.AuditEntry)) *AuditRepository_Append_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*domain.AuditEntry))
+	})
+	return _c
+}
+
+func (_c *AuditRepository_Append_Call) Return(_a0 int64, _a1 error) *AuditRepository_Append_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *AuditRepository_Append_Call) RunAndReturn(run func(context.Context, *domain.AuditEntry) (int64, error)) *AuditRepository_Append_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListByURL provides a mock function with given fields: ctx, urlID
+func (_m *AuditRepository) ListByURL(ctx context.Context, urlID int64) ([]*domain.AuditEntry, error) {
+	ret := _m.Called(ctx, urlID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListByURL")
+	}
+
+	var r0 []*domain.AuditEntry
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) ([]*domain.AuditEntry, error)); ok {
+		return rf(ctx, urlID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64) []*domain.AuditEntry); ok {
+		r0 = rf(ctx, urlID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*domain.AuditEntry)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, urlID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// AuditRepository_ListByURL_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListByURL'
+type AuditRepository_ListByURL_Call struct {
+	*mock.Call
+}
+
+// ListByURL is a helper method to define mock.On call
+//   - ctx context.Context
+//   - urlID int64
+func (_e *AuditRepository_Expecter) ListByURL(ctx interface{}, urlID interface{}) *AuditRepository_ListByURL_Call {
+	return &AuditRepository_ListByURL_Call{Call: _e.mock.On("ListByURL", ctx, urlID)}
+}
+
+func (_c *AuditRepository_ListByURL_Call) Run(run func(ctx context.Context, urlID int64)) *AuditRepository_ListByURL_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int64))
+	})
+	return _c
+}
+
+func (_c *AuditRepository_ListByURL_Call) Return(_a0 []*domain.AuditEntry, _a1 error) *AuditRepository_ListByURL_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *AuditRepository_ListByURL_Call) RunAndReturn(run func(context.Context, int64) ([]*domain.AuditEntry, error)) *AuditRepository_ListByURL_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewAuditRepository creates a new instance of AuditRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewAuditRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *AuditRepository {
+	mock := &AuditRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -7,6 +7,10 @@ import (
 
 	domain "github.com/kodeyeen/shortify/internal/domain"
 	mock "github.com/stretchr/testify/mock"
+
+	persistence "github.com/kodeyeen/shortify/internal/persistence"
+
+	time "time"
 )
 
 // URLRepository is an autogenerated mock type for the Repository type
@@ -79,6 +83,62 @@ func (_c *URLRepository_Add_Call) RunAndReturn(run func(context.Context, *domain
 	return _c
 }
 
+// Count provides a mock function with given fields: ctx
+func (_m *URLRepository) Count(ctx context.Context) (int64, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Count")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) (int64, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) int64); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// URLRepository_Count_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Count'
+type URLRepository_Count_Call struct {
+	*mock.Call
+}
+
+// Count is a helper method to define mock.On call
+//   - ctx context.Context
+func (_e *URLRepository_Expecter) Count(ctx interface{}) *URLRepository_Count_Call {
+	return &URLRepository_Count_Call{Call: _e.mock.On("Count", ctx)}
+}
+
+func (_c *URLRepository_Count_Call) Run(run func(ctx context.Context)) *URLRepository_Count_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context))
+	})
+	return _c
+}
+
+func (_c *URLRepository_Count_Call) Return(_a0 int64, _a1 error) *URLRepository_Count_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *URLRepository_Count_Call) RunAndReturn(run func(context.Context) (int64, error)) *URLRepository_Count_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // FindByAlias provides a mock function with given fields: ctx, alias
 func (_m *URLRepository) FindByAlias(ctx context.Context, alias string) (*domain.URL, error) {
 	ret := _m.Called(ctx, alias)
@@ -138,6 +198,691 @@ func (_c *URLRepository_FindByAlias_Call) RunAndReturn(run func(context.Context,
 	return _c
 }
 
+// FindByID provides a mock function with given fields: ctx, id
+func (_m *URLRepository) FindByID(ctx context.Context, id int64) (*domain.URL, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindByID")
+	}
+
+	var r0 *domain.URL
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) (*domain.URL, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64) *domain.URL); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.URL)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// URLRepository_FindByID_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FindByID'
+type URLRepository_FindByID_Call struct {
+	*mock.Call
+}
+
+// FindByID is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id int64
+func (_e *URLRepository_Expecter) FindByID(ctx interface{}, id interface{}) *URLRepository_FindByID_Call {
+	return &URLRepository_FindByID_Call{Call: _e.mock.On("FindByID", ctx, id)}
+}
+
+func (_c *URLRepository_FindByID_Call) Run(run func(ctx context.Context, id int64)) *URLRepository_FindByID_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int64))
+	})
+	return _c
+}
+
+func (_c *URLRepository_FindByID_Call) Return(_a0 *domain.URL, _a1 error) *URLRepository_FindByID_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *URLRepository_FindByID_Call) RunAndReturn(run func(context.Context, int64) (*domain.URL, error)) *URLRepository_FindByID_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// FindByOriginal provides a mock function with given fields: ctx, original
+func (_m *URLRepository) FindByOriginal(ctx context.Context, original string) (*domain.URL, error) {
+	ret := _m.Called(ctx, original)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindByOriginal")
+	}
+
+	var r0 *domain.URL
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*domain.URL, error)); ok {
+		return rf(ctx, original)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *domain.URL); ok {
+		r0 = rf(ctx, original)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.URL)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, original)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// URLRepository_FindByOriginal_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FindByOriginal'
+type URLRepository_FindByOriginal_Call struct {
+	*mock.Call
+}
+
+// FindByOriginal is a helper method to define mock.On call
+//   - ctx context.Context
+//   - original string
+func (_e *URLRepository_Expecter) FindByOriginal(ctx interface{}, original interface{}) *URLRepository_FindByOriginal_Call {
+	return &URLRepository_FindByOriginal_Call{Call: _e.mock.On("FindByOriginal", ctx, original)}
+}
+
+func (_c *URLRepository_FindByOriginal_Call) Run(run func(ctx context.Context, original string)) *URLRepository_FindByOriginal_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *URLRepository_FindByOriginal_Call) Return(_a0 *domain.URL, _a1 error) *URLRepository_FindByOriginal_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *URLRepository_FindByOriginal_Call) RunAndReturn(run func(context.Context, string) (*domain.URL, error)) *URLRepository_FindByOriginal_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// FindRetiredAlias provides a mock function with given fields: ctx, alias
+func (_m *URLRepository) FindRetiredAlias(ctx context.Context, alias string) (*domain.RetiredAlias, error) {
+	ret := _m.Called(ctx, alias)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindRetiredAlias")
+	}
+
+	var r0 *domain.RetiredAlias
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*domain.RetiredAlias, error)); ok {
+		return rf(ctx, alias)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *domain.RetiredAlias); ok {
+		r0 = rf(ctx, alias)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.RetiredAlias)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, alias)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// URLRepository_FindRetiredAlias_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FindRetiredAlias'
+type URLRepository_FindRetiredAlias_Call struct {
+	*mock.Call
+}
+
+// FindRetiredAlias is a helper method to define mock.On call
+//   - ctx context.Context
+//   - alias string
+func (_e *URLRepository_Expecter) FindRetiredAlias(ctx interface{}, alias interface{}) *URLRepository_FindRetiredAlias_Call {
+	return &URLRepository_FindRetiredAlias_Call{Call: _e.mock.On("FindRetiredAlias", ctx, alias)}
+}
+
+func (_c *URLRepository_FindRetiredAlias_Call) Run(run func(ctx context.Context, alias string)) *URLRepository_FindRetiredAlias_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *URLRepository_FindRetiredAlias_Call) Return(_a0 *domain.RetiredAlias, _a1 error) *URLRepository_FindRetiredAlias_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *URLRepository_FindRetiredAlias_Call) RunAndReturn(run func(context.Context, string) (*domain.RetiredAlias, error)) *URLRepository_FindRetiredAlias_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// IncrementClicks provides a mock function with given fields: ctx, alias
+func (_m *URLRepository) IncrementClicks(ctx context.Context, alias string) (int64, error) {
+	ret := _m.Called(ctx, alias)
+
+	if len(ret) == 0 {
+		panic("no return value specified for IncrementClicks")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (int64, error)); ok {
+		return rf(ctx, alias)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) int64); ok {
+		r0 = rf(ctx, alias)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, alias)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// URLRepository_IncrementClicks_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'IncrementClicks'
+type URLRepository_IncrementClicks_Call struct {
+	*mock.Call
+}
+
+// IncrementClicks is a helper method to define mock.On call
+//   - ctx context.Context
+//   - alias string
+func (_e *URLRepository_Expecter) IncrementClicks(ctx interface{}, alias interface{}) *URLRepository_IncrementClicks_Call {
+	return &URLRepository_IncrementClicks_Call{Call: _e.mock.On("IncrementClicks", ctx, alias)}
+}
+
+func (_c *URLRepository_IncrementClicks_Call) Run(run func(ctx context.Context, alias string)) *URLRepository_IncrementClicks_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *URLRepository_IncrementClicks_Call) Return(_a0 int64, _a1 error) *URLRepository_IncrementClicks_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *URLRepository_IncrementClicks_Call) RunAndReturn(run func(context.Context, string) (int64, error)) *URLRepository_IncrementClicks_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// List provides a mock function with given fields: ctx, filter
+func (_m *URLRepository) List(ctx context.Context, filter persistence.URLFilter) ([]*domain.URL, error) {
+	ret := _m.Called(ctx, filter)
+
+	if len(ret) == 0 {
+		panic("no return value specified for List")
+	}
+
+	var r0 []*domain.URL
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, persistence.URLFilter) ([]*domain.URL, error)); ok {
+		return rf(ctx, filter)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, persistence.URLFilter) []*domain.URL); ok {
+		r0 = rf(ctx, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*domain.URL)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, persistence.URLFilter) error); ok {
+		r1 = rf(ctx, filter)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// URLRepository_List_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'List'
+type URLRepository_List_Call struct {
+	*mock.Call
+}
+
+// List is a helper method to define mock.On call
+//   - ctx context.Context
+//   - filter persistence.URLFilter
+func (_e *URLRepository_Expecter) List(ctx interface{}, filter interface{}) *URLRepository_List_Call {
+	return &URLRepository_List_Call{Call: _e.mock.On("List", ctx, filter)}
+}
+
+func (_c *URLRepository_List_Call) Run(run func(ctx context.Context, filter persistence.URLFilter)) *URLRepository_List_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(persistence.URLFilter))
+	})
+	return _c
+}
+
+func (_c *URLRepository_List_Call) Return(_a0 []*domain.URL, _a1 error) *URLRepository_List_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *URLRepository_List_Call) RunAndReturn(run func(context.Context, persistence.URLFilter) ([]*domain.URL, error)) *URLRepository_List_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListRetiredAliases provides a mock function with given fields: ctx, urlID
+func (_m *URLRepository) ListRetiredAliases(ctx context.Context, urlID int64) ([]*domain.RetiredAlias, error) {
+	ret := _m.Called(ctx, urlID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListRetiredAliases")
+	}
+
+	var r0 []*domain.RetiredAlias
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) ([]*domain.RetiredAlias, error)); ok {
+		return rf(ctx, urlID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64) []*domain.RetiredAlias); ok {
+		r0 = rf(ctx, urlID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*domain.RetiredAlias)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, urlID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// URLRepository_ListRetiredAliases_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListRetiredAliases'
+type URLRepository_ListRetiredAliases_Call struct {
+	*mock.Call
+}
+
+// ListRetiredAliases is a helper method to define mock.On call
+//   - ctx context.Context
+//   - urlID int64
+func (_e *URLRepository_Expecter) ListRetiredAliases(ctx interface{}, urlID interface{}) *URLRepository_ListRetiredAliases_Call {
+	return &URLRepository_ListRetiredAliases_Call{Call: _e.mock.On("ListRetiredAliases", ctx, urlID)}
+}
+
+func (_c *URLRepository_ListRetiredAliases_Call) Run(run func(ctx context.Context, urlID int64)) *URLRepository_ListRetiredAliases_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int64))
+	})
+	return _c
+}
+
+func (_c *URLRepository_ListRetiredAliases_Call) Return(_a0 []*domain.RetiredAlias, _a1 error) *URLRepository_ListRetiredAliases_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *URLRepository_ListRetiredAliases_Call) RunAndReturn(run func(context.Context, int64) ([]*domain.RetiredAlias, error)) *URLRepository_ListRetiredAliases_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Purge provides a mock function with given fields: ctx, deletedBefore
+func (_m *URLRepository) Purge(ctx context.Context, deletedBefore time.Time) (int64, error) {
+	ret := _m.Called(ctx, deletedBefore)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Purge")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) (int64, error)); ok {
+		return rf(ctx, deletedBefore)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) int64); ok {
+		r0 = rf(ctx, deletedBefore)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, time.Time) error); ok {
+		r1 = rf(ctx, deletedBefore)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// URLRepository_Purge_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Purge'
+type URLRepository_Purge_Call struct {
+	*mock.Call
+}
+
+// Purge is a helper method to define mock.On call
+//   - ctx context.Context
+//   - deletedBefore time.Time
+func (_e *URLRepository_Expecter) Purge(ctx interface{}, deletedBefore interface{}) *URLRepository_Purge_Call {
+	return &URLRepository_Purge_Call{Call: _e.mock.On("Purge", ctx, deletedBefore)}
+}
+
+func (_c *URLRepository_Purge_Call) Run(run func(ctx context.Context, deletedBefore time.Time)) *URLRepository_Purge_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(time.Time))
+	})
+	return _c
+}
+
+func (_c *URLRepository_Purge_Call) Return(_a0 int64, _a1 error) *URLRepository_Purge_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *URLRepository_Purge_Call) RunAndReturn(run func(context.Context, time.Time) (int64, error)) *URLRepository_Purge_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Rename provides a mock function with given fields: ctx, alias, u, retiredAt
+func (_m *URLRepository) Rename(ctx context.Context, alias string, u *domain.URL, retiredAt time.Time) (*domain.URL, error) {
+	ret := _m.Called(ctx, alias, u, retiredAt)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Rename")
+	}
+
+	var r0 *domain.URL
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, *domain.URL, time.Time) (*domain.URL, error)); ok {
+		return rf(ctx, alias, u, retiredAt)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, *domain.URL, time.Time) *domain.URL); ok {
+		r0 = rf(ctx, alias, u, retiredAt)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.URL)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, *domain.URL, time.Time) error); ok {
+		r1 = rf(ctx, alias, u, retiredAt)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// URLRepository_Rename_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Rename'
+type URLRepository_Rename_Call struct {
+	*mock.Call
+}
+
+// Rename is a helper method to define mock.On call
+//   - ctx context.Context
+//   - alias string
+//   - u *domain.URL
+//   - retiredAt time.Time
+func (_e *URLRepository_Expecter) Rename(ctx interface{}, alias interface{}, u interface{}, retiredAt interface{}) *URLRepository_Rename_Call {
+	return &URLRepository_Rename_Call{Call: _e.mock.On("Rename", ctx, alias, u, retiredAt)}
+}
+
+func (_c *URLRepository_Rename_Call) Run(run func(ctx context.Context, alias string, u *domain.URL, retiredAt time.Time)) *URLRepository_Rename_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(*domain.URL), args[3].(time.Time))
+	})
+	return _c
+}
+
+func (_c *URLRepository_Rename_Call) Return(_a0 *domain.URL, _a1 error) *URLRepository_Rename_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *URLRepository_Rename_Call) RunAndReturn(run func(context.Context, string, *domain.URL, time.Time) (*domain.URL, error)) *URLRepository_Rename_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Restore provides a mock function with given fields: ctx, alias
+func (_m *URLRepository) Restore(ctx context.Context, alias string) (*domain.URL, error) {
+	ret := _m.Called(ctx, alias)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Restore")
+	}
+
+	var r0 *domain.URL
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*domain.URL, error)); ok {
+		return rf(ctx, alias)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *domain.URL); ok {
+		r0 = rf(ctx, alias)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.URL)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, alias)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// URLRepository_Restore_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Restore'
+type URLRepository_Restore_Call struct {
+	*mock.Call
+}
+
+// Restore is a helper method to define mock.On call
+//   - ctx context.Context
+//   - alias string
+func (_e *URLRepository_Expecter) Restore(ctx interface{}, alias interface{}) *URLRepository_Restore_Call {
+	return &URLRepository_Restore_Call{Call: _e.mock.On("Restore", ctx, alias)}
+}
+
+func (_c *URLRepository_Restore_Call) Run(run func(ctx context.Context, alias string)) *URLRepository_Restore_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *URLRepository_Restore_Call) Return(_a0 *domain.URL, _a1 error) *URLRepository_Restore_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *URLRepository_Restore_Call) RunAndReturn(run func(context.Context, string) (*domain.URL, error)) *URLRepository_Restore_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// SoftDelete provides a mock function with given fields: ctx, alias, deletedAt
+func (_m *URLRepository) SoftDelete(ctx context.Context, alias string, deletedAt time.Time) (*domain.URL, error) {
+	ret := _m.Called(ctx, alias, deletedAt)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SoftDelete")
+	}
+
+	var r0 *domain.URL
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Time) (*domain.URL, error)); ok {
+		return rf(ctx, alias, deletedAt)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Time) *domain.URL); ok {
+		r0 = rf(ctx, alias, deletedAt)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.URL)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, time.Time) error); ok {
+		r1 = rf(ctx, alias, deletedAt)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// URLRepository_SoftDelete_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SoftDelete'
+type URLRepository_SoftDelete_Call struct {
+	*mock.Call
+}
+
+// SoftDelete is a helper method to define mock.On call
+//   - ctx context.Context
+//   - alias string
+//   - deletedAt time.Time
+func (_e *URLRepository_Expecter) SoftDelete(ctx interface{}, alias interface{}, deletedAt interface{}) *URLRepository_SoftDelete_Call {
+	return &URLRepository_SoftDelete_Call{Call: _e.mock.On("SoftDelete", ctx, alias, deletedAt)}
+}
+
+func (_c *URLRepository_SoftDelete_Call) Run(run func(ctx context.Context, alias string, deletedAt time.Time)) *URLRepository_SoftDelete_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(time.Time))
+	})
+	return _c
+}
+
+func (_c *URLRepository_SoftDelete_Call) Return(_a0 *domain.URL, _a1 error) *URLRepository_SoftDelete_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *URLRepository_SoftDelete_Call) RunAndReturn(run func(context.Context, string, time.Time) (*domain.URL, error)) *URLRepository_SoftDelete_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Update provides a mock function with given fields: ctx, u
+func (_m *URLRepository) Update(ctx context.Context, u *domain.URL) error {
+	ret := _m.Called(ctx, u)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Update")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.URL) error); ok {
+		r0 = rf(ctx, u)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// URLRepository_Update_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Update'
+type URLRepository_Update_Call struct {
+	*mock.Call
+}
+
+// Update is a helper method to define mock.On call
+//   - ctx context.Context
+//   - u *domain.URL
+func (_e *URLRepository_Expecter) Update(ctx interface{}, u interface{}) *URLRepository_Update_Call {
+	return &URLRepository_Update_Call{Call: _e.mock.On("Update", ctx, u)}
+}
+
+func (_c *URLRepository_Update_Call) Run(run func(ctx context.Context, u *domain.URL)) *URLRepository_Update_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*domain.URL))
+	})
+	return _c
+}
+
+func (_c *URLRepository_Update_Call) Return(_a0 error) *URLRepository_Update_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *URLRepository_Update_Call) RunAndReturn(run func(context.Context, *domain.URL) error) *URLRepository_Update_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// UpdateOpenGraph provides a mock function with given fields: ctx, id, original, og
+func (_m *URLRepository) UpdateOpenGraph(ctx context.Context, id int64, original string, og *domain.OpenGraph) error {
+	ret := _m.Called(ctx, id, original, og)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateOpenGraph")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, string, *domain.OpenGraph) error); ok {
+		r0 = rf(ctx, id, original, og)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// URLRepository_UpdateOpenGraph_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdateOpenGraph'
+type URLRepository_UpdateOpenGraph_Call struct {
+	*mock.Call
+}
+
+// UpdateOpenGraph is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id int64
+//   - original string
+//   - og *domain.OpenGraph
+func (_e *URLRepository_Expecter) UpdateOpenGraph(ctx interface{}, id interface{}, original interface{}, og interface{}) *URLRepository_UpdateOpenGraph_Call {
+	return &URLRepository_UpdateOpenGraph_Call{Call: _e.mock.On("UpdateOpenGraph", ctx, id, original, og)}
+}
+
+func (_c *URLRepository_UpdateOpenGraph_Call) Run(run func(ctx context.Context, id int64, original string, og *domain.OpenGraph)) *URLRepository_UpdateOpenGraph_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int64), args[2].(string), args[3].(*domain.OpenGraph))
+	})
+	return _c
+}
+
+func (_c *URLRepository_UpdateOpenGraph_Call) Return(_a0 error) *URLRepository_UpdateOpenGraph_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *URLRepository_UpdateOpenGraph_Call) RunAndReturn(run func(context.Context, int64, string, *domain.OpenGraph) error) *URLRepository_UpdateOpenGraph_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // NewURLRepository creates a new instance of URLRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewURLRepository(t interface {
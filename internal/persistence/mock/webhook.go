@@ -0,0 +1,475 @@
+// Code generated by mockery. DO NOT EDIT.
+
+package mock
+
+import (
+	context "context"
+
+	domain "github.com/kodeyeen/shortify/internal/domain"
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+)
+
+// WebhookRepository is an autogenerated mock type for the Repository type
+type WebhookRepository struct {
+	mock.Mock
+}
+
+type WebhookRepository_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *WebhookRepository) EXPECT() *WebhookRepository_Expecter {
+	return &WebhookRepository_Expecter{mock: &_m.Mock}
+}
+
+// Add provides a mock function with given fields: ctx, w
+func (_m *WebhookRepository) Add(ctx context.Context, w *domain.Webhook) (int64, error) {
+	ret := _m.Called(ctx, w)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Add")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.Webhook) (int64, error)); ok {
+		return rf(ctx, w)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.Webhook) int64); ok {
+		r0 = rf(ctx, w)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *domain.Webhook) error); ok {
+		r1 = rf(ctx, w)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// WebhookRepository_Add_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Add'
+type WebhookRepository_Add_Call struct {
+	*mock.Call
+}
+
+// Add is a helper method to define mock.On call
+//   - ctx context.Context
+//   - w *domain.Webhook
+func (_e *WebhookRepository_Expecter) Add(ctx interface{}, w interface{}) *WebhookRepository_Add_Call {
+	return &WebhookRepository_Add_Call{Call: _e.mock.On("Add", ctx, w)}
+}
+
+func (_c *WebhookRepository_Add_Call) Run(run func(ctx context.Context, w *domain.Webhook)) *WebhookRepository_Add_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*domain.Webhook))
+	})
+	return _c
+}
+
+func (_c *WebhookRepository_Add_Call) Return(_a0 int64, _a1 error) *WebhookRepository_Add_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *WebhookRepository_Add_Call) RunAndReturn(run func(context.Context, *domain.Webhook) (int64, error)) *WebhookRepository_Add_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// AddDeliveries provides a mock function with given fields: ctx, ds
+func (_m *WebhookRepository) AddDeliveries(ctx context.Context, ds []*domain.Delivery) error {
+	ret := _m.Called(ctx, ds)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AddDeliveries")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, []*domain.Delivery) error); ok {
+		r0 = rf(ctx, ds)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// WebhookRepository_AddDeliveries_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'AddDeliveries'
+type WebhookRepository_AddDeliveries_Call struct {
+	*mock.Call
+}
+
+// AddDeliveries is a helper method to define mock.On call
+//   - ctx context.Context
+//   - ds []*domain.Delivery
+func (_e *WebhookRepository_Expecter) AddDeliveries(ctx interface{}, ds interface{}) *WebhookRepository_AddDeliveries_Call {
+	return &WebhookRepository_AddDeliveries_Call{Call: _e.mock.On("AddDeliveries", ctx, ds)}
+}
+
+func (_c *WebhookRepository_AddDeliveries_Call) Run(run func(ctx context.Context, ds []*domain.Delivery)) *WebhookRepository_AddDeliveries_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].([]*domain.Delivery))
+	})
+	return _c
+}
+
+func (_c *WebhookRepository_AddDeliveries_Call) Return(_a0 error) *WebhookRepository_AddDeliveries_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *WebhookRepository_AddDeliveries_Call) RunAndReturn(run func(context.Context, []*domain.Delivery) error) *WebhookRepository_AddDeliveries_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ClaimDue provides a mock function with given fields: ctx, now, leaseUntil, limit
+func (_m *WebhookRepository) ClaimDue(ctx context.Context, now time.Time, leaseUntil time.Time, limit int) ([]*domain.Delivery, error) {
+	ret := _m.Called(ctx, now, leaseUntil, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ClaimDue")
+	}
+
+	var r0 []*domain.Delivery
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time, time.Time, int) ([]*domain.Delivery, error)); ok {
+		return rf(ctx, now, leaseUntil, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time, time.Time, int) []*domain.Delivery); ok {
+		r0 = rf(ctx, now, leaseUntil, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*domain.Delivery)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, time.Time, time.Time, int) error); ok {
+		r1 = rf(ctx, now, leaseUntil, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// WebhookRepository_ClaimDue_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ClaimDue'
+type WebhookRepository_ClaimDue_Call struct {
+	*mock.Call
+}
+
+// ClaimDue is a helper method to define mock.On call
+//   - ctx context.Context
+//   - now time.Time
+//   - leaseUntil time.Time
+//   - limit int
+func (_e *WebhookRepository_Expecter) ClaimDue(ctx interface{}, now interface{}, leaseUntil interface{}, limit interface{}) *WebhookRepository_ClaimDue_Call {
+	return &WebhookRepository_ClaimDue_Call{Call: _e.mock.On("ClaimDue", ctx, now, leaseUntil, limit)}
+}
+
+func (_c *WebhookRepository_ClaimDue_Call) Run(run func(ctx context.Context, now time.Time, leaseUntil time.Time, limit int)) *WebhookRepository_ClaimDue_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(time.Time), args[2].(time.Time), args[3].(int))
+	})
+	return _c
+}
+
+func (_c *WebhookRepository_ClaimDue_Call) Return(_a0 []*domain.Delivery, _a1 error) *WebhookRepository_ClaimDue_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *WebhookRepository_ClaimDue_Call) RunAndReturn(run func(context.Context, time.Time, time.Time, int) ([]*domain.Delivery, error)) *WebhookRepository_ClaimDue_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Delete provides a mock function with given fields: ctx, id
+func (_m *WebhookRepository) Delete(ctx context.Context, id int64) error {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Delete")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// WebhookRepository_Delete_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Delete'
+type WebhookRepository_Delete_Call struct {
+	*mock.Call
+}
+
+// Delete is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id int64
+func (_e *WebhookRepository_Expecter) Delete(ctx interface{}, id interface{}) *WebhookRepository_Delete_Call {
+	return &WebhookRepository_Delete_Call{Call: _e.mock.On("Delete", ctx, id)}
+}
+
+func (_c *WebhookRepository_Delete_Call) Run(run func(ctx context.Context, id int64)) *WebhookRepository_Delete_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int64))
+	})
+	return _c
+}
+
+func (_c *WebhookRepository_Delete_Call) Return(_a0 error) *WebhookRepository_Delete_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *WebhookRepository_Delete_Call) RunAndReturn(run func(context.Context, int64) error) *WebhookRepository_Delete_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// FindByID provides a mock function with given fields: ctx, id
+func (_m *WebhookRepository) FindByID(ctx context.Context, id int64) (*domain.Webhook, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindByID")
+	}
+
+	var r0 *domain.Webhook
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) (*domain.Webhook, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64) *domain.Webhook); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.Webhook)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// WebhookRepository_FindByID_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FindByID'
+type WebhookRepository_FindByID_Call struct {
+	*mock.Call
+}
+
+// FindByID is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id int64
+func (_e *WebhookRepository_Expecter) FindByID(ctx interface{}, id interface{}) *WebhookRepository_FindByID_Call {
+	return &WebhookRepository_FindByID_Call{Call: _e.mock.On("FindByID", ctx, id)}
+}
+
+func (_c *WebhookRepository_FindByID_Call) Run(run func(ctx context.Context, id int64)) *WebhookRepository_FindByID_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int64))
+	})
+	return _c
+}
+
+func (_c *WebhookRepository_FindByID_Call) Return(_a0 *domain.Webhook, _a1 error) *WebhookRepository_FindByID_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *WebhookRepository_FindByID_Call) RunAndReturn(run func(context.Context, int64) (*domain.Webhook, error)) *WebhookRepository_FindByID_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// List provides a mock function with given fields: ctx
+func (_m *WebhookRepository) List(ctx context.Context) ([]*domain.Webhook, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for List")
+	}
+
+	var r0 []*domain.Webhook
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) ([]*domain.Webhook, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) []*domain.Webhook); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*domain.Webhook)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// WebhookRepository_List_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'List'
+type WebhookRepository_List_Call struct {
+	*mock.Call
+}
+
+// List is a helper method to define mock.On call
+//   - ctx context.Context
+func (_e *WebhookRepository_Expecter) List(ctx interface{}) *WebhookRepository_List_Call {
+	return &WebhookRepository_List_Call{Call: _e.mock.On("List", ctx)}
+}
+
+func (_c *WebhookRepository_List_Call) Run(run func(ctx context.Context)) *WebhookRepository_List_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context))
+	})
+	return _c
+}
+
+func (_c *WebhookRepository_List_Call) Return(_a0 []*domain.Webhook, _a1 error) *WebhookRepository_List_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *WebhookRepository_List_Call) RunAndReturn(run func(context.Context) ([]*domain.Webhook, error)) *WebhookRepository_List_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListDeliveries provides a mock function with given fields: ctx, webhookID, limit
+func (_m *WebhookRepository) ListDeliveries(ctx context.Context, webhookID int64, limit int) ([]*domain.Delivery, error) {
+	ret := _m.Called(ctx, webhookID, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListDeliveries")
+	}
+
+	var r0 []*domain.Delivery
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int) ([]*domain.Delivery, error)); ok {
+		return rf(ctx, webhookID, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int) []*domain.Delivery); ok {
+		r0 = rf(ctx, webhookID, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*domain.Delivery)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64, int) error); ok {
+		r1 = rf(ctx, webhookID, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// WebhookRepository_ListDeliveries_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListDeliveries'
+type WebhookRepository_ListDeliveries_Call struct {
+	*mock.Call
+}
+
+// ListDeliveries is a helper method to define mock.On call
+//   - ctx context.Context
+//   - webhookID int64
+//   - limit int
+func (_e *WebhookRepository_Expecter) ListDeliveries(ctx interface{}, webhookID interface{}, limit interface{}) *WebhookRepository_ListDeliveries_Call {
+	return &WebhookRepository_ListDeliveries_Call{Call: _e.mock.On("ListDeliveries", ctx, webhookID, limit)}
+}
+
+func (_c *WebhookRepository_ListDeliveries_Call) Run(run func(ctx context.Context, webhookID int64, limit int)) *WebhookRepository_ListDeliveries_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int64), args[2].(int))
+	})
+	return _c
+}
+
+func (_c *WebhookRepository_ListDeliveries_Call) Return(_a0 []*domain.Delivery, _a1 error) *WebhookRepository_ListDeliveries_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *WebhookRepository_ListDeliveries_Call) RunAndReturn(run func(context.Context, int64, int) ([]*domain.Delivery, error)) *WebhookRepository_ListDeliveries_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// UpdateDelivery provides a mock function with given fields: ctx, d
+func (_m *WebhookRepository) UpdateDelivery(ctx context.Context, d *domain.Delivery) error {
+	ret := _m.Called(ctx, d)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateDelivery")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.Delivery) error); ok {
+		r0 = rf(ctx, d)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// WebhookRepository_UpdateDelivery_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdateDelivery'
+type WebhookRepository_UpdateDelivery_Call struct {
+	*mock.Call
+}
+
+// UpdateDelivery is a helper method to define mock.On call
+//   - ctx context.Context
+//   - d *domain.Delivery
+func (_e *WebhookRepository_Expecter) UpdateDelivery(ctx interface{}, d interface{}) *WebhookRepository_UpdateDelivery_Call {
+	return &WebhookRepository_UpdateDelivery_Call{Call: _e.mock.On("UpdateDelivery", ctx, d)}
+}
+
+func (_c *WebhookRepository_UpdateDelivery_Call) Run(run func(ctx context.Context, d *domain.Delivery)) *WebhookRepository_UpdateDelivery_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*domain.Delivery))
+	})
+	return _c
+}
+
+func (_c *WebhookRepository_UpdateDelivery_Call) Return(_a0 error) *WebhookRepository_UpdateDelivery_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *WebhookRepository_UpdateDelivery_Call) RunAndReturn(run func(context.Context, *domain.Delivery) error) *WebhookRepository_UpdateDelivery_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewWebhookRepository creates a new instance of WebhookRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewWebhookRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *WebhookRepository {
+	mock := &WebhookRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -10,8 +10,22 @@ var (
 	ErrURLAlreadyExists = errors.New("URL already exists")
 
 	ErrDuplicateAlias = errors.New("duplicate alias")
+	ErrAliasNotFound  = errors.New("alias not found")
+
+	ErrWebhookNotFound = errors.New("webhook not found")
 )
 
+// URLFilter narrows down the URLs returned by a listing.
+// Zero fields don't filter anything.
+type URLFilter struct {
+	Tag      string
+	Metadata map[string]string
+	// AfterID skips the URLs up to and including this ID, so the listing can be paged through by the last seen ID.
+	AfterID int64
+	Limit   int
+	Offset  int
+}
+
 // type URLRepository interface {
 // 	Add(ctx context.Context, u *domain.URL) (int64, error)
 // 	FindByAlias(ctx context.Context, alias string) (*domain.URL, error)
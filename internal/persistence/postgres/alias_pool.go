@@ -0,0 +1,77 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// AliasPool keeps the reservations of pooled aliases in the alias_pool table
+// so replicas don't hand out the same alias.
+type AliasPool struct {
+	dbpool *pgxpool.Pool
+}
+
+func NewAliasPool(dbpool *pgxpool.Pool) *AliasPool {
+	return &AliasPool{
+		dbpool: dbpool,
+	}
+}
+
+func (p *AliasPool) Reserve(ctx context.Context, aliases []string, expiredBefore time.Time) ([]string, error) {
+	var reserved []string
+
+	err := pgx.BeginFunc(ctx, p.dbpool, func(tx pgx.Tx) error {
+		query := `DELETE FROM alias_pool WHERE reserved_at < @expired_before`
+		args := pgx.NamedArgs{
+			"expired_before": expiredBefore,
+		}
+
+		_, err := tx.Exec(ctx, query, args)
+		if err != nil {
+			return err
+		}
+
+		query = `
+			INSERT INTO alias_pool (alias, reserved_at)
+			SELECT a.alias, now()
+			FROM unnest(@aliases::text[]) AS a(alias)
+			WHERE NOT EXISTS (SELECT 1 FROM urls WHERE urls.alias = a.alias)
+			ON CONFLICT (alias) DO NOTHING
+			RETURNING alias`
+		args = pgx.NamedArgs{
+			"aliases": aliases,
+		}
+
+		rows, err := tx.Query(ctx, query, args)
+		if err != nil {
+			return err
+		}
+
+		reserved, err = pgx.CollectRows(rows, pgx.RowTo[string])
+
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to reserve aliases: %w", err)
+	}
+
+	return reserved, nil
+}
+
+func (p *AliasPool) Release(ctx context.Context, aliases []string) error {
+	query := `DELETE FROM alias_pool WHERE alias = ANY(@aliases::text[])`
+	args := pgx.NamedArgs{
+		"aliases": aliases,
+	}
+
+	_, err := p.dbpool.Exec(ctx, query, args)
+	if err != nil {
+		return fmt.Errorf("failed to release aliases: %w", err)
+	}
+
+	return nil
+}
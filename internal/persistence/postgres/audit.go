@@ -0,0 +1,84 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/kodeyeen/shortify/internal/domain"
+)
+
+// AuditRepository stores the audit entries in the url_audit table.
+// The URL states are kept as JSON.
+type AuditRepository struct {
+	dbpool *pgxpool.Pool
+}
+
+func NewAuditRepository(dbpool *pgxpool.Pool) *AuditRepository {
+	return &AuditRepository{
+		dbpool: dbpool,
+	}
+}
+
+func (r *AuditRepository) Append(ctx context.Context, e *domain.AuditEntry) (int64, error) {
+	query := `
+		INSERT INTO url_audit (url_id, action, actor, request_id, before, after, created_at)
+		VALUES (@url_id, @action, @actor, @request_id, @before, @after, @created_at)
+		RETURNING id`
+	args := pgx.NamedArgs{
+		"url_id":     e.URLID,
+		"action":     e.Action,
+		"actor":      e.Actor,
+		"request_id": e.RequestID,
+		"before":     e.Before,
+		"after":      e.After,
+		"created_at": e.CreatedAt,
+	}
+
+	var insertID int64
+
+	err := r.dbpool.QueryRow(ctx, query, args).Scan(&insertID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to append audit entry: %w", err)
+	}
+
+	return insertID, nil
+}
+
+// ListByURL returns the entries of the URL, the oldest first.
+func (r *AuditRepository) ListByURL(ctx context.Context, urlID int64) ([]*domain.AuditEntry, error) {
+	query := `
+		SELECT id, url_id, action, actor, request_id, before, after, created_at
+		FROM url_audit
+		WHERE url_id = @url_id
+		ORDER BY id`
+	args := pgx.NamedArgs{
+		"url_id": urlID,
+	}
+
+	rows, err := r.dbpool.Query(ctx, query, args)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list audit entries: %w", err)
+	}
+	defer rows.Close()
+
+	entries := []*domain.AuditEntry{}
+
+	for rows.Next() {
+		var e domain.AuditEntry
+
+		err := rows.Scan(&e.ID, &e.URLID, &e.Action, &e.Actor, &e.RequestID, &e.Before, &e.After, &e.CreatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan audit entry: %w", err)
+		}
+
+		entries = append(entries, &e)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list audit entries: %w", err)
+	}
+
+	return entries, nil
+}
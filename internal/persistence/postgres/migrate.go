@@ -0,0 +1,369 @@
+package postgres
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"regexp"
+	"slices"
+	"strconv"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// ErrDirty is returned when a previous migration failed halfway.
+// The schema has to be fixed by hand before migrating further.
+var ErrDirty = errors.New("database is dirty")
+
+var migrationName = regexp.MustCompile(`^(\d+)_(.+)\.(up|down)\.sql$`)
+
+// Migration is a schema change read from <version>_<name>.up.sql
+// and <version>_<name>.down.sql files.
+type Migration struct {
+	Version uint64
+	Name    string
+
+	up   string
+	down string
+}
+
+// Migrator applies migrations keeping the schema version in the
+// schema_migrations table the same way golang-migrate does,
+// so both can be used on the same database.
+//
+// Operations hold a session advisory lock so replicas started
+// at the same time don't migrate concurrently.
+type Migrator struct {
+	dbpool *pgxpool.Pool
+	fsys   fs.FS
+}
+
+// MigrationStatus tells whether the migration is applied.
+type MigrationStatus struct {
+	Migration
+	Applied bool
+}
+
+// migrationLockID is an arbitrary key of the advisory lock.
+const migrationLockID = 4_752_186_310
+
+func NewMigrator(dbpool *pgxpool.Pool, fsys fs.FS) *Migrator {
+	return &Migrator{
+		dbpool: dbpool,
+		fsys:   fsys,
+	}
+}
+
+// Up applies the migrations newer than the current version in order
+// returning the applied ones. Every migration runs in its own transaction.
+func (m *Migrator) Up(ctx context.Context) ([]Migration, error) {
+	migrations, err := loadMigrations(m.fsys)
+	if err != nil {
+		return nil, err
+	}
+
+	var applied []Migration
+
+	err = m.locked(ctx, func(conn *pgxpool.Conn) error {
+		version, err := cleanVersion(ctx, conn)
+		if err != nil {
+			return err
+		}
+
+		for _, mig := range pending(migrations, version) {
+			if mig.up == "" {
+				return fmt.Errorf("migration %d_%s has no up file", mig.Version, mig.Name)
+			}
+
+			err := m.apply(ctx, conn, mig.up, mig.Version)
+			if err != nil {
+				return fmt.Errorf("failed to apply migration %d_%s: %w", mig.Version, mig.Name, err)
+			}
+
+			applied = append(applied, mig)
+		}
+
+		return nil
+	})
+
+	return applied, err
+}
+
+// Down reverts up to steps applied migrations starting from the newest one
+// returning the reverted ones. All of them are reverted when steps isn't positive.
+func (m *Migrator) Down(ctx context.Context, steps int) ([]Migration, error) {
+	migrations, err := loadMigrations(m.fsys)
+	if err != nil {
+		return nil, err
+	}
+
+	var reverted []Migration
+
+	err = m.locked(ctx, func(conn *pgxpool.Conn) error {
+		version, err := cleanVersion(ctx, conn)
+		if err != nil {
+			return err
+		}
+
+		toRevert, err := rollback(migrations, version, steps)
+		if err != nil {
+			return err
+		}
+
+		for _, mig := range toRevert {
+			if mig.down == "" {
+				return fmt.Errorf("migration %d_%s has no down file", mig.Version, mig.Name)
+			}
+
+			err := m.apply(ctx, conn, mig.down, previousVersion(migrations, mig.Version))
+			if err != nil {
+				return fmt.Errorf("failed to revert migration %d_%s: %w", mig.Version, mig.Name, err)
+			}
+
+			reverted = append(reverted, mig)
+		}
+
+		return nil
+	})
+
+	return reverted, err
+}
+
+// Status returns all known migrations marking the applied ones.
+func (m *Migrator) Status(ctx context.Context) ([]MigrationStatus, error) {
+	migrations, err := loadMigrations(m.fsys)
+	if err != nil {
+		return nil, err
+	}
+
+	version, _, err := m.Version(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]MigrationStatus, 0, len(migrations))
+
+	for _, mig := range migrations {
+		statuses = append(statuses, MigrationStatus{
+			Migration: mig,
+			Applied:   mig.Version <= version,
+		})
+	}
+
+	return statuses, nil
+}
+
+// Version returns the current schema version, zero when no migration was applied.
+func (m *Migrator) Version(ctx context.Context) (uint64, bool, error) {
+	var (
+		version uint64
+		dirty   bool
+	)
+
+	err := m.locked(ctx, func(conn *pgxpool.Conn) error {
+		var err error
+
+		version, dirty, err = currentVersion(ctx, conn)
+
+		return err
+	})
+
+	return version, dirty, err
+}
+
+// locked runs fn on a connection holding the migration lock.
+// The version table is created if needed.
+func (m *Migrator) locked(ctx context.Context, fn func(conn *pgxpool.Conn) error) error {
+	conn, err := m.dbpool.Acquire(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to acquire connection: %w", err)
+	}
+	defer conn.Release()
+
+	_, err = conn.Exec(ctx, `SELECT pg_advisory_lock($1)`, migrationLockID)
+	if err != nil {
+		return fmt.Errorf("failed to acquire migration lock: %w", err)
+	}
+
+	defer func() {
+		// the context may be already canceled but the lock has to be released
+		// before the connection gets back to the pool
+		_, err := conn.Exec(context.WithoutCancel(ctx), `SELECT pg_advisory_unlock($1)`, migrationLockID)
+		if err != nil {
+			conn.Conn().Close(context.WithoutCancel(ctx))
+		}
+	}()
+
+	query := `CREATE TABLE IF NOT EXISTS schema_migrations (version bigint NOT NULL PRIMARY KEY, dirty boolean NOT NULL)`
+
+	_, err = conn.Exec(ctx, query)
+	if err != nil {
+		return fmt.Errorf("failed to create schema_migrations table: %w", err)
+	}
+
+	return fn(conn)
+}
+
+// apply runs the migration file and sets the version in the same transaction.
+// Zero version means no migration is applied.
+func (m *Migrator) apply(ctx context.Context, conn *pgxpool.Conn, path string, version uint64) error {
+	sql, err := fs.ReadFile(m.fsys, path)
+	if err != nil {
+		return fmt.Errorf("failed to read migration: %w", err)
+	}
+
+	return pgx.BeginFunc(ctx, conn, func(tx pgx.Tx) error {
+		// without arguments the statements are sent with the simple protocol
+		// which allows several of them in one call
+		_, err := tx.Exec(ctx, string(sql))
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.Exec(ctx, `TRUNCATE schema_migrations`)
+		if err != nil || version == 0 {
+			return err
+		}
+
+		_, err = tx.Exec(ctx, `INSERT INTO schema_migrations (version, dirty) VALUES ($1, false)`, int64(version))
+
+		return err
+	})
+}
+
+func currentVersion(ctx context.Context, conn *pgxpool.Conn) (uint64, bool, error) {
+	var (
+		version int64
+		dirty   bool
+	)
+
+	err := conn.QueryRow(ctx, `SELECT version, dirty FROM schema_migrations LIMIT 1`).Scan(&version, &dirty)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return 0, false, nil
+		}
+
+		return 0, false, fmt.Errorf("failed to get schema version: %w", err)
+	}
+
+	return uint64(version), dirty, nil
+}
+
+func cleanVersion(ctx context.Context, conn *pgxpool.Conn) (uint64, error) {
+	version, dirty, err := currentVersion(ctx, conn)
+	if err != nil {
+		return 0, err
+	}
+
+	if dirty {
+		return 0, fmt.Errorf("%w at version %d", ErrDirty, version)
+	}
+
+	return version, nil
+}
+
+// pending returns the migrations newer than version.
+func pending(migrations []Migration, version uint64) []Migration {
+	var res []Migration
+
+	for _, mig := range migrations {
+		if mig.Version > version {
+			res = append(res, mig)
+		}
+	}
+
+	return res
+}
+
+// rollback returns up to steps migrations to revert from version, newest first.
+func rollback(migrations []Migration, version uint64, steps int) ([]Migration, error) {
+	if version == 0 {
+		return nil, nil
+	}
+
+	var res []Migration
+
+	for i := len(migrations) - 1; i >= 0; i-- {
+		if migrations[i].Version <= version {
+			res = append(res, migrations[i])
+		}
+	}
+
+	if len(res) == 0 || res[0].Version != version {
+		return nil, fmt.Errorf("migration of the current version %d not found", version)
+	}
+
+	if steps > 0 && steps < len(res) {
+		res = res[:steps]
+	}
+
+	return res, nil
+}
+
+// previousVersion returns the version preceding the given one, zero if there is none.
+func previousVersion(migrations []Migration, version uint64) uint64 {
+	var prev uint64
+
+	for _, mig := range migrations {
+		if mig.Version >= version {
+			break
+		}
+
+		prev = mig.Version
+	}
+
+	return prev
+}
+
+// loadMigrations reads the migrations in fsys ordered by version.
+func loadMigrations(fsys fs.FS) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read migrations: %w", err)
+	}
+
+	byVersion := map[uint64]*Migration{}
+
+	for _, entry := range entries {
+		match := migrationName.FindStringSubmatch(entry.Name())
+		if entry.IsDir() || match == nil {
+			continue
+		}
+
+		version, err := strconv.ParseUint(match[1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid migration version %q: %w", match[1], err)
+		}
+
+		mig, ok := byVersion[version]
+		if !ok {
+			mig = &Migration{Version: version, Name: match[2]}
+			byVersion[version] = mig
+		}
+
+		if mig.Name != match[2] {
+			return nil, fmt.Errorf("migrations %d_%s and %d_%s share the version", version, mig.Name, version, match[2])
+		}
+
+		if match[3] == "up" {
+			mig.up = entry.Name()
+		} else {
+			mig.down = entry.Name()
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+
+	for _, mig := range byVersion {
+		migrations = append(migrations, *mig)
+	}
+
+	slices.SortFunc(migrations, func(a, b Migration) int {
+		return cmp.Compare(a.Version, b.Version)
+	})
+
+	return migrations, nil
+}
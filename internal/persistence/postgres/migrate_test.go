@@ -0,0 +1,143 @@
+package postgres
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/kodeyeen/shortify/migrations"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadMigrations(t *testing.T) {
+	testCases := map[string]struct {
+		fsys     fstest.MapFS
+		expected []Migration
+		err      bool
+	}{
+		"Ordered by version": {
+			fsys: fstest.MapFS{
+				"000010_add_clicks.up.sql":      {},
+				"000010_add_clicks.down.sql":    {},
+				"000002_create_urls.up.sql":     {},
+				"000002_create_urls.down.sql":   {},
+				"README.md":                     {},
+				"000003_without_down.up.sql":    {},
+				"not_a_migration.up.sql":        {},
+				"000004_without_up.down.sql":    {},
+				"000005_invalid.sideways.sql":   {},
+				"000006_nested/000007_x.up.sql": {},
+			},
+			expected: []Migration{
+				{Version: 2, Name: "create_urls", up: "000002_create_urls.up.sql", down: "000002_create_urls.down.sql"},
+				{Version: 3, Name: "without_down", up: "000003_without_down.up.sql"},
+				{Version: 4, Name: "without_up", down: "000004_without_up.down.sql"},
+				{Version: 10, Name: "add_clicks", up: "000010_add_clicks.up.sql", down: "000010_add_clicks.down.sql"},
+			},
+		},
+		"Duplicate version": {
+			fsys: fstest.MapFS{
+				"000001_create_urls.up.sql":  {},
+				"000001_create_users.up.sql": {},
+			},
+			err: true,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			// When
+			migrations, err := loadMigrations(tc.fsys)
+
+			// Then
+			if tc.err {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, migrations)
+		})
+	}
+}
+
+func TestLoadMigrations_Embedded(t *testing.T) {
+	// When
+	got, err := loadMigrations(migrations.FS)
+
+	// Then
+	require.NoError(t, err)
+	require.NotEmpty(t, got)
+
+	for i, mig := range got {
+		require.Equal(t, uint64(i+1), mig.Version)
+		require.NotEmpty(t, mig.up)
+		require.NotEmpty(t, mig.down)
+	}
+}
+
+func TestRollback(t *testing.T) {
+	all := []Migration{{Version: 1}, {Version: 2}, {Version: 5}}
+
+	testCases := map[string]struct {
+		version  uint64
+		steps    int
+		expected []Migration
+		err      bool
+	}{
+		"Nothing applied": {
+			version: 0,
+			steps:   1,
+		},
+		"One step": {
+			version:  5,
+			steps:    1,
+			expected: []Migration{{Version: 5}},
+		},
+		"All": {
+			version:  5,
+			steps:    0,
+			expected: []Migration{{Version: 5}, {Version: 2}, {Version: 1}},
+		},
+		"More steps than applied": {
+			version:  2,
+			steps:    10,
+			expected: []Migration{{Version: 2}, {Version: 1}},
+		},
+		"Unknown version": {
+			version: 3,
+			steps:   1,
+			err:     true,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			// When
+			got, err := rollback(all, tc.version, tc.steps)
+
+			// Then
+			if tc.err {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, got)
+		})
+	}
+}
+
+func TestPendingAndPreviousVersion(t *testing.T) {
+	all := []Migration{{Version: 1}, {Version: 2}, {Version: 5}}
+
+	require.Equal(t, all, pending(all, 0))
+	require.Equal(t, []Migration{{Version: 5}}, pending(all, 2))
+	require.Empty(t, pending(all, 5))
+
+	require.Equal(t, uint64(0), previousVersion(all, 1))
+	require.Equal(t, uint64(2), previousVersion(all, 5))
+}
@@ -0,0 +1,31 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// AliasSequence hands out values of the alias_seq sequence
+// shared by all the replicas.
+type AliasSequence struct {
+	dbpool *pgxpool.Pool
+}
+
+func NewAliasSequence(dbpool *pgxpool.Pool) *AliasSequence {
+	return &AliasSequence{
+		dbpool: dbpool,
+	}
+}
+
+func (s *AliasSequence) Next(ctx context.Context) (uint64, error) {
+	var n int64
+
+	err := s.dbpool.QueryRow(ctx, `SELECT nextval('alias_seq')`).Scan(&n)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get next alias sequence value: %w", err)
+	}
+
+	return uint64(n), nil
+}
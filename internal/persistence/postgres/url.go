@@ -4,6 +4,8 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/jackc/pgerrcode"
 	"github.com/jackc/pgx/v5"
@@ -13,14 +15,48 @@ import (
 	"github.com/kodeyeen/shortify/internal/persistence"
 )
 
+const urlColumns = `id, original, alias, query_mode, preview, title, description, tags, metadata, clicks, created_at,
+	og_title, og_description, og_image, og_fetched_at, deleted_at`
+
+const (
+	// aliasMatch matches the URL by alias
+	aliasMatch = `alias = @alias`
+	// foldedAliasMatch matches the URL by alias regardless of case using the urls_alias_lower_idx index.
+	// The exact match wins when several aliases differ only in case.
+	foldedAliasMatch = `id = (SELECT id FROM urls WHERE lower(alias) = lower(@alias) ORDER BY alias = @alias DESC LIMIT 1)`
+)
+
 type URLRepository struct {
 	dbpool *pgxpool.Pool
+
+	caseInsensitive bool
+	aliasMatch      string
+}
+
+// URLOption configures a URLRepository.
+type URLOption func(r *URLRepository)
+
+// WithCaseInsensitiveAliases makes aliases match regardless of case.
+// Adding an alias differing from a stored one only in case fails with persistence.ErrDuplicateAlias.
+func WithCaseInsensitiveAliases() URLOption {
+	return func(r *URLRepository) {
+		r.caseInsensitive = true
+		r.aliasMatch = foldedAliasMatch
+	}
 }
 
-func NewURLRepository(dbpool *pgxpool.Pool) *URLRepository {
-	return &URLRepository{
+func NewURLRepository(dbpool *pgxpool.Pool, opts ...URLOption) *URLRepository {
+	r := &URLRepository{
 		dbpool: dbpool,
+
+		aliasMatch: aliasMatch,
+	}
+
+	for _, opt := range opts {
+		opt(r)
 	}
+
+	return r
 }
 
 func (r *URLRepository) Close() {
@@ -28,25 +64,48 @@ func (r *URLRepository) Close() {
 }
 
 func (r *URLRepository) Add(ctx context.Context, u *domain.URL) (int64, error) {
-	query := `INSERT INTO urls (original, alias) VALUES (@original, @alias) RETURNING id`
+	query := `
+		INSERT INTO urls (original, alias, query_mode, preview, title, description, tags, metadata, created_at)
+		VALUES (@original, @alias, @query_mode, @preview, @title, @description,
+			COALESCE(@tags::text[], '{}'), COALESCE(@metadata::jsonb, '{}'), @created_at)
+		RETURNING id`
 	args := pgx.NamedArgs{
-		"original": u.Original,
-		"alias":    u.Alias,
+		"original":    u.Original,
+		"alias":       u.Alias,
+		"query_mode":  u.QueryMode,
+		"preview":     u.Preview,
+		"title":       u.Title,
+		"description": u.Description,
+		"tags":        u.Tags,
+		"metadata":    u.Metadata,
+		"created_at":  u.CreatedAt,
 	}
 
-	var insertID int64
+	var (
+		insertID int64
+		err      error
+	)
+
+	if r.caseInsensitive {
+		err = pgx.BeginFunc(ctx, r.dbpool, func(tx pgx.Tx) error {
+			err := checkFoldedAlias(ctx, tx, u.Alias)
+			if err != nil {
+				return err
+			}
+
+			return tx.QueryRow(ctx, query, args).Scan(&insertID)
+		})
+	} else {
+		err = r.dbpool.QueryRow(ctx, query, args).Scan(&insertID)
+	}
 
-	err := r.dbpool.QueryRow(ctx, query, args).Scan(&insertID)
 	if err != nil {
-		var pgErr *pgconn.PgError
+		if errors.Is(err, persistence.ErrDuplicateAlias) {
+			return 0, err
+		}
 
-		if errors.As(err, &pgErr) && pgErr.Code == pgerrcode.UniqueViolation {
-			switch pgErr.ConstraintName {
-			case "urls_original_key":
-				return 0, persistence.ErrURLAlreadyExists
-			case "urls_alias_key":
-				return 0, persistence.ErrDuplicateAlias
-			}
+		if err := uniqueViolation(err); err != nil {
+			return 0, err
 		}
 
 		return 0, fmt.Errorf("failed to add url: %w", err)
@@ -55,25 +114,498 @@ func (r *URLRepository) Add(ctx context.Context, u *domain.URL) (int64, error) {
 	return insertID, nil
 }
 
+func (r *URLRepository) FindByID(ctx context.Context, id int64) (*domain.URL, error) {
+	query := `SELECT ` + urlColumns + ` FROM urls WHERE id = @id`
+	args := pgx.NamedArgs{
+		"id": id,
+	}
+
+	u, err := scanURL(r.dbpool.QueryRow(ctx, query, args))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, persistence.ErrURLNotFound
+		}
+
+		return nil, fmt.Errorf("failed to find url by id: %w", err)
+	}
+
+	return u, nil
+}
+
 func (r *URLRepository) FindByAlias(ctx context.Context, alias string) (*domain.URL, error) {
-	query := `SELECT id, original, alias FROM urls WHERE alias = @alias`
+	query := `SELECT ` + urlColumns + ` FROM urls WHERE ` + r.aliasMatch
+	args := pgx.NamedArgs{
+		"alias": alias,
+	}
+
+	u, err := scanURL(r.dbpool.QueryRow(ctx, query, args))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, persistence.ErrURLNotFound
+		}
+
+		return nil, fmt.Errorf("failed to find url by alias: %w", err)
+	}
+
+	return u, nil
+}
+
+func (r *URLRepository) FindByOriginal(ctx context.Context, original string) (*domain.URL, error) {
+	query := `SELECT ` + urlColumns + ` FROM urls WHERE original = @original`
+	args := pgx.NamedArgs{
+		"original": original,
+	}
+
+	u, err := scanURL(r.dbpool.QueryRow(ctx, query, args))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, persistence.ErrURLNotFound
+		}
+
+		return nil, fmt.Errorf("failed to find url by original: %w", err)
+	}
+
+	return u, nil
+}
+
+func (r *URLRepository) Count(ctx context.Context) (int64, error) {
+	var count int64
+
+	err := r.dbpool.QueryRow(ctx, `SELECT count(*) FROM urls`).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count urls: %w", err)
+	}
+
+	return count, nil
+}
+
+func (r *URLRepository) List(ctx context.Context, filter persistence.URLFilter) ([]*domain.URL, error) {
+	conds := []string{`deleted_at IS NULL`}
+
+	args := pgx.NamedArgs{
+		"offset": filter.Offset,
+	}
+
+	if filter.AfterID > 0 {
+		conds = append(conds, `id > @after_id`)
+		args["after_id"] = filter.AfterID
+	}
+
+	if filter.Tag != "" {
+		conds = append(conds, `tags @> ARRAY[@tag::text]`)
+		args["tag"] = filter.Tag
+	}
+
+	if len(filter.Metadata) > 0 {
+		conds = append(conds, `metadata @> @metadata::jsonb`)
+		args["metadata"] = filter.Metadata
+	}
+
+	query := `SELECT ` + urlColumns + ` FROM urls WHERE ` + strings.Join(conds, ` AND `) + ` ORDER BY id OFFSET @offset`
+
+	if filter.Limit > 0 {
+		query += ` LIMIT @limit`
+		args["limit"] = filter.Limit
+	}
+
+	rows, err := r.dbpool.Query(ctx, query, args)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list urls: %w", err)
+	}
+	defer rows.Close()
+
+	urls := []*domain.URL{}
+
+	for rows.Next() {
+		u, err := scanURL(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan url: %w", err)
+		}
+
+		urls = append(urls, u)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list urls: %w", err)
+	}
+
+	return urls, nil
+}
+
+// Update updates the editable fields of the URL with the same alias.
+// Changing the original URL resets its Open Graph metadata.
+func (r *URLRepository) Update(ctx context.Context, u *domain.URL) error {
+	query := `
+		UPDATE urls
+		SET original = @original, query_mode = @query_mode, preview = @preview,
+			title = @title, description = @description,
+			tags = COALESCE(@tags::text[], '{}'), metadata = COALESCE(@metadata::jsonb, '{}'),
+			og_fetched_at = CASE WHEN original = @original THEN og_fetched_at END
+		WHERE ` + r.aliasMatch
+	args := pgx.NamedArgs{
+		"alias":       u.Alias,
+		"original":    u.Original,
+		"query_mode":  u.QueryMode,
+		"preview":     u.Preview,
+		"title":       u.Title,
+		"description": u.Description,
+		"tags":        u.Tags,
+		"metadata":    u.Metadata,
+	}
+
+	tag, err := r.dbpool.Exec(ctx, query, args)
+	if err != nil {
+		if err := uniqueViolation(err); err != nil {
+			return err
+		}
+
+		return fmt.Errorf("failed to update url: %w", err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return persistence.ErrURLNotFound
+	}
+
+	return nil
+}
+
+// UpdateOpenGraph stores the metadata of the URL with the id unless its original URL
+// is no longer original, failing with persistence.ErrURLNotFound then.
+func (r *URLRepository) UpdateOpenGraph(ctx context.Context, id int64, original string, og *domain.OpenGraph) error {
+	query := `
+		UPDATE urls
+		SET og_title = @title, og_description = @description, og_image = @image, og_fetched_at = @fetched_at
+		WHERE id = @id AND original = @original`
+	args := pgx.NamedArgs{
+		"id":          id,
+		"original":    original,
+		"title":       og.Title,
+		"description": og.Description,
+		"image":       og.Image,
+		"fetched_at":  og.FetchedAt,
+	}
+
+	tag, err := r.dbpool.Exec(ctx, query, args)
+	if err != nil {
+		return fmt.Errorf("failed to update url open graph: %w", err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return persistence.ErrURLNotFound
+	}
+
+	return nil
+}
+
+func (r *URLRepository) IncrementClicks(ctx context.Context, alias string) (int64, error) {
+	query := `UPDATE urls SET clicks = clicks + 1 WHERE ` + r.aliasMatch + ` RETURNING clicks`
 	args := pgx.NamedArgs{
 		"alias": alias,
 	}
 
-	var u domain.URL
+	var clicks int64
+
+	err := r.dbpool.QueryRow(ctx, query, args).Scan(&clicks)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return 0, persistence.ErrURLNotFound
+		}
 
-	err := r.dbpool.QueryRow(ctx, query, args).Scan(
+		return 0, fmt.Errorf("failed to increment url clicks: %w", err)
+	}
+
+	return clicks, nil
+}
+
+// SoftDelete moves the URL to the trash keeping its alias and original URL taken.
+func (r *URLRepository) SoftDelete(ctx context.Context, alias string, deletedAt time.Time) (*domain.URL, error) {
+	query := `UPDATE urls SET deleted_at = @deleted_at WHERE ` + r.aliasMatch + ` AND deleted_at IS NULL RETURNING ` + urlColumns
+	args := pgx.NamedArgs{
+		"alias":      alias,
+		"deleted_at": deletedAt,
+	}
+
+	u, err := scanURL(r.dbpool.QueryRow(ctx, query, args))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, persistence.ErrURLNotFound
+		}
+
+		return nil, fmt.Errorf("failed to soft delete url: %w", err)
+	}
+
+	return u, nil
+}
+
+// Restore takes the URL out of the trash.
+func (r *URLRepository) Restore(ctx context.Context, alias string) (*domain.URL, error) {
+	query := `UPDATE urls SET deleted_at = NULL WHERE ` + r.aliasMatch + ` AND deleted_at IS NOT NULL RETURNING ` + urlColumns
+	args := pgx.NamedArgs{
+		"alias": alias,
+	}
+
+	u, err := scanURL(r.dbpool.QueryRow(ctx, query, args))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, persistence.ErrURLNotFound
+		}
+
+		return nil, fmt.Errorf("failed to restore url: %w", err)
+	}
+
+	return u, nil
+}
+
+// Purge deletes the URLs moved to the trash before deletedBefore,
+// their alias history is deleted by the foreign key.
+func (r *URLRepository) Purge(ctx context.Context, deletedBefore time.Time) (int64, error) {
+	query := `DELETE FROM urls WHERE deleted_at < @deleted_before`
+	args := pgx.NamedArgs{
+		"deleted_before": deletedBefore,
+	}
+
+	tag, err := r.dbpool.Exec(ctx, query, args)
+	if err != nil {
+		return 0, fmt.Errorf("failed to purge urls: %w", err)
+	}
+
+	return tag.RowsAffected(), nil
+}
+
+// Rename moves the URL with alias to the alias of u, updates its editable fields like Update
+// and records the old alias in the alias history, all in one transaction.
+func (r *URLRepository) Rename(ctx context.Context, alias string, u *domain.URL, retiredAt time.Time) (*domain.URL, error) {
+	var renamed *domain.URL
+
+	err := pgx.BeginFunc(ctx, r.dbpool, func(tx pgx.Tx) error {
+		var (
+			id       int64
+			oldAlias string
+		)
+
+		query := `SELECT id, alias FROM urls WHERE ` + r.aliasMatch + ` FOR UPDATE`
+		args := pgx.NamedArgs{
+			"alias": alias,
+		}
+
+		err := tx.QueryRow(ctx, query, args).Scan(&id, &oldAlias)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return persistence.ErrURLNotFound
+			}
+
+			return err
+		}
+
+		if r.caseInsensitive && !strings.EqualFold(oldAlias, u.Alias) {
+			err := checkFoldedAlias(ctx, tx, u.Alias)
+			if err != nil {
+				return err
+			}
+		}
+
+		query = `
+			UPDATE urls
+			SET alias = @new_alias, original = @original, query_mode = @query_mode, preview = @preview,
+				title = @title, description = @description,
+				tags = COALESCE(@tags::text[], '{}'), metadata = COALESCE(@metadata::jsonb, '{}'),
+				og_fetched_at = CASE WHEN original = @original THEN og_fetched_at END
+			WHERE id = @id
+			RETURNING ` + urlColumns
+		args = pgx.NamedArgs{
+			"id":          id,
+			"new_alias":   u.Alias,
+			"original":    u.Original,
+			"query_mode":  u.QueryMode,
+			"preview":     u.Preview,
+			"title":       u.Title,
+			"description": u.Description,
+			"tags":        u.Tags,
+			"metadata":    u.Metadata,
+		}
+
+		renamed, err = scanURL(tx.QueryRow(ctx, query, args))
+		if err != nil {
+			return err
+		}
+
+		query = `
+			INSERT INTO alias_history (alias, url_id, retired_at)
+			VALUES (@alias, @url_id, @retired_at)
+			ON CONFLICT (alias, url_id) DO UPDATE SET retired_at = EXCLUDED.retired_at`
+		args = pgx.NamedArgs{
+			"alias":      oldAlias,
+			"url_id":     id,
+			"retired_at": retiredAt,
+		}
+
+		_, err = tx.Exec(ctx, query, args)
+
+		return err
+	})
+	if err != nil {
+		if errors.Is(err, persistence.ErrURLNotFound) || errors.Is(err, persistence.ErrDuplicateAlias) {
+			return nil, err
+		}
+
+		if err := uniqueViolation(err); err != nil {
+			return nil, err
+		}
+
+		return nil, fmt.Errorf("failed to rename url: %w", err)
+	}
+
+	return renamed, nil
+}
+
+// FindRetiredAlias returns the latest retirement of the alias.
+func (r *URLRepository) FindRetiredAlias(ctx context.Context, alias string) (*domain.RetiredAlias, error) {
+	match := `alias = @alias`
+	if r.caseInsensitive {
+		match = `lower(alias) = lower(@alias)`
+	}
+
+	query := `SELECT alias, url_id, retired_at FROM alias_history WHERE ` + match + ` ORDER BY retired_at DESC LIMIT 1`
+	args := pgx.NamedArgs{
+		"alias": alias,
+	}
+
+	var ra domain.RetiredAlias
+
+	err := r.dbpool.QueryRow(ctx, query, args).Scan(&ra.Alias, &ra.URLID, &ra.RetiredAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, persistence.ErrAliasNotFound
+		}
+
+		return nil, fmt.Errorf("failed to find retired alias: %w", err)
+	}
+
+	return &ra, nil
+}
+
+// ListRetiredAliases returns the retired aliases of the URL, the most recently retired first.
+func (r *URLRepository) ListRetiredAliases(ctx context.Context, urlID int64) ([]*domain.RetiredAlias, error) {
+	query := `SELECT alias, url_id, retired_at FROM alias_history WHERE url_id = @url_id ORDER BY retired_at DESC`
+	args := pgx.NamedArgs{
+		"url_id": urlID,
+	}
+
+	rows, err := r.dbpool.Query(ctx, query, args)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list retired aliases: %w", err)
+	}
+	defer rows.Close()
+
+	retired := []*domain.RetiredAlias{}
+
+	for rows.Next() {
+		var ra domain.RetiredAlias
+
+		err := rows.Scan(&ra.Alias, &ra.URLID, &ra.RetiredAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan retired alias: %w", err)
+		}
+
+		retired = append(retired, &ra)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list retired aliases: %w", err)
+	}
+
+	return retired, nil
+}
+
+// Reindex rebuilds the indexes of the urls table.
+func (r *URLRepository) Reindex(ctx context.Context) error {
+	_, err := r.dbpool.Exec(ctx, `REINDEX TABLE urls`)
+	if err != nil {
+		return fmt.Errorf("failed to reindex urls: %w", err)
+	}
+
+	return nil
+}
+
+// checkFoldedAlias fails with persistence.ErrDuplicateAlias when an alias differing from alias
+// only in case exists. It holds a lock on the folded alias until tx ends so concurrent
+// additions of the same folded alias are serialized.
+func checkFoldedAlias(ctx context.Context, tx pgx.Tx, alias string) error {
+	query := `SELECT pg_advisory_xact_lock(hashtext('urls.alias'), hashtext(lower(@alias)))`
+	args := pgx.NamedArgs{
+		"alias": alias,
+	}
+
+	_, err := tx.Exec(ctx, query, args)
+	if err != nil {
+		return err
+	}
+
+	var exists bool
+
+	query = `SELECT EXISTS (SELECT 1 FROM urls WHERE lower(alias) = lower(@alias))`
+
+	err = tx.QueryRow(ctx, query, args).Scan(&exists)
+	if err != nil {
+		return err
+	}
+
+	if exists {
+		return persistence.ErrDuplicateAlias
+	}
+
+	return nil
+}
+
+// uniqueViolation maps violations of the urls unique constraints to persistence errors.
+// It returns nil for other errors.
+func uniqueViolation(err error) error {
+	var pgErr *pgconn.PgError
+
+	if errors.As(err, &pgErr) && pgErr.Code == pgerrcode.UniqueViolation {
+		switch pgErr.ConstraintName {
+		case "urls_original_key":
+			return persistence.ErrURLAlreadyExists
+		case "urls_alias_key":
+			return persistence.ErrDuplicateAlias
+		}
+	}
+
+	return nil
+}
+
+func scanURL(row pgx.Row) (*domain.URL, error) {
+	var (
+		u           domain.URL
+		og          domain.OpenGraph
+		ogFetchedAt *time.Time
+	)
+
+	err := row.Scan(
 		&u.ID,
 		&u.Original,
 		&u.Alias,
+		&u.QueryMode,
+		&u.Preview,
+		&u.Title,
+		&u.Description,
+		&u.Tags,
+		&u.Metadata,
+		&u.Clicks,
+		&u.CreatedAt,
+		&og.Title,
+		&og.Description,
+		&og.Image,
+		&ogFetchedAt,
+		&u.DeletedAt,
 	)
 	if err != nil {
-		if errors.Is(err, pgx.ErrNoRows) {
-			return nil, persistence.ErrURLNotFound
-		}
+		return nil, err
+	}
 
-		return nil, fmt.Errorf("failed to find url by alias: %w", err)
+	if ogFetchedAt != nil {
+		og.FetchedAt = *ogFetchedAt
+		u.OpenGraph = &og
 	}
 
 	return &u, nil
@@ -0,0 +1,231 @@
+package postgres_test
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/kodeyeen/shortify/internal/domain"
+	"github.com/kodeyeen/shortify/internal/persistence/postgres"
+	"github.com/kodeyeen/shortify/internal/persistence/repotest"
+	"github.com/kodeyeen/shortify/migrations"
+	"github.com/stretchr/testify/require"
+)
+
+// dsnEnv points the tests to an existing database. Without it they start
+// a throwaway cluster if the Postgres binaries are installed, or get skipped.
+const dsnEnv = "SHORTIFY_TEST_POSTGRES_DSN"
+
+var (
+	dsn        string
+	dsnMissing error
+)
+
+func TestMain(m *testing.M) {
+	dsn = os.Getenv(dsnEnv)
+
+	var stop func()
+
+	if dsn == "" {
+		dsn, stop, dsnMissing = startCluster()
+	}
+
+	code := m.Run()
+
+	if stop != nil {
+		stop()
+	}
+
+	os.Exit(code)
+}
+
+func TestURLRepository(t *testing.T) {
+	repotest.TestURLRepository(t, func(t *testing.T) repotest.URLRepository {
+		return postgres.NewURLRepository(newSchema(t))
+	})
+}
+
+func TestURLRepository_CaseInsensitive(t *testing.T) {
+	repotest.TestCaseInsensitiveURLRepository(t, func(t *testing.T) repotest.URLRepository {
+		return postgres.NewURLRepository(newSchema(t), postgres.WithCaseInsensitiveAliases())
+	})
+}
+
+func TestAuditRepository(t *testing.T) {
+	repotest.TestAuditRepository(t, func(t *testing.T) repotest.AuditRepository {
+		return postgres.NewAuditRepository(newSchema(t))
+	})
+}
+
+// newSchema returns a pool bound to a new migrated schema dropped after the test.
+func newSchema(t *testing.T) *pgxpool.Pool {
+	t.Helper()
+
+	if dsn == "" {
+		t.Skipf("set %s to run Postgres tests: %s", dsnEnv, dsnMissing)
+	}
+
+	ctx := context.Background()
+
+	admin, err := pgxpool.New(ctx, dsn)
+	require.NoError(t, err)
+
+	suffix := make([]byte, 6)
+	_, _ = rand.Read(suffix)
+	schema := "repotest_" + hex.EncodeToString(suffix)
+
+	_, err = admin.Exec(ctx, `CREATE SCHEMA `+schema)
+	require.NoError(t, err)
+
+	cfg, err := pgxpool.ParseConfig(dsn)
+	require.NoError(t, err)
+
+	cfg.ConnConfig.RuntimeParams["search_path"] = schema
+
+	dbpool, err := pgxpool.NewWithConfig(ctx, cfg)
+	require.NoError(t, err)
+
+	t.Cleanup(func() {
+		dbpool.Close()
+
+		_, err := admin.Exec(ctx, `DROP SCHEMA `+schema+` CASCADE`)
+		require.NoError(t, err)
+
+		admin.Close()
+	})
+
+	_, err = postgres.NewMigrator(dbpool, migrations.FS).Up(ctx)
+	require.NoError(t, err)
+
+	return dbpool
+}
+
+// startCluster starts a Postgres cluster in a temporary directory
+// listening on a unix socket only.
+func startCluster() (string, func(), error) {
+	initdb, err := findBinary("initdb")
+	if err != nil {
+		return "", nil, err
+	}
+
+	pgCtl, err := findBinary("pg_ctl")
+	if err != nil {
+		return "", nil, err
+	}
+
+	dir, err := os.MkdirTemp("", "shortify-postgres")
+	if err != nil {
+		return "", nil, err
+	}
+
+	port, err := freePort()
+	if err != nil {
+		os.RemoveAll(dir)
+
+		return "", nil, err
+	}
+
+	data := filepath.Join(dir, "data")
+
+	out, err := exec.Command(initdb, "-D", data, "-U", "postgres", "--auth=trust", "--no-sync").CombinedOutput()
+	if err != nil {
+		os.RemoveAll(dir)
+
+		return "", nil, fmt.Errorf("initdb failed: %w: %s", err, out)
+	}
+
+	opts := fmt.Sprintf("-p %d -k %s -c listen_addresses='' -c fsync=off", port, dir)
+
+	out, err = exec.Command(pgCtl, "-D", data, "-o", opts, "-l", filepath.Join(dir, "log"), "-w", "-t", "30", "start").CombinedOutput()
+	if err != nil {
+		os.RemoveAll(dir)
+
+		return "", nil, fmt.Errorf("pg_ctl start failed: %w: %s", err, out)
+	}
+
+	stop := func() {
+		_ = exec.Command(pgCtl, "-D", data, "-m", "immediate", "-w", "stop").Run()
+		os.RemoveAll(dir)
+	}
+
+	return fmt.Sprintf("host=%s port=%d user=postgres dbname=postgres sslmode=disable", dir, port), stop, nil
+}
+
+// findBinary looks for the Postgres binary in PATH and the usual Debian location.
+func findBinary(name string) (string, error) {
+	if path, err := exec.LookPath(name); err == nil {
+		return path, nil
+	}
+
+	matches, _ := filepath.Glob(filepath.Join("/usr/lib/postgresql/*/bin", name))
+	if len(matches) > 0 {
+		return matches[len(matches)-1], nil
+	}
+
+	return "", errors.New(name + " not found")
+}
+
+func freePort() (int, error) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return 0, err
+	}
+	defer l.Close()
+
+	return l.Addr().(*net.TCPAddr).Port, nil
+}
+
+func TestAliasSequence_Next(t *testing.T) {
+	ctx := context.Background()
+	seq := postgres.NewAliasSequence(newSchema(t))
+
+	first, err := seq.Next(ctx)
+	require.NoError(t, err)
+
+	second, err := seq.Next(ctx)
+	require.NoError(t, err)
+
+	require.Greater(t, second, first)
+}
+
+func TestAliasPool(t *testing.T) {
+	ctx := context.Background()
+	dbpool := newSchema(t)
+
+	// Given
+	_, err := postgres.NewURLRepository(dbpool).Add(ctx, &domain.URL{Original: "https://example.com", Alias: "taken"})
+	require.NoError(t, err)
+
+	aliasPool := postgres.NewAliasPool(dbpool)
+
+	// When
+	reserved, err := aliasPool.Reserve(ctx, []string{"free1", "taken", "free2"}, time.Now().Add(-time.Hour))
+
+	// Then
+	require.NoError(t, err)
+	require.ElementsMatch(t, []string{"free1", "free2"}, reserved)
+
+	reserved, err = aliasPool.Reserve(ctx, []string{"free1", "free3"}, time.Now().Add(-time.Hour))
+	require.NoError(t, err)
+	require.Equal(t, []string{"free3"}, reserved)
+
+	require.NoError(t, aliasPool.Release(ctx, []string{"free1"}))
+
+	reserved, err = aliasPool.Reserve(ctx, []string{"free1", "free2"}, time.Now().Add(-time.Hour))
+	require.NoError(t, err)
+	require.Equal(t, []string{"free1"}, reserved)
+
+	// expired reservations are dropped
+	reserved, err = aliasPool.Reserve(ctx, []string{"free2"}, time.Now().Add(time.Hour))
+	require.NoError(t, err)
+	require.Equal(t, []string{"free2"}, reserved)
+}
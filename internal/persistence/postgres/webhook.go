@@ -0,0 +1,261 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/kodeyeen/shortify/internal/domain"
+	"github.com/kodeyeen/shortify/internal/persistence"
+)
+
+const deliveryColumns = `id, webhook_id, event, payload, status, attempts, next_attempt_at,
+	last_error, response_status, created_at, delivered_at`
+
+type WebhookRepository struct {
+	dbpool *pgxpool.Pool
+}
+
+func NewWebhookRepository(dbpool *pgxpool.Pool) *WebhookRepository {
+	return &WebhookRepository{
+		dbpool: dbpool,
+	}
+}
+
+func (r *WebhookRepository) Add(ctx context.Context, w *domain.Webhook) (int64, error) {
+	query := `
+		INSERT INTO webhooks (url, secret, events, created_at)
+		VALUES (@url, @secret, @events, @created_at)
+		RETURNING id`
+	args := pgx.NamedArgs{
+		"url":        w.URL,
+		"secret":     w.Secret,
+		"events":     w.Events,
+		"created_at": w.CreatedAt,
+	}
+
+	var insertID int64
+
+	err := r.dbpool.QueryRow(ctx, query, args).Scan(&insertID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to add webhook: %w", err)
+	}
+
+	return insertID, nil
+}
+
+func (r *WebhookRepository) FindByID(ctx context.Context, id int64) (*domain.Webhook, error) {
+	query := `SELECT id, url, secret, events, created_at FROM webhooks WHERE id = @id`
+	args := pgx.NamedArgs{
+		"id": id,
+	}
+
+	var w domain.Webhook
+
+	err := r.dbpool.QueryRow(ctx, query, args).Scan(&w.ID, &w.URL, &w.Secret, &w.Events, &w.CreatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, persistence.ErrWebhookNotFound
+		}
+
+		return nil, fmt.Errorf("failed to find webhook by id: %w", err)
+	}
+
+	return &w, nil
+}
+
+func (r *WebhookRepository) List(ctx context.Context) ([]*domain.Webhook, error) {
+	query := `SELECT id, url, secret, events, created_at FROM webhooks ORDER BY id`
+
+	rows, err := r.dbpool.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list webhooks: %w", err)
+	}
+	defer rows.Close()
+
+	webhooks := []*domain.Webhook{}
+
+	for rows.Next() {
+		var w domain.Webhook
+
+		err := rows.Scan(&w.ID, &w.URL, &w.Secret, &w.Events, &w.CreatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan webhook: %w", err)
+		}
+
+		webhooks = append(webhooks, &w)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list webhooks: %w", err)
+	}
+
+	return webhooks, nil
+}
+
+func (r *WebhookRepository) Delete(ctx context.Context, id int64) error {
+	query := `DELETE FROM webhooks WHERE id = @id`
+	args := pgx.NamedArgs{
+		"id": id,
+	}
+
+	tag, err := r.dbpool.Exec(ctx, query, args)
+	if err != nil {
+		return fmt.Errorf("failed to delete webhook: %w", err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return persistence.ErrWebhookNotFound
+	}
+
+	return nil
+}
+
+func (r *WebhookRepository) AddDeliveries(ctx context.Context, ds []*domain.Delivery) error {
+	query := `
+		INSERT INTO webhook_deliveries (webhook_id, event, payload, status, attempts, next_attempt_at, created_at)
+		VALUES (@webhook_id, @event, @payload, @status, @attempts, @next_attempt_at, @created_at)
+		RETURNING id`
+
+	batch := &pgx.Batch{}
+
+	for _, d := range ds {
+		batch.Queue(query, pgx.NamedArgs{
+			"webhook_id":      d.WebhookID,
+			"event":           d.Event,
+			"payload":         string(d.Payload),
+			"status":          d.Status,
+			"attempts":        d.Attempts,
+			"next_attempt_at": d.NextAttemptAt,
+			"created_at":      d.CreatedAt,
+		}).QueryRow(func(row pgx.Row) error {
+			return row.Scan(&d.ID)
+		})
+	}
+
+	err := pgx.BeginFunc(ctx, r.dbpool, func(tx pgx.Tx) error {
+		return tx.SendBatch(ctx, batch).Close()
+	})
+	if err != nil {
+		return fmt.Errorf("failed to add deliveries: %w", err)
+	}
+
+	return nil
+}
+
+func (r *WebhookRepository) ClaimDue(ctx context.Context, now, leaseUntil time.Time, limit int) ([]*domain.Delivery, error) {
+	// SKIP LOCKED lets several replicas claim deliveries concurrently
+	query := `
+		UPDATE webhook_deliveries d
+		SET next_attempt_at = @lease_until
+		FROM (
+			SELECT id
+			FROM webhook_deliveries
+			WHERE status = 'pending' AND next_attempt_at <= @now
+			ORDER BY next_attempt_at, id
+			LIMIT @limit
+			FOR UPDATE SKIP LOCKED
+		) due
+		WHERE d.id = due.id
+		RETURNING d.id, d.webhook_id, d.event, d.payload, d.status, d.attempts, d.next_attempt_at,
+			d.last_error, d.response_status, d.created_at, d.delivered_at`
+	args := pgx.NamedArgs{
+		"now":         now,
+		"lease_until": leaseUntil,
+		"limit":       limit,
+	}
+
+	rows, err := r.dbpool.Query(ctx, query, args)
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim deliveries: %w", err)
+	}
+
+	return collectDeliveries(rows)
+}
+
+func (r *WebhookRepository) UpdateDelivery(ctx context.Context, d *domain.Delivery) error {
+	query := `
+		UPDATE webhook_deliveries
+		SET status = @status, attempts = @attempts, next_attempt_at = @next_attempt_at,
+			last_error = @last_error, response_status = @response_status, delivered_at = @delivered_at
+		WHERE id = @id`
+	args := pgx.NamedArgs{
+		"id":              d.ID,
+		"status":          d.Status,
+		"attempts":        d.Attempts,
+		"next_attempt_at": d.NextAttemptAt,
+		"last_error":      d.LastError,
+		"response_status": d.ResponseStatus,
+		"delivered_at":    d.DeliveredAt,
+	}
+
+	// no rows are affected when the webhook and its deliveries were deleted
+	_, err := r.dbpool.Exec(ctx, query, args)
+	if err != nil {
+		return fmt.Errorf("failed to update delivery: %w", err)
+	}
+
+	return nil
+}
+
+func (r *WebhookRepository) ListDeliveries(ctx context.Context, webhookID int64, limit int) ([]*domain.Delivery, error) {
+	query := `SELECT ` + deliveryColumns + ` FROM webhook_deliveries WHERE webhook_id = @webhook_id ORDER BY id DESC`
+	args := pgx.NamedArgs{
+		"webhook_id": webhookID,
+	}
+
+	if limit > 0 {
+		query += ` LIMIT @limit`
+		args["limit"] = limit
+	}
+
+	rows, err := r.dbpool.Query(ctx, query, args)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list deliveries: %w", err)
+	}
+
+	return collectDeliveries(rows)
+}
+
+func collectDeliveries(rows pgx.Rows) ([]*domain.Delivery, error) {
+	defer rows.Close()
+
+	deliveries := []*domain.Delivery{}
+
+	for rows.Next() {
+		var (
+			d       domain.Delivery
+			payload string
+		)
+
+		err := rows.Scan(
+			&d.ID,
+			&d.WebhookID,
+			&d.Event,
+			&payload,
+			&d.Status,
+			&d.Attempts,
+			&d.NextAttemptAt,
+			&d.LastError,
+			&d.ResponseStatus,
+			&d.CreatedAt,
+			&d.DeliveredAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan delivery: %w", err)
+		}
+
+		d.Payload = []byte(payload)
+
+		deliveries = append(deliveries, &d)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read deliveries: %w", err)
+	}
+
+	return deliveries, nil
+}
@@ -0,0 +1,147 @@
+package repotest
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/kodeyeen/shortify/internal/domain"
+	"github.com/stretchr/testify/require"
+)
+
+// AuditRepository is the audit log under test.
+type AuditRepository interface {
+	Append(ctx context.Context, e *domain.AuditEntry) (int64, error)
+	ListByURL(ctx context.Context, urlID int64) ([]*domain.AuditEntry, error)
+}
+
+// TestAuditRepository runs the conformance tests against empty audit logs returned by newRepo.
+func TestAuditRepository(t *testing.T, newRepo func(t *testing.T) AuditRepository) {
+	tests := map[string]func(t *testing.T, repo AuditRepository){
+		"Append and list": testAppendAndList,
+		"Stored copies":   testAuditStoredCopies,
+		"Parallel append": testParallelAppend,
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			test(t, newRepo(t))
+		})
+	}
+}
+
+func testAppendAndList(t *testing.T, repo AuditRepository) {
+	ctx := context.Background()
+	createdAt := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	created := &domain.AuditEntry{
+		URLID:     1,
+		Action:    domain.AuditActionCreated,
+		Actor:     "alice",
+		RequestID: "req-1",
+		After: &domain.URLState{
+			Original:  "https://example.com/1",
+			Alias:     "alias00001",
+			QueryMode: domain.QueryModeIgnore,
+			Tags:      []string{"promo"},
+			Metadata:  map[string]string{"campaign": "spring"},
+		},
+		CreatedAt: createdAt,
+	}
+	deleted := &domain.AuditEntry{
+		URLID:     1,
+		Action:    domain.AuditActionDeleted,
+		Actor:     "bob",
+		RequestID: "req-2",
+		Before:    created.After,
+		CreatedAt: createdAt.Add(time.Hour),
+	}
+
+	createdID, err := repo.Append(ctx, created)
+	require.NoError(t, err)
+
+	_, err = repo.Append(ctx, &domain.AuditEntry{URLID: 2, Action: domain.AuditActionCreated, CreatedAt: createdAt})
+	require.NoError(t, err)
+
+	deletedID, err := repo.Append(ctx, deleted)
+	require.NoError(t, err)
+	require.Greater(t, deletedID, createdID)
+
+	entries, err := repo.ListByURL(ctx, 1)
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+
+	require.Equal(t, createdID, entries[0].ID)
+	require.Equal(t, domain.AuditActionCreated, entries[0].Action)
+	require.Equal(t, "alice", entries[0].Actor)
+	require.Equal(t, "req-1", entries[0].RequestID)
+	require.Nil(t, entries[0].Before)
+	require.Equal(t, created.After, entries[0].After)
+	require.True(t, entries[0].CreatedAt.Equal(createdAt))
+
+	require.Equal(t, deletedID, entries[1].ID)
+	require.Equal(t, created.After, entries[1].Before)
+	require.Nil(t, entries[1].After)
+
+	entries, err = repo.ListByURL(ctx, 3)
+	require.NoError(t, err)
+	require.Empty(t, entries)
+}
+
+func testAuditStoredCopies(t *testing.T, repo AuditRepository) {
+	ctx := context.Background()
+
+	e := &domain.AuditEntry{
+		URLID:  1,
+		Action: domain.AuditActionCreated,
+		After: &domain.URLState{
+			Original: "https://example.com/1",
+			Alias:    "alias00001",
+			Tags:     []string{"promo"},
+		},
+	}
+
+	_, err := repo.Append(ctx, e)
+	require.NoError(t, err)
+
+	e.After.Tags[0] = "changed"
+
+	entries, err := repo.ListByURL(ctx, 1)
+	require.NoError(t, err)
+	require.Equal(t, []string{"promo"}, entries[0].After.Tags)
+
+	entries[0].After.Alias = "changed"
+
+	entries, err = repo.ListByURL(ctx, 1)
+	require.NoError(t, err)
+	require.Equal(t, "alias00001", entries[0].After.Alias)
+}
+
+func testParallelAppend(t *testing.T, repo AuditRepository) {
+	ctx := context.Background()
+
+	errs := make([]error, parallelism)
+
+	parallel(func(i int) {
+		_, errs[i] = repo.Append(ctx, &domain.AuditEntry{
+			URLID:     1,
+			Action:    domain.AuditActionUpdated,
+			RequestID: fmt.Sprintf("req-%d", i),
+		})
+	})
+
+	for _, err := range errs {
+		require.NoError(t, err)
+	}
+
+	entries, err := repo.ListByURL(ctx, 1)
+	require.NoError(t, err)
+	require.Len(t, entries, parallelism)
+
+	for i := 1; i < len(entries); i++ {
+		require.Greater(t, entries[i].ID, entries[i-1].ID)
+	}
+}
@@ -0,0 +1,1110 @@
+// Package repotest contains conformance tests every repository implementation must pass.
+package repotest
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/kodeyeen/shortify/internal/domain"
+	"github.com/kodeyeen/shortify/internal/persistence"
+	"github.com/stretchr/testify/require"
+)
+
+// URLRepository is the repository under test.
+type URLRepository interface {
+	Add(ctx context.Context, u *domain.URL) (int64, error)
+	FindByID(ctx context.Context, id int64) (*domain.URL, error)
+	FindByAlias(ctx context.Context, alias string) (*domain.URL, error)
+	FindByOriginal(ctx context.Context, original string) (*domain.URL, error)
+	Count(ctx context.Context) (int64, error)
+	List(ctx context.Context, filter persistence.URLFilter) ([]*domain.URL, error)
+	Update(ctx context.Context, u *domain.URL) error
+	UpdateOpenGraph(ctx context.Context, id int64, original string, og *domain.OpenGraph) error
+	IncrementClicks(ctx context.Context, alias string) (int64, error)
+	SoftDelete(ctx context.Context, alias string, deletedAt time.Time) (*domain.URL, error)
+	Restore(ctx context.Context, alias string) (*domain.URL, error)
+	Purge(ctx context.Context, deletedBefore time.Time) (int64, error)
+	Rename(ctx context.Context, alias string, u *domain.URL, retiredAt time.Time) (*domain.URL, error)
+	FindRetiredAlias(ctx context.Context, alias string) (*domain.RetiredAlias, error)
+	ListRetiredAliases(ctx context.Context, urlID int64) ([]*domain.RetiredAlias, error)
+}
+
+// TestURLRepository runs the conformance tests against empty repositories returned by newRepo.
+func TestURLRepository(t *testing.T, newRepo func(t *testing.T) URLRepository) {
+	tests := map[string]func(t *testing.T, repo URLRepository){
+		"Add and find":           testAddAndFind,
+		"Add duplicate original": testAddDuplicateOriginal,
+		"Add duplicate alias":    testAddDuplicateAlias,
+		"Find missing":           testFindMissing,
+		"Find by ID":             testFindByID,
+		"Find by original":       testFindByOriginal,
+		"Count":                  testCount,
+		"Stored copies":          testStoredCopies,
+		"List":                   testList,
+		"List after changes":     testListAfterChanges,
+		"Update":                 testUpdate,
+		"Update missing":         testUpdateMissing,
+		"Update conflict":        testUpdateConflict,
+		"Update open graph":      testUpdateOpenGraph,
+		"Increment clicks":       testIncrementClicks,
+		"Soft delete":            testSoftDelete,
+		"Restore":                testRestore,
+		"Purge":                  testPurge,
+		"Rename":                 testRename,
+		"Rename conflict":        testRenameConflict,
+		"Rename with update":     testRenameWithUpdate,
+		"Retired aliases":        testRetiredAliases,
+		"ID assignment":          testIDAssignment,
+		"Parallel add":           testParallelAdd,
+		"Parallel add conflicts": testParallelAddConflicts,
+		"Parallel clicks":        testParallelClicks,
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			test(t, newRepo(t))
+		})
+	}
+}
+
+// TestCaseInsensitiveURLRepository runs the tests of case-insensitive aliases against empty
+// repositories returned by newRepo.
+func TestCaseInsensitiveURLRepository(t *testing.T, newRepo func(t *testing.T) URLRepository) {
+	ctx := context.Background()
+
+	// Given
+	repo := newRepo(t)
+
+	id := add(t, repo, "https://example.com/1", "AbcDef")
+
+	// When
+	got, err := repo.FindByAlias(ctx, "aBCdEF")
+
+	// Then
+	require.NoError(t, err)
+	require.Equal(t, id, got.ID)
+	require.Equal(t, "AbcDef", got.Alias)
+
+	_, err = repo.Add(ctx, &domain.URL{Original: "https://example.com/2", Alias: "ABCDEF"})
+	require.ErrorIs(t, err, persistence.ErrDuplicateAlias)
+
+	clicks, err := repo.IncrementClicks(ctx, "abcdef")
+	require.NoError(t, err)
+	require.Equal(t, int64(1), clicks)
+
+	got.Title = "Updated"
+	got.Alias = "ABCDEF"
+	require.NoError(t, repo.Update(ctx, got))
+
+	require.NoError(t, repo.UpdateOpenGraph(ctx, got.ID, got.Original, &domain.OpenGraph{Title: "OG", FetchedAt: time.Now()}))
+
+	got, err = repo.FindByAlias(ctx, "AbcDef")
+	require.NoError(t, err)
+	require.Equal(t, "AbcDef", got.Alias)
+	require.Equal(t, "Updated", got.Title)
+	require.Equal(t, "OG", got.OpenGraph.Title)
+
+	deleted, err := repo.SoftDelete(ctx, "ABCdef", time.Now())
+	require.NoError(t, err)
+	require.Equal(t, "AbcDef", deleted.Alias)
+
+	_, err = repo.Purge(ctx, deleted.DeletedAt.Add(time.Second))
+	require.NoError(t, err)
+
+	_, err = repo.FindByAlias(ctx, "AbcDef")
+	require.ErrorIs(t, err, persistence.ErrURLNotFound)
+
+	id = add(t, repo, "https://example.com/3", "GhiJkl")
+
+	renamed, err := rename(t, repo, "ghijkl", "MnoPqr", time.Now())
+	require.NoError(t, err)
+	require.Equal(t, "MnoPqr", renamed.Alias)
+
+	retired, err := repo.FindRetiredAlias(ctx, "GHIJKL")
+	require.NoError(t, err)
+	require.Equal(t, "GhiJkl", retired.Alias)
+	require.Equal(t, id, retired.URLID)
+
+	add(t, repo, "https://example.com/4", "StuVwx")
+
+	_, err = rename(t, repo, "mnopqr", "stuvwx", time.Now())
+	require.ErrorIs(t, err, persistence.ErrDuplicateAlias)
+}
+
+func testAddAndFind(t *testing.T, repo URLRepository) {
+	ctx := context.Background()
+
+	// Given
+	createdAt := time.Date(2025, 3, 1, 12, 30, 0, 0, time.UTC)
+	given := &domain.URL{
+		Original:    "https://example.com/long",
+		Alias:       "alias00001",
+		QueryMode:   domain.QueryModeMerge,
+		Preview:     true,
+		Title:       "Title",
+		Description: "Description",
+		Tags:        []string{"promo", "spring"},
+		Metadata:    map[string]string{"campaign": "spring"},
+		CreatedAt:   createdAt,
+	}
+
+	// When
+	id, err := repo.Add(ctx, given)
+
+	// Then
+	require.NoError(t, err)
+	require.Positive(t, id)
+
+	got, err := repo.FindByAlias(ctx, "alias00001")
+	require.NoError(t, err)
+	require.Equal(t, id, got.ID)
+	require.Equal(t, "https://example.com/long", got.Original)
+	require.Equal(t, "alias00001", got.Alias)
+	require.Equal(t, domain.QueryModeMerge, got.QueryMode)
+	require.True(t, got.Preview)
+	require.Equal(t, "Title", got.Title)
+	require.Equal(t, "Description", got.Description)
+	require.Equal(t, []string{"promo", "spring"}, got.Tags)
+	require.Equal(t, map[string]string{"campaign": "spring"}, got.Metadata)
+	require.Zero(t, got.Clicks)
+	require.True(t, createdAt.Equal(got.CreatedAt), "created at %s, expected %s", got.CreatedAt, createdAt)
+	require.Nil(t, got.OpenGraph)
+}
+
+func testAddDuplicateOriginal(t *testing.T, repo URLRepository) {
+	ctx := context.Background()
+
+	// Given
+	add(t, repo, "https://example.com/1", "alias00001")
+
+	// When
+	_, err := repo.Add(ctx, &domain.URL{Original: "https://example.com/1", Alias: "alias00002"})
+
+	// Then
+	require.ErrorIs(t, err, persistence.ErrURLAlreadyExists)
+
+	_, err = repo.FindByAlias(ctx, "alias00002")
+	require.ErrorIs(t, err, persistence.ErrURLNotFound)
+}
+
+func testAddDuplicateAlias(t *testing.T, repo URLRepository) {
+	ctx := context.Background()
+
+	// Given
+	add(t, repo, "https://example.com/1", "alias00001")
+
+	// When
+	_, err := repo.Add(ctx, &domain.URL{Original: "https://example.com/2", Alias: "alias00001"})
+
+	// Then
+	require.ErrorIs(t, err, persistence.ErrDuplicateAlias)
+
+	got, err := repo.FindByAlias(ctx, "alias00001")
+	require.NoError(t, err)
+	require.Equal(t, "https://example.com/1", got.Original)
+}
+
+func testFindMissing(t *testing.T, repo URLRepository) {
+	// When
+	_, err := repo.FindByAlias(context.Background(), "missing")
+
+	// Then
+	require.ErrorIs(t, err, persistence.ErrURLNotFound)
+}
+
+func testFindByID(t *testing.T, repo URLRepository) {
+	ctx := context.Background()
+
+	// Given
+	first := add(t, repo, "https://example.com/1", "alias00001")
+	second := add(t, repo, "https://example.com/2", "alias00002")
+
+	// When
+	got, err := repo.FindByID(ctx, second)
+
+	// Then
+	require.NoError(t, err)
+	require.Equal(t, second, got.ID)
+	require.Equal(t, "alias00002", got.Alias)
+	require.Equal(t, "https://example.com/2", got.Original)
+
+	purge(t, repo, "alias00001")
+
+	_, err = repo.FindByID(ctx, first)
+	require.ErrorIs(t, err, persistence.ErrURLNotFound)
+
+	_, err = repo.FindByID(ctx, second+100)
+	require.ErrorIs(t, err, persistence.ErrURLNotFound)
+}
+
+func testFindByOriginal(t *testing.T, repo URLRepository) {
+	ctx := context.Background()
+
+	// Given
+	add(t, repo, "https://example.com/1", "alias00001")
+	id := add(t, repo, "https://example.com/2", "alias00002")
+
+	// When
+	got, err := repo.FindByOriginal(ctx, "https://example.com/2")
+
+	// Then
+	require.NoError(t, err)
+	require.Equal(t, id, got.ID)
+	require.Equal(t, "alias00002", got.Alias)
+
+	purge(t, repo, "alias00002")
+
+	_, err = repo.FindByOriginal(ctx, "https://example.com/2")
+	require.ErrorIs(t, err, persistence.ErrURLNotFound)
+
+	_, err = repo.FindByOriginal(ctx, "https://example.com/3")
+	require.ErrorIs(t, err, persistence.ErrURLNotFound)
+}
+
+func testCount(t *testing.T, repo URLRepository) {
+	ctx := context.Background()
+
+	// Given
+	count, err := repo.Count(ctx)
+	require.NoError(t, err)
+	require.Zero(t, count)
+
+	add(t, repo, "https://example.com/1", "alias00001")
+	add(t, repo, "https://example.com/2", "alias00002")
+	add(t, repo, "https://example.com/3", "alias00003")
+
+	purge(t, repo, "alias00002")
+
+	// When
+	count, err = repo.Count(ctx)
+
+	// Then
+	require.NoError(t, err)
+	require.Equal(t, int64(2), count)
+}
+
+func testStoredCopies(t *testing.T, repo URLRepository) {
+	ctx := context.Background()
+
+	// Given
+	given := &domain.URL{
+		Original: "https://example.com/1",
+		Alias:    "alias00001",
+		Tags:     []string{"promo"},
+		Metadata: map[string]string{"team": "a"},
+	}
+
+	id, err := repo.Add(ctx, given)
+	require.NoError(t, err)
+
+	err = repo.UpdateOpenGraph(ctx, id, given.Original, &domain.OpenGraph{Title: "OG", FetchedAt: time.Now()})
+	require.NoError(t, err)
+
+	// When
+	given.Original = "https://example.com/changed"
+	given.Tags[0] = "changed"
+	given.Metadata["team"] = "changed"
+
+	found, err := repo.FindByAlias(ctx, "alias00001")
+	require.NoError(t, err)
+
+	found.Title = "changed"
+	found.Tags[0] = "changed"
+	found.Metadata["team"] = "changed"
+	found.OpenGraph.Title = "changed"
+
+	listed, err := repo.List(ctx, persistence.URLFilter{})
+	require.NoError(t, err)
+	require.Len(t, listed, 1)
+
+	listed[0].Tags[0] = "changed"
+
+	// Then
+	got, err := repo.FindByAlias(ctx, "alias00001")
+	require.NoError(t, err)
+	require.Equal(t, "https://example.com/1", got.Original)
+	require.Empty(t, got.Title)
+	require.Equal(t, []string{"promo"}, got.Tags)
+	require.Equal(t, map[string]string{"team": "a"}, got.Metadata)
+	require.Equal(t, "OG", got.OpenGraph.Title)
+}
+
+func testListAfterChanges(t *testing.T, repo URLRepository) {
+	ctx := context.Background()
+
+	// Given
+	first := add(t, repo, "https://example.com/1", "alias00001")
+	second := add(t, repo, "https://example.com/2", "alias00002")
+	add(t, repo, "https://example.com/3", "alias00003")
+	fourth := add(t, repo, "https://example.com/4", "alias00004")
+
+	_, err := rename(t, repo, "alias00001", "alias00005", time.Now())
+	require.NoError(t, err)
+
+	purge(t, repo, "alias00003")
+
+	fifth := add(t, repo, "https://example.com/5", "alias00006")
+
+	// When
+	urls, err := repo.List(ctx, persistence.URLFilter{})
+
+	// Then
+	require.NoError(t, err)
+
+	ids := make([]int64, 0, len(urls))
+	for _, u := range urls {
+		ids = append(ids, u.ID)
+	}
+
+	require.Equal(t, []int64{first, second, fourth, fifth}, ids)
+
+	urls, err = repo.List(ctx, persistence.URLFilter{AfterID: second, Limit: 1})
+	require.NoError(t, err)
+	require.Len(t, urls, 1)
+	require.Equal(t, fourth, urls[0].ID)
+}
+
+func testList(t *testing.T, repo URLRepository) {
+	ctx := context.Background()
+
+	// Given
+	urls := []*domain.URL{
+		{Original: "https://example.com/1", Alias: "alias00001", Tags: []string{"promo"}, Metadata: map[string]string{"team": "a"}},
+		{Original: "https://example.com/2", Alias: "alias00002", Tags: []string{"promo", "spring"}, Metadata: map[string]string{"team": "b"}},
+		{Original: "https://example.com/3", Alias: "alias00003", Metadata: map[string]string{"team": "a", "env": "prod"}},
+		{Original: "https://example.com/4", Alias: "alias00004", Tags: []string{"spring"}},
+	}
+
+	ids := make([]int64, 0, len(urls))
+
+	for _, u := range urls {
+		id, err := repo.Add(ctx, u)
+		require.NoError(t, err)
+
+		ids = append(ids, id)
+	}
+
+	testCases := map[string]struct {
+		filter   persistence.URLFilter
+		expected []string
+	}{
+		"All": {
+			expected: []string{"alias00001", "alias00002", "alias00003", "alias00004"},
+		},
+		"Tag": {
+			filter:   persistence.URLFilter{Tag: "promo"},
+			expected: []string{"alias00001", "alias00002"},
+		},
+		"Metadata": {
+			filter:   persistence.URLFilter{Metadata: map[string]string{"team": "a"}},
+			expected: []string{"alias00001", "alias00003"},
+		},
+		"Several metadata keys": {
+			filter:   persistence.URLFilter{Metadata: map[string]string{"team": "a", "env": "prod"}},
+			expected: []string{"alias00003"},
+		},
+		"Tag and metadata": {
+			filter:   persistence.URLFilter{Tag: "spring", Metadata: map[string]string{"team": "b"}},
+			expected: []string{"alias00002"},
+		},
+		"Unknown tag": {
+			filter:   persistence.URLFilter{Tag: "unknown"},
+			expected: []string{},
+		},
+		"Limit": {
+			filter:   persistence.URLFilter{Limit: 2},
+			expected: []string{"alias00001", "alias00002"},
+		},
+		"Offset": {
+			filter:   persistence.URLFilter{Offset: 3},
+			expected: []string{"alias00004"},
+		},
+		"Limit and offset": {
+			filter:   persistence.URLFilter{Limit: 2, Offset: 1},
+			expected: []string{"alias00002", "alias00003"},
+		},
+		"Offset past the end": {
+			filter:   persistence.URLFilter{Offset: 10},
+			expected: []string{},
+		},
+		"After ID": {
+			filter:   persistence.URLFilter{AfterID: ids[1]},
+			expected: []string{"alias00003", "alias00004"},
+		},
+		"After ID and limit": {
+			filter:   persistence.URLFilter{AfterID: ids[0], Limit: 2},
+			expected: []string{"alias00002", "alias00003"},
+		},
+		"After ID and tag": {
+			filter:   persistence.URLFilter{AfterID: ids[0], Tag: "spring"},
+			expected: []string{"alias00002", "alias00004"},
+		},
+		"After the last ID": {
+			filter:   persistence.URLFilter{AfterID: ids[3]},
+			expected: []string{},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			// When
+			got, err := repo.List(ctx, tc.filter)
+
+			// Then
+			require.NoError(t, err)
+			require.NotNil(t, got)
+
+			aliases := make([]string, 0, len(got))
+			for _, u := range got {
+				aliases = append(aliases, u.Alias)
+			}
+
+			require.Equal(t, tc.expected, aliases)
+		})
+	}
+}
+
+func testUpdate(t *testing.T, repo URLRepository) {
+	ctx := context.Background()
+
+	// Given
+	id := add(t, repo, "https://example.com/1", "alias00001")
+
+	err := repo.UpdateOpenGraph(ctx, id, "https://example.com/1", &domain.OpenGraph{Title: "OG", FetchedAt: time.Now()})
+	require.NoError(t, err)
+
+	// When
+	err = repo.Update(ctx, &domain.URL{
+		Original:    "https://example.com/2",
+		Alias:       "alias00001",
+		QueryMode:   domain.QueryModeOverride,
+		Preview:     true,
+		Title:       "Title",
+		Description: "Description",
+		Tags:        []string{"promo"},
+		Metadata:    map[string]string{"team": "a"},
+	})
+
+	// Then
+	require.NoError(t, err)
+
+	got, err := repo.FindByAlias(ctx, "alias00001")
+	require.NoError(t, err)
+	require.Equal(t, "https://example.com/2", got.Original)
+	require.Equal(t, domain.QueryModeOverride, got.QueryMode)
+	require.True(t, got.Preview)
+	require.Equal(t, "Title", got.Title)
+	require.Equal(t, "Description", got.Description)
+	require.Equal(t, []string{"promo"}, got.Tags)
+	require.Equal(t, map[string]string{"team": "a"}, got.Metadata)
+	require.Nil(t, got.OpenGraph, "open graph of the previous original URL must be reset")
+
+	tagged, err := repo.List(ctx, persistence.URLFilter{Tag: "promo"})
+	require.NoError(t, err)
+	require.Len(t, tagged, 1)
+
+	// the previous original URL can be used again
+	add(t, repo, "https://example.com/1", "alias00002")
+}
+
+func testUpdateMissing(t *testing.T, repo URLRepository) {
+	// When
+	err := repo.Update(context.Background(), &domain.URL{Original: "https://example.com/1", Alias: "missing"})
+
+	// Then
+	require.ErrorIs(t, err, persistence.ErrURLNotFound)
+}
+
+func testUpdateConflict(t *testing.T, repo URLRepository) {
+	ctx := context.Background()
+
+	// Given
+	add(t, repo, "https://example.com/1", "alias00001")
+	add(t, repo, "https://example.com/2", "alias00002")
+
+	// When
+	err := repo.Update(ctx, &domain.URL{Original: "https://example.com/2", Alias: "alias00001"})
+
+	// Then
+	require.ErrorIs(t, err, persistence.ErrURLAlreadyExists)
+
+	got, err := repo.FindByAlias(ctx, "alias00001")
+	require.NoError(t, err)
+	require.Equal(t, "https://example.com/1", got.Original)
+}
+
+func testUpdateOpenGraph(t *testing.T, repo URLRepository) {
+	ctx := context.Background()
+
+	// Given
+	id := add(t, repo, "https://example.com/1", "alias00001")
+
+	fetchedAt := time.Date(2025, 3, 1, 12, 30, 0, 0, time.UTC)
+
+	// When
+	err := repo.UpdateOpenGraph(ctx, id, "https://example.com/1", &domain.OpenGraph{
+		Title:       "OG title",
+		Description: "OG description",
+		Image:       "https://example.com/image.png",
+		FetchedAt:   fetchedAt,
+	})
+
+	// Then
+	require.NoError(t, err)
+
+	got, err := repo.FindByAlias(ctx, "alias00001")
+	require.NoError(t, err)
+	require.NotNil(t, got.OpenGraph)
+	require.Equal(t, "OG title", got.OpenGraph.Title)
+	require.Equal(t, "OG description", got.OpenGraph.Description)
+	require.Equal(t, "https://example.com/image.png", got.OpenGraph.Image)
+	require.True(t, fetchedAt.Equal(got.OpenGraph.FetchedAt))
+
+	err = repo.UpdateOpenGraph(ctx, id+1000, "https://example.com/1", &domain.OpenGraph{FetchedAt: fetchedAt})
+	require.ErrorIs(t, err, persistence.ErrURLNotFound)
+
+	err = repo.UpdateOpenGraph(ctx, id, "https://example.com/stale", &domain.OpenGraph{Title: "Stale", FetchedAt: fetchedAt})
+	require.ErrorIs(t, err, persistence.ErrURLNotFound)
+
+	got, err = repo.FindByAlias(ctx, "alias00001")
+	require.NoError(t, err)
+	require.Equal(t, "OG title", got.OpenGraph.Title)
+}
+
+func testIncrementClicks(t *testing.T, repo URLRepository) {
+	ctx := context.Background()
+
+	// Given
+	add(t, repo, "https://example.com/1", "alias00001")
+
+	// When
+	clicks, err := repo.IncrementClicks(ctx, "alias00001")
+	require.NoError(t, err)
+	require.Equal(t, int64(1), clicks)
+
+	clicks, err = repo.IncrementClicks(ctx, "alias00001")
+
+	// Then
+	require.NoError(t, err)
+	require.Equal(t, int64(2), clicks)
+
+	got, err := repo.FindByAlias(ctx, "alias00001")
+	require.NoError(t, err)
+	require.Equal(t, int64(2), got.Clicks)
+
+	_, err = repo.IncrementClicks(ctx, "missing")
+	require.ErrorIs(t, err, persistence.ErrURLNotFound)
+}
+
+func testSoftDelete(t *testing.T, repo URLRepository) {
+	ctx := context.Background()
+
+	// Given
+	id := add(t, repo, "https://example.com/1", "alias00001")
+	add(t, repo, "https://example.com/2", "alias00002")
+
+	deletedAt := time.Date(2025, time.March, 1, 12, 0, 0, 0, time.UTC)
+
+	// When
+	deleted, err := repo.SoftDelete(ctx, "alias00001", deletedAt)
+
+	// Then
+	require.NoError(t, err)
+	require.Equal(t, id, deleted.ID)
+	require.NotNil(t, deleted.DeletedAt)
+	require.True(t, deletedAt.Equal(*deleted.DeletedAt))
+
+	got, err := repo.FindByAlias(ctx, "alias00001")
+	require.NoError(t, err)
+	require.NotNil(t, got.DeletedAt)
+	require.True(t, deletedAt.Equal(*got.DeletedAt))
+
+	got, err = repo.FindByID(ctx, id)
+	require.NoError(t, err)
+	require.NotNil(t, got.DeletedAt)
+
+	// deleted URLs aren't listed but still count as their aliases are taken
+	list, err := repo.List(ctx, persistence.URLFilter{})
+	require.NoError(t, err)
+	require.Len(t, list, 1)
+	require.Equal(t, "alias00002", list[0].Alias)
+
+	count, err := repo.Count(ctx)
+	require.NoError(t, err)
+	require.Equal(t, int64(2), count)
+
+	_, err = repo.Add(ctx, &domain.URL{Original: "https://example.com/3", Alias: "alias00001"})
+	require.ErrorIs(t, err, persistence.ErrDuplicateAlias)
+
+	_, err = repo.Add(ctx, &domain.URL{Original: "https://example.com/1", Alias: "alias00003"})
+	require.ErrorIs(t, err, persistence.ErrURLAlreadyExists)
+
+	_, err = repo.SoftDelete(ctx, "alias00001", deletedAt)
+	require.ErrorIs(t, err, persistence.ErrURLNotFound)
+
+	_, err = repo.SoftDelete(ctx, "missing001", deletedAt)
+	require.ErrorIs(t, err, persistence.ErrURLNotFound)
+}
+
+func testRestore(t *testing.T, repo URLRepository) {
+	ctx := context.Background()
+
+	// Given
+	id := add(t, repo, "https://example.com/1", "alias00001")
+
+	_, err := repo.SoftDelete(ctx, "alias00001", time.Now())
+	require.NoError(t, err)
+
+	// When
+	restored, err := repo.Restore(ctx, "alias00001")
+
+	// Then
+	require.NoError(t, err)
+	require.Equal(t, id, restored.ID)
+	require.Nil(t, restored.DeletedAt)
+
+	got, err := repo.FindByAlias(ctx, "alias00001")
+	require.NoError(t, err)
+	require.Nil(t, got.DeletedAt)
+
+	list, err := repo.List(ctx, persistence.URLFilter{})
+	require.NoError(t, err)
+	require.Len(t, list, 1)
+
+	// only deleted URLs are restored
+	_, err = repo.Restore(ctx, "alias00001")
+	require.ErrorIs(t, err, persistence.ErrURLNotFound)
+
+	_, err = repo.Restore(ctx, "missing001")
+	require.ErrorIs(t, err, persistence.ErrURLNotFound)
+}
+
+func testPurge(t *testing.T, repo URLRepository) {
+	ctx := context.Background()
+
+	// Given
+	at := time.Date(2025, time.March, 1, 12, 0, 0, 0, time.UTC)
+
+	expired := add(t, repo, "https://example.com/1", "alias00001")
+	add(t, repo, "https://example.com/2", "alias00002")
+	add(t, repo, "https://example.com/3", "alias00003")
+
+	_, err := rename(t, repo, "alias00001", "alias00004", at.Add(-time.Hour))
+	require.NoError(t, err)
+
+	_, err = repo.SoftDelete(ctx, "alias00004", at.Add(-time.Minute))
+	require.NoError(t, err)
+
+	_, err = repo.SoftDelete(ctx, "alias00002", at)
+	require.NoError(t, err)
+
+	// When
+	purged, err := repo.Purge(ctx, at)
+
+	// Then
+	require.NoError(t, err)
+	require.Equal(t, int64(1), purged)
+
+	_, err = repo.FindByAlias(ctx, "alias00004")
+	require.ErrorIs(t, err, persistence.ErrURLNotFound)
+
+	_, err = repo.FindByID(ctx, expired)
+	require.ErrorIs(t, err, persistence.ErrURLNotFound)
+
+	// the retired aliases of purged URLs are forgotten
+	_, err = repo.FindRetiredAlias(ctx, "alias00001")
+	require.ErrorIs(t, err, persistence.ErrAliasNotFound)
+
+	got, err := repo.FindByAlias(ctx, "alias00002")
+	require.NoError(t, err)
+	require.NotNil(t, got.DeletedAt)
+
+	got, err = repo.FindByAlias(ctx, "alias00003")
+	require.NoError(t, err)
+	require.Nil(t, got.DeletedAt)
+
+	// the alias and the original URL are free again
+	add(t, repo, "https://example.com/1", "alias00004")
+
+	purged, err = repo.Purge(ctx, at)
+	require.NoError(t, err)
+	require.Zero(t, purged)
+}
+
+func testRename(t *testing.T, repo URLRepository) {
+	ctx := context.Background()
+
+	// Given
+	id := add(t, repo, "https://example.com/1", "alias00001")
+	retiredAt := time.Date(2025, time.March, 1, 12, 0, 0, 0, time.UTC)
+
+	// When
+	renamed, err := rename(t, repo, "alias00001", "renamed001", retiredAt)
+
+	// Then
+	require.NoError(t, err)
+	require.Equal(t, id, renamed.ID)
+	require.Equal(t, "renamed001", renamed.Alias)
+	require.Equal(t, "https://example.com/1", renamed.Original)
+
+	got, err := repo.FindByAlias(ctx, "renamed001")
+	require.NoError(t, err)
+	require.Equal(t, id, got.ID)
+
+	_, err = repo.FindByAlias(ctx, "alias00001")
+	require.ErrorIs(t, err, persistence.ErrURLNotFound)
+
+	retired, err := repo.FindRetiredAlias(ctx, "alias00001")
+	require.NoError(t, err)
+	require.Equal(t, "alias00001", retired.Alias)
+	require.Equal(t, id, retired.URLID)
+	require.True(t, retiredAt.Equal(retired.RetiredAt))
+
+	_, err = repo.FindRetiredAlias(ctx, "renamed001")
+	require.ErrorIs(t, err, persistence.ErrAliasNotFound)
+
+	// the retired alias is free for other URLs
+	add(t, repo, "https://example.com/2", "alias00001")
+
+	_, err = repo.Rename(ctx, "missing001", &domain.URL{Original: "https://example.com/3", Alias: "renamed002"}, retiredAt)
+	require.ErrorIs(t, err, persistence.ErrURLNotFound)
+}
+
+func testRenameConflict(t *testing.T, repo URLRepository) {
+	ctx := context.Background()
+
+	// Given
+	add(t, repo, "https://example.com/1", "alias00001")
+	add(t, repo, "https://example.com/2", "alias00002")
+
+	// When
+	_, err := rename(t, repo, "alias00001", "alias00002", time.Now())
+
+	// Then
+	require.ErrorIs(t, err, persistence.ErrDuplicateAlias)
+
+	got, err := repo.FindByAlias(ctx, "alias00001")
+	require.NoError(t, err)
+	require.Equal(t, "https://example.com/1", got.Original)
+
+	_, err = repo.FindRetiredAlias(ctx, "alias00001")
+	require.ErrorIs(t, err, persistence.ErrAliasNotFound)
+}
+
+func testRenameWithUpdate(t *testing.T, repo URLRepository) {
+	ctx := context.Background()
+
+	// Given
+	id := add(t, repo, "https://example.com/1", "alias00001")
+	add(t, repo, "https://example.com/2", "alias00002")
+
+	require.NoError(t, repo.UpdateOpenGraph(ctx, id, "https://example.com/1", &domain.OpenGraph{Title: "OG", FetchedAt: time.Now()}))
+
+	// When
+	renamed, err := repo.Rename(ctx, "alias00001", &domain.URL{
+		Original:  "https://example.com/3",
+		Alias:     "renamed001",
+		QueryMode: domain.QueryModeIgnore,
+		Title:     "Renamed",
+		Tags:      []string{"spring"},
+	}, time.Now())
+
+	// Then
+	require.NoError(t, err)
+	require.Equal(t, id, renamed.ID)
+	require.Equal(t, "renamed001", renamed.Alias)
+	require.Equal(t, "https://example.com/3", renamed.Original)
+	require.Equal(t, "Renamed", renamed.Title)
+	require.Equal(t, []string{"spring"}, renamed.Tags)
+	require.Nil(t, renamed.OpenGraph)
+
+	got, err := repo.FindByOriginal(ctx, "https://example.com/3")
+	require.NoError(t, err)
+	require.Equal(t, "renamed001", got.Alias)
+
+	_, err = repo.FindByOriginal(ctx, "https://example.com/1")
+	require.ErrorIs(t, err, persistence.ErrURLNotFound)
+
+	// a conflicting update leaves both the alias and the fields as they were
+	_, err = repo.Rename(ctx, "renamed001", &domain.URL{
+		Original:  "https://example.com/2",
+		Alias:     "renamed002",
+		QueryMode: domain.QueryModeIgnore,
+	}, time.Now())
+	require.ErrorIs(t, err, persistence.ErrURLAlreadyExists)
+
+	got, err = repo.FindByAlias(ctx, "renamed001")
+	require.NoError(t, err)
+	require.Equal(t, "https://example.com/3", got.Original)
+	require.Equal(t, "Renamed", got.Title)
+
+	_, err = repo.FindByAlias(ctx, "renamed002")
+	require.ErrorIs(t, err, persistence.ErrURLNotFound)
+
+	_, err = repo.FindRetiredAlias(ctx, "renamed001")
+	require.ErrorIs(t, err, persistence.ErrAliasNotFound)
+}
+
+func testRetiredAliases(t *testing.T, repo URLRepository) {
+	ctx := context.Background()
+
+	// Given
+	first := add(t, repo, "https://example.com/1", "alias00001")
+	second := add(t, repo, "https://example.com/2", "alias00002")
+
+	at := time.Date(2025, time.March, 1, 12, 0, 0, 0, time.UTC)
+
+	renameAlias := func(alias, newAlias string, retiredAt time.Time) {
+		t.Helper()
+
+		_, err := rename(t, repo, alias, newAlias, retiredAt)
+		require.NoError(t, err)
+	}
+
+	renameAlias("alias00001", "alias00003", at)
+	renameAlias("alias00003", "alias00004", at.Add(time.Hour))
+	renameAlias("alias00004", "alias00001", at.Add(2*time.Hour))
+	renameAlias("alias00001", "alias00005", at.Add(3*time.Hour))
+
+	// When
+	retired, err := repo.ListRetiredAliases(ctx, first)
+
+	// Then
+	require.NoError(t, err)
+	require.Len(t, retired, 3)
+
+	// newest first, retiring the alias again replaces the previous retirement
+	require.Equal(t, "alias00001", retired[0].Alias)
+	require.True(t, at.Add(3*time.Hour).Equal(retired[0].RetiredAt))
+	require.Equal(t, "alias00004", retired[1].Alias)
+	require.Equal(t, "alias00003", retired[2].Alias)
+
+	for _, r := range retired {
+		require.Equal(t, first, r.URLID)
+	}
+
+	retired, err = repo.ListRetiredAliases(ctx, second)
+	require.NoError(t, err)
+	require.Empty(t, retired)
+
+	// the latest retirement wins when several URLs retired the alias
+	renameAlias("alias00002", "alias00001", at.Add(4*time.Hour))
+	renameAlias("alias00001", "alias00006", at.Add(5*time.Hour))
+
+	got, err := repo.FindRetiredAlias(ctx, "alias00001")
+	require.NoError(t, err)
+	require.Equal(t, second, got.URLID)
+
+	// purging the URL forgets its retired aliases
+	purge(t, repo, "alias00006")
+
+	got, err = repo.FindRetiredAlias(ctx, "alias00001")
+	require.NoError(t, err)
+	require.Equal(t, first, got.URLID)
+
+	retired, err = repo.ListRetiredAliases(ctx, second)
+	require.NoError(t, err)
+	require.Empty(t, retired)
+}
+
+func testIDAssignment(t *testing.T, repo URLRepository) {
+	ctx := context.Background()
+
+	// When
+	first := add(t, repo, "https://example.com/1", "alias00001")
+	second := add(t, repo, "https://example.com/2", "alias00002")
+
+	// Then
+	require.Positive(t, first)
+	require.Greater(t, second, first)
+
+	for alias, id := range map[string]int64{"alias00001": first, "alias00002": second} {
+		got, err := repo.FindByAlias(ctx, alias)
+		require.NoError(t, err)
+		require.Equal(t, id, got.ID)
+	}
+
+	// failed adds don't take the next ID away from successful ones
+	_, err := repo.Add(ctx, &domain.URL{Original: "https://example.com/1", Alias: "alias00003"})
+	require.ErrorIs(t, err, persistence.ErrURLAlreadyExists)
+
+	// IDs are never reused, even after purging the newest URL
+	purge(t, repo, "alias00002")
+
+	third := add(t, repo, "https://example.com/3", "alias00003")
+	require.Greater(t, third, second)
+
+	// updates keep the ID
+	err = repo.Update(ctx, &domain.URL{Original: "https://example.com/updated", Alias: "alias00001"})
+	require.NoError(t, err)
+
+	got, err := repo.FindByAlias(ctx, "alias00001")
+	require.NoError(t, err)
+	require.Equal(t, first, got.ID)
+}
+
+const parallelism = 32
+
+func testParallelAdd(t *testing.T, repo URLRepository) {
+	ctx := context.Background()
+
+	// When
+	ids := make([]int64, parallelism)
+	errs := make([]error, parallelism)
+
+	parallel(func(i int) {
+		ids[i], errs[i] = repo.Add(ctx, &domain.URL{
+			Original: fmt.Sprintf("https://example.com/%d", i),
+			Alias:    fmt.Sprintf("alias%05d", i),
+		})
+	})
+
+	// Then
+	seen := map[int64]bool{}
+
+	for i := range parallelism {
+		require.NoError(t, errs[i])
+		require.False(t, seen[ids[i]], "ID %d assigned twice", ids[i])
+
+		seen[ids[i]] = true
+	}
+
+	urls, err := repo.List(ctx, persistence.URLFilter{})
+	require.NoError(t, err)
+	require.Len(t, urls, parallelism)
+
+	for i := 1; i < len(urls); i++ {
+		require.Greater(t, urls[i].ID, urls[i-1].ID)
+	}
+}
+
+func testParallelAddConflicts(t *testing.T, repo URLRepository) {
+	ctx := context.Background()
+
+	testCases := map[string]struct {
+		url      func(i int) *domain.URL
+		expected error
+	}{
+		"Same alias": {
+			url: func(i int) *domain.URL {
+				return &domain.URL{Original: fmt.Sprintf("https://example.com/alias/%d", i), Alias: "alias00001"}
+			},
+			expected: persistence.ErrDuplicateAlias,
+		},
+		"Same original": {
+			url: func(i int) *domain.URL {
+				return &domain.URL{Original: "https://example.com/original", Alias: fmt.Sprintf("original%03d", i)}
+			},
+			expected: persistence.ErrURLAlreadyExists,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			// When
+			errs := make([]error, parallelism)
+
+			parallel(func(i int) {
+				_, errs[i] = repo.Add(ctx, tc.url(i))
+			})
+
+			// Then
+			succeeded := 0
+
+			for _, err := range errs {
+				if err == nil {
+					succeeded++
+					continue
+				}
+
+				require.ErrorIs(t, err, tc.expected)
+			}
+
+			require.Equal(t, 1, succeeded)
+		})
+	}
+}
+
+func testParallelClicks(t *testing.T, repo URLRepository) {
+	ctx := context.Background()
+
+	// Given
+	add(t, repo, "https://example.com/1", "alias00001")
+
+	// When
+	errs := make([]error, parallelism)
+
+	parallel(func(i int) {
+		_, errs[i] = repo.IncrementClicks(ctx, "alias00001")
+	})
+
+	// Then
+	for _, err := range errs {
+		require.NoError(t, err)
+	}
+
+	got, err := repo.FindByAlias(ctx, "alias00001")
+	require.NoError(t, err)
+	require.Equal(t, int64(parallelism), got.Clicks)
+}
+
+// parallel runs fn for every index up to parallelism at once.
+func parallel(fn func(i int)) {
+	var (
+		wg    sync.WaitGroup
+		start = make(chan struct{})
+	)
+
+	for i := range parallelism {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			<-start
+			fn(i)
+		}()
+	}
+
+	close(start)
+	wg.Wait()
+}
+
+func add(t *testing.T, repo URLRepository, original, alias string) int64 {
+	t.Helper()
+
+	id, err := repo.Add(context.Background(), &domain.URL{Original: original, Alias: alias, QueryMode: domain.QueryModeIgnore})
+	require.NoError(t, err)
+
+	return id
+}
+
+// purge deletes the URL with alias for good moving it to the trash and emptying the trash.
+func purge(t *testing.T, repo URLRepository, alias string) {
+	t.Helper()
+
+	deletedAt := time.Now()
+
+	_, err := repo.SoftDelete(context.Background(), alias, deletedAt)
+	require.NoError(t, err)
+
+	_, err = repo.Purge(context.Background(), deletedAt.Add(time.Second))
+	require.NoError(t, err)
+}
+
+// rename changes only the alias of the URL with alias.
+func rename(t *testing.T, repo URLRepository, alias, newAlias string, retiredAt time.Time) (*domain.URL, error) {
+	t.Helper()
+
+	u, err := repo.FindByAlias(context.Background(), alias)
+	require.NoError(t, err)
+
+	u.Alias = newAlias
+
+	return repo.Rename(context.Background(), alias, u, retiredAt)
+}
@@ -0,0 +1,135 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	"github.com/kodeyeen/shortify/internal/domain"
+)
+
+// AuditRepository stores the audit entries in the url_audit table.
+// The URL states are kept as JSON.
+type AuditRepository struct {
+	db *sql.DB
+}
+
+func NewAuditRepository(db *sql.DB) *AuditRepository {
+	return &AuditRepository{
+		db: db,
+	}
+}
+
+func (r *AuditRepository) Append(ctx context.Context, e *domain.AuditEntry) (int64, error) {
+	before, err := encodeState(e.Before)
+	if err != nil {
+		return 0, err
+	}
+
+	after, err := encodeState(e.After)
+	if err != nil {
+		return 0, err
+	}
+
+	query := `
+		INSERT INTO url_audit (url_id, action, actor, request_id, before, after, created_at)
+		VALUES (@url_id, @action, @actor, @request_id, @before, @after, @created_at)`
+	args := []any{
+		sql.Named("url_id", e.URLID),
+		sql.Named("action", e.Action),
+		sql.Named("actor", e.Actor),
+		sql.Named("request_id", e.RequestID),
+		sql.Named("before", before),
+		sql.Named("after", after),
+		sql.Named("created_at", e.CreatedAt),
+	}
+
+	res, err := r.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return 0, fmt.Errorf("failed to append audit entry: %w", err)
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get audit entry id: %w", err)
+	}
+
+	return id, nil
+}
+
+// ListByURL returns the entries of the URL, the oldest first.
+func (r *AuditRepository) ListByURL(ctx context.Context, urlID int64) ([]*domain.AuditEntry, error) {
+	query := `
+		SELECT id, url_id, action, actor, request_id, before, after, created_at
+		FROM url_audit
+		WHERE url_id = @url_id
+		ORDER BY id`
+
+	rows, err := r.db.QueryContext(ctx, query, sql.Named("url_id", urlID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list audit entries: %w", err)
+	}
+	defer rows.Close()
+
+	entries := []*domain.AuditEntry{}
+
+	for rows.Next() {
+		var (
+			e             domain.AuditEntry
+			before, after sql.NullString
+		)
+
+		err := rows.Scan(&e.ID, &e.URLID, &e.Action, &e.Actor, &e.RequestID, &before, &after, &e.CreatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan audit entry: %w", err)
+		}
+
+		e.Before, err = decodeState(before)
+		if err != nil {
+			return nil, err
+		}
+
+		e.After, err = decodeState(after)
+		if err != nil {
+			return nil, err
+		}
+
+		entries = append(entries, &e)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list audit entries: %w", err)
+	}
+
+	return entries, nil
+}
+
+// encodeState encodes the URL state as JSON, a missing state is NULL.
+func encodeState(s *domain.URLState) (sql.NullString, error) {
+	if s == nil {
+		return sql.NullString{}, nil
+	}
+
+	data, err := json.Marshal(s)
+	if err != nil {
+		return sql.NullString{}, fmt.Errorf("failed to encode url state: %w", err)
+	}
+
+	return sql.NullString{String: string(data), Valid: true}, nil
+}
+
+func decodeState(data sql.NullString) (*domain.URLState, error) {
+	if !data.Valid {
+		return nil, nil
+	}
+
+	var s domain.URLState
+
+	err := json.Unmarshal([]byte(data.String), &s)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode url state: %w", err)
+	}
+
+	return &s, nil
+}
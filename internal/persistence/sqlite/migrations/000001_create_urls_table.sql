@@ -0,0 +1,17 @@
+CREATE TABLE IF NOT EXISTS urls (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    original TEXT NOT NULL UNIQUE,
+    alias TEXT NOT NULL UNIQUE,
+    query_mode TEXT NOT NULL DEFAULT 'ignore',
+    preview BOOLEAN NOT NULL DEFAULT false,
+    title TEXT NOT NULL DEFAULT '',
+    description TEXT NOT NULL DEFAULT '',
+    tags TEXT NOT NULL DEFAULT '[]',
+    metadata TEXT NOT NULL DEFAULT '{}',
+    clicks INTEGER NOT NULL DEFAULT 0,
+    created_at DATETIME NOT NULL,
+    og_title TEXT NOT NULL DEFAULT '',
+    og_description TEXT NOT NULL DEFAULT '',
+    og_image TEXT NOT NULL DEFAULT '',
+    og_fetched_at DATETIME
+);
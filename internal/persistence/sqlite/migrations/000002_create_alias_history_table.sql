@@ -0,0 +1,8 @@
+CREATE TABLE IF NOT EXISTS alias_history (
+    alias TEXT NOT NULL,
+    url_id INTEGER NOT NULL REFERENCES urls (id) ON DELETE CASCADE,
+    retired_at DATETIME NOT NULL,
+    PRIMARY KEY (alias, url_id)
+);
+
+CREATE INDEX IF NOT EXISTS alias_history_url_id_idx ON alias_history (url_id, retired_at DESC);
@@ -0,0 +1,3 @@
+ALTER TABLE urls ADD COLUMN deleted_at DATETIME;
+
+CREATE INDEX IF NOT EXISTS urls_deleted_at_idx ON urls (deleted_at) WHERE deleted_at IS NOT NULL;
@@ -0,0 +1,13 @@
+-- entries outlive their URLs so there is no foreign key
+CREATE TABLE IF NOT EXISTS url_audit (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    url_id INTEGER NOT NULL,
+    action TEXT NOT NULL,
+    actor TEXT NOT NULL DEFAULT '',
+    request_id TEXT NOT NULL DEFAULT '',
+    before TEXT,
+    after TEXT,
+    created_at DATETIME NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS url_audit_url_id_idx ON url_audit (url_id, id);
@@ -0,0 +1,133 @@
+// Package sqlite stores URLs and their audit log in a SQLite database file.
+package sqlite
+
+import (
+	"cmp"
+	"context"
+	"database/sql"
+	"embed"
+	"fmt"
+	"io/fs"
+	"regexp"
+	"slices"
+	"strconv"
+
+	_ "modernc.org/sqlite"
+)
+
+//go:embed migrations/*.sql
+var migrationsFS embed.FS
+
+var migrationName = regexp.MustCompile(`^(\d+)_.+\.sql$`)
+
+// Open opens the database at path applying the pending migrations.
+// Use ":memory:" for a database that lives as long as the returned handle.
+func Open(ctx context.Context, path string) (*sql.DB, error) {
+	dsn := "file:" + path + "?_pragma=foreign_keys(1)&_pragma=busy_timeout(5000)&_txlock=immediate"
+	if path != ":memory:" {
+		dsn += "&_pragma=journal_mode(WAL)"
+	}
+
+	db, err := sql.Open("sqlite", dsn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open sqlite database: %w", err)
+	}
+
+	// SQLite allows a single writer anyway and an in-memory
+	// database is private to its connection
+	db.SetMaxOpenConns(1)
+
+	err = Migrate(ctx, db)
+	if err != nil {
+		db.Close()
+
+		return nil, err
+	}
+
+	return db, nil
+}
+
+// Migrate applies the embedded migrations newer than the version
+// kept in the user_version pragma.
+func Migrate(ctx context.Context, db *sql.DB) error {
+	entries, err := fs.ReadDir(migrationsFS, "migrations")
+	if err != nil {
+		return fmt.Errorf("failed to read migrations: %w", err)
+	}
+
+	type migration struct {
+		version int
+		path    string
+	}
+
+	var migrations []migration
+
+	for _, entry := range entries {
+		match := migrationName.FindStringSubmatch(entry.Name())
+		if match == nil {
+			continue
+		}
+
+		version, err := strconv.Atoi(match[1])
+		if err != nil {
+			return fmt.Errorf("invalid migration version %q: %w", match[1], err)
+		}
+
+		migrations = append(migrations, migration{version: version, path: "migrations/" + entry.Name()})
+	}
+
+	slices.SortFunc(migrations, func(a, b migration) int {
+		return cmp.Compare(a.version, b.version)
+	})
+
+	var current int
+
+	err = db.QueryRowContext(ctx, `PRAGMA user_version`).Scan(&current)
+	if err != nil {
+		return fmt.Errorf("failed to get schema version: %w", err)
+	}
+
+	for _, mig := range migrations {
+		if mig.version <= current {
+			continue
+		}
+
+		query, err := fs.ReadFile(migrationsFS, mig.path)
+		if err != nil {
+			return fmt.Errorf("failed to read migration: %w", err)
+		}
+
+		err = withTx(ctx, db, func(tx *sql.Tx) error {
+			_, err := tx.ExecContext(ctx, string(query))
+			if err != nil {
+				return err
+			}
+
+			// pragmas don't take parameters
+			_, err = tx.ExecContext(ctx, fmt.Sprintf(`PRAGMA user_version = %d`, mig.version))
+
+			return err
+		})
+		if err != nil {
+			return fmt.Errorf("failed to apply migration %s: %w", mig.path, err)
+		}
+	}
+
+	return nil
+}
+
+func withTx(ctx context.Context, db *sql.DB, fn func(tx *sql.Tx) error) error {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	err = fn(tx)
+	if err != nil {
+		tx.Rollback()
+
+		return err
+	}
+
+	return tx.Commit()
+}
@@ -0,0 +1,548 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/kodeyeen/shortify/internal/domain"
+	"github.com/kodeyeen/shortify/internal/persistence"
+	"modernc.org/sqlite"
+	sqlite3 "modernc.org/sqlite/lib"
+)
+
+const urlColumns = `id, original, alias, query_mode, preview, title, description, tags, metadata, clicks, created_at,
+	og_title, og_description, og_image, og_fetched_at, deleted_at`
+
+type URLRepository struct {
+	db *sql.DB
+}
+
+func NewURLRepository(db *sql.DB) *URLRepository {
+	return &URLRepository{
+		db: db,
+	}
+}
+
+func (r *URLRepository) Close() {
+	r.db.Close()
+}
+
+func (r *URLRepository) Add(ctx context.Context, u *domain.URL) (int64, error) {
+	tags, metadata, err := encodeLabels(u)
+	if err != nil {
+		return 0, err
+	}
+
+	query := `
+		INSERT INTO urls (original, alias, query_mode, preview, title, description, tags, metadata, created_at)
+		VALUES (@original, @alias, @query_mode, @preview, @title, @description, @tags, @metadata, @created_at)
+		RETURNING id`
+	args := []any{
+		sql.Named("original", u.Original),
+		sql.Named("alias", u.Alias),
+		sql.Named("query_mode", u.QueryMode),
+		sql.Named("preview", u.Preview),
+		sql.Named("title", u.Title),
+		sql.Named("description", u.Description),
+		sql.Named("tags", tags),
+		sql.Named("metadata", metadata),
+		sql.Named("created_at", u.CreatedAt),
+	}
+
+	var insertID int64
+
+	err = r.db.QueryRowContext(ctx, query, args...).Scan(&insertID)
+	if err != nil {
+		if err := uniqueViolation(err); err != nil {
+			return 0, err
+		}
+
+		return 0, fmt.Errorf("failed to add url: %w", err)
+	}
+
+	return insertID, nil
+}
+
+func (r *URLRepository) FindByID(ctx context.Context, id int64) (*domain.URL, error) {
+	query := `SELECT ` + urlColumns + ` FROM urls WHERE id = @id`
+
+	u, err := scanURL(r.db.QueryRowContext(ctx, query, sql.Named("id", id)))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, persistence.ErrURLNotFound
+		}
+
+		return nil, fmt.Errorf("failed to find url by id: %w", err)
+	}
+
+	return u, nil
+}
+
+func (r *URLRepository) FindByAlias(ctx context.Context, alias string) (*domain.URL, error) {
+	query := `SELECT ` + urlColumns + ` FROM urls WHERE alias = @alias`
+
+	u, err := scanURL(r.db.QueryRowContext(ctx, query, sql.Named("alias", alias)))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, persistence.ErrURLNotFound
+		}
+
+		return nil, fmt.Errorf("failed to find url by alias: %w", err)
+	}
+
+	return u, nil
+}
+
+func (r *URLRepository) FindByOriginal(ctx context.Context, original string) (*domain.URL, error) {
+	query := `SELECT ` + urlColumns + ` FROM urls WHERE original = @original`
+
+	u, err := scanURL(r.db.QueryRowContext(ctx, query, sql.Named("original", original)))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, persistence.ErrURLNotFound
+		}
+
+		return nil, fmt.Errorf("failed to find url by original: %w", err)
+	}
+
+	return u, nil
+}
+
+func (r *URLRepository) Count(ctx context.Context) (int64, error) {
+	var count int64
+
+	err := r.db.QueryRowContext(ctx, `SELECT count(*) FROM urls`).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count urls: %w", err)
+	}
+
+	return count, nil
+}
+
+func (r *URLRepository) List(ctx context.Context, filter persistence.URLFilter) ([]*domain.URL, error) {
+	var (
+		conds = []string{`deleted_at IS NULL`}
+		args  []any
+	)
+
+	if filter.AfterID > 0 {
+		conds = append(conds, `id > ?`)
+		args = append(args, filter.AfterID)
+	}
+
+	if filter.Tag != "" {
+		conds = append(conds, `EXISTS (SELECT 1 FROM json_each(urls.tags) WHERE value = ?)`)
+		args = append(args, filter.Tag)
+	}
+
+	for key, value := range filter.Metadata {
+		conds = append(conds, `EXISTS (SELECT 1 FROM json_each(urls.metadata) WHERE key = ? AND value = ?)`)
+		args = append(args, key, value)
+	}
+
+	query := `SELECT ` + urlColumns + ` FROM urls WHERE ` + strings.Join(conds, ` AND `)
+
+	// SQLite doesn't support OFFSET without LIMIT, negative limit means no limit
+	limit := -1
+	if filter.Limit > 0 {
+		limit = filter.Limit
+	}
+
+	query += ` ORDER BY id LIMIT ? OFFSET ?`
+	args = append(args, limit, filter.Offset)
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list urls: %w", err)
+	}
+	defer rows.Close()
+
+	urls := []*domain.URL{}
+
+	for rows.Next() {
+		u, err := scanURL(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan url: %w", err)
+		}
+
+		urls = append(urls, u)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list urls: %w", err)
+	}
+
+	return urls, nil
+}
+
+// Update updates the editable fields of the URL with the same alias.
+// Changing the original URL resets its Open Graph metadata.
+func (r *URLRepository) Update(ctx context.Context, u *domain.URL) error {
+	tags, metadata, err := encodeLabels(u)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		UPDATE urls
+		SET original = @original, query_mode = @query_mode, preview = @preview,
+			title = @title, description = @description, tags = @tags, metadata = @metadata,
+			og_fetched_at = CASE WHEN original = @original THEN og_fetched_at END
+		WHERE alias = @alias`
+	args := []any{
+		sql.Named("alias", u.Alias),
+		sql.Named("original", u.Original),
+		sql.Named("query_mode", u.QueryMode),
+		sql.Named("preview", u.Preview),
+		sql.Named("title", u.Title),
+		sql.Named("description", u.Description),
+		sql.Named("tags", tags),
+		sql.Named("metadata", metadata),
+	}
+
+	res, err := r.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		if err := uniqueViolation(err); err != nil {
+			return err
+		}
+
+		return fmt.Errorf("failed to update url: %w", err)
+	}
+
+	return requireAffected(res)
+}
+
+// UpdateOpenGraph stores the metadata of the URL with the id unless its original URL
+// is no longer original, failing with persistence.ErrURLNotFound then.
+func (r *URLRepository) UpdateOpenGraph(ctx context.Context, id int64, original string, og *domain.OpenGraph) error {
+	query := `
+		UPDATE urls
+		SET og_title = @title, og_description = @description, og_image = @image, og_fetched_at = @fetched_at
+		WHERE id = @id AND original = @original`
+	args := []any{
+		sql.Named("id", id),
+		sql.Named("original", original),
+		sql.Named("title", og.Title),
+		sql.Named("description", og.Description),
+		sql.Named("image", og.Image),
+		sql.Named("fetched_at", og.FetchedAt),
+	}
+
+	res, err := r.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("failed to update url open graph: %w", err)
+	}
+
+	return requireAffected(res)
+}
+
+func (r *URLRepository) IncrementClicks(ctx context.Context, alias string) (int64, error) {
+	query := `UPDATE urls SET clicks = clicks + 1 WHERE alias = @alias RETURNING clicks`
+
+	var clicks int64
+
+	err := r.db.QueryRowContext(ctx, query, sql.Named("alias", alias)).Scan(&clicks)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, persistence.ErrURLNotFound
+		}
+
+		return 0, fmt.Errorf("failed to increment url clicks: %w", err)
+	}
+
+	return clicks, nil
+}
+
+// SoftDelete moves the URL to the trash keeping its alias and original URL taken.
+func (r *URLRepository) SoftDelete(ctx context.Context, alias string, deletedAt time.Time) (*domain.URL, error) {
+	query := `UPDATE urls SET deleted_at = @deleted_at WHERE alias = @alias AND deleted_at IS NULL RETURNING ` + urlColumns
+	args := []any{
+		sql.Named("alias", alias),
+		// times are compared as text so they are stored in one time zone
+		sql.Named("deleted_at", deletedAt.UTC()),
+	}
+
+	u, err := scanURL(r.db.QueryRowContext(ctx, query, args...))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, persistence.ErrURLNotFound
+		}
+
+		return nil, fmt.Errorf("failed to soft delete url: %w", err)
+	}
+
+	return u, nil
+}
+
+// Restore takes the URL out of the trash.
+func (r *URLRepository) Restore(ctx context.Context, alias string) (*domain.URL, error) {
+	query := `UPDATE urls SET deleted_at = NULL WHERE alias = @alias AND deleted_at IS NOT NULL RETURNING ` + urlColumns
+
+	u, err := scanURL(r.db.QueryRowContext(ctx, query, sql.Named("alias", alias)))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, persistence.ErrURLNotFound
+		}
+
+		return nil, fmt.Errorf("failed to restore url: %w", err)
+	}
+
+	return u, nil
+}
+
+// Purge deletes the URLs moved to the trash before deletedBefore,
+// their alias history is deleted by the foreign key.
+func (r *URLRepository) Purge(ctx context.Context, deletedBefore time.Time) (int64, error) {
+	res, err := r.db.ExecContext(ctx, `DELETE FROM urls WHERE deleted_at < @deleted_before`, sql.Named("deleted_before", deletedBefore.UTC()))
+	if err != nil {
+		return 0, fmt.Errorf("failed to purge urls: %w", err)
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get affected rows: %w", err)
+	}
+
+	return n, nil
+}
+
+// Rename moves the URL with alias to the alias of u, updates its editable fields like Update
+// and records the old alias in the alias history, all in one transaction.
+func (r *URLRepository) Rename(ctx context.Context, alias string, u *domain.URL, retiredAt time.Time) (*domain.URL, error) {
+	tags, metadata, err := encodeLabels(u)
+	if err != nil {
+		return nil, err
+	}
+
+	var renamed *domain.URL
+
+	err = withTx(ctx, r.db, func(tx *sql.Tx) error {
+		var err error
+
+		query := `
+			UPDATE urls
+			SET alias = @new_alias, original = @original, query_mode = @query_mode, preview = @preview,
+				title = @title, description = @description, tags = @tags, metadata = @metadata,
+				og_fetched_at = CASE WHEN original = @original THEN og_fetched_at END
+			WHERE alias = @alias
+			RETURNING ` + urlColumns
+		args := []any{
+			sql.Named("alias", alias),
+			sql.Named("new_alias", u.Alias),
+			sql.Named("original", u.Original),
+			sql.Named("query_mode", u.QueryMode),
+			sql.Named("preview", u.Preview),
+			sql.Named("title", u.Title),
+			sql.Named("description", u.Description),
+			sql.Named("tags", tags),
+			sql.Named("metadata", metadata),
+		}
+
+		renamed, err = scanURL(tx.QueryRowContext(ctx, query, args...))
+		if err != nil {
+			return err
+		}
+
+		query = `
+			INSERT INTO alias_history (alias, url_id, retired_at)
+			VALUES (@alias, @url_id, @retired_at)
+			ON CONFLICT (alias, url_id) DO UPDATE SET retired_at = excluded.retired_at`
+		args = []any{
+			sql.Named("alias", alias),
+			sql.Named("url_id", renamed.ID),
+			sql.Named("retired_at", retiredAt),
+		}
+
+		_, err = tx.ExecContext(ctx, query, args...)
+
+		return err
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, persistence.ErrURLNotFound
+		}
+
+		if err := uniqueViolation(err); err != nil {
+			return nil, err
+		}
+
+		return nil, fmt.Errorf("failed to rename url: %w", err)
+	}
+
+	return renamed, nil
+}
+
+// FindRetiredAlias returns the latest retirement of the alias.
+func (r *URLRepository) FindRetiredAlias(ctx context.Context, alias string) (*domain.RetiredAlias, error) {
+	query := `SELECT alias, url_id, retired_at FROM alias_history WHERE alias = @alias ORDER BY retired_at DESC LIMIT 1`
+
+	var ra domain.RetiredAlias
+
+	err := r.db.QueryRowContext(ctx, query, sql.Named("alias", alias)).Scan(&ra.Alias, &ra.URLID, &ra.RetiredAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, persistence.ErrAliasNotFound
+		}
+
+		return nil, fmt.Errorf("failed to find retired alias: %w", err)
+	}
+
+	return &ra, nil
+}
+
+// ListRetiredAliases returns the retired aliases of the URL, the most recently retired first.
+func (r *URLRepository) ListRetiredAliases(ctx context.Context, urlID int64) ([]*domain.RetiredAlias, error) {
+	query := `SELECT alias, url_id, retired_at FROM alias_history WHERE url_id = @url_id ORDER BY retired_at DESC`
+
+	rows, err := r.db.QueryContext(ctx, query, sql.Named("url_id", urlID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list retired aliases: %w", err)
+	}
+	defer rows.Close()
+
+	retired := []*domain.RetiredAlias{}
+
+	for rows.Next() {
+		var ra domain.RetiredAlias
+
+		err := rows.Scan(&ra.Alias, &ra.URLID, &ra.RetiredAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan retired alias: %w", err)
+		}
+
+		retired = append(retired, &ra)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list retired aliases: %w", err)
+	}
+
+	return retired, nil
+}
+
+// Reindex rebuilds the indexes of the urls table.
+func (r *URLRepository) Reindex(ctx context.Context) error {
+	_, err := r.db.ExecContext(ctx, `REINDEX urls`)
+	if err != nil {
+		return fmt.Errorf("failed to reindex urls: %w", err)
+	}
+
+	return nil
+}
+
+// uniqueViolation maps violations of the urls unique constraints to persistence errors.
+// It returns nil for other errors.
+func uniqueViolation(err error) error {
+	var sqliteErr *sqlite.Error
+
+	if errors.As(err, &sqliteErr) && sqliteErr.Code() == sqlite3.SQLITE_CONSTRAINT_UNIQUE {
+		switch {
+		case strings.Contains(sqliteErr.Error(), "urls.original"):
+			return persistence.ErrURLAlreadyExists
+		case strings.Contains(sqliteErr.Error(), "urls.alias"):
+			return persistence.ErrDuplicateAlias
+		}
+	}
+
+	return nil
+}
+
+func requireAffected(res sql.Result) error {
+	n, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+
+	if n == 0 {
+		return persistence.ErrURLNotFound
+	}
+
+	return nil
+}
+
+// encodeLabels encodes the tags and metadata as JSON stored in text columns.
+func encodeLabels(u *domain.URL) (string, string, error) {
+	tags := u.Tags
+	if tags == nil {
+		tags = []string{}
+	}
+
+	metadata := u.Metadata
+	if metadata == nil {
+		metadata = map[string]string{}
+	}
+
+	tagsJSON, err := json.Marshal(tags)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to encode tags: %w", err)
+	}
+
+	metadataJSON, err := json.Marshal(metadata)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to encode metadata: %w", err)
+	}
+
+	return string(tagsJSON), string(metadataJSON), nil
+}
+
+type row interface {
+	Scan(dest ...any) error
+}
+
+func scanURL(row row) (*domain.URL, error) {
+	var (
+		u                  domain.URL
+		og                 domain.OpenGraph
+		ogFetchedAt        sql.NullTime
+		deletedAt          sql.NullTime
+		tagsJSON, metaJSON string
+	)
+
+	err := row.Scan(
+		&u.ID,
+		&u.Original,
+		&u.Alias,
+		&u.QueryMode,
+		&u.Preview,
+		&u.Title,
+		&u.Description,
+		&tagsJSON,
+		&metaJSON,
+		&u.Clicks,
+		&u.CreatedAt,
+		&og.Title,
+		&og.Description,
+		&og.Image,
+		&ogFetchedAt,
+		&deletedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	err = json.Unmarshal([]byte(tagsJSON), &u.Tags)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode tags: %w", err)
+	}
+
+	err = json.Unmarshal([]byte(metaJSON), &u.Metadata)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode metadata: %w", err)
+	}
+
+	if ogFetchedAt.Valid {
+		og.FetchedAt = ogFetchedAt.Time
+		u.OpenGraph = &og
+	}
+
+	if deletedAt.Valid {
+		u.DeletedAt = &deletedAt.Time
+	}
+
+	return &u, nil
+}
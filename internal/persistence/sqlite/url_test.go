@@ -0,0 +1,57 @@
+package sqlite_test
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/kodeyeen/shortify/internal/persistence/repotest"
+	"github.com/kodeyeen/shortify/internal/persistence/sqlite"
+	"github.com/stretchr/testify/require"
+)
+
+func TestURLRepository(t *testing.T) {
+	repotest.TestURLRepository(t, func(t *testing.T) repotest.URLRepository {
+		db, err := sqlite.Open(context.Background(), ":memory:")
+		require.NoError(t, err)
+
+		t.Cleanup(func() { db.Close() })
+
+		return sqlite.NewURLRepository(db)
+	})
+}
+
+func TestAuditRepository(t *testing.T) {
+	repotest.TestAuditRepository(t, func(t *testing.T) repotest.AuditRepository {
+		db, err := sqlite.Open(context.Background(), ":memory:")
+		require.NoError(t, err)
+
+		t.Cleanup(func() { db.Close() })
+
+		return sqlite.NewAuditRepository(db)
+	})
+}
+
+func TestOpen_Reopen(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "shortify.db")
+
+	// Given
+	db, err := sqlite.Open(ctx, path)
+	require.NoError(t, err)
+
+	_, err = db.ExecContext(ctx, `INSERT INTO urls (original, alias, created_at) VALUES ('https://example.com', 'alias00001', CURRENT_TIMESTAMP)`)
+	require.NoError(t, err)
+	require.NoError(t, db.Close())
+
+	// When
+	db, err = sqlite.Open(ctx, path)
+	require.NoError(t, err)
+
+	defer db.Close()
+
+	// Then
+	u, err := sqlite.NewURLRepository(db).FindByAlias(ctx, "alias00001")
+	require.NoError(t, err)
+	require.Equal(t, "https://example.com", u.Original)
+}
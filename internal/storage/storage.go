@@ -0,0 +1,167 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/kodeyeen/shortify/internal/config"
+	"github.com/kodeyeen/shortify/internal/persistence"
+	"github.com/kodeyeen/shortify/internal/persistence/bolt"
+	"github.com/kodeyeen/shortify/internal/persistence/inmemory"
+	"github.com/kodeyeen/shortify/internal/persistence/postgres"
+	"github.com/kodeyeen/shortify/internal/persistence/sqlite"
+	"github.com/kodeyeen/shortify/internal/url"
+	"github.com/kodeyeen/shortify/internal/webhook"
+)
+
+// Storage holds the repositories of the configured persistence type
+// so the binaries don't wire them up separately.
+type Storage struct {
+	URLs     url.Repository
+	Webhooks webhook.Repository
+	Audit    url.AuditLog
+
+	// DB is the Postgres pool, it's nil for other persistence types.
+	DB *pgxpool.Pool
+
+	// closers release the storage of other persistence types
+	closers []io.Closer
+}
+
+// Open opens the storage of the configured persistence type.
+func Open(ctx context.Context, cfg *config.Config) (*Storage, error) {
+	var (
+		inmemoryOpts []inmemory.URLOption
+		postgresOpts []postgres.URLOption
+	)
+
+	if cfg.Alias.CaseInsensitive {
+		switch cfg.PersistenceType {
+		case config.PersistenceTypeInmemory, config.PersistenceTypePostgres:
+			inmemoryOpts = append(inmemoryOpts, inmemory.WithCaseInsensitiveAliases())
+			postgresOpts = append(postgresOpts, postgres.WithCaseInsensitiveAliases())
+		default:
+			return nil, fmt.Errorf("case-insensitive aliases aren't supported by %s persistence", cfg.PersistenceType)
+		}
+	}
+
+	// only postgres keeps the webhook outbox, other storages keeping URLs
+	// across restarts would lose the pending deliveries
+	if cfg.Webhooks.Enabled {
+		switch {
+		case cfg.PersistenceType == config.PersistenceTypePostgres:
+		case cfg.PersistenceType == config.PersistenceTypeInmemory && cfg.Inmemory.Dir == "":
+		default:
+			return nil, fmt.Errorf("webhooks aren't supported by durable %s persistence", cfg.PersistenceType)
+		}
+	}
+
+	switch cfg.PersistenceType {
+	case config.PersistenceTypeInmemory:
+		if cfg.Inmemory.Dir == "" {
+			return &Storage{
+				URLs:     inmemory.NewURLRepository(inmemoryOpts...),
+				Webhooks: inmemory.NewWebhookRepository(),
+				Audit:    inmemory.NewAuditRepository(),
+			}, nil
+		}
+
+		logCfg := inmemory.LogConfig{
+			Fsync:         inmemory.FsyncPolicy(cfg.Inmemory.Fsync),
+			FsyncInterval: cfg.Inmemory.FsyncInterval,
+			SnapshotEvery: cfg.Inmemory.SnapshotEvery,
+		}
+
+		urls, err := inmemory.OpenURLRepository(cfg.Inmemory.Dir, logCfg, inmemoryOpts...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open inmemory storage: %w", err)
+		}
+
+		audit, err := inmemory.OpenAuditRepository(cfg.Inmemory.Dir, logCfg)
+		if err != nil {
+			urls.Close()
+
+			return nil, fmt.Errorf("failed to open inmemory audit log: %w", err)
+		}
+
+		return &Storage{
+			URLs:     urls,
+			Webhooks: inmemory.NewWebhookRepository(),
+			Audit:    audit,
+			closers:  []io.Closer{urls, audit},
+		}, nil
+	case config.PersistenceTypePostgres:
+		connString := persistence.NewConnString(
+			"postgres",
+			cfg.Postgres.Username,
+			cfg.Postgres.Password,
+			cfg.Postgres.Host,
+			cfg.Postgres.Database,
+		)
+
+		dbpool, err := pgxpool.New(ctx, connString)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create dbpool: %w", err)
+		}
+
+		err = dbpool.Ping(ctx)
+		if err != nil {
+			dbpool.Close()
+
+			return nil, fmt.Errorf("failed to ping db: %w", err)
+		}
+
+		return &Storage{
+			URLs:     postgres.NewURLRepository(dbpool, postgresOpts...),
+			Webhooks: postgres.NewWebhookRepository(dbpool),
+			Audit:    postgres.NewAuditRepository(dbpool),
+			DB:       dbpool,
+		}, nil
+	case config.PersistenceTypeSQLite:
+		db, err := sqlite.Open(ctx, cfg.SQLite.Path)
+		if err != nil {
+			return nil, err
+		}
+
+		// webhooks are disabled, their repository only serves the webhooks API
+		return &Storage{
+			URLs:     sqlite.NewURLRepository(db),
+			Webhooks: inmemory.NewWebhookRepository(),
+			Audit:    sqlite.NewAuditRepository(db),
+			closers:  []io.Closer{db},
+		}, nil
+	case config.PersistenceTypeBolt:
+		db, err := bolt.Open(cfg.Bolt.Path)
+		if err != nil {
+			return nil, err
+		}
+
+		// webhooks are disabled, their repository only serves the webhooks API
+		return &Storage{
+			URLs:     bolt.NewURLRepository(db),
+			Webhooks: inmemory.NewWebhookRepository(),
+			Audit:    bolt.NewAuditRepository(db),
+			closers:  []io.Closer{db},
+		}, nil
+	default:
+		return nil, fmt.Errorf("invalid persistence type %q", cfg.PersistenceType)
+	}
+}
+
+// Close releases the resources of the storage.
+func (s *Storage) Close() error {
+	if s.DB != nil {
+		s.DB.Close()
+	}
+
+	var errs []error
+
+	for _, c := range s.closers {
+		errs = append(errs, c.Close())
+	}
+
+	return errors.Join(errs...)
+}
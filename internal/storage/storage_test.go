@@ -0,0 +1,89 @@
+package storage_test
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/kodeyeen/shortify/internal/config"
+	"github.com/kodeyeen/shortify/internal/storage"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOpen_Webhooks(t *testing.T) {
+	type Given struct {
+		persistenceType string
+		inmemoryDir     bool
+		webhooks        bool
+	}
+
+	type Expected struct {
+		err string
+	}
+
+	testCases := map[string]struct {
+		given    Given
+		expected Expected
+	}{
+		"Inmemory": {
+			Given{persistenceType: config.PersistenceTypeInmemory, webhooks: true},
+			Expected{},
+		},
+		"Durable inmemory": {
+			Given{persistenceType: config.PersistenceTypeInmemory, inmemoryDir: true, webhooks: true},
+			Expected{err: "webhooks aren't supported by durable inmemory persistence"},
+		},
+		"Durable inmemory without webhooks": {
+			Given{persistenceType: config.PersistenceTypeInmemory, inmemoryDir: true},
+			Expected{},
+		},
+		"SQLite": {
+			Given{persistenceType: config.PersistenceTypeSQLite, webhooks: true},
+			Expected{err: "webhooks aren't supported by durable sqlite persistence"},
+		},
+		"SQLite without webhooks": {
+			Given{persistenceType: config.PersistenceTypeSQLite},
+			Expected{},
+		},
+		"Bolt": {
+			Given{persistenceType: config.PersistenceTypeBolt, webhooks: true},
+			Expected{err: "webhooks aren't supported by durable bolt persistence"},
+		},
+		"Bolt without webhooks": {
+			Given{persistenceType: config.PersistenceTypeBolt},
+			Expected{},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			// Given
+			dir := t.TempDir()
+
+			cfg := &config.Config{PersistenceType: tc.given.persistenceType}
+			cfg.Webhooks.Enabled = tc.given.webhooks
+			cfg.SQLite.Path = filepath.Join(dir, "shortify.db")
+			cfg.Bolt.Path = filepath.Join(dir, "shortify.bolt")
+			cfg.Inmemory.Fsync = "always"
+			cfg.Inmemory.SnapshotEvery = 10
+
+			if tc.given.inmemoryDir {
+				cfg.Inmemory.Dir = dir
+			}
+
+			// When
+			store, err := storage.Open(context.Background(), cfg)
+
+			// Then
+			if tc.expected.err != "" {
+				require.EqualError(t, err, tc.expected.err)
+				return
+			}
+
+			require.NoError(t, err)
+			require.NoError(t, store.Close())
+		})
+	}
+}
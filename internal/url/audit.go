@@ -0,0 +1,179 @@
+package url
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"maps"
+	"slices"
+
+	"github.com/kodeyeen/shortify/internal/audit"
+	"github.com/kodeyeen/shortify/internal/domain"
+	"github.com/kodeyeen/shortify/internal/dto"
+)
+
+// History lists the changes to the URL, the oldest first
+func (s *Service) History(ctx context.Context, req *dto.URLHistoryRequest) (*dto.URLHistoryResponse, error) {
+	if s.auditLog == nil {
+		return nil, errors.New("audit log is not set")
+	}
+
+	u, _, err := s.find(ctx, req.Alias)
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := s.auditLog.ListByURL(ctx, u.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list audit entries: %w", err)
+	}
+
+	resp := &dto.URLHistoryResponse{
+		Alias:    u.Alias,
+		Versions: make([]*dto.URLVersionResponse, 0, len(entries)),
+	}
+
+	for i, e := range entries {
+		resp.Versions = append(resp.Versions, &dto.URLVersionResponse{
+			Version:   i + 1,
+			Action:    e.Action,
+			Actor:     e.Actor,
+			RequestID: e.RequestID,
+			CreatedAt: e.CreatedAt,
+			Changes:   changes(e.Before, e.After),
+		})
+	}
+
+	return resp, nil
+}
+
+// Rollback restores the URL fields to the state after the version
+func (s *Service) Rollback(ctx context.Context, req *dto.RollbackURLRequest) (*dto.GetURLByAliasResponse, error) {
+	if s.auditLog == nil {
+		return nil, errors.New("audit log is not set")
+	}
+
+	u, _, err := s.find(ctx, req.Alias)
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := s.auditLog.ListByURL(ctx, u.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list audit entries: %w", err)
+	}
+
+	if req.Version < 1 || req.Version > len(entries) || entries[req.Version-1].After == nil {
+		return nil, ErrVersionNotFound
+	}
+
+	state := entries[req.Version-1].After
+
+	return s.update(ctx, &dto.UpdateURLRequest{
+		Alias:       u.Alias,
+		NewAlias:    &state.Alias,
+		Original:    &state.Original,
+		QueryMode:   &state.QueryMode,
+		Preview:     &state.Preview,
+		Title:       &state.Title,
+		Description: &state.Description,
+		Tags:        &state.Tags,
+		Metadata:    &state.Metadata,
+	}, domain.AuditActionRolledBack)
+}
+
+// record appends the change to the audit log. Changes that leave the URL as is aren't recorded.
+// Failures are logged as the change has already happened.
+func (s *Service) record(ctx context.Context, action string, urlID int64, before, after *domain.URL) {
+	if s.auditLog == nil {
+		return
+	}
+
+	origin := audit.FromContext(ctx)
+
+	e := &domain.AuditEntry{
+		URLID:     urlID,
+		Action:    action,
+		Actor:     origin.Actor,
+		RequestID: origin.RequestID,
+		CreatedAt: s.now(),
+	}
+
+	if before != nil {
+		e.Before = domain.NewURLState(before)
+	}
+
+	if after != nil {
+		e.After = domain.NewURLState(after)
+	}
+
+	if len(changes(e.Before, e.After)) == 0 {
+		return
+	}
+
+	_, err := s.auditLog.Append(ctx, e)
+	if err != nil {
+		s.log.Error("failed to record audit entry",
+			slog.String("action", action),
+			slog.Int64("url_id", urlID),
+			slog.String("error", err.Error()),
+		)
+	}
+}
+
+// changes lists the fields differing between the states. Every field of the state
+// is listed when the other one is nil.
+func changes(before, after *domain.URLState) []*dto.FieldChange {
+	beforeFields := stateFields(before)
+	afterFields := stateFields(after)
+
+	res := []*dto.FieldChange{}
+
+	for i, name := range stateFieldNames {
+		var b, a any
+
+		if beforeFields != nil {
+			b = beforeFields[i]
+		}
+
+		if afterFields != nil {
+			a = afterFields[i]
+		}
+
+		if beforeFields != nil && afterFields != nil && equalField(b, a) {
+			continue
+		}
+
+		res = append(res, &dto.FieldChange{
+			Field:  name,
+			Before: b,
+			After:  a,
+		})
+	}
+
+	return res
+}
+
+// stateFieldNames are the names of the fields returned by stateFields.
+var stateFieldNames = []string{"original", "alias", "query_mode", "preview", "title", "description", "tags", "metadata"}
+
+func stateFields(s *domain.URLState) []any {
+	if s == nil {
+		return nil
+	}
+
+	return []any{s.Original, s.Alias, s.QueryMode, s.Preview, s.Title, s.Description, s.Tags, s.Metadata}
+}
+
+// equalField compares field values treating nil and empty tags and metadata as equal.
+func equalField(a, b any) bool {
+	switch a := a.(type) {
+	case []string:
+		return slices.Equal(a, b.([]string))
+	case map[string]string:
+		return maps.Equal(a, b.(map[string]string))
+	default:
+		return a == b
+	}
+}
@@ -0,0 +1,34 @@
+package url
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/kodeyeen/shortify/internal/dto"
+	"github.com/kodeyeen/shortify/internal/generation/keyspace"
+)
+
+// Capacity estimates how much of the alias keyspace is occupied
+func (s *Service) Capacity(ctx context.Context) (*dto.CapacityResponse, error) {
+	if s.keyspace == nil {
+		return nil, errors.New("alias keyspace is not set")
+	}
+
+	count, err := s.urls.Count(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count URLs: %w", err)
+	}
+
+	c := keyspace.Estimate(s.keyspace, count, MaxAliasAttempts)
+
+	return &dto.CapacityResponse{
+		Provider:             s.aliasProvider,
+		KeyspaceSize:         c.Size.String(),
+		KeyspaceBits:         c.Bits,
+		Occupied:             c.Occupied,
+		Occupancy:            c.Occupancy,
+		CollisionProbability: c.CollisionProbability,
+		FailureProbability:   c.FailureProbability,
+	}, nil
+}
@@ -6,4 +6,11 @@ var (
 	ErrAlreadyExists         = errors.New("URL already exists")
 	ErrNotFound              = errors.New("URL not found")
 	ErrAliasGenerationFailed = errors.New("alias generation failed")
+	ErrInvalidDestination    = errors.New("invalid destination URL")
+	ErrUnknownAliasProvider  = errors.New("unknown alias provider")
+	ErrAliasTaken            = errors.New("alias is taken")
+	ErrAliasQuarantined      = errors.New("alias is quarantined")
+	ErrVersionNotFound       = errors.New("URL version not found")
+	ErrDeleted               = errors.New("URL is deleted")
+	ErrNotDeleted            = errors.New("URL is not deleted")
 )
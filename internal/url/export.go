@@ -0,0 +1,42 @@
+package url
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/kodeyeen/shortify/internal/dto"
+	"github.com/kodeyeen/shortify/internal/persistence"
+)
+
+// ExportBatchSize is the number of URLs read from the repository at once while exporting.
+const ExportBatchSize = 500
+
+// Export calls fn for every URL matching the request in the order of creation until fn fails.
+// URLs are read in batches so the whole set is never held in memory. Deleted URLs are skipped.
+func (s *Service) Export(ctx context.Context, req *dto.ExportURLsRequest, fn func(u *dto.GetURLByAliasResponse) error) error {
+	filter := persistence.URLFilter{
+		Tag:      normalizeTag(req.Tag),
+		Metadata: req.Metadata,
+		Limit:    ExportBatchSize,
+	}
+
+	for {
+		urls, err := s.urls.List(ctx, filter)
+		if err != nil {
+			return fmt.Errorf("failed to list URLs: %w", err)
+		}
+
+		for _, u := range urls {
+			err := fn(newGetURLByAliasResponse(u))
+			if err != nil {
+				return err
+			}
+		}
+
+		if len(urls) < ExportBatchSize {
+			return nil
+		}
+
+		filter.AfterID = urls[len(urls)-1].ID
+	}
+}
@@ -0,0 +1,133 @@
+package url
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/kodeyeen/shortify/internal/domain"
+	"github.com/kodeyeen/shortify/internal/dto"
+	"github.com/kodeyeen/shortify/internal/persistence"
+)
+
+// ListAliases lists the aliases the URL had before its current one
+func (s *Service) ListAliases(ctx context.Context, req *dto.ListAliasesRequest) (*dto.ListAliasesResponse, error) {
+	u, err := s.findLive(ctx, req.Alias)
+	if err != nil {
+		return nil, err
+	}
+
+	retired, err := s.urls.ListRetiredAliases(ctx, u.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list retired aliases: %w", err)
+	}
+
+	resp := &dto.ListAliasesResponse{
+		Alias:   u.Alias,
+		Retired: make([]*dto.RetiredAliasResponse, 0, len(retired)),
+	}
+
+	for _, ra := range retired {
+		resp.Retired = append(resp.Retired, &dto.RetiredAliasResponse{
+			Alias:            ra.Alias,
+			RetiredAt:        ra.RetiredAt,
+			QuarantinedUntil: ra.RetiredAt.Add(s.quarantine),
+		})
+	}
+
+	return resp, nil
+}
+
+// find finds the URL by its current alias falling back to the retired ones.
+// It reports whether the alias is retired.
+func (s *Service) find(ctx context.Context, alias string) (*domain.URL, bool, error) {
+	u, err := s.urls.FindByAlias(ctx, alias)
+	if err == nil {
+		return u, false, nil
+	}
+
+	if !errors.Is(err, persistence.ErrURLNotFound) {
+		return nil, false, fmt.Errorf("failed to get URL by alias: %w", err)
+	}
+
+	ra, err := s.urls.FindRetiredAlias(ctx, alias)
+	if err != nil {
+		if errors.Is(err, persistence.ErrAliasNotFound) {
+			return nil, false, ErrNotFound
+		}
+
+		return nil, false, fmt.Errorf("failed to find retired alias: %w", err)
+	}
+
+	u, err = s.urls.FindByID(ctx, ra.URLID)
+	if err != nil {
+		if errors.Is(err, persistence.ErrURLNotFound) {
+			return nil, false, ErrNotFound
+		}
+
+		return nil, false, fmt.Errorf("failed to get URL by id: %w", err)
+	}
+
+	return u, true, nil
+}
+
+// findLive finds the URL like find failing with ErrDeleted when it's in the trash.
+func (s *Service) findLive(ctx context.Context, alias string) (*domain.URL, error) {
+	u, _, err := s.find(ctx, alias)
+	if err != nil {
+		return nil, err
+	}
+
+	if u.DeletedAt != nil {
+		return nil, ErrDeleted
+	}
+
+	return u, nil
+}
+
+// rename stores updated in place of u under its new alias unless another URL
+// holds the alias or retired it within the quarantine period.
+func (s *Service) rename(ctx context.Context, u, updated *domain.URL) error {
+	quarantined, err := s.quarantined(ctx, updated.Alias, u.ID)
+	if err != nil {
+		return err
+	}
+
+	if quarantined {
+		return ErrAliasQuarantined
+	}
+
+	_, err = s.urls.Rename(ctx, u.Alias, updated, s.now())
+	if err != nil {
+		if errors.Is(err, persistence.ErrURLNotFound) {
+			return ErrNotFound
+		} else if errors.Is(err, persistence.ErrDuplicateAlias) {
+			return ErrAliasTaken
+		} else if errors.Is(err, persistence.ErrURLAlreadyExists) {
+			return ErrAlreadyExists
+		}
+
+		return fmt.Errorf("failed to rename URL: %w", err)
+	}
+
+	return nil
+}
+
+// quarantined reports whether a URL other than the one with urlID
+// retired the alias within the quarantine period.
+func (s *Service) quarantined(ctx context.Context, alias string, urlID int64) (bool, error) {
+	if s.quarantine <= 0 {
+		return false, nil
+	}
+
+	ra, err := s.urls.FindRetiredAlias(ctx, alias)
+	if err != nil {
+		if errors.Is(err, persistence.ErrAliasNotFound) {
+			return false, nil
+		}
+
+		return false, fmt.Errorf("failed to find retired alias: %w", err)
+	}
+
+	return ra.URLID != urlID && s.now().Before(ra.RetiredAt.Add(s.quarantine)), nil
+}
@@ -0,0 +1,170 @@
+package url
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/kodeyeen/shortify/internal/domain"
+	"github.com/kodeyeen/shortify/internal/dto"
+	"github.com/kodeyeen/shortify/internal/persistence"
+)
+
+// Import adds the URL under its own alias or a generated one when the alias is empty.
+// It fails with ErrAliasTaken, ErrAliasQuarantined or ErrAlreadyExists on conflicts.
+// A dry run only checks for the conflicts, so its response has no generated alias.
+func (s *Service) Import(ctx context.Context, req *dto.ImportURLRequest) (*dto.CreateURLResponse, error) {
+	queryMode := req.QueryMode
+	if queryMode == "" {
+		queryMode = domain.QueryModeIgnore
+	}
+
+	createdAt := req.CreatedAt
+	if createdAt.IsZero() {
+		createdAt = s.now()
+	}
+
+	u := &domain.URL{
+		Original:    req.Original,
+		Alias:       req.Alias,
+		QueryMode:   queryMode,
+		Preview:     req.Preview,
+		Title:       req.Title,
+		Description: req.Description,
+		Tags:        normalizeTags(req.Tags),
+		Metadata:    req.Metadata,
+		CreatedAt:   createdAt,
+	}
+
+	if req.DryRun != nil {
+		err := s.checkImport(ctx, req.DryRun, u)
+		if err != nil {
+			return nil, err
+		}
+
+		return newCreateURLResponse(u), nil
+	}
+
+	var (
+		id  int64
+		err error
+	)
+
+	if u.Alias == "" {
+		id, err = s.add(ctx, s.aliases, u)
+	} else {
+		id, err = s.addWithAlias(ctx, u)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	u.ID = id
+
+	s.enqueueMetadata(u)
+	s.notify(ctx, domain.EventURLCreated, u)
+	s.record(ctx, domain.AuditActionCreated, u.ID, nil, u)
+
+	return newCreateURLResponse(u), nil
+}
+
+// addWithAlias stores u under its alias unless another URL holds it or retired it
+// within the quarantine period.
+func (s *Service) addWithAlias(ctx context.Context, u *domain.URL) (int64, error) {
+	quarantined, err := s.quarantined(ctx, u.Alias, 0)
+	if err != nil {
+		return 0, err
+	}
+
+	if quarantined {
+		return 0, ErrAliasQuarantined
+	}
+
+	id, err := s.urls.Add(ctx, u)
+	if err != nil {
+		if errors.Is(err, persistence.ErrDuplicateAlias) {
+			return 0, ErrAliasTaken
+		} else if errors.Is(err, persistence.ErrURLAlreadyExists) {
+			return 0, ErrAlreadyExists
+		}
+
+		return 0, fmt.Errorf("failed to create URL: %w", err)
+	}
+
+	return id, nil
+}
+
+// checkImport reports the conflicts adding u would run into, including the conflicts
+// with the URLs checked earlier by the same dry run. u is added to the dry run when
+// there are none.
+func (s *Service) checkImport(ctx context.Context, run *dto.ImportDryRun, u *domain.URL) error {
+	if _, ok := run.Originals[u.Original]; ok {
+		return ErrAlreadyExists
+	}
+
+	_, err := s.urls.FindByOriginal(ctx, u.Original)
+	if err == nil {
+		return ErrAlreadyExists
+	} else if !errors.Is(err, persistence.ErrURLNotFound) {
+		return fmt.Errorf("failed to get URL by original: %w", err)
+	}
+
+	alias := s.aliasKey(u.Alias)
+
+	if u.Alias != "" {
+		err = s.checkImportAlias(ctx, run, alias, u.Alias)
+		if err != nil {
+			return err
+		}
+	}
+
+	if run.Originals == nil {
+		run.Originals = map[string]struct{}{}
+		run.Aliases = map[string]struct{}{}
+	}
+
+	run.Originals[u.Original] = struct{}{}
+
+	if u.Alias != "" {
+		run.Aliases[alias] = struct{}{}
+	}
+
+	return nil
+}
+
+// checkImportAlias reports whether the alias is held by a stored URL or by a URL checked earlier
+// by the dry run, or is quarantined. key is the alias folded by Service.aliasKey.
+func (s *Service) checkImportAlias(ctx context.Context, run *dto.ImportDryRun, key, alias string) error {
+	if _, ok := run.Aliases[key]; ok {
+		return ErrAliasTaken
+	}
+
+	_, err := s.urls.FindByAlias(ctx, alias)
+	if err == nil {
+		return ErrAliasTaken
+	} else if !errors.Is(err, persistence.ErrURLNotFound) {
+		return fmt.Errorf("failed to get URL by alias: %w", err)
+	}
+
+	quarantined, err := s.quarantined(ctx, alias, 0)
+	if err != nil {
+		return err
+	}
+
+	if quarantined {
+		return ErrAliasQuarantined
+	}
+
+	return nil
+}
+
+// aliasKey returns the alias the way the repository compares it.
+func (s *Service) aliasKey(alias string) string {
+	if s.caseInsensitive {
+		return strings.ToLower(alias)
+	}
+
+	return alias
+}
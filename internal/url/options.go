@@ -0,0 +1,90 @@
+package url
+
+import (
+	"math/big"
+	"time"
+)
+
+// Option configures optional Service dependencies.
+type Option func(s *Service)
+
+// WithClock sets the function the service uses to get the current time.
+func WithClock(now func() time.Time) Option {
+	return func(s *Service) {
+		s.now = now
+	}
+}
+
+// WithMetadataQueue enables fetching preview metadata of created URLs.
+func WithMetadataQueue(q MetadataQueue) Option {
+	return func(s *Service) {
+		s.metadata = q
+	}
+}
+
+// WithNotifier enables notifications about URL events.
+func WithNotifier(n Notifier) Option {
+	return func(s *Service) {
+		s.notifier = n
+	}
+}
+
+// WithAuditLog enables recording the changes to URLs.
+func WithAuditLog(l AuditLog) Option {
+	return func(s *Service) {
+		s.auditLog = l
+	}
+}
+
+// WithAliasProviders sets the alias providers requests may choose by name.
+func WithAliasProviders(providers map[string]AliasProvider) Option {
+	return func(s *Service) {
+		s.aliasProviders = providers
+	}
+}
+
+// WithKeyspace sets the name and the keyspace size of the default alias provider
+// reported by Service.Capacity.
+func WithKeyspace(provider string, size *big.Int) Option {
+	return func(s *Service) {
+		s.aliasProvider = provider
+		s.keyspace = size
+	}
+}
+
+// WithAliasQuarantine blocks reusing retired aliases for d after they were retired.
+func WithAliasQuarantine(d time.Duration) Option {
+	return func(s *Service) {
+		s.quarantine = d
+	}
+}
+
+// WithRetiredAliasRedirect makes retired aliases redirect to the current alias
+// instead of resolving to the destination directly.
+func WithRetiredAliasRedirect() Option {
+	return func(s *Service) {
+		s.redirectRetired = true
+	}
+}
+
+// WithTrashRetention keeps deleted URLs restorable for d before Service.Purge deletes them permanently.
+func WithTrashRetention(d time.Duration) Option {
+	return func(s *Service) {
+		s.trashRetention = d
+	}
+}
+
+// WithClickThresholds sets the numbers of clicks to notify about.
+func WithClickThresholds(thresholds []int64) Option {
+	return func(s *Service) {
+		s.clickThresholds = thresholds
+	}
+}
+
+// WithCaseInsensitiveAliases treats aliases differing only in case as the same alias
+// like the repository does in the case-insensitive mode.
+func WithCaseInsensitiveAliases() Option {
+	return func(s *Service) {
+		s.caseInsensitive = true
+	}
+}
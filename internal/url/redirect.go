@@ -0,0 +1,44 @@
+package url
+
+import (
+	"fmt"
+	neturl "net/url"
+	"strings"
+
+	"github.com/kodeyeen/shortify/internal/domain"
+)
+
+// destination builds the redirect location for u substituting path into
+// the original URL template and applying the query mode to query.
+func destination(u *domain.URL, path string, query neturl.Values) (string, error) {
+	original := u.Original
+
+	if strings.Contains(original, domain.PathPlaceholder) {
+		original = strings.ReplaceAll(original, domain.PathPlaceholder, path)
+	} else if path != "" {
+		return "", ErrNotFound
+	}
+
+	if len(query) == 0 || u.QueryMode == domain.QueryModeIgnore || u.QueryMode == "" {
+		return original, nil
+	}
+
+	dest, err := neturl.Parse(original)
+	if err != nil {
+		return "", fmt.Errorf("%w: %w", ErrInvalidDestination, err)
+	}
+
+	destQuery := dest.Query()
+
+	for key, values := range query {
+		if _, ok := destQuery[key]; ok && u.QueryMode == domain.QueryModeMerge {
+			continue
+		}
+
+		destQuery[key] = values
+	}
+
+	dest.RawQuery = destQuery.Encode()
+
+	return dest.String(), nil
+}
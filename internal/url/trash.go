@@ -0,0 +1,89 @@
+package url
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/kodeyeen/shortify/internal/domain"
+	"github.com/kodeyeen/shortify/internal/dto"
+	"github.com/kodeyeen/shortify/internal/persistence"
+)
+
+// Restore takes the deleted URL out of the trash
+func (s *Service) Restore(ctx context.Context, req *dto.RestoreURLRequest) (*dto.GetURLByAliasResponse, error) {
+	u, err := s.urls.FindByAlias(ctx, req.Alias)
+	if err != nil {
+		if errors.Is(err, persistence.ErrURLNotFound) {
+			return nil, ErrNotFound
+		}
+
+		return nil, fmt.Errorf("failed to get URL by alias: %w", err)
+	}
+
+	if u.DeletedAt == nil {
+		return nil, ErrNotDeleted
+	}
+
+	u, err = s.urls.Restore(ctx, u.Alias)
+	if err != nil {
+		if errors.Is(err, persistence.ErrURLNotFound) {
+			return nil, ErrNotFound
+		}
+
+		return nil, fmt.Errorf("failed to restore URL: %w", err)
+	}
+
+	s.notify(ctx, domain.EventURLRestored, u)
+	s.record(ctx, domain.AuditActionRestored, u.ID, nil, u)
+
+	return newGetURLByAliasResponse(u), nil
+}
+
+// Purge permanently deletes the URLs that have been in the trash
+// for longer than the retention period and returns their number.
+// Their aliases can be taken by other URLs after that.
+func (s *Service) Purge(ctx context.Context) (int64, error) {
+	purged, err := s.urls.Purge(ctx, s.now().Add(-s.trashRetention))
+	if err != nil {
+		return 0, fmt.Errorf("failed to purge URLs: %w", err)
+	}
+
+	return purged, nil
+}
+
+// StartPurge purges the trash every interval in the background until ctx is done.
+// The returned channel is closed once the purging has stopped.
+func (s *Service) StartPurge(ctx context.Context, interval time.Duration) <-chan struct{} {
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+
+		s.runPurge(ctx, interval)
+	}()
+
+	return done
+}
+
+func (s *Service) runPurge(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		purged, err := s.Purge(ctx)
+		if err != nil {
+			s.log.Error("failed to purge deleted URLs", slog.String("error", err.Error()))
+		} else if purged > 0 {
+			s.log.Info("purged deleted URLs", slog.Int64("count", purged))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
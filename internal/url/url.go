@@ -5,77 +5,239 @@ import (
 	"errors"
 	"fmt"
 	"log/slog"
+	"maps"
+	"math/big"
+	"slices"
+	"strings"
+	"time"
 
 	"github.com/kodeyeen/shortify/internal/domain"
 	"github.com/kodeyeen/shortify/internal/dto"
 	"github.com/kodeyeen/shortify/internal/persistence"
 )
 
+const (
+	DefaultListLimit = 50
+	MaxListLimit     = 1000
+)
+
+// MaxAliasAttempts limits generating another alias when the previous one is taken.
+const MaxAliasAttempts = 10
+
 type Repository interface {
 	Add(ctx context.Context, u *domain.URL) (int64, error)
+	FindByID(ctx context.Context, id int64) (*domain.URL, error)
 	FindByAlias(ctx context.Context, alias string) (*domain.URL, error)
+	FindByOriginal(ctx context.Context, original string) (*domain.URL, error)
+	Count(ctx context.Context) (int64, error)
+	List(ctx context.Context, filter persistence.URLFilter) ([]*domain.URL, error)
+	Update(ctx context.Context, u *domain.URL) error
+	UpdateOpenGraph(ctx context.Context, id int64, original string, og *domain.OpenGraph) error
+	IncrementClicks(ctx context.Context, alias string) (int64, error)
+	SoftDelete(ctx context.Context, alias string, deletedAt time.Time) (*domain.URL, error)
+	Restore(ctx context.Context, alias string) (*domain.URL, error)
+	Purge(ctx context.Context, deletedBefore time.Time) (int64, error)
+	Rename(ctx context.Context, alias string, u *domain.URL, retiredAt time.Time) (*domain.URL, error)
+	FindRetiredAlias(ctx context.Context, alias string) (*domain.RetiredAlias, error)
+	ListRetiredAliases(ctx context.Context, urlID int64) ([]*domain.RetiredAlias, error)
 }
 
 type AliasProvider interface {
 	Generate(ctx context.Context, original string) (string, error)
 }
 
+// MetadataQueue schedules fetching preview metadata of created URLs.
+type MetadataQueue interface {
+	Enqueue(id int64, original string) bool
+}
+
+// AuditLog records the changes to URLs.
+type AuditLog interface {
+	Append(ctx context.Context, e *domain.AuditEntry) (int64, error)
+	ListByURL(ctx context.Context, urlID int64) ([]*domain.AuditEntry, error)
+}
+
+// Notifier notifies about URL events such as domain.EventURLCreated.
+type Notifier interface {
+	Notify(ctx context.Context, event string, u *domain.URL) error
+}
+
 type Service struct {
-	urls    Repository
-	aliases AliasProvider
+	urls     Repository
+	aliases  AliasProvider
+	metadata MetadataQueue
+	notifier Notifier
+	auditLog AuditLog
+
+	// aliasProviders are the providers requests may choose by name
+	aliasProviders map[string]AliasProvider
+
+	// aliasProvider and keyspace describe the default alias provider
+	aliasProvider string
+	keyspace      *big.Int
 
+	// quarantine is how long retired aliases can't be reused
+	quarantine time.Duration
+	// redirectRetired makes Resolve redirect retired aliases to the current ones
+	redirectRetired bool
+	// caseInsensitive makes aliases differing only in case the same alias
+	caseInsensitive bool
+
+	// trashRetention is how long deleted URLs can be restored
+	trashRetention time.Duration
+
+	clickThresholds []int64
+
+	now func() time.Time
 	log *slog.Logger
 }
 
-func NewService(urls Repository, aliases AliasProvider, log *slog.Logger) *Service {
-	return &Service{
+func NewService(urls Repository, aliases AliasProvider, log *slog.Logger, opts ...Option) *Service {
+	s := &Service{
 		urls:    urls,
 		aliases: aliases,
 
+		now: time.Now,
 		log: log,
 	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	return s
 }
 
 // Create creates new URL
 func (s *Service) Create(ctx context.Context, req *dto.CreateURLRequest) (*dto.CreateURLResponse, error) {
-	alias, err := s.aliases.Generate(ctx, req.Original)
-	if err != nil {
-		return nil, ErrAliasGenerationFailed
+	aliases := s.aliases
+	if req.AliasProvider != "" {
+		var ok bool
+
+		aliases, ok = s.aliasProviders[req.AliasProvider]
+		if !ok {
+			return nil, ErrUnknownAliasProvider
+		}
+	}
+
+	queryMode := req.QueryMode
+	if queryMode == "" {
+		queryMode = domain.QueryModeIgnore
 	}
 
 	u := &domain.URL{
-		Original: req.Original,
-		Alias:    alias,
+		Original:    req.Original,
+		QueryMode:   queryMode,
+		Preview:     req.Preview,
+		Title:       req.Title,
+		Description: req.Description,
+		Tags:        normalizeTags(req.Tags),
+		Metadata:    req.Metadata,
+		CreatedAt:   s.now(),
+	}
+
+	id, err := s.add(ctx, aliases, u)
+	if err != nil {
+		return nil, err
 	}
 
-	var id int64
+	u.ID = id
+
+	s.enqueueMetadata(u)
+	s.notify(ctx, domain.EventURLCreated, u)
+	s.record(ctx, domain.AuditActionCreated, u.ID, nil, u)
 
-	for {
-		id, err = s.urls.Add(ctx, u)
+	return newCreateURLResponse(u), nil
+}
+
+// add stores u under a generated alias generating another one
+// when the alias is taken, up to MaxAliasAttempts times.
+func (s *Service) add(ctx context.Context, aliases AliasProvider, u *domain.URL) (int64, error) {
+	for range MaxAliasAttempts {
+		alias, err := aliases.Generate(ctx, u.Original)
+		if err != nil {
+			s.log.Error("failed to generate alias", slog.String("error", err.Error()))
+
+			return 0, ErrAliasGenerationFailed
+		}
+
+		quarantined, err := s.quarantined(ctx, alias, 0)
+		if err != nil {
+			return 0, err
+		}
+
+		if quarantined {
+			continue
+		}
+
+		u.Alias = alias
+
+		id, err := s.urls.Add(ctx, u)
 		if err != nil {
 			if errors.Is(err, persistence.ErrDuplicateAlias) {
 				continue
 			} else if errors.Is(err, persistence.ErrURLAlreadyExists) {
-				return nil, ErrAlreadyExists
+				return 0, ErrAlreadyExists
 			}
 
-			return nil, fmt.Errorf("failed to create URL: %w", err)
+			return 0, fmt.Errorf("failed to create URL: %w", err)
 		}
 
-		break
+		return id, nil
 	}
 
-	u.ID = id
+	s.log.Warn("all generated aliases are taken", slog.Int("attempts", MaxAliasAttempts))
 
-	return &dto.CreateURLResponse{
-		ID:       u.ID,
-		Original: u.Original,
-		Alias:    u.Alias,
-	}, nil
+	return 0, ErrAliasGenerationFailed
 }
 
-// GetByAlias gets URL by its alias
+// GetByAlias gets URL by its current or retired alias
 func (s *Service) GetByAlias(ctx context.Context, req *dto.GetURLByAliasRequest) (*dto.GetURLByAliasResponse, error) {
+	u, err := s.findLive(ctx, req.Alias)
+	if err != nil {
+		return nil, err
+	}
+
+	return newGetURLByAliasResponse(u), nil
+}
+
+// List lists URLs matching the tag and metadata filters
+func (s *Service) List(ctx context.Context, req *dto.ListURLsRequest) (*dto.ListURLsResponse, error) {
+	limit := req.Limit
+	if limit <= 0 {
+		limit = DefaultListLimit
+	}
+
+	limit = min(limit, MaxListLimit)
+
+	urls, err := s.urls.List(ctx, persistence.URLFilter{
+		Tag:      normalizeTag(req.Tag),
+		Metadata: req.Metadata,
+		Limit:    limit,
+		Offset:   max(req.Offset, 0),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list URLs: %w", err)
+	}
+
+	resp := &dto.ListURLsResponse{
+		URLs: make([]*dto.GetURLByAliasResponse, 0, len(urls)),
+	}
+
+	for _, u := range urls {
+		resp.URLs = append(resp.URLs, newGetURLByAliasResponse(u))
+	}
+
+	return resp, nil
+}
+
+// Update changes URL fields present in the request
+func (s *Service) Update(ctx context.Context, req *dto.UpdateURLRequest) (*dto.GetURLByAliasResponse, error) {
+	return s.update(ctx, req, domain.AuditActionUpdated)
+}
+
+// update changes URL fields present in the request recording the change as action.
+func (s *Service) update(ctx context.Context, req *dto.UpdateURLRequest, action string) (*dto.GetURLByAliasResponse, error) {
 	u, err := s.urls.FindByAlias(ctx, req.Alias)
 	if err != nil {
 		if errors.Is(err, persistence.ErrURLNotFound) {
@@ -85,8 +247,248 @@ func (s *Service) GetByAlias(ctx context.Context, req *dto.GetURLByAliasRequest)
 		return nil, fmt.Errorf("failed to get URL by alias: %w", err)
 	}
 
-	return &dto.GetURLByAliasResponse{
-		Original: u.Original,
-		Alias:    u.Alias,
+	if u.DeletedAt != nil {
+		return nil, ErrDeleted
+	}
+
+	updated := *u
+	originalChanged := req.Original != nil && *req.Original != u.Original
+
+	if req.Original != nil {
+		updated.Original = *req.Original
+	}
+
+	if req.QueryMode != nil {
+		updated.QueryMode = *req.QueryMode
+	}
+
+	if req.Preview != nil {
+		updated.Preview = *req.Preview
+	}
+
+	if req.Title != nil {
+		updated.Title = *req.Title
+	}
+
+	if req.Description != nil {
+		updated.Description = *req.Description
+	}
+
+	if req.Tags != nil {
+		updated.Tags = normalizeTags(*req.Tags)
+	}
+
+	if req.Metadata != nil {
+		updated.Metadata = maps.Clone(*req.Metadata)
+	}
+
+	if originalChanged {
+		updated.OpenGraph = nil
+	}
+
+	// the alias is changed along with the fields so a failed update leaves the URL as it was
+	if req.NewAlias != nil && *req.NewAlias != u.Alias {
+		updated.Alias = *req.NewAlias
+
+		err = s.rename(ctx, u, &updated)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		err = s.urls.Update(ctx, &updated)
+		if err != nil {
+			if errors.Is(err, persistence.ErrURLNotFound) {
+				return nil, ErrNotFound
+			} else if errors.Is(err, persistence.ErrURLAlreadyExists) {
+				return nil, ErrAlreadyExists
+			}
+
+			return nil, fmt.Errorf("failed to update URL: %w", err)
+		}
+	}
+
+	if originalChanged {
+		s.enqueueMetadata(&updated)
+	}
+
+	s.notify(ctx, domain.EventURLUpdated, &updated)
+	s.record(ctx, action, u.ID, u, &updated)
+
+	return newGetURLByAliasResponse(&updated), nil
+}
+
+// Delete moves URL to the trash by its alias.
+// It can be restored until the trash retention period passes.
+func (s *Service) Delete(ctx context.Context, req *dto.DeleteURLRequest) error {
+	u, err := s.urls.FindByAlias(ctx, req.Alias)
+	if err != nil {
+		if errors.Is(err, persistence.ErrURLNotFound) {
+			return ErrNotFound
+		}
+
+		return fmt.Errorf("failed to get URL by alias: %w", err)
+	}
+
+	if u.DeletedAt != nil {
+		return ErrDeleted
+	}
+
+	u, err = s.urls.SoftDelete(ctx, u.Alias, s.now())
+	if err != nil {
+		if errors.Is(err, persistence.ErrURLNotFound) {
+			return ErrNotFound
+		}
+
+		return fmt.Errorf("failed to delete URL: %w", err)
+	}
+
+	s.notify(ctx, domain.EventURLDeleted, u)
+	s.record(ctx, domain.AuditActionDeleted, u.ID, u, nil)
+
+	return nil
+}
+
+// Resolve resolves URL alias to the location the client should be redirected to
+func (s *Service) Resolve(ctx context.Context, req *dto.ResolveURLRequest) (*dto.ResolveURLResponse, error) {
+	u, retired, err := s.find(ctx, req.Alias)
+	if err != nil {
+		return nil, err
+	}
+
+	if u.DeletedAt != nil {
+		return nil, ErrDeleted
+	}
+
+	if retired && s.redirectRetired {
+		// the click is counted when the client follows the redirect
+		return &dto.ResolveURLResponse{
+			Alias:     u.Alias,
+			Preview:   u.Preview,
+			CreatedAt: u.CreatedAt,
+			Moved:     true,
+		}, nil
+	}
+
+	location, err := destination(u, req.Path, req.Query)
+	if err != nil {
+		return nil, err
+	}
+
+	s.countClick(ctx, u)
+
+	return &dto.ResolveURLResponse{
+		Location:  location,
+		Alias:     u.Alias,
+		Preview:   u.Preview,
+		CreatedAt: u.CreatedAt,
 	}, nil
 }
+
+// countClick increments the URL clicks notifying when a threshold is reached.
+// Failing to count a click doesn't fail the redirect.
+func (s *Service) countClick(ctx context.Context, u *domain.URL) {
+	clicks, err := s.urls.IncrementClicks(ctx, u.Alias)
+	if err != nil {
+		s.log.Error("failed to count click", slog.String("alias", u.Alias), slog.String("error", err.Error()))
+		return
+	}
+
+	u.Clicks = clicks
+
+	if slices.Contains(s.clickThresholds, clicks) {
+		s.notify(ctx, domain.EventURLClickThreshold, u)
+	}
+}
+
+// notify notifies about the event. Notification failures are logged
+// as the URL change they are about has already happened.
+func (s *Service) notify(ctx context.Context, event string, u *domain.URL) {
+	if s.notifier == nil {
+		return
+	}
+
+	err := s.notifier.Notify(ctx, event, u)
+	if err != nil {
+		s.log.Error("failed to notify",
+			slog.String("event", event),
+			slog.String("alias", u.Alias),
+			slog.String("error", err.Error()),
+		)
+	}
+}
+
+func (s *Service) enqueueMetadata(u *domain.URL) {
+	if s.metadata == nil {
+		return
+	}
+
+	s.metadata.Enqueue(u.ID, u.Original)
+}
+
+func newCreateURLResponse(u *domain.URL) *dto.CreateURLResponse {
+	return &dto.CreateURLResponse{
+		ID:          u.ID,
+		Original:    u.Original,
+		Alias:       u.Alias,
+		QueryMode:   u.QueryMode,
+		Preview:     u.Preview,
+		Title:       u.Title,
+		Description: u.Description,
+		Tags:        u.Tags,
+		Metadata:    u.Metadata,
+		CreatedAt:   u.CreatedAt,
+	}
+}
+
+func newGetURLByAliasResponse(u *domain.URL) *dto.GetURLByAliasResponse {
+	return &dto.GetURLByAliasResponse{
+		Original:    u.Original,
+		Alias:       u.Alias,
+		QueryMode:   u.QueryMode,
+		Preview:     u.Preview,
+		Title:       u.Title,
+		Description: u.Description,
+		Tags:        u.Tags,
+		Metadata:    u.Metadata,
+		Clicks:      u.Clicks,
+		CreatedAt:   u.CreatedAt,
+		OpenGraph:   newOpenGraphResponse(u.OpenGraph),
+	}
+}
+
+func newOpenGraphResponse(og *domain.OpenGraph) *dto.OpenGraphResponse {
+	if og == nil {
+		return nil
+	}
+
+	return &dto.OpenGraphResponse{
+		Title:       og.Title,
+		Description: og.Description,
+		Image:       og.Image,
+		FetchedAt:   og.FetchedAt,
+	}
+}
+
+// normalizeTags lowercases tags dropping empty and repeated ones.
+func normalizeTags(tags []string) []string {
+	if len(tags) == 0 {
+		return nil
+	}
+
+	res := make([]string, 0, len(tags))
+
+	for _, tag := range tags {
+		tag = normalizeTag(tag)
+		if tag == "" || slices.Contains(res, tag) {
+			continue
+		}
+
+		res = append(res, tag)
+	}
+
+	return res
+}
+
+func normalizeTag(tag string) string {
+	return strings.ToLower(strings.TrimSpace(tag))
+}
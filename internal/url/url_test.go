@@ -6,18 +6,28 @@ import (
 	"fmt"
 	"io"
 	"log/slog"
+	"math"
+	"math/big"
+	neturl "net/url"
 	"testing"
+	"time"
 
+	"github.com/kodeyeen/shortify/internal/audit"
 	"github.com/kodeyeen/shortify/internal/domain"
 	"github.com/kodeyeen/shortify/internal/dto"
 	mockgen "github.com/kodeyeen/shortify/internal/generation/mock"
+	mockog "github.com/kodeyeen/shortify/internal/opengraph/mock"
 	"github.com/kodeyeen/shortify/internal/persistence"
 	mockpers "github.com/kodeyeen/shortify/internal/persistence/mock"
 	"github.com/kodeyeen/shortify/internal/url"
+	mockwh "github.com/kodeyeen/shortify/internal/webhook/mock"
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 )
 
 func TestService_Create(t *testing.T) {
+	createdAt := time.Date(2025, time.March, 1, 12, 0, 0, 0, time.UTC)
+
 	type Given struct {
 		req *dto.CreateURLRequest
 
@@ -46,8 +56,10 @@ func TestService_Create(t *testing.T) {
 				},
 
 				url: &domain.URL{
-					Original: "https://example.com/longlonglonglonglonglonglonglong",
-					Alias:    "randomstri",
+					Original:  "https://example.com/longlonglonglonglonglonglonglong",
+					Alias:     "randomstri",
+					QueryMode: domain.QueryModeIgnore,
+					CreatedAt: createdAt,
 				},
 
 				urlID:  1,
@@ -58,9 +70,11 @@ func TestService_Create(t *testing.T) {
 			},
 			Expected{
 				svcResp: &dto.CreateURLResponse{
-					ID:       1,
-					Original: "https://example.com/longlonglonglonglonglonglonglong",
-					Alias:    "randomstri",
+					ID:        1,
+					Original:  "https://example.com/longlonglonglonglonglonglonglong",
+					Alias:     "randomstri",
+					QueryMode: domain.QueryModeIgnore,
+					CreatedAt: createdAt,
 				},
 				svcErr: nil,
 			},
@@ -72,8 +86,10 @@ func TestService_Create(t *testing.T) {
 				},
 
 				url: &domain.URL{
-					Original: "https://example.com/longlonglonglonglonglonglonglong",
-					Alias:    "randomstri",
+					Original:  "https://example.com/longlonglonglonglonglonglonglong",
+					Alias:     "randomstri",
+					QueryMode: domain.QueryModeIgnore,
+					CreatedAt: createdAt,
 				},
 
 				urlID:  1,
@@ -94,8 +110,9 @@ func TestService_Create(t *testing.T) {
 				},
 
 				url: &domain.URL{
-					Original: "https://example.com/longlonglonglonglonglonglonglong",
-					Alias:    "",
+					Original:  "https://example.com/longlonglonglonglonglonglonglong",
+					Alias:     "",
+					CreatedAt: createdAt,
 				},
 
 				urlID:  0,
@@ -134,7 +151,9 @@ func TestService_Create(t *testing.T) {
 
 			log := slog.New(slog.NewTextHandler(io.Discard, nil))
 
-			svc := url.NewService(urls, aliases, log)
+			svc := url.NewService(urls, aliases, log, url.WithClock(func() time.Time {
+				return createdAt
+			}))
 
 			// When
 			resp, err := svc.Create(ctx, tc.given.req)
@@ -146,6 +165,184 @@ func TestService_Create(t *testing.T) {
 	}
 }
 
+func TestService_Create_DuplicateAlias(t *testing.T) {
+	testCases := map[string]struct {
+		duplicates int
+
+		expectedAlias string
+		expectedErr   error
+	}{
+		"Taken alias is regenerated": {
+			duplicates:    2,
+			expectedAlias: "alias00003",
+		},
+		"All attempts taken": {
+			duplicates:  url.MaxAliasAttempts,
+			expectedErr: url.ErrAliasGenerationFailed,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			// Given
+			ctx := context.Background()
+			original := "https://example.com/long"
+
+			aliases := mockgen.NewAliasProvider(t)
+			urls := mockpers.NewURLRepository(t)
+
+			attempts := min(tc.duplicates+1, url.MaxAliasAttempts)
+
+			for i := range attempts {
+				alias := fmt.Sprintf("alias%05d", i+1)
+
+				aliases.On("Generate", ctx, original).
+					Return(alias, nil).
+					Once()
+
+				var err error
+				if i < tc.duplicates {
+					err = persistence.ErrDuplicateAlias
+				}
+
+				urls.On("Add", ctx, mock.MatchedBy(func(u *domain.URL) bool { return u.Alias == alias })).
+					Return(int64(1), err).
+					Once()
+			}
+
+			log := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+			svc := url.NewService(urls, aliases, log)
+
+			// When
+			resp, err := svc.Create(ctx, &dto.CreateURLRequest{Original: original})
+
+			// Then
+			require.ErrorIs(t, err, tc.expectedErr)
+
+			if tc.expectedErr == nil {
+				require.Equal(t, tc.expectedAlias, resp.Alias)
+			}
+		})
+	}
+}
+
+func TestService_Create_AliasProvider(t *testing.T) {
+	testCases := map[string]struct {
+		provider string
+
+		expectedAlias string
+		expectedErr   error
+	}{
+		"Default provider": {
+			provider:      "",
+			expectedAlias: "randomstri",
+		},
+		"Chosen provider": {
+			provider:      "words",
+			expectedAlias: "brave-orange-tiger",
+		},
+		"Unknown provider": {
+			provider:    "emoji",
+			expectedErr: url.ErrUnknownAliasProvider,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			// Given
+			ctx := context.Background()
+			original := "https://example.com/long"
+
+			defaultAliases := mockgen.NewAliasProvider(t)
+			wordAliases := mockgen.NewAliasProvider(t)
+			urls := mockpers.NewURLRepository(t)
+
+			if tc.expectedErr == nil {
+				aliases := defaultAliases
+				if tc.provider != "" {
+					aliases = wordAliases
+				}
+
+				aliases.On("Generate", ctx, original).
+					Return(tc.expectedAlias, nil).
+					Once()
+
+				urls.On("Add", ctx, mock.MatchedBy(func(u *domain.URL) bool { return u.Alias == tc.expectedAlias })).
+					Return(int64(1), nil).
+					Once()
+			}
+
+			log := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+			svc := url.NewService(urls, defaultAliases, log, url.WithAliasProviders(map[string]url.AliasProvider{
+				"words": wordAliases,
+			}))
+
+			// When
+			resp, err := svc.Create(ctx, &dto.CreateURLRequest{Original: original, AliasProvider: tc.provider})
+
+			// Then
+			require.ErrorIs(t, err, tc.expectedErr)
+
+			if tc.expectedErr == nil {
+				require.Equal(t, tc.expectedAlias, resp.Alias)
+			}
+		})
+	}
+}
+
+func TestService_Create_MetadataQueue(t *testing.T) {
+	testCases := map[string]struct {
+		original string
+	}{
+		"Plain URL": {
+			original: "https://example.com/long",
+		},
+		"Templated URL": {
+			original: "https://example.com/p/{path}",
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			// Given
+			ctx := context.Background()
+
+			aliases := mockgen.NewAliasProvider(t)
+			aliases.On("Generate", ctx, tc.original).
+				Return("randomstri", nil).
+				Once()
+
+			urls := mockpers.NewURLRepository(t)
+			urls.On("Add", ctx, mock.Anything).
+				Return(int64(1), nil).
+				Once()
+
+			queue := mockog.NewQueue(t)
+			queue.On("Enqueue", int64(1), tc.original).
+				Return(true).
+				Once()
+
+			log := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+			svc := url.NewService(urls, aliases, log, url.WithMetadataQueue(queue))
+
+			// When
+			_, err := svc.Create(ctx, &dto.CreateURLRequest{Original: tc.original})
+
+			// Then
+			require.NoError(t, err)
+		})
+	}
+}
+
 func TestService_GetByAlias(t *testing.T) {
 	type Given struct {
 		req *dto.GetURLByAliasRequest
@@ -184,6 +381,58 @@ func TestService_GetByAlias(t *testing.T) {
 				svcErr: nil,
 			},
 		},
+		"With Open Graph": {
+			Given{
+				req: &dto.GetURLByAliasRequest{
+					Alias: "fjda89fadb",
+				},
+
+				url: &domain.URL{
+					ID:       1,
+					Original: "https://example.com/longlonglonglonglonglonglonglong",
+					Alias:    "fjda89fadb",
+					OpenGraph: &domain.OpenGraph{
+						Title:       "Title",
+						Description: "Description",
+						Image:       "https://example.com/image.png",
+						FetchedAt:   time.Date(2025, time.March, 1, 12, 0, 0, 0, time.UTC),
+					},
+				},
+				urlErr: nil,
+			},
+			Expected{
+				svcResp: &dto.GetURLByAliasResponse{
+					Original: "https://example.com/longlonglonglonglonglonglonglong",
+					Alias:    "fjda89fadb",
+					OpenGraph: &dto.OpenGraphResponse{
+						Title:       "Title",
+						Description: "Description",
+						Image:       "https://example.com/image.png",
+						FetchedAt:   time.Date(2025, time.March, 1, 12, 0, 0, 0, time.UTC),
+					},
+				},
+				svcErr: nil,
+			},
+		},
+		"Deleted": {
+			Given{
+				req: &dto.GetURLByAliasRequest{
+					Alias: "fjda89fadb",
+				},
+
+				url: &domain.URL{
+					ID:        1,
+					Original:  "https://example.com/long",
+					Alias:     "fjda89fadb",
+					DeletedAt: ptr(time.Date(2025, time.March, 1, 12, 0, 0, 0, time.UTC)),
+				},
+				urlErr: nil,
+			},
+			Expected{
+				svcResp: nil,
+				svcErr:  url.ErrDeleted,
+			},
+		},
 		"Not found": {
 			Given{
 				req: &dto.GetURLByAliasRequest{
@@ -228,6 +477,12 @@ func TestService_GetByAlias(t *testing.T) {
 				Return(tc.given.url, tc.given.urlErr).
 				Once()
 
+			if errors.Is(tc.given.urlErr, persistence.ErrURLNotFound) {
+				urls.On("FindRetiredAlias", ctx, tc.given.req.Alias).
+					Return(nil, persistence.ErrAliasNotFound).
+					Once()
+			}
+
 			log := slog.New(slog.NewTextHandler(io.Discard, nil))
 
 			svc := url.NewService(urls, aliases, log)
@@ -241,3 +496,1940 @@ func TestService_GetByAlias(t *testing.T) {
 		})
 	}
 }
+
+func TestService_Resolve(t *testing.T) {
+	type Given struct {
+		req *dto.ResolveURLRequest
+
+		url    *domain.URL
+		urlErr error
+	}
+
+	type Expected struct {
+		svcResp *dto.ResolveURLResponse
+		svcErr  error
+	}
+
+	testCases := map[string]struct {
+		given    Given
+		expected Expected
+	}{
+		"Success": {
+			Given{
+				req: &dto.ResolveURLRequest{
+					Alias: "fjda89fadb",
+				},
+
+				url: &domain.URL{
+					ID:        1,
+					Original:  "https://example.com/long?a=1",
+					Alias:     "fjda89fadb",
+					QueryMode: domain.QueryModeIgnore,
+				},
+				urlErr: nil,
+			},
+			Expected{
+				svcResp: &dto.ResolveURLResponse{
+					Location: "https://example.com/long?a=1",
+					Alias:    "fjda89fadb",
+				},
+				svcErr: nil,
+			},
+		},
+		"Query ignored": {
+			Given{
+				req: &dto.ResolveURLRequest{
+					Alias: "fjda89fadb",
+					Query: neturl.Values{"ref": {"twitter"}},
+				},
+
+				url: &domain.URL{
+					ID:        1,
+					Original:  "https://example.com/long?a=1",
+					Alias:     "fjda89fadb",
+					QueryMode: domain.QueryModeIgnore,
+				},
+				urlErr: nil,
+			},
+			Expected{
+				svcResp: &dto.ResolveURLResponse{
+					Location: "https://example.com/long?a=1",
+					Alias:    "fjda89fadb",
+				},
+				svcErr: nil,
+			},
+		},
+		"Query merged": {
+			Given{
+				req: &dto.ResolveURLRequest{
+					Alias: "fjda89fadb",
+					Query: neturl.Values{"ref": {"twitter"}, "a": {"2"}},
+				},
+
+				url: &domain.URL{
+					ID:        1,
+					Original:  "https://example.com/long?a=1",
+					Alias:     "fjda89fadb",
+					QueryMode: domain.QueryModeMerge,
+				},
+				urlErr: nil,
+			},
+			Expected{
+				svcResp: &dto.ResolveURLResponse{
+					Location: "https://example.com/long?a=1&ref=twitter",
+					Alias:    "fjda89fadb",
+				},
+				svcErr: nil,
+			},
+		},
+		"Query overridden": {
+			Given{
+				req: &dto.ResolveURLRequest{
+					Alias: "fjda89fadb",
+					Query: neturl.Values{"ref": {"twitter"}, "a": {"2"}},
+				},
+
+				url: &domain.URL{
+					ID:        1,
+					Original:  "https://example.com/long?a=1",
+					Alias:     "fjda89fadb",
+					QueryMode: domain.QueryModeOverride,
+				},
+				urlErr: nil,
+			},
+			Expected{
+				svcResp: &dto.ResolveURLResponse{
+					Location: "https://example.com/long?a=2&ref=twitter",
+					Alias:    "fjda89fadb",
+				},
+				svcErr: nil,
+			},
+		},
+		"Path substituted": {
+			Given{
+				req: &dto.ResolveURLRequest{
+					Alias: "fjda89fadb",
+					Path:  "products/42",
+				},
+
+				url: &domain.URL{
+					ID:        1,
+					Original:  "https://example.com/p/{path}",
+					Alias:     "fjda89fadb",
+					QueryMode: domain.QueryModeIgnore,
+				},
+				urlErr: nil,
+			},
+			Expected{
+				svcResp: &dto.ResolveURLResponse{
+					Location: "https://example.com/p/products/42",
+					Alias:    "fjda89fadb",
+				},
+				svcErr: nil,
+			},
+		},
+		"Path without template": {
+			Given{
+				req: &dto.ResolveURLRequest{
+					Alias: "fjda89fadb",
+					Path:  "products/42",
+				},
+
+				url: &domain.URL{
+					ID:        1,
+					Original:  "https://example.com/long",
+					Alias:     "fjda89fadb",
+					QueryMode: domain.QueryModeIgnore,
+				},
+				urlErr: nil,
+			},
+			Expected{
+				svcResp: nil,
+				svcErr:  url.ErrNotFound,
+			},
+		},
+		"Preview": {
+			Given{
+				req: &dto.ResolveURLRequest{
+					Alias: "fjda89fadb",
+				},
+
+				url: &domain.URL{
+					ID:        1,
+					Original:  "https://example.com/long",
+					Alias:     "fjda89fadb",
+					QueryMode: domain.QueryModeIgnore,
+					Preview:   true,
+					CreatedAt: time.Date(2025, time.March, 1, 12, 0, 0, 0, time.UTC),
+				},
+				urlErr: nil,
+			},
+			Expected{
+				svcResp: &dto.ResolveURLResponse{
+					Location:  "https://example.com/long",
+					Alias:     "fjda89fadb",
+					Preview:   true,
+					CreatedAt: time.Date(2025, time.March, 1, 12, 0, 0, 0, time.UTC),
+				},
+				svcErr: nil,
+			},
+		},
+		"Deleted": {
+			Given{
+				req: &dto.ResolveURLRequest{
+					Alias: "fjda89fadb",
+				},
+
+				url: &domain.URL{
+					ID:        1,
+					Original:  "https://example.com/long",
+					Alias:     "fjda89fadb",
+					DeletedAt: ptr(time.Date(2025, time.March, 1, 12, 0, 0, 0, time.UTC)),
+				},
+				urlErr: nil,
+			},
+			Expected{
+				svcResp: nil,
+				svcErr:  url.ErrDeleted,
+			},
+		},
+		"Not found": {
+			Given{
+				req: &dto.ResolveURLRequest{
+					Alias: "fjda89fadb",
+				},
+
+				url:    nil,
+				urlErr: persistence.ErrURLNotFound,
+			},
+			Expected{
+				svcResp: nil,
+				svcErr:  url.ErrNotFound,
+			},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			// Given
+			ctx := context.Background()
+
+			aliases := mockgen.NewAliasProvider(t)
+
+			urls := mockpers.NewURLRepository(t)
+			urls.On("FindByAlias", ctx, tc.given.req.Alias).
+				Return(tc.given.url, tc.given.urlErr).
+				Once()
+
+			if errors.Is(tc.given.urlErr, persistence.ErrURLNotFound) {
+				urls.On("FindRetiredAlias", ctx, tc.given.req.Alias).
+					Return(nil, persistence.ErrAliasNotFound).
+					Once()
+			}
+
+			if tc.expected.svcResp != nil {
+				urls.On("IncrementClicks", ctx, tc.given.req.Alias).
+					Return(int64(1), nil).
+					Once()
+			}
+
+			log := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+			svc := url.NewService(urls, aliases, log)
+
+			// When
+			resp, err := svc.Resolve(ctx, tc.given.req)
+
+			// Then
+			require.Equal(t, tc.expected.svcResp, resp)
+			require.ErrorIs(t, err, tc.expected.svcErr)
+		})
+	}
+}
+
+func TestService_Resolve_ClickThreshold(t *testing.T) {
+	testCases := map[string]struct {
+		clicks   int64
+		notified bool
+	}{
+		"Threshold reached": {
+			clicks:   100,
+			notified: true,
+		},
+		"Threshold not reached": {
+			clicks:   99,
+			notified: false,
+		},
+		"Threshold passed": {
+			clicks:   101,
+			notified: false,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			// Given
+			ctx := context.Background()
+
+			u := &domain.URL{
+				ID:        1,
+				Original:  "https://example.com/long",
+				Alias:     "fjda89fadb",
+				QueryMode: domain.QueryModeIgnore,
+			}
+
+			aliases := mockgen.NewAliasProvider(t)
+
+			urls := mockpers.NewURLRepository(t)
+			urls.On("FindByAlias", ctx, u.Alias).
+				Return(u, nil).
+				Once()
+			urls.On("IncrementClicks", ctx, u.Alias).
+				Return(tc.clicks, nil).
+				Once()
+
+			notifier := mockwh.NewNotifier(t)
+			if tc.notified {
+				notifier.On("Notify", ctx, domain.EventURLClickThreshold, mock.MatchedBy(func(u *domain.URL) bool {
+					return u.Clicks == tc.clicks
+				})).
+					Return(nil).
+					Once()
+			}
+
+			log := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+			svc := url.NewService(urls, aliases, log,
+				url.WithNotifier(notifier),
+				url.WithClickThresholds([]int64{100, 1000}),
+			)
+
+			// When
+			_, err := svc.Resolve(ctx, &dto.ResolveURLRequest{Alias: u.Alias})
+
+			// Then
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestService_List(t *testing.T) {
+	type Given struct {
+		req *dto.ListURLsRequest
+
+		filter  persistence.URLFilter
+		urls    []*domain.URL
+		urlsErr error
+	}
+
+	type Expected struct {
+		svcResp *dto.ListURLsResponse
+		svcErr  error
+	}
+
+	testCases := map[string]struct {
+		given    Given
+		expected Expected
+	}{
+		"Success": {
+			Given{
+				req: &dto.ListURLsRequest{
+					Tag:      " Podcast ",
+					Metadata: map[string]string{"campaign": "spring"},
+					Limit:    10,
+					Offset:   20,
+				},
+
+				filter: persistence.URLFilter{
+					Tag:      "podcast",
+					Metadata: map[string]string{"campaign": "spring"},
+					Limit:    10,
+					Offset:   20,
+				},
+				urls: []*domain.URL{
+					{
+						ID:       1,
+						Original: "https://example.com/1",
+						Alias:    "fjda89fadb",
+						Tags:     []string{"podcast"},
+						Metadata: map[string]string{"campaign": "spring"},
+					},
+				},
+				urlsErr: nil,
+			},
+			Expected{
+				svcResp: &dto.ListURLsResponse{
+					URLs: []*dto.GetURLByAliasResponse{
+						{
+							Original: "https://example.com/1",
+							Alias:    "fjda89fadb",
+							Tags:     []string{"podcast"},
+							Metadata: map[string]string{"campaign": "spring"},
+						},
+					},
+				},
+				svcErr: nil,
+			},
+		},
+		"Default limit": {
+			Given{
+				req: &dto.ListURLsRequest{},
+
+				filter: persistence.URLFilter{
+					Limit: url.DefaultListLimit,
+				},
+				urls:    []*domain.URL{},
+				urlsErr: nil,
+			},
+			Expected{
+				svcResp: &dto.ListURLsResponse{
+					URLs: []*dto.GetURLByAliasResponse{},
+				},
+				svcErr: nil,
+			},
+		},
+		"Limit capped": {
+			Given{
+				req: &dto.ListURLsRequest{
+					Limit: url.MaxListLimit + 1,
+				},
+
+				filter: persistence.URLFilter{
+					Limit: url.MaxListLimit,
+				},
+				urls:    []*domain.URL{},
+				urlsErr: nil,
+			},
+			Expected{
+				svcResp: &dto.ListURLsResponse{
+					URLs: []*dto.GetURLByAliasResponse{},
+				},
+				svcErr: nil,
+			},
+		},
+		"Other error": {
+			Given{
+				req: &dto.ListURLsRequest{},
+
+				filter: persistence.URLFilter{
+					Limit: url.DefaultListLimit,
+				},
+				urls:    nil,
+				urlsErr: errors.New("some retrieval error"),
+			},
+			Expected{
+				svcResp: nil,
+				svcErr:  fmt.Errorf("failed to list URLs: %w", errors.New("some retrieval error")),
+			},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			// Given
+			ctx := context.Background()
+
+			aliases := mockgen.NewAliasProvider(t)
+
+			urls := mockpers.NewURLRepository(t)
+			urls.On("List", ctx, tc.given.filter).
+				Return(tc.given.urls, tc.given.urlsErr).
+				Once()
+
+			log := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+			svc := url.NewService(urls, aliases, log)
+
+			// When
+			resp, err := svc.List(ctx, tc.given.req)
+
+			// Then
+			require.Equal(t, tc.expected.svcResp, resp)
+			require.Equal(t, tc.expected.svcErr, err)
+		})
+	}
+}
+
+func TestService_Update(t *testing.T) {
+	type Given struct {
+		req *dto.UpdateURLRequest
+
+		found    *domain.URL
+		findErr  error
+		updated  *domain.URL
+		updErr   error
+		enqueued string
+	}
+
+	type Expected struct {
+		svcResp *dto.GetURLByAliasResponse
+		svcErr  error
+	}
+
+	found := &domain.URL{
+		ID:        1,
+		Original:  "https://example.com/old",
+		Alias:     "fjda89fadb",
+		QueryMode: domain.QueryModeIgnore,
+		Title:     "Old title",
+		Tags:      []string{"old"},
+		Metadata:  map[string]string{"campaign": "spring"},
+		OpenGraph: &domain.OpenGraph{Title: "Old page"},
+	}
+
+	testCases := map[string]struct {
+		given    Given
+		expected Expected
+	}{
+		"Metadata edited": {
+			Given{
+				req: &dto.UpdateURLRequest{
+					Alias:    "fjda89fadb",
+					Title:    ptr("New title"),
+					Tags:     &[]string{"New", "podcast", "new"},
+					Metadata: &map[string]string{"campaign": "autumn"},
+				},
+
+				found:   found,
+				findErr: nil,
+				updated: &domain.URL{
+					ID:        1,
+					Original:  "https://example.com/old",
+					Alias:     "fjda89fadb",
+					QueryMode: domain.QueryModeIgnore,
+					Title:     "New title",
+					Tags:      []string{"new", "podcast"},
+					Metadata:  map[string]string{"campaign": "autumn"},
+					OpenGraph: &domain.OpenGraph{Title: "Old page"},
+				},
+				updErr: nil,
+			},
+			Expected{
+				svcResp: &dto.GetURLByAliasResponse{
+					Original:  "https://example.com/old",
+					Alias:     "fjda89fadb",
+					QueryMode: domain.QueryModeIgnore,
+					Title:     "New title",
+					Tags:      []string{"new", "podcast"},
+					Metadata:  map[string]string{"campaign": "autumn"},
+					OpenGraph: &dto.OpenGraphResponse{Title: "Old page"},
+				},
+				svcErr: nil,
+			},
+		},
+		"Destination changed": {
+			Given{
+				req: &dto.UpdateURLRequest{
+					Alias:    "fjda89fadb",
+					Original: ptr("https://example.com/new"),
+				},
+
+				found:   found,
+				findErr: nil,
+				updated: &domain.URL{
+					ID:        1,
+					Original:  "https://example.com/new",
+					Alias:     "fjda89fadb",
+					QueryMode: domain.QueryModeIgnore,
+					Title:     "Old title",
+					Tags:      []string{"old"},
+					Metadata:  map[string]string{"campaign": "spring"},
+				},
+				updErr:   nil,
+				enqueued: "https://example.com/new",
+			},
+			Expected{
+				svcResp: &dto.GetURLByAliasResponse{
+					Original:  "https://example.com/new",
+					Alias:     "fjda89fadb",
+					QueryMode: domain.QueryModeIgnore,
+					Title:     "Old title",
+					Tags:      []string{"old"},
+					Metadata:  map[string]string{"campaign": "spring"},
+				},
+				svcErr: nil,
+			},
+		},
+		"URL already exists": {
+			Given{
+				req: &dto.UpdateURLRequest{
+					Alias:    "fjda89fadb",
+					Original: ptr("https://example.com/taken"),
+				},
+
+				found:   found,
+				findErr: nil,
+				updated: &domain.URL{
+					ID:        1,
+					Original:  "https://example.com/taken",
+					Alias:     "fjda89fadb",
+					QueryMode: domain.QueryModeIgnore,
+					Title:     "Old title",
+					Tags:      []string{"old"},
+					Metadata:  map[string]string{"campaign": "spring"},
+				},
+				updErr: persistence.ErrURLAlreadyExists,
+			},
+			Expected{
+				svcResp: nil,
+				svcErr:  url.ErrAlreadyExists,
+			},
+		},
+		"Not found": {
+			Given{
+				req: &dto.UpdateURLRequest{
+					Alias: "fjda89fadb",
+					Title: ptr("New title"),
+				},
+
+				found:   nil,
+				findErr: persistence.ErrURLNotFound,
+			},
+			Expected{
+				svcResp: nil,
+				svcErr:  url.ErrNotFound,
+			},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			// Given
+			ctx := context.Background()
+
+			aliases := mockgen.NewAliasProvider(t)
+
+			urls := mockpers.NewURLRepository(t)
+			urls.On("FindByAlias", ctx, tc.given.req.Alias).
+				Return(tc.given.found, tc.given.findErr).
+				Once()
+
+			if tc.given.updated != nil {
+				urls.On("Update", ctx, tc.given.updated).
+					Return(tc.given.updErr).
+					Once()
+			}
+
+			queue := mockog.NewQueue(t)
+
+			if tc.given.enqueued != "" {
+				queue.On("Enqueue", tc.given.found.ID, tc.given.enqueued).
+					Return(true).
+					Once()
+			}
+
+			log := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+			svc := url.NewService(urls, aliases, log, url.WithMetadataQueue(queue))
+
+			// When
+			resp, err := svc.Update(ctx, tc.given.req)
+
+			// Then
+			require.Equal(t, tc.expected.svcResp, resp)
+			require.ErrorIs(t, err, tc.expected.svcErr)
+		})
+	}
+}
+
+func TestService_Delete(t *testing.T) {
+	now := time.Date(2025, time.March, 1, 12, 0, 0, 0, time.UTC)
+
+	type Given struct {
+		req *dto.DeleteURLRequest
+
+		url    *domain.URL
+		urlErr error
+
+		deleteErr error
+	}
+
+	type Expected struct {
+		svcErr error
+	}
+
+	testCases := map[string]struct {
+		given    Given
+		expected Expected
+	}{
+		"Success": {
+			Given{
+				req: &dto.DeleteURLRequest{
+					Alias: "fjda89fadb",
+				},
+
+				url: &domain.URL{
+					ID:       1,
+					Original: "https://example.com/long",
+					Alias:    "fjda89fadb",
+				},
+				urlErr: nil,
+			},
+			Expected{
+				svcErr: nil,
+			},
+		},
+		"Not found": {
+			Given{
+				req: &dto.DeleteURLRequest{
+					Alias: "fjda89fadb",
+				},
+
+				urlErr: persistence.ErrURLNotFound,
+			},
+			Expected{
+				svcErr: url.ErrNotFound,
+			},
+		},
+		"Already deleted": {
+			Given{
+				req: &dto.DeleteURLRequest{
+					Alias: "fjda89fadb",
+				},
+
+				url: &domain.URL{
+					ID:        1,
+					Original:  "https://example.com/long",
+					Alias:     "fjda89fadb",
+					DeletedAt: ptr(now.Add(-time.Hour)),
+				},
+			},
+			Expected{
+				svcErr: url.ErrDeleted,
+			},
+		},
+		"Other error": {
+			Given{
+				req: &dto.DeleteURLRequest{
+					Alias: "fjda89fadb",
+				},
+
+				url: &domain.URL{
+					ID:       1,
+					Original: "https://example.com/long",
+					Alias:    "fjda89fadb",
+				},
+				deleteErr: errors.New("some deletion error"),
+			},
+			Expected{
+				svcErr: fmt.Errorf("failed to delete URL: %w", errors.New("some deletion error")),
+			},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			// Given
+			ctx := context.Background()
+
+			aliases := mockgen.NewAliasProvider(t)
+
+			urls := mockpers.NewURLRepository(t)
+			urls.On("FindByAlias", ctx, tc.given.req.Alias).
+				Return(tc.given.url, tc.given.urlErr).
+				Once()
+
+			if tc.given.url != nil && tc.given.url.DeletedAt == nil {
+				var deleted *domain.URL
+
+				if tc.given.deleteErr == nil {
+					deleted = &domain.URL{
+						ID:        tc.given.url.ID,
+						Original:  tc.given.url.Original,
+						Alias:     tc.given.url.Alias,
+						DeletedAt: &now,
+					}
+				}
+
+				urls.On("SoftDelete", ctx, tc.given.req.Alias, now).
+					Return(deleted, tc.given.deleteErr).
+					Once()
+			}
+
+			log := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+			svc := url.NewService(urls, aliases, log, url.WithClock(func() time.Time { return now }))
+
+			// When
+			err := svc.Delete(ctx, tc.given.req)
+
+			// Then
+			require.Equal(t, tc.expected.svcErr, err)
+		})
+	}
+}
+
+func TestService_Restore(t *testing.T) {
+	deletedAt := time.Date(2025, time.March, 1, 12, 0, 0, 0, time.UTC)
+
+	type Given struct {
+		url    *domain.URL
+		urlErr error
+	}
+
+	type Expected struct {
+		svcResp *dto.GetURLByAliasResponse
+		svcErr  error
+	}
+
+	testCases := map[string]struct {
+		given    Given
+		expected Expected
+	}{
+		"Success": {
+			Given{
+				url: &domain.URL{
+					ID:        1,
+					Original:  "https://example.com/long",
+					Alias:     "fjda89fadb",
+					DeletedAt: &deletedAt,
+				},
+			},
+			Expected{
+				svcResp: &dto.GetURLByAliasResponse{
+					Original: "https://example.com/long",
+					Alias:    "fjda89fadb",
+				},
+			},
+		},
+		"Not deleted": {
+			Given{
+				url: &domain.URL{
+					ID:       1,
+					Original: "https://example.com/long",
+					Alias:    "fjda89fadb",
+				},
+			},
+			Expected{
+				svcErr: url.ErrNotDeleted,
+			},
+		},
+		"Not found": {
+			Given{
+				urlErr: persistence.ErrURLNotFound,
+			},
+			Expected{
+				svcErr: url.ErrNotFound,
+			},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			// Given
+			ctx := context.Background()
+
+			urls := mockpers.NewURLRepository(t)
+			urls.On("FindByAlias", ctx, "fjda89fadb").
+				Return(tc.given.url, tc.given.urlErr).
+				Once()
+
+			auditLog := mockpers.NewAuditRepository(t)
+
+			if tc.expected.svcResp != nil {
+				urls.On("Restore", ctx, "fjda89fadb").
+					Return(&domain.URL{
+						ID:       tc.given.url.ID,
+						Original: tc.given.url.Original,
+						Alias:    tc.given.url.Alias,
+					}, nil).
+					Once()
+
+				auditLog.On("Append", ctx, mock.MatchedBy(func(e *domain.AuditEntry) bool {
+					return e.Action == domain.AuditActionRestored && e.Before == nil && e.After.Alias == "fjda89fadb"
+				})).
+					Return(int64(1), nil).
+					Once()
+			}
+
+			log := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+			svc := url.NewService(urls, mockgen.NewAliasProvider(t), log, url.WithAuditLog(auditLog))
+
+			// When
+			resp, err := svc.Restore(ctx, &dto.RestoreURLRequest{Alias: "fjda89fadb"})
+
+			// Then
+			require.Equal(t, tc.expected.svcResp, resp)
+			require.ErrorIs(t, err, tc.expected.svcErr)
+		})
+	}
+}
+
+func TestService_Purge(t *testing.T) {
+	// Given
+	ctx := context.Background()
+	now := time.Date(2025, time.March, 31, 12, 0, 0, 0, time.UTC)
+
+	urls := mockpers.NewURLRepository(t)
+	urls.On("Purge", ctx, now.Add(-30*24*time.Hour)).
+		Return(int64(2), nil).
+		Once()
+
+	log := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	svc := url.NewService(urls, mockgen.NewAliasProvider(t), log,
+		url.WithClock(func() time.Time { return now }),
+		url.WithTrashRetention(30*24*time.Hour),
+	)
+
+	// When
+	purged, err := svc.Purge(ctx)
+
+	// Then
+	require.NoError(t, err)
+	require.Equal(t, int64(2), purged)
+}
+
+func TestService_Import(t *testing.T) {
+	now := time.Date(2025, time.March, 1, 12, 0, 0, 0, time.UTC)
+	createdAt := time.Date(2024, time.June, 1, 8, 0, 0, 0, time.UTC)
+	original := "https://example.com/long"
+
+	type Given struct {
+		req *dto.ImportURLRequest
+
+		retired *domain.RetiredAlias
+
+		url    *domain.URL
+		addErr error
+	}
+
+	type Expected struct {
+		svcResp *dto.CreateURLResponse
+		svcErr  error
+	}
+
+	testCases := map[string]struct {
+		given    Given
+		expected Expected
+	}{
+		"Success": {
+			Given{
+				req: &dto.ImportURLRequest{
+					Alias:     "promo",
+					Original:  original,
+					Tags:      []string{"Spring"},
+					CreatedAt: createdAt,
+				},
+
+				url: &domain.URL{
+					Original:  original,
+					Alias:     "promo",
+					QueryMode: domain.QueryModeIgnore,
+					Tags:      []string{"spring"},
+					CreatedAt: createdAt,
+				},
+			},
+			Expected{
+				svcResp: &dto.CreateURLResponse{
+					ID:        1,
+					Original:  original,
+					Alias:     "promo",
+					QueryMode: domain.QueryModeIgnore,
+					Tags:      []string{"spring"},
+					CreatedAt: createdAt,
+				},
+			},
+		},
+		"Without creation time": {
+			Given{
+				req: &dto.ImportURLRequest{
+					Alias:    "promo",
+					Original: original,
+				},
+
+				url: &domain.URL{
+					Original:  original,
+					Alias:     "promo",
+					QueryMode: domain.QueryModeIgnore,
+					CreatedAt: now,
+				},
+			},
+			Expected{
+				svcResp: &dto.CreateURLResponse{
+					ID:        1,
+					Original:  original,
+					Alias:     "promo",
+					QueryMode: domain.QueryModeIgnore,
+					CreatedAt: now,
+				},
+			},
+		},
+		"Alias taken": {
+			Given{
+				req: &dto.ImportURLRequest{
+					Alias:     "promo",
+					Original:  original,
+					CreatedAt: createdAt,
+				},
+
+				url: &domain.URL{
+					Original:  original,
+					Alias:     "promo",
+					QueryMode: domain.QueryModeIgnore,
+					CreatedAt: createdAt,
+				},
+				addErr: persistence.ErrDuplicateAlias,
+			},
+			Expected{
+				svcErr: url.ErrAliasTaken,
+			},
+		},
+		"URL already exists": {
+			Given{
+				req: &dto.ImportURLRequest{
+					Alias:     "promo",
+					Original:  original,
+					CreatedAt: createdAt,
+				},
+
+				url: &domain.URL{
+					Original:  original,
+					Alias:     "promo",
+					QueryMode: domain.QueryModeIgnore,
+					CreatedAt: createdAt,
+				},
+				addErr: persistence.ErrURLAlreadyExists,
+			},
+			Expected{
+				svcErr: url.ErrAlreadyExists,
+			},
+		},
+		"Alias quarantined": {
+			Given{
+				req: &dto.ImportURLRequest{
+					Alias:    "promo",
+					Original: original,
+				},
+
+				retired: &domain.RetiredAlias{Alias: "promo", URLID: 2, RetiredAt: now.Add(-time.Hour)},
+			},
+			Expected{
+				svcErr: url.ErrAliasQuarantined,
+			},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			// Given
+			ctx := context.Background()
+
+			urls := mockpers.NewURLRepository(t)
+
+			if tc.given.retired != nil {
+				urls.On("FindRetiredAlias", ctx, tc.given.req.Alias).
+					Return(tc.given.retired, nil).
+					Once()
+			} else {
+				urls.On("FindRetiredAlias", ctx, tc.given.req.Alias).
+					Return(nil, persistence.ErrAliasNotFound).
+					Once()
+				urls.On("Add", ctx, tc.given.url).
+					Return(int64(1), tc.given.addErr).
+					Once()
+			}
+
+			log := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+			svc := url.NewService(urls, mockgen.NewAliasProvider(t), log,
+				url.WithClock(func() time.Time { return now }),
+				url.WithAliasQuarantine(24*time.Hour),
+			)
+
+			// When
+			resp, err := svc.Import(ctx, tc.given.req)
+
+			// Then
+			require.Equal(t, tc.expected.svcResp, resp)
+			require.ErrorIs(t, err, tc.expected.svcErr)
+		})
+	}
+}
+
+func TestService_Import_GeneratedAlias(t *testing.T) {
+	// Given
+	ctx := context.Background()
+	original := "https://example.com/long"
+
+	aliases := mockgen.NewAliasProvider(t)
+	aliases.On("Generate", ctx, original).
+		Return("alias00001", nil).
+		Once()
+
+	urls := mockpers.NewURLRepository(t)
+	urls.On("Add", ctx, mock.MatchedBy(func(u *domain.URL) bool { return u.Alias == "alias00001" })).
+		Return(int64(1), nil).
+		Once()
+
+	log := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	svc := url.NewService(urls, aliases, log)
+
+	// When
+	resp, err := svc.Import(ctx, &dto.ImportURLRequest{Original: original})
+
+	// Then
+	require.NoError(t, err)
+	require.Equal(t, "alias00001", resp.Alias)
+}
+
+func TestService_Import_DryRun(t *testing.T) {
+	now := time.Date(2025, time.March, 1, 12, 0, 0, 0, time.UTC)
+	original := "https://example.com/long"
+
+	type Given struct {
+		alias string
+
+		byOriginal *domain.URL
+		byAlias    *domain.URL
+		retired    *domain.RetiredAlias
+	}
+
+	type Expected struct {
+		svcErr error
+	}
+
+	testCases := map[string]struct {
+		given    Given
+		expected Expected
+	}{
+		"No conflicts": {
+			Given{
+				alias: "promo",
+			},
+			Expected{},
+		},
+		"Generated alias": {
+			Given{},
+			Expected{},
+		},
+		"URL already exists": {
+			Given{
+				alias:      "promo",
+				byOriginal: &domain.URL{ID: 2, Original: original, Alias: "other"},
+			},
+			Expected{
+				svcErr: url.ErrAlreadyExists,
+			},
+		},
+		"Alias taken": {
+			Given{
+				alias:   "promo",
+				byAlias: &domain.URL{ID: 2, Original: "https://example.com/other", Alias: "promo"},
+			},
+			Expected{
+				svcErr: url.ErrAliasTaken,
+			},
+		},
+		"Alias quarantined": {
+			Given{
+				alias:   "promo",
+				retired: &domain.RetiredAlias{Alias: "promo", URLID: 2, RetiredAt: now.Add(-time.Hour)},
+			},
+			Expected{
+				svcErr: url.ErrAliasQuarantined,
+			},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			// Given
+			ctx := context.Background()
+
+			urls := mockpers.NewURLRepository(t)
+
+			if tc.given.byOriginal != nil {
+				urls.On("FindByOriginal", ctx, original).
+					Return(tc.given.byOriginal, nil).
+					Once()
+			} else {
+				urls.On("FindByOriginal", ctx, original).
+					Return(nil, persistence.ErrURLNotFound).
+					Once()
+			}
+
+			if tc.given.byOriginal == nil && tc.given.alias != "" {
+				if tc.given.byAlias != nil {
+					urls.On("FindByAlias", ctx, tc.given.alias).
+						Return(tc.given.byAlias, nil).
+						Once()
+				} else {
+					urls.On("FindByAlias", ctx, tc.given.alias).
+						Return(nil, persistence.ErrURLNotFound).
+						Once()
+
+					if tc.given.retired != nil {
+						urls.On("FindRetiredAlias", ctx, tc.given.alias).
+							Return(tc.given.retired, nil).
+							Once()
+					} else {
+						urls.On("FindRetiredAlias", ctx, tc.given.alias).
+							Return(nil, persistence.ErrAliasNotFound).
+							Once()
+					}
+				}
+			}
+
+			log := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+			svc := url.NewService(urls, mockgen.NewAliasProvider(t), log,
+				url.WithClock(func() time.Time { return now }),
+				url.WithAliasQuarantine(24*time.Hour),
+			)
+
+			// When
+			resp, err := svc.Import(ctx, &dto.ImportURLRequest{
+				Alias:    tc.given.alias,
+				Original: original,
+				DryRun:   &dto.ImportDryRun{},
+			})
+
+			// Then
+			require.ErrorIs(t, err, tc.expected.svcErr)
+
+			if tc.expected.svcErr == nil {
+				require.Equal(t, tc.given.alias, resp.Alias)
+				require.Equal(t, now, resp.CreatedAt)
+			}
+		})
+	}
+}
+
+func TestService_Import_DryRunDuplicates(t *testing.T) {
+	type Row struct {
+		alias    string
+		original string
+	}
+
+	type Given struct {
+		rows            []Row
+		caseInsensitive bool
+	}
+
+	type Expected struct {
+		errs []error
+	}
+
+	testCases := map[string]struct {
+		given    Given
+		expected Expected
+	}{
+		"Distinct": {
+			Given{
+				rows: []Row{{"promo", "https://example.com/1"}, {"sale", "https://example.com/2"}, {"", "https://example.com/3"}},
+			},
+			Expected{
+				errs: []error{nil, nil, nil},
+			},
+		},
+		"Duplicate alias": {
+			Given{
+				rows: []Row{{"promo", "https://example.com/1"}, {"promo", "https://example.com/2"}},
+			},
+			Expected{
+				errs: []error{nil, url.ErrAliasTaken},
+			},
+		},
+		"Duplicate original": {
+			Given{
+				rows: []Row{{"promo", "https://example.com/1"}, {"", "https://example.com/1"}},
+			},
+			Expected{
+				errs: []error{nil, url.ErrAlreadyExists},
+			},
+		},
+		"Aliases differing in case": {
+			Given{
+				rows: []Row{{"promo", "https://example.com/1"}, {"PROMO", "https://example.com/2"}},
+			},
+			Expected{
+				errs: []error{nil, nil},
+			},
+		},
+		"Case-insensitive aliases": {
+			Given{
+				rows:            []Row{{"promo", "https://example.com/1"}, {"PROMO", "https://example.com/2"}},
+				caseInsensitive: true,
+			},
+			Expected{
+				errs: []error{nil, url.ErrAliasTaken},
+			},
+		},
+		"Failed row is not remembered": {
+			Given{
+				rows: []Row{{"promo", "https://example.com/1"}, {"promo", "https://example.com/2"}, {"sale", "https://example.com/2"}},
+			},
+			Expected{
+				errs: []error{nil, url.ErrAliasTaken, nil},
+			},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			// Given
+			ctx := context.Background()
+
+			urls := mockpers.NewURLRepository(t)
+			urls.On("FindByOriginal", ctx, mock.Anything).
+				Return(nil, persistence.ErrURLNotFound).
+				Maybe()
+			urls.On("FindByAlias", ctx, mock.Anything).
+				Return(nil, persistence.ErrURLNotFound).
+				Maybe()
+
+			log := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+			var opts []url.Option
+			if tc.given.caseInsensitive {
+				opts = append(opts, url.WithCaseInsensitiveAliases())
+			}
+
+			svc := url.NewService(urls, mockgen.NewAliasProvider(t), log, opts...)
+
+			run := &dto.ImportDryRun{}
+
+			// When
+			errs := make([]error, 0, len(tc.given.rows))
+
+			for _, row := range tc.given.rows {
+				_, err := svc.Import(ctx, &dto.ImportURLRequest{
+					Alias:    row.alias,
+					Original: row.original,
+					DryRun:   run,
+				})
+				errs = append(errs, err)
+			}
+
+			// Then
+			require.Equal(t, tc.expected.errs, errs)
+		})
+	}
+}
+
+func TestService_Export(t *testing.T) {
+	// Given
+	ctx := context.Background()
+
+	first := make([]*domain.URL, 0, url.ExportBatchSize)
+	for i := range url.ExportBatchSize {
+		first = append(first, &domain.URL{
+			ID:       int64(i + 1),
+			Original: fmt.Sprintf("https://example.com/%d", i+1),
+			Alias:    fmt.Sprintf("alias%05d", i+1),
+		})
+	}
+
+	last := &domain.URL{ID: 600, Original: "https://example.com/600", Alias: "alias00600", Tags: []string{"promo"}}
+
+	urls := mockpers.NewURLRepository(t)
+	urls.On("List", ctx, persistence.URLFilter{Tag: "promo", Limit: url.ExportBatchSize}).
+		Return(first, nil).
+		Once()
+	urls.On("List", ctx, persistence.URLFilter{Tag: "promo", AfterID: url.ExportBatchSize, Limit: url.ExportBatchSize}).
+		Return([]*domain.URL{last}, nil).
+		Once()
+
+	log := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	svc := url.NewService(urls, mockgen.NewAliasProvider(t), log)
+
+	var aliases []string
+
+	// When
+	err := svc.Export(ctx, &dto.ExportURLsRequest{Tag: "Promo"}, func(u *dto.GetURLByAliasResponse) error {
+		aliases = append(aliases, u.Alias)
+
+		return nil
+	})
+
+	// Then
+	require.NoError(t, err)
+	require.Len(t, aliases, url.ExportBatchSize+1)
+	require.Equal(t, "alias00001", aliases[0])
+	require.Equal(t, "alias00600", aliases[url.ExportBatchSize])
+}
+
+func TestService_Export_Stopped(t *testing.T) {
+	// Given
+	ctx := context.Background()
+	errStop := errors.New("stop")
+
+	urls := mockpers.NewURLRepository(t)
+	urls.On("List", ctx, persistence.URLFilter{Limit: url.ExportBatchSize}).
+		Return([]*domain.URL{
+			{ID: 1, Original: "https://example.com/1", Alias: "alias00001"},
+			{ID: 2, Original: "https://example.com/2", Alias: "alias00002"},
+		}, nil).
+		Once()
+
+	log := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	svc := url.NewService(urls, mockgen.NewAliasProvider(t), log)
+
+	calls := 0
+
+	// When
+	err := svc.Export(ctx, &dto.ExportURLsRequest{}, func(u *dto.GetURLByAliasResponse) error {
+		calls++
+
+		return errStop
+	})
+
+	// Then
+	require.ErrorIs(t, err, errStop)
+	require.Equal(t, 1, calls)
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
+
+func TestService_StartPurge(t *testing.T) {
+	// Given
+	ctx, cancel := context.WithCancel(context.Background())
+
+	purged := make(chan struct{})
+
+	urls := mockpers.NewURLRepository(t)
+	urls.On("Purge", ctx, mock.Anything).
+		Run(func(mock.Arguments) { close(purged) }).
+		Return(int64(0), nil).
+		Once()
+
+	log := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	svc := url.NewService(urls, mockgen.NewAliasProvider(t), log)
+
+	// When
+	done := svc.StartPurge(ctx, time.Hour)
+
+	<-purged
+	cancel()
+
+	// Then
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("purging didn't stop")
+	}
+}
+
+func TestService_Capacity(t *testing.T) {
+	// Given
+	ctx := context.Background()
+
+	urls := mockpers.NewURLRepository(t)
+	urls.On("Count", ctx).
+		Return(int64(500), nil).
+		Once()
+
+	log := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	svc := url.NewService(urls, mockgen.NewAliasProvider(t), log, url.WithKeyspace("random", big.NewInt(1000)))
+
+	// When
+	resp, err := svc.Capacity(ctx)
+
+	// Then
+	require.NoError(t, err)
+	require.Equal(t, "random", resp.Provider)
+	require.Equal(t, "1000", resp.KeyspaceSize)
+	require.Equal(t, int64(500), resp.Occupied)
+	require.Equal(t, 0.5, resp.Occupancy)
+	require.Equal(t, 0.5, resp.CollisionProbability)
+	require.InDelta(t, math.Pow(0.5, url.MaxAliasAttempts), resp.FailureProbability, 1e-12)
+}
+
+func TestService_Update_Alias(t *testing.T) {
+	type Given struct {
+		retired    *domain.RetiredAlias
+		retiredErr error
+		renameErr  error
+	}
+
+	type Expected struct {
+		alias  string
+		svcErr error
+	}
+
+	now := time.Date(2025, time.March, 1, 12, 0, 0, 0, time.UTC)
+
+	testCases := map[string]struct {
+		given    Given
+		expected Expected
+	}{
+		"Renamed": {
+			Given{
+				retiredErr: persistence.ErrAliasNotFound,
+			},
+			Expected{
+				alias: "spring-sale",
+			},
+		},
+		"Own retired alias": {
+			Given{
+				retired: &domain.RetiredAlias{Alias: "spring-sale", URLID: 1, RetiredAt: now.Add(-time.Hour)},
+			},
+			Expected{
+				alias: "spring-sale",
+			},
+		},
+		"Quarantine is over": {
+			Given{
+				retired: &domain.RetiredAlias{Alias: "spring-sale", URLID: 2, RetiredAt: now.Add(-25 * time.Hour)},
+			},
+			Expected{
+				alias: "spring-sale",
+			},
+		},
+		"Quarantined": {
+			Given{
+				retired: &domain.RetiredAlias{Alias: "spring-sale", URLID: 2, RetiredAt: now.Add(-time.Hour)},
+			},
+			Expected{
+				svcErr: url.ErrAliasQuarantined,
+			},
+		},
+		"Taken": {
+			Given{
+				retiredErr: persistence.ErrAliasNotFound,
+				renameErr:  persistence.ErrDuplicateAlias,
+			},
+			Expected{
+				svcErr: url.ErrAliasTaken,
+			},
+		},
+		"Original taken": {
+			Given{
+				retiredErr: persistence.ErrAliasNotFound,
+				renameErr:  persistence.ErrURLAlreadyExists,
+			},
+			Expected{
+				svcErr: url.ErrAlreadyExists,
+			},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			// Given
+			ctx := context.Background()
+
+			u := &domain.URL{
+				ID:        1,
+				Original:  "https://example.com/long",
+				Alias:     "fjda89fadb",
+				QueryMode: domain.QueryModeIgnore,
+			}
+
+			urls := mockpers.NewURLRepository(t)
+			urls.On("FindByAlias", ctx, u.Alias).
+				Return(u, nil).
+				Once()
+			urls.On("FindRetiredAlias", ctx, "spring-sale").
+				Return(tc.given.retired, tc.given.retiredErr).
+				Once()
+
+			// the fields are updated along with the alias, Update isn't called
+			if !errors.Is(tc.expected.svcErr, url.ErrAliasQuarantined) {
+				urls.On("Rename", ctx, u.Alias, mock.MatchedBy(func(updated *domain.URL) bool {
+					return updated.Alias == "spring-sale" && updated.Title == "Sale"
+				}), now).
+					Return(&domain.URL{ID: 1, Alias: "spring-sale", Title: "Sale"}, tc.given.renameErr).
+					Once()
+			}
+
+			log := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+			svc := url.NewService(urls, mockgen.NewAliasProvider(t), log,
+				url.WithClock(func() time.Time { return now }),
+				url.WithAliasQuarantine(24*time.Hour),
+			)
+
+			// When
+			resp, err := svc.Update(ctx, &dto.UpdateURLRequest{
+				Alias:    u.Alias,
+				NewAlias: ptr("spring-sale"),
+				Title:    ptr("Sale"),
+			})
+
+			// Then
+			require.ErrorIs(t, err, tc.expected.svcErr)
+
+			if tc.expected.svcErr == nil {
+				require.Equal(t, tc.expected.alias, resp.Alias)
+			}
+		})
+	}
+}
+
+func TestService_Create_QuarantinedAlias(t *testing.T) {
+	// Given
+	ctx := context.Background()
+	now := time.Date(2025, time.March, 1, 12, 0, 0, 0, time.UTC)
+	original := "https://example.com/long"
+
+	aliases := mockgen.NewAliasProvider(t)
+	aliases.On("Generate", ctx, original).
+		Return("alias00001", nil).
+		Once()
+	aliases.On("Generate", ctx, original).
+		Return("alias00002", nil).
+		Once()
+
+	urls := mockpers.NewURLRepository(t)
+	urls.On("FindRetiredAlias", ctx, "alias00001").
+		Return(&domain.RetiredAlias{Alias: "alias00001", URLID: 2, RetiredAt: now.Add(-time.Hour)}, nil).
+		Once()
+	urls.On("FindRetiredAlias", ctx, "alias00002").
+		Return(nil, persistence.ErrAliasNotFound).
+		Once()
+	urls.On("Add", ctx, mock.MatchedBy(func(u *domain.URL) bool { return u.Alias == "alias00002" })).
+		Return(int64(1), nil).
+		Once()
+
+	log := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	svc := url.NewService(urls, aliases, log,
+		url.WithClock(func() time.Time { return now }),
+		url.WithAliasQuarantine(24*time.Hour),
+	)
+
+	// When
+	resp, err := svc.Create(ctx, &dto.CreateURLRequest{Original: original})
+
+	// Then
+	require.NoError(t, err)
+	require.Equal(t, "alias00002", resp.Alias)
+}
+
+func TestService_Resolve_RetiredAlias(t *testing.T) {
+	testCases := map[string]struct {
+		redirect bool
+
+		expected *dto.ResolveURLResponse
+	}{
+		"Resolved": {
+			redirect: false,
+			expected: &dto.ResolveURLResponse{
+				Location: "https://example.com/long",
+				Alias:    "spring-sale",
+			},
+		},
+		"Redirected to current alias": {
+			redirect: true,
+			expected: &dto.ResolveURLResponse{
+				Alias: "spring-sale",
+				Moved: true,
+			},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			// Given
+			ctx := context.Background()
+
+			u := &domain.URL{
+				ID:        1,
+				Original:  "https://example.com/long",
+				Alias:     "spring-sale",
+				QueryMode: domain.QueryModeIgnore,
+			}
+
+			urls := mockpers.NewURLRepository(t)
+			urls.On("FindByAlias", ctx, "fjda89fadb").
+				Return(nil, persistence.ErrURLNotFound).
+				Once()
+			urls.On("FindRetiredAlias", ctx, "fjda89fadb").
+				Return(&domain.RetiredAlias{Alias: "fjda89fadb", URLID: 1}, nil).
+				Once()
+			urls.On("FindByID", ctx, int64(1)).
+				Return(u, nil).
+				Once()
+
+			if !tc.redirect {
+				urls.On("IncrementClicks", ctx, "spring-sale").
+					Return(int64(1), nil).
+					Once()
+			}
+
+			log := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+			opts := []url.Option{}
+			if tc.redirect {
+				opts = append(opts, url.WithRetiredAliasRedirect())
+			}
+
+			svc := url.NewService(urls, mockgen.NewAliasProvider(t), log, opts...)
+
+			// When
+			resp, err := svc.Resolve(ctx, &dto.ResolveURLRequest{Alias: "fjda89fadb"})
+
+			// Then
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, resp)
+		})
+	}
+}
+
+func TestService_ListAliases(t *testing.T) {
+	// Given
+	ctx := context.Background()
+	retiredAt := time.Date(2025, time.March, 1, 12, 0, 0, 0, time.UTC)
+
+	u := &domain.URL{
+		ID:    1,
+		Alias: "spring-sale",
+	}
+
+	urls := mockpers.NewURLRepository(t)
+	urls.On("FindByAlias", ctx, "spring-sale").
+		Return(u, nil).
+		Once()
+	urls.On("ListRetiredAliases", ctx, int64(1)).
+		Return([]*domain.RetiredAlias{{Alias: "fjda89fadb", URLID: 1, RetiredAt: retiredAt}}, nil).
+		Once()
+
+	log := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	svc := url.NewService(urls, mockgen.NewAliasProvider(t), log, url.WithAliasQuarantine(24*time.Hour))
+
+	// When
+	resp, err := svc.ListAliases(ctx, &dto.ListAliasesRequest{Alias: "spring-sale"})
+
+	// Then
+	require.NoError(t, err)
+	require.Equal(t, &dto.ListAliasesResponse{
+		Alias: "spring-sale",
+		Retired: []*dto.RetiredAliasResponse{
+			{Alias: "fjda89fadb", RetiredAt: retiredAt, QuarantinedUntil: retiredAt.Add(24 * time.Hour)},
+		},
+	}, resp)
+}
+
+func TestService_Update_Audit(t *testing.T) {
+	// Given
+	ctx := audit.NewContext(context.Background(), audit.Origin{Actor: "alice", RequestID: "req-1"})
+	now := time.Date(2025, time.March, 1, 12, 0, 0, 0, time.UTC)
+
+	u := &domain.URL{
+		ID:        1,
+		Original:  "https://example.com/old",
+		Alias:     "fjda89fadb",
+		QueryMode: domain.QueryModeIgnore,
+		Title:     "Old title",
+	}
+
+	urls := mockpers.NewURLRepository(t)
+	urls.On("FindByAlias", ctx, u.Alias).
+		Return(u, nil).
+		Once()
+	urls.On("Update", ctx, mock.Anything).
+		Return(nil).
+		Once()
+
+	auditLog := mockpers.NewAuditRepository(t)
+	auditLog.On("Append", ctx, &domain.AuditEntry{
+		URLID:     1,
+		Action:    domain.AuditActionUpdated,
+		Actor:     "alice",
+		RequestID: "req-1",
+		Before:    domain.NewURLState(u),
+		After: &domain.URLState{
+			Original:  "https://example.com/old",
+			Alias:     "fjda89fadb",
+			QueryMode: domain.QueryModeIgnore,
+			Title:     "New title",
+		},
+		CreatedAt: now,
+	}).
+		Return(int64(1), nil).
+		Once()
+
+	log := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	svc := url.NewService(urls, mockgen.NewAliasProvider(t), log,
+		url.WithClock(func() time.Time { return now }),
+		url.WithAuditLog(auditLog),
+	)
+
+	// When
+	_, err := svc.Update(ctx, &dto.UpdateURLRequest{
+		Alias: u.Alias,
+		Title: ptr("New title"),
+	})
+
+	// Then
+	require.NoError(t, err)
+}
+
+func TestService_History(t *testing.T) {
+	// Given
+	ctx := context.Background()
+	createdAt := time.Date(2025, time.March, 1, 12, 0, 0, 0, time.UTC)
+
+	created := &domain.URLState{
+		Original:  "https://example.com/old",
+		Alias:     "fjda89fadb",
+		QueryMode: domain.QueryModeIgnore,
+	}
+	updated := *created
+	updated.Original = "https://example.com/new"
+	updated.Tags = []string{"promo"}
+
+	urls := mockpers.NewURLRepository(t)
+	urls.On("FindByAlias", ctx, "fjda89fadb").
+		Return(&domain.URL{ID: 1, Alias: "fjda89fadb"}, nil).
+		Once()
+
+	auditLog := mockpers.NewAuditRepository(t)
+	auditLog.On("ListByURL", ctx, int64(1)).
+		Return([]*domain.AuditEntry{
+			{ID: 3, URLID: 1, Action: domain.AuditActionCreated, Actor: "alice", After: created, CreatedAt: createdAt},
+			{ID: 7, URLID: 1, Action: domain.AuditActionUpdated, Actor: "bob", RequestID: "req-2", Before: created, After: &updated, CreatedAt: createdAt.Add(time.Hour)},
+		}, nil).
+		Once()
+
+	log := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	svc := url.NewService(urls, mockgen.NewAliasProvider(t), log, url.WithAuditLog(auditLog))
+
+	// When
+	resp, err := svc.History(ctx, &dto.URLHistoryRequest{Alias: "fjda89fadb"})
+
+	// Then
+	require.NoError(t, err)
+	require.Equal(t, "fjda89fadb", resp.Alias)
+	require.Len(t, resp.Versions, 2)
+
+	require.Equal(t, 1, resp.Versions[0].Version)
+	require.Equal(t, "alice", resp.Versions[0].Actor)
+	require.Len(t, resp.Versions[0].Changes, 8)
+	require.Equal(t, &dto.FieldChange{Field: "original", Before: nil, After: "https://example.com/old"}, resp.Versions[0].Changes[0])
+
+	require.Equal(t, &dto.URLVersionResponse{
+		Version:   2,
+		Action:    domain.AuditActionUpdated,
+		Actor:     "bob",
+		RequestID: "req-2",
+		CreatedAt: createdAt.Add(time.Hour),
+		Changes: []*dto.FieldChange{
+			{Field: "original", Before: "https://example.com/old", After: "https://example.com/new"},
+			{Field: "tags", Before: []string(nil), After: []string{"promo"}},
+		},
+	}, resp.Versions[1])
+}
+
+func TestService_Rollback(t *testing.T) {
+	testCases := map[string]struct {
+		version int
+
+		expectedErr error
+	}{
+		"Rolled back": {
+			version: 1,
+		},
+		"Deletion": {
+			version:     3,
+			expectedErr: url.ErrVersionNotFound,
+		},
+		"Unknown version": {
+			version:     4,
+			expectedErr: url.ErrVersionNotFound,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			// Given
+			ctx := context.Background()
+
+			u := &domain.URL{
+				ID:        1,
+				Original:  "https://example.com/new",
+				Alias:     "fjda89fadb",
+				QueryMode: domain.QueryModeMerge,
+			}
+			first := &domain.URLState{
+				Original:  "https://example.com/old",
+				Alias:     "fjda89fadb",
+				QueryMode: domain.QueryModeIgnore,
+				Tags:      []string{"promo"},
+			}
+
+			urls := mockpers.NewURLRepository(t)
+			urls.On("FindByAlias", ctx, u.Alias).
+				Return(u, nil)
+
+			auditLog := mockpers.NewAuditRepository(t)
+			auditLog.On("ListByURL", ctx, int64(1)).
+				Return([]*domain.AuditEntry{
+					{URLID: 1, Action: domain.AuditActionCreated, After: first},
+					{URLID: 1, Action: domain.AuditActionUpdated, Before: first, After: domain.NewURLState(u)},
+					{URLID: 1, Action: domain.AuditActionDeleted, Before: domain.NewURLState(u)},
+				}, nil).
+				Once()
+
+			if tc.expectedErr == nil {
+				urls.On("Update", ctx, &domain.URL{
+					ID:        1,
+					Original:  "https://example.com/old",
+					Alias:     "fjda89fadb",
+					QueryMode: domain.QueryModeIgnore,
+					Tags:      []string{"promo"},
+				}).
+					Return(nil).
+					Once()
+
+				auditLog.On("Append", ctx, mock.MatchedBy(func(e *domain.AuditEntry) bool {
+					return e.Action == domain.AuditActionRolledBack && e.After.Original == "https://example.com/old"
+				})).
+					Return(int64(4), nil).
+					Once()
+			}
+
+			log := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+			svc := url.NewService(urls, mockgen.NewAliasProvider(t), log, url.WithAuditLog(auditLog))
+
+			// When
+			resp, err := svc.Rollback(ctx, &dto.RollbackURLRequest{Alias: u.Alias, Version: tc.version})
+
+			// Then
+			require.ErrorIs(t, err, tc.expectedErr)
+
+			if tc.expectedErr == nil {
+				require.Equal(t, "https://example.com/old", resp.Original)
+			}
+		})
+	}
+}
@@ -23,6 +23,64 @@ func (_m *Service) EXPECT() *Service_Expecter {
 	return &Service_Expecter{mock: &_m.Mock}
 }
 
+// Capacity provides a mock function with given fields: ctx
+func (_m *Service) Capacity(ctx context.Context) (*dto.CapacityResponse, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Capacity")
+	}
+
+	var r0 *dto.CapacityResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) (*dto.CapacityResponse, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) *dto.CapacityResponse); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*dto.CapacityResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Service_Capacity_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Capacity'
+type Service_Capacity_Call struct {
+	*mock.Call
+}
+
+// Capacity is a helper method to define mock.On call
+//   - ctx context.Context
+func (_e *Service_Expecter) Capacity(ctx interface{}) *Service_Capacity_Call {
+	return &Service_Capacity_Call{Call: _e.mock.On("Capacity", ctx)}
+}
+
+func (_c *Service_Capacity_Call) Run(run func(ctx context.Context)) *Service_Capacity_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context))
+	})
+	return _c
+}
+
+func (_c *Service_Capacity_Call) Return(_a0 *dto.CapacityResponse, _a1 error) *Service_Capacity_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Service_Capacity_Call) RunAndReturn(run func(context.Context) (*dto.CapacityResponse, error)) *Service_Capacity_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // Create provides a mock function with given fields: ctx, req
 func (_m *Service) Create(ctx context.Context, req *dto.CreateURLRequest) (*dto.CreateURLResponse, error) {
 	ret := _m.Called(ctx, req)
@@ -82,6 +140,101 @@ func (_c *Service_Create_Call) RunAndReturn(run func(context.Context, *dto.Creat
 	return _c
 }
 
+// Delete provides a mock function with given fields: ctx, req
+func (_m *Service) Delete(ctx context.Context, req *dto.DeleteURLRequest) error {
+	ret := _m.Called(ctx, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Delete")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *dto.DeleteURLRequest) error); ok {
+		r0 = rf(ctx, req)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Service_Delete_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Delete'
+type Service_Delete_Call struct {
+	*mock.Call
+}
+
+// Delete is a helper method to define mock.On call
+//   - ctx context.Context
+//   - req *dto.DeleteURLRequest
+func (_e *Service_Expecter) Delete(ctx interface{}, req interface{}) *Service_Delete_Call {
+	return &Service_Delete_Call{Call: _e.mock.On("Delete", ctx, req)}
+}
+
+func (_c *Service_Delete_Call) Run(run func(ctx context.Context, req *dto.DeleteURLRequest)) *Service_Delete_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*dto.DeleteURLRequest))
+	})
+	return _c
+}
+
+func (_c *Service_Delete_Call) Return(_a0 error) *Service_Delete_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *Service_Delete_Call) RunAndReturn(run func(context.Context, *dto.DeleteURLRequest) error) *Service_Delete_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Export provides a mock function with given fields: ctx, req, fn
+func (_m *Service) Export(ctx context.Context, req *dto.ExportURLsRequest, fn func(*dto.GetURLByAliasResponse) error) error {
+	ret := _m.Called(ctx, req, fn)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Export")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *dto.ExportURLsRequest, func(*dto.GetURLByAliasResponse) error) error); ok {
+		r0 = rf(ctx, req, fn)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Service_Export_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Export'
+type Service_Export_Call struct {
+	*mock.Call
+}
+
+// Export is a helper method to define mock.On call
+//   - ctx context.Context
+//   - req *dto.ExportURLsRequest
+//   - fn func(*dto.GetURLByAliasResponse) error
+func (_e *Service_Expecter) Export(ctx interface{}, req interface{}, fn interface{}) *Service_Export_Call {
+	return &Service_Export_Call{Call: _e.mock.On("Export", ctx, req, fn)}
+}
+
+func (_c *Service_Export_Call) Run(run func(ctx context.Context, req *dto.ExportURLsRequest, fn func(*dto.GetURLByAliasResponse) error)) *Service_Export_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*dto.ExportURLsRequest), args[2].(func(*dto.GetURLByAliasResponse) error))
+	})
+	return _c
+}
+
+func (_c *Service_Export_Call) Return(_a0 error) *Service_Export_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *Service_Export_Call) RunAndReturn(run func(context.Context, *dto.ExportURLsRequest, func(*dto.GetURLByAliasResponse) error) error) *Service_Export_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // GetByAlias provides a mock function with given fields: ctx, req
 func (_m *Service) GetByAlias(ctx context.Context, req *dto.GetURLByAliasRequest) (*dto.GetURLByAliasResponse, error) {
 	ret := _m.Called(ctx, req)
@@ -141,6 +294,478 @@ func (_c *Service_GetByAlias_Call) RunAndReturn(run func(context.Context, *dto.G
 	return _c
 }
 
+// History provides a mock function with given fields: ctx, req
+func (_m *Service) History(ctx context.Context, req *dto.URLHistoryRequest) (*dto.URLHistoryResponse, error) {
+	ret := _m.Called(ctx, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for History")
+	}
+
+	var r0 *dto.URLHistoryResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *dto.URLHistoryRequest) (*dto.URLHistoryResponse, error)); ok {
+		return rf(ctx, req)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *dto.URLHistoryRequest) *dto.URLHistoryResponse); ok {
+		r0 = rf(ctx, req)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*dto.URLHistoryResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *dto.URLHistoryRequest) error); ok {
+		r1 = rf(ctx, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Service_History_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'History'
+type Service_History_Call struct {
+	*mock.Call
+}
+
+// History is a helper method to define mock.On call
+//   - ctx context.Context
+//   - req *dto.URLHistoryRequest
+func (_e *Service_Expecter) History(ctx interface{}, req interface{}) *Service_History_Call {
+	return &Service_History_Call{Call: _e.mock.On("History", ctx, req)}
+}
+
+func (_c *Service_History_Call) Run(run func(ctx context.Context, req *dto.URLHistoryRequest)) *Service_History_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*dto.URLHistoryRequest))
+	})
+	return _c
+}
+
+func (_c *Service_History_Call) Return(_a0 *dto.URLHistoryResponse, _a1 error) *Service_History_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Service_History_Call) RunAndReturn(run func(context.Context, *dto.URLHistoryRequest) (*dto.URLHistoryResponse, error)) *Service_History_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Import provides a mock function with given fields: ctx, req
+func (_m *Service) Import(ctx context.Context, req *dto.ImportURLRequest) (*dto.CreateURLResponse, error) {
+	ret := _m.Called(ctx, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Import")
+	}
+
+	var r0 *dto.CreateURLResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *dto.ImportURLRequest) (*dto.CreateURLResponse, error)); ok {
+		return rf(ctx, req)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *dto.ImportURLRequest) *dto.CreateURLResponse); ok {
+		r0 = rf(ctx, req)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*dto.CreateURLResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *dto.ImportURLRequest) error); ok {
+		r1 = rf(ctx, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Service_Import_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Import'
+type Service_Import_Call struct {
+	*mock.Call
+}
+
+// Import is a helper method to define mock.On call
+//   - ctx context.Context
+//   - req *dto.ImportURLRequest
+func (_e *Service_Expecter) Import(ctx interface{}, req interface{}) *Service_Import_Call {
+	return &Service_Import_Call{Call: _e.mock.On("Import", ctx, req)}
+}
+
+func (_c *Service_Import_Call) Run(run func(ctx context.Context, req *dto.ImportURLRequest)) *Service_Import_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*dto.ImportURLRequest))
+	})
+	return _c
+}
+
+func (_c *Service_Import_Call) Return(_a0 *dto.CreateURLResponse, _a1 error) *Service_Import_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Service_Import_Call) RunAndReturn(run func(context.Context, *dto.ImportURLRequest) (*dto.CreateURLResponse, error)) *Service_Import_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// List provides a mock function with given fields: ctx, req
+func (_m *Service) List(ctx context.Context, req *dto.ListURLsRequest) (*dto.ListURLsResponse, error) {
+	ret := _m.Called(ctx, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for List")
+	}
+
+	var r0 *dto.ListURLsResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *dto.ListURLsRequest) (*dto.ListURLsResponse, error)); ok {
+		return rf(ctx, req)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *dto.ListURLsRequest) *dto.ListURLsResponse); ok {
+		r0 = rf(ctx, req)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*dto.ListURLsResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *dto.ListURLsRequest) error); ok {
+		r1 = rf(ctx, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Service_List_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'List'
+type Service_List_Call struct {
+	*mock.Call
+}
+
+// List is a helper method to define mock.On call
+//   - ctx context.Context
+//   - req *dto.ListURLsRequest
+func (_e *Service_Expecter) List(ctx interface{}, req interface{}) *Service_List_Call {
+	return &Service_List_Call{Call: _e.mock.On("List", ctx, req)}
+}
+
+func (_c *Service_List_Call) Run(run func(ctx context.Context, req *dto.ListURLsRequest)) *Service_List_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*dto.ListURLsRequest))
+	})
+	return _c
+}
+
+func (_c *Service_List_Call) Return(_a0 *dto.ListURLsResponse, _a1 error) *Service_List_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Service_List_Call) RunAndReturn(run func(context.Context, *dto.ListURLsRequest) (*dto.ListURLsResponse, error)) *Service_List_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListAliases provides a mock function with given fields: ctx, req
+func (_m *Service) ListAliases(ctx context.Context, req *dto.ListAliasesRequest) (*dto.ListAliasesResponse, error) {
+	ret := _m.Called(ctx, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListAliases")
+	}
+
+	var r0 *dto.ListAliasesResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *dto.ListAliasesRequest) (*dto.ListAliasesResponse, error)); ok {
+		return rf(ctx, req)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *dto.ListAliasesRequest) *dto.ListAliasesResponse); ok {
+		r0 = rf(ctx, req)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*dto.ListAliasesResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *dto.ListAliasesRequest) error); ok {
+		r1 = rf(ctx, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Service_ListAliases_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListAliases'
+type Service_ListAliases_Call struct {
+	*mock.Call
+}
+
+// ListAliases is a helper method to define mock.On call
+//   - ctx context.Context
+//   - req *dto.ListAliasesRequest
+func (_e *Service_Expecter) ListAliases(ctx interface{}, req interface{}) *Service_ListAliases_Call {
+	return &Service_ListAliases_Call{Call: _e.mock.On("ListAliases", ctx, req)}
+}
+
+func (_c *Service_ListAliases_Call) Run(run func(ctx context.Context, req *dto.ListAliasesRequest)) *Service_ListAliases_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*dto.ListAliasesRequest))
+	})
+	return _c
+}
+
+func (_c *Service_ListAliases_Call) Return(_a0 *dto.ListAliasesResponse, _a1 error) *Service_ListAliases_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Service_ListAliases_Call) RunAndReturn(run func(context.Context, *dto.ListAliasesRequest) (*dto.ListAliasesResponse, error)) *Service_ListAliases_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Resolve provides a mock function with given fields: ctx, req
+func (_m *Service) Resolve(ctx context.Context, req *dto.ResolveURLRequest) (*dto.ResolveURLResponse, error) {
+	ret := _m.Called(ctx, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Resolve")
+	}
+
+	var r0 *dto.ResolveURLResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *dto.ResolveURLRequest) (*dto.ResolveURLResponse, error)); ok {
+		return rf(ctx, req)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *dto.ResolveURLRequest) *dto.ResolveURLResponse); ok {
+		r0 = rf(ctx, req)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*dto.ResolveURLResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *dto.ResolveURLRequest) error); ok {
+		r1 = rf(ctx, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Service_Resolve_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Resolve'
+type Service_Resolve_Call struct {
+	*mock.Call
+}
+
+// Resolve is a helper method to define mock.On call
+//   - ctx context.Context
+//   - req *dto.ResolveURLRequest
+func (_e *Service_Expecter) Resolve(ctx interface{}, req interface{}) *Service_Resolve_Call {
+	return &Service_Resolve_Call{Call: _e.mock.On("Resolve", ctx, req)}
+}
+
+func (_c *Service_Resolve_Call) Run(run func(ctx context.Context, req *dto.ResolveURLRequest)) *Service_Resolve_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*dto.ResolveURLRequest))
+	})
+	return _c
+}
+
+func (_c *Service_Resolve_Call) Return(_a0 *dto.ResolveURLResponse, _a1 error) *Service_Resolve_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Service_Resolve_Call) RunAndReturn(run func(context.Context, *dto.ResolveURLRequest) (*dto.ResolveURLResponse, error)) *Service_Resolve_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Restore provides a mock function with given fields: ctx, req
+func (_m *Service) Restore(ctx context.Context, req *dto.RestoreURLRequest) (*dto.GetURLByAliasResponse, error) {
+	ret := _m.Called(ctx, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Restore")
+	}
+
+	var r0 *dto.GetURLByAliasResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *dto.RestoreURLRequest) (*dto.GetURLByAliasResponse, error)); ok {
+		return rf(ctx, req)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *dto.RestoreURLRequest) *dto.GetURLByAliasResponse); ok {
+		r0 = rf(ctx, req)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*dto.GetURLByAliasResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *dto.RestoreURLRequest) error); ok {
+		r1 = rf(ctx, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Service_Restore_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Restore'
+type Service_Restore_Call struct {
+	*mock.Call
+}
+
+// Restore is a helper method to define mock.On call
+//   - ctx context.Context
+//   - req *dto.RestoreURLRequest
+func (_e *Service_Expecter) Restore(ctx interface{}, req interface{}) *Service_Restore_Call {
+	return &Service_Restore_Call{Call: _e.mock.On("Restore", ctx, req)}
+}
+
+func (_c *Service_Restore_Call) Run(run func(ctx context.Context, req *dto.RestoreURLRequest)) *Service_Restore_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*dto.RestoreURLRequest))
+	})
+	return _c
+}
+
+func (_c *Service_Restore_Call) Return(_a0 *dto.GetURLByAliasResponse, _a1 error) *Service_Restore_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Service_Restore_Call) RunAndReturn(run func(context.Context, *dto.RestoreURLRequest) (*dto.GetURLByAliasResponse, error)) *Service_Restore_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Rollback provides a mock function with given fields: ctx, req
+func (_m *Service) Rollback(ctx context.Context, req *dto.RollbackURLRequest) (*dto.GetURLByAliasResponse, error) {
+	ret := _m.Called(ctx, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Rollback")
+	}
+
+	var r0 *dto.GetURLByAliasResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *dto.RollbackURLRequest) (*dto.GetURLByAliasResponse, error)); ok {
+		return rf(ctx, req)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *dto.RollbackURLRequest) *dto.GetURLByAliasResponse); ok {
+		r0 = rf(ctx, req)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*dto.GetURLByAliasResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *dto.RollbackURLRequest) error); ok {
+		r1 = rf(ctx, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Service_Rollback_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Rollback'
+type Service_Rollback_Call struct {
+	*mock.Call
+}
+
+// Rollback is a helper method to define mock.On call
+//   - ctx context.Context
+//   - req *dto.RollbackURLRequest
+func (_e *Service_Expecter) Rollback(ctx interface{}, req interface{}) *Service_Rollback_Call {
+	return &Service_Rollback_Call{Call: _e.mock.On("Rollback", ctx, req)}
+}
+
+func (_c *Service_Rollback_Call) Run(run func(ctx context.Context, req *dto.RollbackURLRequest)) *Service_Rollback_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*dto.RollbackURLRequest))
+	})
+	return _c
+}
+
+func (_c *Service_Rollback_Call) Return(_a0 *dto.GetURLByAliasResponse, _a1 error) *Service_Rollback_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Service_Rollback_Call) RunAndReturn(run func(context.Context, *dto.RollbackURLRequest) (*dto.GetURLByAliasResponse, error)) *Service_Rollback_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Update provides a mock function with given fields: ctx, req
+func (_m *Service) Update(ctx context.Context, req *dto.UpdateURLRequest) (*dto.GetURLByAliasResponse, error) {
+	ret := _m.Called(ctx, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Update")
+	}
+
+	var r0 *dto.GetURLByAliasResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *dto.UpdateURLRequest) (*dto.GetURLByAliasResponse, error)); ok {
+		return rf(ctx, req)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *dto.UpdateURLRequest) *dto.GetURLByAliasResponse); ok {
+		r0 = rf(ctx, req)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*dto.GetURLByAliasResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *dto.UpdateURLRequest) error); ok {
+		r1 = rf(ctx, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Service_Update_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Update'
+type Service_Update_Call struct {
+	*mock.Call
+}
+
+// Update is a helper method to define mock.On call
+//   - ctx context.Context
+//   - req *dto.UpdateURLRequest
+func (_e *Service_Expecter) Update(ctx interface{}, req interface{}) *Service_Update_Call {
+	return &Service_Update_Call{Call: _e.mock.On("Update", ctx, req)}
+}
+
+func (_c *Service_Update_Call) Run(run func(ctx context.Context, req *dto.UpdateURLRequest)) *Service_Update_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*dto.UpdateURLRequest))
+	})
+	return _c
+}
+
+func (_c *Service_Update_Call) Return(_a0 *dto.GetURLByAliasResponse, _a1 error) *Service_Update_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Service_Update_Call) RunAndReturn(run func(context.Context, *dto.UpdateURLRequest) (*dto.GetURLByAliasResponse, error)) *Service_Update_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // NewService creates a new instance of Service. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewService(t interface {
@@ -0,0 +1,233 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/kodeyeen/shortify/internal/domain"
+	"github.com/kodeyeen/shortify/internal/persistence"
+)
+
+// maxErrorBodySize limits how much of an unsuccessful response is kept as the delivery error.
+const maxErrorBodySize = 512
+
+type DispatcherConfig struct {
+	PollInterval   time.Duration
+	Timeout        time.Duration
+	BatchSize      int
+	Workers        int
+	MaxAttempts    int
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+}
+
+// Dispatcher sends pending deliveries retrying failed ones with exponential
+// backoff. Deliveries that fail MaxAttempts times are marked dead.
+type Dispatcher struct {
+	webhooks Repository
+	client   *http.Client
+	cfg      DispatcherConfig
+
+	now func() time.Time
+	log *slog.Logger
+}
+
+func NewDispatcher(webhooks Repository, client *http.Client, cfg DispatcherConfig, log *slog.Logger) *Dispatcher {
+	return &Dispatcher{
+		webhooks: webhooks,
+		client:   client,
+		cfg:      cfg,
+
+		now: time.Now,
+		log: log.With(slog.String("component", "webhook/dispatcher")),
+	}
+}
+
+// Start dispatches deliveries in the background until ctx is done.
+// The returned channel is closed once the dispatcher has stopped.
+func (d *Dispatcher) Start(ctx context.Context) <-chan struct{} {
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+
+		d.run(ctx)
+	}()
+
+	return done
+}
+
+func (d *Dispatcher) run(ctx context.Context) {
+	ticker := time.NewTicker(d.cfg.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		for {
+			n, err := d.DispatchDue(ctx)
+			if err != nil {
+				d.log.Error("failed to dispatch deliveries", slog.String("error", err.Error()))
+			}
+
+			// keep going without waiting while there is a backlog
+			if err != nil || n < d.cfg.BatchSize {
+				break
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// DispatchDue sends a batch of due deliveries and returns their number.
+func (d *Dispatcher) DispatchDue(ctx context.Context) (int, error) {
+	now := d.now()
+
+	// the lease outlives the attempts of the whole batch
+	lease := d.cfg.Timeout * time.Duration(d.cfg.BatchSize/max(d.cfg.Workers, 1)+1)
+
+	deliveries, err := d.webhooks.ClaimDue(ctx, now, now.Add(lease), d.cfg.BatchSize)
+	if err != nil {
+		return 0, fmt.Errorf("failed to claim deliveries: %w", err)
+	}
+
+	webhooks := map[int64]*domain.Webhook{}
+
+	for _, delivery := range deliveries {
+		if _, ok := webhooks[delivery.WebhookID]; ok {
+			continue
+		}
+
+		w, err := d.webhooks.FindByID(ctx, delivery.WebhookID)
+		if err != nil && !errors.Is(err, persistence.ErrWebhookNotFound) {
+			return 0, fmt.Errorf("failed to get webhook: %w", err)
+		}
+
+		webhooks[delivery.WebhookID] = w
+	}
+
+	sem := make(chan struct{}, max(d.cfg.Workers, 1))
+
+	var wg sync.WaitGroup
+
+	for _, delivery := range deliveries {
+		w := webhooks[delivery.WebhookID]
+		if w == nil {
+			// the webhook was deleted after the delivery was claimed
+			continue
+		}
+
+		sem <- struct{}{}
+		wg.Add(1)
+
+		go func() {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+
+			d.attempt(ctx, w, delivery)
+		}()
+	}
+
+	wg.Wait()
+
+	return len(deliveries), nil
+}
+
+func (d *Dispatcher) attempt(ctx context.Context, w *domain.Webhook, delivery *domain.Delivery) {
+	log := d.log.With(
+		slog.Int64("webhook_id", w.ID),
+		slog.Int64("delivery_id", delivery.ID),
+		slog.String("event", delivery.Event),
+	)
+
+	status, err := d.send(ctx, w, delivery)
+
+	now := d.now()
+
+	delivery.Attempts++
+	delivery.ResponseStatus = status
+
+	switch {
+	case err == nil:
+		delivery.Status = domain.DeliveryStatusDelivered
+		delivery.DeliveredAt = &now
+		delivery.LastError = ""
+
+		log.Info("delivered")
+	case delivery.Attempts >= d.cfg.MaxAttempts:
+		delivery.Status = domain.DeliveryStatusDead
+		delivery.LastError = err.Error()
+
+		log.Warn("delivery is dead", slog.Int("attempts", delivery.Attempts), slog.String("error", err.Error()))
+	default:
+		delivery.NextAttemptAt = now.Add(d.backoff(delivery.Attempts))
+		delivery.LastError = err.Error()
+
+		log.Info("delivery failed", slog.Int("attempts", delivery.Attempts), slog.String("error", err.Error()))
+	}
+
+	err = d.webhooks.UpdateDelivery(ctx, delivery)
+	if err != nil {
+		log.Error("failed to update delivery", slog.String("error", err.Error()))
+	}
+}
+
+func (d *Dispatcher) send(ctx context.Context, w *domain.Webhook, delivery *domain.Delivery) (int, error) {
+	ctx, cancel := context.WithTimeout(ctx, d.cfg.Timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.URL, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return 0, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	now := d.now()
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "Shortify-Webhook/1.0")
+	req.Header.Set(HeaderEvent, delivery.Event)
+	req.Header.Set(HeaderDelivery, strconv.FormatInt(delivery.ID, 10))
+	req.Header.Set(HeaderTimestamp, strconv.FormatInt(now.Unix(), 10))
+	req.Header.Set(HeaderSignature, Sign(w.Secret, now, delivery.Payload))
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBodySize))
+
+		return resp.StatusCode, fmt.Errorf("unexpected response status %d: %s", resp.StatusCode, bytes.TrimSpace(body))
+	}
+
+	// drain the body so the connection can be reused
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, maxErrorBodySize))
+
+	return resp.StatusCode, nil
+}
+
+// backoff returns the delay before the attempt following the given number of attempts.
+func (d *Dispatcher) backoff(attempts int) time.Duration {
+	delay := d.cfg.InitialBackoff
+
+	for i := 1; i < attempts && delay < d.cfg.MaxBackoff; i++ {
+		delay *= 2
+	}
+
+	return min(delay, d.cfg.MaxBackoff)
+}
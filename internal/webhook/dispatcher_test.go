@@ -0,0 +1,228 @@
+package webhook_test
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/kodeyeen/shortify/internal/domain"
+	"github.com/kodeyeen/shortify/internal/dto"
+	"github.com/kodeyeen/shortify/internal/persistence/inmemory"
+	"github.com/kodeyeen/shortify/internal/webhook"
+	"github.com/stretchr/testify/require"
+)
+
+const testSecret = "0123456789abcdef"
+
+func TestDispatcher_DispatchDue(t *testing.T) {
+	type Given struct {
+		failures    int32
+		maxAttempts int
+		backoff     time.Duration
+		dispatches  int
+	}
+
+	type Expected struct {
+		requests  int32
+		status    string
+		attempts  int
+		postponed bool
+	}
+
+	testCases := map[string]struct {
+		given    Given
+		expected Expected
+	}{
+		"Delivered": {
+			Given{
+				failures:    0,
+				maxAttempts: 3,
+				backoff:     time.Hour,
+				dispatches:  1,
+			},
+			Expected{
+				requests: 1,
+				status:   domain.DeliveryStatusDelivered,
+				attempts: 1,
+			},
+		},
+		"Postponed with backoff": {
+			Given{
+				failures:    1,
+				maxAttempts: 3,
+				backoff:     time.Hour,
+				dispatches:  2,
+			},
+			Expected{
+				requests:  1,
+				status:    domain.DeliveryStatusPending,
+				attempts:  1,
+				postponed: true,
+			},
+		},
+		"Retried when due": {
+			Given{
+				failures:    2,
+				maxAttempts: 3,
+				backoff:     0,
+				dispatches:  3,
+			},
+			Expected{
+				requests: 3,
+				status:   domain.DeliveryStatusDelivered,
+				attempts: 3,
+			},
+		},
+		"Dead after max attempts": {
+			Given{
+				failures:    1,
+				maxAttempts: 1,
+				backoff:     time.Hour,
+				dispatches:  1,
+			},
+			Expected{
+				requests: 1,
+				status:   domain.DeliveryStatusDead,
+				attempts: 1,
+			},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			// Given
+			var requests atomic.Int32
+
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				n := requests.Add(1)
+
+				body, err := io.ReadAll(r.Body)
+				require.NoError(t, err)
+
+				ts, err := strconv.ParseInt(r.Header.Get(webhook.HeaderTimestamp), 10, 64)
+				require.NoError(t, err)
+
+				require.Equal(t, domain.EventURLCreated, r.Header.Get(webhook.HeaderEvent))
+				require.Equal(t, webhook.Sign(testSecret, time.Unix(ts, 0), body), r.Header.Get(webhook.HeaderSignature))
+
+				if n <= tc.given.failures {
+					w.WriteHeader(http.StatusServiceUnavailable)
+					return
+				}
+
+				w.WriteHeader(http.StatusNoContent)
+			}))
+			defer srv.Close()
+
+			ctx := context.Background()
+
+			webhooks := inmemory.NewWebhookRepository()
+
+			log := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+			svc := webhook.NewService(webhooks, log)
+
+			wh, err := svc.Create(ctx, &dto.CreateWebhookRequest{
+				URL:    srv.URL,
+				Secret: testSecret,
+				Events: []string{domain.EventURLCreated},
+			})
+			require.NoError(t, err)
+
+			err = svc.Notify(ctx, domain.EventURLCreated, &domain.URL{Alias: "fjda89fadb"})
+			require.NoError(t, err)
+
+			dispatcher := webhook.NewDispatcher(webhooks, srv.Client(), webhook.DispatcherConfig{
+				PollInterval:   time.Second,
+				Timeout:        time.Second,
+				BatchSize:      10,
+				Workers:        2,
+				MaxAttempts:    tc.given.maxAttempts,
+				InitialBackoff: tc.given.backoff,
+				MaxBackoff:     tc.given.backoff,
+			}, log)
+
+			// When
+			for range tc.given.dispatches {
+				_, err := dispatcher.DispatchDue(ctx)
+				require.NoError(t, err)
+			}
+
+			// Then
+			require.Equal(t, tc.expected.requests, requests.Load())
+
+			deliveries, err := svc.ListDeliveries(ctx, &dto.ListDeliveriesRequest{WebhookID: wh.ID})
+			require.NoError(t, err)
+			require.Len(t, deliveries.Deliveries, 1)
+
+			delivery := deliveries.Deliveries[0]
+			require.Equal(t, tc.expected.status, delivery.Status)
+			require.Equal(t, tc.expected.attempts, delivery.Attempts)
+
+			if tc.expected.postponed {
+				require.Equal(t, http.StatusServiceUnavailable, delivery.ResponseStatus)
+				require.NotEmpty(t, delivery.LastError)
+				require.True(t, delivery.NextAttemptAt.After(time.Now().Add(30*time.Minute)))
+			}
+		})
+	}
+}
+
+func TestDispatcher_Start(t *testing.T) {
+	// Given
+	delivered := make(chan struct{})
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+		close(delivered)
+	}))
+	defer srv.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	webhooks := inmemory.NewWebhookRepository()
+
+	log := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	svc := webhook.NewService(webhooks, log)
+
+	_, err := svc.Create(ctx, &dto.CreateWebhookRequest{
+		URL:    srv.URL,
+		Secret: testSecret,
+		Events: []string{domain.EventURLCreated},
+	})
+	require.NoError(t, err)
+
+	err = svc.Notify(ctx, domain.EventURLCreated, &domain.URL{Alias: "fjda89fadb"})
+	require.NoError(t, err)
+
+	dispatcher := webhook.NewDispatcher(webhooks, srv.Client(), webhook.DispatcherConfig{
+		PollInterval: time.Hour,
+		Timeout:      time.Second,
+		BatchSize:    10,
+		Workers:      1,
+		MaxAttempts:  1,
+	}, log)
+
+	// When
+	done := dispatcher.Start(ctx)
+
+	<-delivered
+	cancel()
+
+	// Then
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("dispatcher didn't stop")
+	}
+}
@@ -0,0 +1,7 @@
+package webhook
+
+import "errors"
+
+var (
+	ErrNotFound = errors.New("webhook not found")
+)
@@ -0,0 +1,13 @@
+package webhook
+
+import "time"
+
+// Option configures optional Service dependencies.
+type Option func(s *Service)
+
+// WithClock sets the function the service uses to get the current time.
+func WithClock(now func() time.Time) Option {
+	return func(s *Service) {
+		s.now = now
+	}
+}
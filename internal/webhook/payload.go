@@ -0,0 +1,43 @@
+package webhook
+
+import (
+	"time"
+
+	"github.com/kodeyeen/shortify/internal/domain"
+)
+
+// payload is the body of webhook deliveries.
+// It mirrors shortify.WebhookPayload of the public package.
+type payload struct {
+	Event      string     `json:"event"`
+	OccurredAt time.Time  `json:"occurred_at"`
+	URL        payloadURL `json:"url"`
+}
+
+type payloadURL struct {
+	Original    string            `json:"original"`
+	Alias       string            `json:"alias"`
+	Title       string            `json:"title"`
+	Description string            `json:"description"`
+	Tags        []string          `json:"tags"`
+	Metadata    map[string]string `json:"metadata"`
+	Clicks      int64             `json:"clicks"`
+	CreatedAt   time.Time         `json:"created_at"`
+}
+
+func newPayload(event string, t time.Time, u *domain.URL) payload {
+	return payload{
+		Event:      event,
+		OccurredAt: t,
+		URL: payloadURL{
+			Original:    u.Original,
+			Alias:       u.Alias,
+			Title:       u.Title,
+			Description: u.Description,
+			Tags:        u.Tags,
+			Metadata:    u.Metadata,
+			Clicks:      u.Clicks,
+			CreatedAt:   u.CreatedAt,
+		},
+	}
+}
@@ -0,0 +1,31 @@
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+	"time"
+)
+
+// Headers sent with every delivery.
+const (
+	HeaderEvent     = "X-Shortify-Event"
+	HeaderDelivery  = "X-Shortify-Delivery"
+	HeaderTimestamp = "X-Shortify-Timestamp"
+	HeaderSignature = "X-Shortify-Signature"
+)
+
+const signaturePrefix = "sha256="
+
+// Sign signs the payload sent at t with the webhook secret. The signature
+// is the hex encoded HMAC-SHA256 of the Unix timestamp, a dot and the payload
+// so receivers can reject replayed deliveries.
+func Sign(secret string, t time.Time, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(t.Unix(), 10)))
+	mac.Write([]byte("."))
+	mac.Write(payload)
+
+	return signaturePrefix + hex.EncodeToString(mac.Sum(nil))
+}
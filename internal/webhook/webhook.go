@@ -0,0 +1,229 @@
+package webhook
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"time"
+
+	"github.com/kodeyeen/shortify/internal/domain"
+	"github.com/kodeyeen/shortify/internal/dto"
+	"github.com/kodeyeen/shortify/internal/persistence"
+)
+
+const secretSize = 32
+
+type Repository interface {
+	Add(ctx context.Context, w *domain.Webhook) (int64, error)
+	FindByID(ctx context.Context, id int64) (*domain.Webhook, error)
+	List(ctx context.Context) ([]*domain.Webhook, error)
+	Delete(ctx context.Context, id int64) error
+
+	AddDeliveries(ctx context.Context, ds []*domain.Delivery) error
+	// ClaimDue returns up to limit pending deliveries due at now postponing
+	// their next attempt till leaseUntil so they aren't claimed twice.
+	ClaimDue(ctx context.Context, now, leaseUntil time.Time, limit int) ([]*domain.Delivery, error)
+	UpdateDelivery(ctx context.Context, d *domain.Delivery) error
+	ListDeliveries(ctx context.Context, webhookID int64, limit int) ([]*domain.Delivery, error)
+}
+
+type Service struct {
+	webhooks Repository
+
+	now func() time.Time
+	log *slog.Logger
+}
+
+func NewService(webhooks Repository, log *slog.Logger, opts ...Option) *Service {
+	s := &Service{
+		webhooks: webhooks,
+
+		now: time.Now,
+		log: log,
+	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	return s
+}
+
+// Create registers new webhook generating its secret unless it's provided
+func (s *Service) Create(ctx context.Context, req *dto.CreateWebhookRequest) (*dto.WebhookResponse, error) {
+	secret := req.Secret
+
+	if secret == "" {
+		b := make([]byte, secretSize)
+
+		_, err := rand.Read(b)
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate secret: %w", err)
+		}
+
+		secret = hex.EncodeToString(b)
+	}
+
+	w := &domain.Webhook{
+		URL:       req.URL,
+		Secret:    secret,
+		Events:    slices.Compact(slices.Sorted(slices.Values(req.Events))),
+		CreatedAt: s.now(),
+	}
+
+	id, err := s.webhooks.Add(ctx, w)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create webhook: %w", err)
+	}
+
+	w.ID = id
+
+	resp := newWebhookResponse(w)
+	resp.Secret = w.Secret
+
+	return resp, nil
+}
+
+// GetByID gets webhook by its ID
+func (s *Service) GetByID(ctx context.Context, req *dto.GetWebhookRequest) (*dto.WebhookResponse, error) {
+	w, err := s.webhooks.FindByID(ctx, req.ID)
+	if err != nil {
+		if errors.Is(err, persistence.ErrWebhookNotFound) {
+			return nil, ErrNotFound
+		}
+
+		return nil, fmt.Errorf("failed to get webhook: %w", err)
+	}
+
+	return newWebhookResponse(w), nil
+}
+
+// List lists all webhooks
+func (s *Service) List(ctx context.Context) (*dto.ListWebhooksResponse, error) {
+	webhooks, err := s.webhooks.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list webhooks: %w", err)
+	}
+
+	resp := &dto.ListWebhooksResponse{
+		Webhooks: make([]*dto.WebhookResponse, 0, len(webhooks)),
+	}
+
+	for _, w := range webhooks {
+		resp.Webhooks = append(resp.Webhooks, newWebhookResponse(w))
+	}
+
+	return resp, nil
+}
+
+// Delete deletes webhook with its deliveries
+func (s *Service) Delete(ctx context.Context, req *dto.DeleteWebhookRequest) error {
+	err := s.webhooks.Delete(ctx, req.ID)
+	if err != nil {
+		if errors.Is(err, persistence.ErrWebhookNotFound) {
+			return ErrNotFound
+		}
+
+		return fmt.Errorf("failed to delete webhook: %w", err)
+	}
+
+	return nil
+}
+
+// ListDeliveries lists the most recent deliveries of the webhook
+func (s *Service) ListDeliveries(ctx context.Context, req *dto.ListDeliveriesRequest) (*dto.ListDeliveriesResponse, error) {
+	_, err := s.webhooks.FindByID(ctx, req.WebhookID)
+	if err != nil {
+		if errors.Is(err, persistence.ErrWebhookNotFound) {
+			return nil, ErrNotFound
+		}
+
+		return nil, fmt.Errorf("failed to get webhook: %w", err)
+	}
+
+	deliveries, err := s.webhooks.ListDeliveries(ctx, req.WebhookID, req.Limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list deliveries: %w", err)
+	}
+
+	resp := &dto.ListDeliveriesResponse{
+		Deliveries: make([]*dto.DeliveryResponse, 0, len(deliveries)),
+	}
+
+	for _, d := range deliveries {
+		resp.Deliveries = append(resp.Deliveries, &dto.DeliveryResponse{
+			ID:             d.ID,
+			Event:          d.Event,
+			Payload:        d.Payload,
+			Status:         d.Status,
+			Attempts:       d.Attempts,
+			NextAttemptAt:  d.NextAttemptAt,
+			LastError:      d.LastError,
+			ResponseStatus: d.ResponseStatus,
+			CreatedAt:      d.CreatedAt,
+			DeliveredAt:    d.DeliveredAt,
+		})
+	}
+
+	return resp, nil
+}
+
+// Notify stores deliveries of the event for every webhook subscribed to it.
+// They are sent by the Dispatcher.
+func (s *Service) Notify(ctx context.Context, event string, u *domain.URL) error {
+	webhooks, err := s.webhooks.List(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list webhooks: %w", err)
+	}
+
+	now := s.now()
+
+	payload, err := json.Marshal(newPayload(event, now, u))
+	if err != nil {
+		return fmt.Errorf("failed to marshal payload: %w", err)
+	}
+
+	var deliveries []*domain.Delivery
+
+	for _, w := range webhooks {
+		if !slices.Contains(w.Events, event) {
+			continue
+		}
+
+		deliveries = append(deliveries, &domain.Delivery{
+			WebhookID:     w.ID,
+			Event:         event,
+			Payload:       payload,
+			Status:        domain.DeliveryStatusPending,
+			NextAttemptAt: now,
+			CreatedAt:     now,
+		})
+	}
+
+	if len(deliveries) == 0 {
+		return nil
+	}
+
+	err = s.webhooks.AddDeliveries(ctx, deliveries)
+	if err != nil {
+		return fmt.Errorf("failed to add deliveries: %w", err)
+	}
+
+	s.log.Debug("deliveries added", slog.String("event", event), slog.Int("count", len(deliveries)))
+
+	return nil
+}
+
+func newWebhookResponse(w *domain.Webhook) *dto.WebhookResponse {
+	return &dto.WebhookResponse{
+		ID:        w.ID,
+		URL:       w.URL,
+		Events:    w.Events,
+		CreatedAt: w.CreatedAt,
+	}
+}
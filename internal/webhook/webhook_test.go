@@ -0,0 +1,239 @@
+package webhook_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/kodeyeen/shortify/internal/domain"
+	"github.com/kodeyeen/shortify/internal/dto"
+	"github.com/kodeyeen/shortify/internal/persistence"
+	"github.com/kodeyeen/shortify/internal/persistence/inmemory"
+	mockpers "github.com/kodeyeen/shortify/internal/persistence/mock"
+	"github.com/kodeyeen/shortify/internal/webhook"
+	"github.com/kodeyeen/shortify/v1"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestService_Create(t *testing.T) {
+	createdAt := time.Date(2025, time.March, 1, 12, 0, 0, 0, time.UTC)
+
+	type Given struct {
+		req *dto.CreateWebhookRequest
+	}
+
+	type Expected struct {
+		events    []string
+		secret    string
+		secretLen int
+	}
+
+	testCases := map[string]struct {
+		given    Given
+		expected Expected
+	}{
+		"Secret provided": {
+			Given{
+				req: &dto.CreateWebhookRequest{
+					URL:    "https://example.com/hook",
+					Secret: "0123456789abcdef",
+					Events: []string{domain.EventURLDeleted, domain.EventURLCreated, domain.EventURLDeleted},
+				},
+			},
+			Expected{
+				events:    []string{domain.EventURLCreated, domain.EventURLDeleted},
+				secret:    "0123456789abcdef",
+				secretLen: 16,
+			},
+		},
+		"Secret generated": {
+			Given{
+				req: &dto.CreateWebhookRequest{
+					URL:    "https://example.com/hook",
+					Events: []string{domain.EventURLCreated},
+				},
+			},
+			Expected{
+				events:    []string{domain.EventURLCreated},
+				secretLen: 64,
+			},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			// Given
+			ctx := context.Background()
+
+			webhooks := mockpers.NewWebhookRepository(t)
+			webhooks.On("Add", ctx, mock.MatchedBy(func(w *domain.Webhook) bool {
+				return w.URL == tc.given.req.URL && w.CreatedAt.Equal(createdAt)
+			})).
+				Return(int64(1), nil).
+				Once()
+
+			log := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+			svc := webhook.NewService(webhooks, log, webhook.WithClock(func() time.Time { return createdAt }))
+
+			// When
+			resp, err := svc.Create(ctx, tc.given.req)
+
+			// Then
+			require.NoError(t, err)
+			require.Equal(t, int64(1), resp.ID)
+			require.Equal(t, tc.expected.events, resp.Events)
+			require.Len(t, resp.Secret, tc.expected.secretLen)
+
+			if tc.expected.secret != "" {
+				require.Equal(t, tc.expected.secret, resp.Secret)
+			}
+		})
+	}
+}
+
+func TestService_GetByID(t *testing.T) {
+	type Given struct {
+		webhook    *domain.Webhook
+		webhookErr error
+	}
+
+	type Expected struct {
+		svcResp *dto.WebhookResponse
+		svcErr  error
+	}
+
+	testCases := map[string]struct {
+		given    Given
+		expected Expected
+	}{
+		"Success": {
+			Given{
+				webhook: &domain.Webhook{
+					ID:     1,
+					URL:    "https://example.com/hook",
+					Secret: "0123456789abcdef",
+					Events: []string{domain.EventURLCreated},
+				},
+			},
+			Expected{
+				svcResp: &dto.WebhookResponse{
+					ID:     1,
+					URL:    "https://example.com/hook",
+					Events: []string{domain.EventURLCreated},
+				},
+			},
+		},
+		"Not found": {
+			Given{
+				webhookErr: persistence.ErrWebhookNotFound,
+			},
+			Expected{
+				svcErr: webhook.ErrNotFound,
+			},
+		},
+		"Other error": {
+			Given{
+				webhookErr: errors.New("some db error"),
+			},
+			Expected{
+				svcErr: fmt.Errorf("failed to get webhook: %w", errors.New("some db error")),
+			},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			// Given
+			ctx := context.Background()
+
+			webhooks := mockpers.NewWebhookRepository(t)
+			webhooks.On("FindByID", ctx, int64(1)).
+				Return(tc.given.webhook, tc.given.webhookErr).
+				Once()
+
+			log := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+			svc := webhook.NewService(webhooks, log)
+
+			// When
+			resp, err := svc.GetByID(ctx, &dto.GetWebhookRequest{ID: 1})
+
+			// Then
+			require.Equal(t, tc.expected.svcResp, resp)
+			require.Equal(t, tc.expected.svcErr, err)
+		})
+	}
+}
+
+func TestService_Notify(t *testing.T) {
+	// Given
+	ctx := context.Background()
+	occurredAt := time.Date(2025, time.March, 1, 12, 0, 0, 0, time.UTC)
+
+	webhooks := inmemory.NewWebhookRepository()
+
+	log := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	svc := webhook.NewService(webhooks, log, webhook.WithClock(func() time.Time { return occurredAt }))
+
+	subscribed, err := svc.Create(ctx, &dto.CreateWebhookRequest{
+		URL:    "https://example.com/subscribed",
+		Events: []string{domain.EventURLCreated, domain.EventURLDeleted},
+	})
+	require.NoError(t, err)
+
+	other, err := svc.Create(ctx, &dto.CreateWebhookRequest{
+		URL:    "https://example.com/other",
+		Events: []string{domain.EventURLUpdated},
+	})
+	require.NoError(t, err)
+
+	u := &domain.URL{
+		ID:       1,
+		Original: "https://example.com/long",
+		Alias:    "fjda89fadb",
+		Tags:     []string{"promo"},
+	}
+
+	// When
+	err = svc.Notify(ctx, domain.EventURLCreated, u)
+
+	// Then
+	require.NoError(t, err)
+
+	deliveries, err := svc.ListDeliveries(ctx, &dto.ListDeliveriesRequest{WebhookID: subscribed.ID})
+	require.NoError(t, err)
+	require.Len(t, deliveries.Deliveries, 1)
+
+	delivery := deliveries.Deliveries[0]
+	require.Equal(t, domain.EventURLCreated, delivery.Event)
+	require.Equal(t, domain.DeliveryStatusPending, delivery.Status)
+
+	var payload shortify.WebhookPayload
+
+	require.NoError(t, json.Unmarshal(delivery.Payload, &payload))
+	require.Equal(t, shortify.WebhookPayload{
+		Event:      domain.EventURLCreated,
+		OccurredAt: occurredAt,
+		URL: shortify.WebhookURL{
+			Original: "https://example.com/long",
+			Alias:    "fjda89fadb",
+			Tags:     []string{"promo"},
+		},
+	}, payload)
+
+	deliveries, err = svc.ListDeliveries(ctx, &dto.ListDeliveriesRequest{WebhookID: other.ID})
+	require.NoError(t, err)
+	require.Empty(t, deliveries.Deliveries)
+}
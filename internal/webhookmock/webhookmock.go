@@ -0,0 +1,320 @@
+// Code generated by mockery. DO NOT EDIT.
+
+package webhookmock
+
+import (
+	context "context"
+
+	dto "github.com/kodeyeen/shortify/internal/dto"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// Service is an autogenerated mock type for the WebhookService type
+type Service struct {
+	mock.Mock
+}
+
+type Service_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *Service) EXPECT() *Service_Expecter {
+	return &Service_Expecter{mock: &_m.Mock}
+}
+
+// Create provides a mock function with given fields: ctx, req
+func (_m *Service) Create(ctx context.Context, req *dto.CreateWebhookRequest) (*dto.WebhookResponse, error) {
+	ret := _m.Called(ctx, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Create")
+	}
+
+	var r0 *dto.WebhookResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *dto.CreateWebhookRequest) (*dto.WebhookResponse, error)); ok {
+		return rf(ctx, req)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *dto.CreateWebhookRequest) *dto.WebhookResponse); ok {
+		r0 = rf(ctx, req)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*dto.WebhookResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *dto.CreateWebhookRequest) error); ok {
+		r1 = rf(ctx, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Service_Create_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Create'
+type Service_Create_Call struct {
+	*mock.Call
+}
+
+// Create is a helper method to define mock.On call
+//   - ctx context.Context
+//   - req *dto.CreateWebhookRequest
+func (_e *Service_Expecter) Create(ctx interface{}, req interface{}) *Service_Create_Call {
+	return &Service_Create_Call{Call: _e.mock.On("Create", ctx, req)}
+}
+
+func (_c *Service_Create_Call) Run(run func(ctx context.Context, req *dto.CreateWebhookRequest)) *Service_Create_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*dto.CreateWebhookRequest))
+	})
+	return _c
+}
+
+func (_c *Service_Create_Call) Return(_a0 *dto.WebhookResponse, _a1 error) *Service_Create_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Service_Create_Call) RunAndReturn(run func(context.Context, *dto.CreateWebhookRequest) (*dto.WebhookResponse, error)) *Service_Create_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Delete provides a mock function with given fields: ctx, req
+func (_m *Service) Delete(ctx context.Context, req *dto.DeleteWebhookRequest) error {
+	ret := _m.Called(ctx, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Delete")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *dto.DeleteWebhookRequest) error); ok {
+		r0 = rf(ctx, req)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Service_Delete_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Delete'
+type Service_Delete_Call struct {
+	*mock.Call
+}
+
+// Delete is a helper method to define mock.On call
+//   - ctx context.Context
+//   - req *dto.DeleteWebhookRequest
+func (_e *Service_Expecter) Delete(ctx interface{}, req interface{}) *Service_Delete_Call {
+	return &Service_Delete_Call{Call: _e.mock.On("Delete", ctx, req)}
+}
+
+func (_c *Service_Delete_Call) Run(run func(ctx context.Context, req *dto.DeleteWebhookRequest)) *Service_Delete_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*dto.DeleteWebhookRequest))
+	})
+	return _c
+}
+
+func (_c *Service_Delete_Call) Return(_a0 error) *Service_Delete_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *Service_Delete_Call) RunAndReturn(run func(context.Context, *dto.DeleteWebhookRequest) error) *Service_Delete_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetByID provides a mock function with given fields: ctx, req
+func (_m *Service) GetByID(ctx context.Context, req *dto.GetWebhookRequest) (*dto.WebhookResponse, error) {
+	ret := _m.Called(ctx, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetByID")
+	}
+
+	var r0 *dto.WebhookResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *dto.GetWebhookRequest) (*dto.WebhookResponse, error)); ok {
+		return rf(ctx, req)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *dto.GetWebhookRequest) *dto.WebhookResponse); ok {
+		r0 = rf(ctx, req)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*dto.WebhookResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *dto.GetWebhookRequest) error); ok {
+		r1 = rf(ctx, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Service_GetByID_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetByID'
+type Service_GetByID_Call struct {
+	*mock.Call
+}
+
+// GetByID is a helper method to define mock.On call
+//   - ctx context.Context
+//   - req *dto.GetWebhookRequest
+func (_e *Service_Expecter) GetByID(ctx interface{}, req interface{}) *Service_GetByID_Call {
+	return &Service_GetByID_Call{Call: _e.mock.On("GetByID", ctx, req)}
+}
+
+func (_c *Service_GetByID_Call) Run(run func(ctx context.Context, req *dto.GetWebhookRequest)) *Service_GetByID_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*dto.GetWebhookRequest))
+	})
+	return _c
+}
+
+func (_c *Service_GetByID_Call) Return(_a0 *dto.WebhookResponse, _a1 error) *Service_GetByID_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Service_GetByID_Call) RunAndReturn(run func(context.Context, *dto.GetWebhookRequest) (*dto.WebhookResponse, error)) *Service_GetByID_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// List provides a mock function with given fields: ctx
+func (_m *Service) List(ctx context.Context) (*dto.ListWebhooksResponse, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for List")
+	}
+
+	var r0 *dto.ListWebhooksResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) (*dto.ListWebhooksResponse, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) *dto.ListWebhooksResponse); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*dto.ListWebhooksResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Service_List_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'List'
+type Service_List_Call struct {
+	*mock.Call
+}
+
+// List is a helper method to define mock.On call
+//   - ctx context.Context
+func (_e *Service_Expecter) List(ctx interface{}) *Service_List_Call {
+	return &Service_List_Call{Call: _e.mock.On("List", ctx)}
+}
+
+func (_c *Service_List_Call) Run(run func(ctx context.Context)) *Service_List_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context))
+	})
+	return _c
+}
+
+func (_c *Service_List_Call) Return(_a0 *dto.ListWebhooksResponse, _a1 error) *Service_List_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Service_List_Call) RunAndReturn(run func(context.Context) (*dto.ListWebhooksResponse, error)) *Service_List_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListDeliveries provides a mock function with given fields: ctx, req
+func (_m *Service) ListDeliveries(ctx context.Context, req *dto.ListDeliveriesRequest) (*dto.ListDeliveriesResponse, error) {
+	ret := _m.Called(ctx, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListDeliveries")
+	}
+
+	var r0 *dto.ListDeliveriesResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *dto.ListDeliveriesRequest) (*dto.ListDeliveriesResponse, error)); ok {
+		return rf(ctx, req)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *dto.ListDeliveriesRequest) *dto.ListDeliveriesResponse); ok {
+		r0 = rf(ctx, req)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*dto.ListDeliveriesResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *dto.ListDeliveriesRequest) error); ok {
+		r1 = rf(ctx, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Service_ListDeliveries_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListDeliveries'
+type Service_ListDeliveries_Call struct {
+	*mock.Call
+}
+
+// ListDeliveries is a helper method to define mock.On call
+//   - ctx context.Context
+//   - req *dto.ListDeliveriesRequest
+func (_e *Service_Expecter) ListDeliveries(ctx interface{}, req interface{}) *Service_ListDeliveries_Call {
+	return &Service_ListDeliveries_Call{Call: _e.mock.On("ListDeliveries", ctx, req)}
+}
+
+func (_c *Service_ListDeliveries_Call) Run(run func(ctx context.Context, req *dto.ListDeliveriesRequest)) *Service_ListDeliveries_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*dto.ListDeliveriesRequest))
+	})
+	return _c
+}
+
+func (_c *Service_ListDeliveries_Call) Return(_a0 *dto.ListDeliveriesResponse, _a1 error) *Service_ListDeliveries_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Service_ListDeliveries_Call) RunAndReturn(run func(context.Context, *dto.ListDeliveriesRequest) (*dto.ListDeliveriesResponse, error)) *Service_ListDeliveries_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewService creates a new instance of Service. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewService(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *Service {
+	mock := &Service{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1 @@
+ALTER TABLE urls ADD COLUMN IF NOT EXISTS query_mode text NOT NULL DEFAULT 'ignore';
@@ -0,0 +1,3 @@
+ALTER TABLE urls
+    DROP COLUMN IF EXISTS preview,
+    DROP COLUMN IF EXISTS created_at;
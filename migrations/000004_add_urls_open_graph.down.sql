@@ -0,0 +1,5 @@
+ALTER TABLE urls
+    DROP COLUMN IF EXISTS og_title,
+    DROP COLUMN IF EXISTS og_description,
+    DROP COLUMN IF EXISTS og_image,
+    DROP COLUMN IF EXISTS og_fetched_at;
@@ -0,0 +1,5 @@
+ALTER TABLE urls
+    ADD COLUMN IF NOT EXISTS og_title text NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS og_description text NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS og_image text NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS og_fetched_at timestamptz;
@@ -0,0 +1,8 @@
+DROP INDEX IF EXISTS urls_metadata_idx;
+DROP INDEX IF EXISTS urls_tags_idx;
+
+ALTER TABLE urls
+    DROP COLUMN IF EXISTS title,
+    DROP COLUMN IF EXISTS description,
+    DROP COLUMN IF EXISTS tags,
+    DROP COLUMN IF EXISTS metadata;
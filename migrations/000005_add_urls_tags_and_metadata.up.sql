@@ -0,0 +1,8 @@
+ALTER TABLE urls
+    ADD COLUMN IF NOT EXISTS title text NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS description text NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS tags text[] NOT NULL DEFAULT '{}',
+    ADD COLUMN IF NOT EXISTS metadata jsonb NOT NULL DEFAULT '{}';
+
+CREATE INDEX IF NOT EXISTS urls_tags_idx ON urls USING GIN (tags);
+CREATE INDEX IF NOT EXISTS urls_metadata_idx ON urls USING GIN (metadata jsonb_path_ops);
@@ -0,0 +1 @@
+CREATE SEQUENCE IF NOT EXISTS alias_seq;
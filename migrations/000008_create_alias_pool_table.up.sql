@@ -0,0 +1,4 @@
+CREATE TABLE IF NOT EXISTS alias_pool (
+    alias text PRIMARY KEY,
+    reserved_at timestamptz NOT NULL
+);
@@ -0,0 +1 @@
+DROP INDEX IF EXISTS urls_alias_lower_idx;
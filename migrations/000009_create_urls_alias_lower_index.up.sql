@@ -0,0 +1 @@
+CREATE INDEX IF NOT EXISTS urls_alias_lower_idx ON urls (lower(alias));
@@ -0,0 +1,9 @@
+CREATE TABLE IF NOT EXISTS alias_history (
+    alias text NOT NULL,
+    url_id bigint NOT NULL REFERENCES urls (id) ON DELETE CASCADE,
+    retired_at timestamptz NOT NULL,
+    PRIMARY KEY (alias, url_id)
+);
+
+CREATE INDEX IF NOT EXISTS alias_history_url_id_idx ON alias_history (url_id, retired_at DESC);
+CREATE INDEX IF NOT EXISTS alias_history_alias_lower_idx ON alias_history (lower(alias));
@@ -0,0 +1,13 @@
+-- entries outlive their URLs so there is no foreign key
+CREATE TABLE IF NOT EXISTS url_audit (
+    id bigserial PRIMARY KEY,
+    url_id bigint NOT NULL,
+    action text NOT NULL,
+    actor text NOT NULL DEFAULT '',
+    request_id text NOT NULL DEFAULT '',
+    before jsonb,
+    after jsonb,
+    created_at timestamptz NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS url_audit_url_id_idx ON url_audit (url_id, id);
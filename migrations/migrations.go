@@ -0,0 +1,9 @@
+// Package migrations embeds the SQL migrations of the Postgres schema.
+package migrations
+
+import "embed"
+
+// FS contains the <version>_<name>.up.sql and <version>_<name>.down.sql files.
+//
+//go:embed *.sql
+var FS embed.FS
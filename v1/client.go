@@ -0,0 +1,241 @@
+package shortify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	neturl "net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	DefaultMaxRetries     = 3
+	DefaultInitialBackoff = 100 * time.Millisecond
+	DefaultMaxBackoff     = 5 * time.Second
+)
+
+// ActorHeader names who makes the request in the audit log of the changed URLs.
+const ActorHeader = "X-Actor"
+
+// Client is a client of the Shortify HTTP API.
+//
+// Requests answered with 429 statuses are retried with exponential backoff.
+// Requests with idempotent methods are retried after 5xx statuses too, while
+// POST and PATCH requests aren't as the server may have already applied them.
+// Unsuccessful responses are returned as *Error which matches
+// ErrNotFound, ErrAlreadyExists and the other sentinel errors with errors.Is.
+type Client struct {
+	baseURL    string
+	httpClient *http.Client
+	header     http.Header
+
+	maxRetries     int
+	initialBackoff time.Duration
+	maxBackoff     time.Duration
+}
+
+// ClientOption configures Client.
+type ClientOption func(c *Client)
+
+// WithHTTPClient sets the HTTP client used to send requests.
+func WithHTTPClient(httpClient *http.Client) ClientOption {
+	return func(c *Client) {
+		c.httpClient = httpClient
+	}
+}
+
+// WithRetries sets the maximum number of retries of a request. Zero disables retries.
+func WithRetries(maxRetries int) ClientOption {
+	return func(c *Client) {
+		c.maxRetries = maxRetries
+	}
+}
+
+// WithBackoff sets the delay before the first retry and the maximum delay.
+// The delay doubles with every retry.
+func WithBackoff(initial, maxDelay time.Duration) ClientOption {
+	return func(c *Client) {
+		c.initialBackoff = initial
+		c.maxBackoff = maxDelay
+	}
+}
+
+// WithHeader sets a header sent with every request.
+func WithHeader(key, value string) ClientOption {
+	return func(c *Client) {
+		c.header.Set(key, value)
+	}
+}
+
+// WithActor sets who the changes made by the client are attributed to in the audit log.
+func WithActor(actor string) ClientOption {
+	return WithHeader(ActorHeader, actor)
+}
+
+// NewClient creates a client of the API served at baseURL, e.g. "https://shortify.example.com".
+func NewClient(baseURL string, opts ...ClientOption) *Client {
+	c := &Client{
+		baseURL:    strings.TrimSuffix(baseURL, "/"),
+		httpClient: http.DefaultClient,
+		header:     http.Header{},
+
+		maxRetries:     DefaultMaxRetries,
+		initialBackoff: DefaultInitialBackoff,
+		maxBackoff:     DefaultMaxBackoff,
+	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return c
+}
+
+// do sends the request encoding in as JSON body when it's not nil
+// and decoding the successful response into out when it's not nil.
+func (c *Client) do(ctx context.Context, method, path string, query neturl.Values, in, out any) error {
+	resp, err := c.send(ctx, method, path, query, in, c.httpClient)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		return decodeError(resp)
+	}
+
+	if out == nil {
+		return nil
+	}
+
+	err = json.NewDecoder(resp.Body).Decode(out)
+	if err != nil {
+		return fmt.Errorf("shortify: failed to decode response: %w", err)
+	}
+
+	return nil
+}
+
+// send sends the request retrying it while the response status is retryable.
+func (c *Client) send(ctx context.Context, method, path string, query neturl.Values, in any, httpClient *http.Client) (*http.Response, error) {
+	var body []byte
+
+	if in != nil {
+		var err error
+
+		body, err = json.Marshal(in)
+		if err != nil {
+			return nil, fmt.Errorf("shortify: failed to encode request: %w", err)
+		}
+	}
+
+	u := c.baseURL + path
+	if len(query) > 0 {
+		u += "?" + query.Encode()
+	}
+
+	for attempt := 0; ; attempt++ {
+		req, err := http.NewRequestWithContext(ctx, method, u, bytes.NewReader(body))
+		if err != nil {
+			return nil, fmt.Errorf("shortify: failed to create request: %w", err)
+		}
+
+		for key, values := range c.header {
+			req.Header[key] = values
+		}
+
+		req.Header.Set("Accept", "application/json")
+
+		if in != nil {
+			req.Header.Set("Content-Type", "application/json")
+		}
+
+		resp, err := httpClient.Do(req)
+		if err != nil {
+			return nil, fmt.Errorf("shortify: failed to send request: %w", err)
+		}
+
+		if !retryable(method, resp.StatusCode) || attempt >= c.maxRetries {
+			return resp, nil
+		}
+
+		delay := c.backoff(attempt, resp.Header.Get("Retry-After"))
+
+		// drain the body so the connection can be reused
+		_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 1<<16))
+		resp.Body.Close()
+
+		timer := time.NewTimer(delay)
+
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// backoff returns the delay before the retry following the attempt.
+// Retry-After given in seconds takes precedence when it's within the maximum delay.
+func (c *Client) backoff(attempt int, retryAfter string) time.Duration {
+	if seconds, err := strconv.Atoi(retryAfter); err == nil && seconds >= 0 {
+		if delay := time.Duration(seconds) * time.Second; delay <= c.maxBackoff {
+			return delay
+		}
+	}
+
+	delay := c.initialBackoff << attempt
+	if delay <= 0 || delay > c.maxBackoff {
+		delay = c.maxBackoff
+	}
+
+	// jitter spreads the retries of concurrent clients
+	return delay/2 + rand.N(delay/2+1)
+}
+
+// retryable reports whether the request with the method can be sent again after the response status.
+// A rate limited request wasn't processed, so it's retried regardless of the method.
+func retryable(method string, statusCode int) bool {
+	if statusCode == http.StatusTooManyRequests {
+		return true
+	}
+
+	return statusCode >= http.StatusInternalServerError && idempotent(method)
+}
+
+func idempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	default:
+		return false
+	}
+}
+
+func decodeError(resp *http.Response) error {
+	apiErr := &Error{
+		StatusCode: resp.StatusCode,
+		Message:    http.StatusText(resp.StatusCode),
+	}
+
+	var errResp ErrorResponse
+
+	err := json.NewDecoder(io.LimitReader(resp.Body, 1<<16)).Decode(&errResp)
+	if err == nil && errResp.Message != "" {
+		apiErr.Message = errResp.Message
+	}
+
+	return apiErr
+}
+
+// escape escapes a path segment.
+func escape(segment string) string {
+	return neturl.PathEscape(segment)
+}
@@ -0,0 +1,470 @@
+package shortify_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	neturl "net/url"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	httpdel "github.com/kodeyeen/shortify/internal/delivery/http/v1"
+	"github.com/kodeyeen/shortify/internal/generation/keyspace"
+	"github.com/kodeyeen/shortify/internal/generation/rand"
+	"github.com/kodeyeen/shortify/internal/persistence/inmemory"
+	"github.com/kodeyeen/shortify/internal/url"
+	"github.com/kodeyeen/shortify/internal/webhook"
+	"github.com/kodeyeen/shortify/v1"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient_URLs(t *testing.T) {
+	// Given
+	ctx := context.Background()
+	client := shortify.NewClient(newServer(t).URL)
+
+	// When
+	created, err := client.CreateURL(ctx, shortify.CreateURLRequest{
+		Original: "https://example.com/p/{path}",
+		Tags:     []string{"promo"},
+	})
+
+	// Then
+	require.NoError(t, err)
+	require.Len(t, created.Alias, 10)
+	require.Equal(t, "ignore", created.QueryMode)
+
+	_, err = client.CreateURL(ctx, shortify.CreateURLRequest{Original: "https://example.com/p/{path}"})
+	require.ErrorIs(t, err, shortify.ErrAlreadyExists)
+
+	_, err = client.CreateURL(ctx, shortify.CreateURLRequest{Original: "invalidurl"})
+	require.ErrorIs(t, err, shortify.ErrBadRequest)
+
+	var apiErr *shortify.Error
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, "Field 'original' is not a valid URL", apiErr.Message)
+
+	location, err := client.Resolve(ctx, created.Alias, "shoes/42", nil)
+	require.NoError(t, err)
+	require.Equal(t, "https://example.com/p/shoes/42", location)
+
+	got, err := client.GetURL(ctx, created.Alias)
+	require.NoError(t, err)
+	require.Equal(t, int64(1), got.Clicks)
+
+	title := "Shoes"
+	updated, err := client.UpdateURL(ctx, created.Alias, shortify.UpdateURLRequest{Title: &title})
+	require.NoError(t, err)
+	require.Equal(t, "Shoes", updated.Title)
+
+	capacity, err := client.AliasCapacity(ctx)
+	require.NoError(t, err)
+	require.Equal(t, "random", capacity.Provider)
+	require.Equal(t, "3656158440062976", capacity.KeyspaceSize)
+	require.Equal(t, int64(1), capacity.Occupied)
+
+	list, err := client.ListURLs(ctx, shortify.ListURLsRequest{Tag: "promo"})
+	require.NoError(t, err)
+	require.Len(t, list.URLs, 1)
+	require.Equal(t, created.Alias, list.URLs[0].Alias)
+
+	err = client.DeleteURL(ctx, created.Alias)
+	require.NoError(t, err)
+
+	_, err = client.GetURL(ctx, created.Alias)
+	require.ErrorIs(t, err, shortify.ErrDeleted)
+
+	_, err = client.Resolve(ctx, created.Alias, "", nil)
+	require.ErrorIs(t, err, shortify.ErrDeleted)
+
+	restored, err := client.RestoreURL(ctx, created.Alias)
+	require.NoError(t, err)
+	require.Equal(t, "Shoes", restored.Title)
+
+	_, err = client.RestoreURL(ctx, created.Alias)
+	require.ErrorIs(t, err, shortify.ErrAlreadyExists)
+
+	_, err = client.GetURL(ctx, "missing")
+	require.ErrorIs(t, err, shortify.ErrNotFound)
+
+	_, err = client.Resolve(ctx, "missing", "", nil)
+	require.ErrorIs(t, err, shortify.ErrNotFound)
+}
+
+func TestClient_Resolve_Preview(t *testing.T) {
+	// Given
+	ctx := context.Background()
+	client := shortify.NewClient(newServer(t).URL)
+
+	created, err := client.CreateURL(ctx, shortify.CreateURLRequest{
+		Original:  "https://example.com/long",
+		QueryMode: "merge",
+	})
+	require.NoError(t, err)
+
+	// When
+	location, err := client.Resolve(ctx, created.Alias, "", neturl.Values{"utm_source": {"newsletter"}})
+
+	// Then
+	require.NoError(t, err)
+	require.Equal(t, "https://example.com/long?utm_source=newsletter", location)
+
+	_, err = client.Resolve(ctx, created.Alias+"+", "", nil)
+	require.ErrorIs(t, err, shortify.ErrPreview)
+}
+
+func TestClient_AliasHistory(t *testing.T) {
+	// Given
+	ctx := context.Background()
+	client := shortify.NewClient(newServer(t).URL)
+
+	created, err := client.CreateURL(ctx, shortify.CreateURLRequest{Original: "https://example.com/sale"})
+	require.NoError(t, err)
+
+	other, err := client.CreateURL(ctx, shortify.CreateURLRequest{Original: "https://example.com/other"})
+	require.NoError(t, err)
+
+	// When
+	alias := "spring-sale"
+	updated, err := client.UpdateURL(ctx, created.Alias, shortify.UpdateURLRequest{Alias: &alias})
+
+	// Then
+	require.NoError(t, err)
+	require.Equal(t, "spring-sale", updated.Alias)
+
+	location, err := client.Resolve(ctx, created.Alias, "", nil)
+	require.NoError(t, err)
+	require.Equal(t, "https://example.com/sale", location)
+
+	history, err := client.ListAliases(ctx, "spring-sale")
+	require.NoError(t, err)
+	require.Equal(t, "spring-sale", history.Alias)
+	require.Len(t, history.Retired, 1)
+	require.Equal(t, created.Alias, history.Retired[0].Alias)
+
+	_, err = client.UpdateURL(ctx, other.Alias, shortify.UpdateURLRequest{Alias: &alias})
+	require.ErrorIs(t, err, shortify.ErrAlreadyExists)
+}
+
+func TestClient_URLHistory(t *testing.T) {
+	// Given
+	ctx := context.Background()
+	client := shortify.NewClient(newServer(t).URL, shortify.WithActor("alice"))
+
+	created, err := client.CreateURL(ctx, shortify.CreateURLRequest{Original: "https://example.com/old"})
+	require.NoError(t, err)
+
+	original := "https://example.com/new"
+
+	_, err = client.UpdateURL(ctx, created.Alias, shortify.UpdateURLRequest{Original: &original})
+	require.NoError(t, err)
+
+	// When
+	history, err := client.URLHistory(ctx, created.Alias)
+
+	// Then
+	require.NoError(t, err)
+	require.Len(t, history.Versions, 2)
+	require.Equal(t, "updated", history.Versions[1].Action)
+	require.Equal(t, "alice", history.Versions[1].Actor)
+	require.NotEmpty(t, history.Versions[1].RequestID)
+	require.Equal(t, []shortify.FieldChange{
+		{Field: "original", Before: "https://example.com/old", After: "https://example.com/new"},
+	}, history.Versions[1].Changes)
+
+	rolledBack, err := client.RollbackURL(ctx, created.Alias, 1)
+	require.NoError(t, err)
+	require.Equal(t, "https://example.com/old", rolledBack.Original)
+
+	_, err = client.RollbackURL(ctx, created.Alias, 10)
+	require.ErrorIs(t, err, shortify.ErrNotFound)
+}
+
+func TestClient_Webhooks(t *testing.T) {
+	// Given
+	ctx := context.Background()
+	client := shortify.NewClient(newServer(t).URL)
+
+	// When
+	created, err := client.CreateWebhook(ctx, shortify.CreateWebhookRequest{
+		URL:    "https://example.com/hook",
+		Events: []string{"url.created"},
+	})
+
+	// Then
+	require.NoError(t, err)
+	require.NotEmpty(t, created.Secret)
+
+	got, err := client.GetWebhook(ctx, created.ID)
+	require.NoError(t, err)
+	require.Empty(t, got.Secret)
+	require.Equal(t, created.URL, got.URL)
+
+	list, err := client.ListWebhooks(ctx)
+	require.NoError(t, err)
+	require.Len(t, list.Webhooks, 1)
+
+	deliveries, err := client.ListWebhookDeliveries(ctx, created.ID, 10)
+	require.NoError(t, err)
+	require.Empty(t, deliveries.Deliveries)
+
+	err = client.DeleteWebhook(ctx, created.ID)
+	require.NoError(t, err)
+
+	_, err = client.GetWebhook(ctx, created.ID)
+	require.ErrorIs(t, err, shortify.ErrNotFound)
+}
+
+func TestClient_Retries(t *testing.T) {
+	testCases := map[string]struct {
+		status   int
+		failures int32
+		retries  int
+		err      error
+		requests int32
+	}{
+		"Retried until success": {
+			status:   http.StatusServiceUnavailable,
+			failures: 2,
+			retries:  3,
+			err:      nil,
+			requests: 3,
+		},
+		"Rate limited": {
+			status:   http.StatusTooManyRequests,
+			failures: 10,
+			retries:  2,
+			err:      shortify.ErrRateLimited,
+			requests: 3,
+		},
+		"Server error": {
+			status:   http.StatusInternalServerError,
+			failures: 10,
+			retries:  1,
+			err:      shortify.ErrServer,
+			requests: 2,
+		},
+		"Not retried": {
+			status:   http.StatusNotFound,
+			failures: 10,
+			retries:  3,
+			err:      shortify.ErrNotFound,
+			requests: 1,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			// Given
+			var requests atomic.Int32
+
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if requests.Add(1) <= tc.failures {
+					w.Header().Set("Retry-After", "0")
+					w.WriteHeader(tc.status)
+					return
+				}
+
+				_, _ = w.Write([]byte(`{"original": "https://example.com/long", "alias": "fjda89fadb"}`))
+			}))
+			defer srv.Close()
+
+			client := shortify.NewClient(srv.URL,
+				shortify.WithHTTPClient(srv.Client()),
+				shortify.WithRetries(tc.retries),
+				shortify.WithBackoff(time.Millisecond, 10*time.Millisecond),
+			)
+
+			// When
+			_, err := client.GetURL(context.Background(), "fjda89fadb")
+
+			// Then
+			require.ErrorIs(t, err, tc.err)
+			require.Equal(t, tc.requests, requests.Load())
+		})
+	}
+}
+
+func TestClient_Retries_NotIdempotent(t *testing.T) {
+	testCases := map[string]struct {
+		status   int
+		err      error
+		requests int32
+	}{
+		"Rate limited": {
+			status:   http.StatusTooManyRequests,
+			err:      shortify.ErrRateLimited,
+			requests: 3,
+		},
+		"Server error": {
+			status:   http.StatusServiceUnavailable,
+			err:      shortify.ErrServer,
+			requests: 1,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			// Given
+			var requests atomic.Int32
+
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				requests.Add(1)
+				w.Header().Set("Retry-After", "0")
+				w.WriteHeader(tc.status)
+			}))
+			defer srv.Close()
+
+			client := shortify.NewClient(srv.URL,
+				shortify.WithHTTPClient(srv.Client()),
+				shortify.WithRetries(2),
+				shortify.WithBackoff(time.Millisecond, 10*time.Millisecond),
+			)
+
+			// When
+			_, err := client.CreateURL(context.Background(), shortify.CreateURLRequest{Original: "https://example.com/long"})
+
+			// Then
+			require.ErrorIs(t, err, tc.err)
+			require.Equal(t, tc.requests, requests.Load())
+		})
+	}
+}
+
+func TestClient_Retries_ContextCanceled(t *testing.T) {
+	// Given
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	client := shortify.NewClient(srv.URL, shortify.WithBackoff(time.Hour, time.Hour))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	// When
+	_, err := client.GetURL(ctx, "fjda89fadb")
+
+	// Then
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestClient_ExportImport(t *testing.T) {
+	for _, format := range []string{shortify.FormatCSV, shortify.FormatJSONL} {
+		t.Run(format, func(t *testing.T) {
+			t.Parallel()
+
+			// Given
+			ctx := context.Background()
+			source := shortify.NewClient(newServer(t).URL)
+			target := shortify.NewClient(newServer(t).URL)
+
+			first, err := source.CreateURL(ctx, shortify.CreateURLRequest{
+				Original: "https://example.com/1",
+				Tags:     []string{"promo"},
+				Metadata: map[string]string{"team": "a"},
+			})
+			require.NoError(t, err)
+
+			second, err := source.CreateURL(ctx, shortify.CreateURLRequest{Original: "https://example.com/2"})
+			require.NoError(t, err)
+
+			var file bytes.Buffer
+
+			// When
+			err = source.ExportURLs(ctx, &file, shortify.ExportURLsRequest{Format: format})
+			require.NoError(t, err)
+
+			data := file.Bytes()
+
+			dryRun, err := target.ImportURLs(ctx, bytes.NewReader(data), shortify.ImportURLsRequest{Format: format, DryRun: true})
+			require.NoError(t, err)
+
+			_, getErr := target.GetURL(ctx, first.Alias)
+
+			imported, err := target.ImportURLs(ctx, bytes.NewReader(data), shortify.ImportURLsRequest{Format: format})
+			require.NoError(t, err)
+
+			again, err := target.ImportURLs(ctx, bytes.NewReader(data), shortify.ImportURLsRequest{Format: format})
+			require.NoError(t, err)
+
+			// Then
+			require.Equal(t, 2, dryRun.Imported)
+			require.ErrorIs(t, getErr, shortify.ErrNotFound)
+
+			require.Equal(t, 2, imported.Imported)
+			require.Zero(t, imported.Failed)
+
+			got, err := target.GetURL(ctx, first.Alias)
+			require.NoError(t, err)
+			require.Equal(t, "https://example.com/1", got.Original)
+			require.Equal(t, []string{"promo"}, got.Tags)
+			require.Equal(t, map[string]string{"team": "a"}, got.Metadata)
+			require.True(t, first.CreatedAt.Equal(got.CreatedAt))
+
+			got, err = target.GetURL(ctx, second.Alias)
+			require.NoError(t, err)
+			require.Equal(t, "https://example.com/2", got.Original)
+
+			require.Zero(t, again.Imported)
+			require.Equal(t, 2, again.Failed)
+			require.Equal(t, http.StatusConflict, again.Errors[0].Status)
+			require.Equal(t, first.Alias, again.Errors[0].Alias)
+		})
+	}
+}
+
+func TestClient_ImportURLs_UnknownColumn(t *testing.T) {
+	// Given
+	ctx := context.Background()
+	client := shortify.NewClient(newServer(t).URL)
+
+	// When
+	_, err := client.ImportURLs(ctx, strings.NewReader("original,owner\nhttps://example.com/1,me\n"), shortify.ImportURLsRequest{
+		Format: shortify.FormatCSV,
+	})
+
+	// Then
+	var apiErr *shortify.Error
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusBadRequest, apiErr.StatusCode)
+	require.Equal(t, "Unknown column 'owner'", apiErr.Message)
+}
+
+func newServer(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	log := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	charset := "abcdefghijklmnopqrstuvwxyz0123456789"
+
+	urlSvc := url.NewService(
+		inmemory.NewURLRepository(),
+		rand.NewAliasProvider(charset, 10),
+		log,
+		url.WithKeyspace("random", keyspace.Size(charset, 10)),
+		url.WithAuditLog(inmemory.NewAuditRepository()),
+	)
+	webhookSvc := webhook.NewService(inmemory.NewWebhookRepository(), log)
+
+	router := httpdel.NewRouter(
+		httpdel.NewURLController(urlSvc, log),
+		httpdel.NewWebhookController(webhookSvc, log),
+		log,
+	)
+
+	srv := httptest.NewServer(router)
+	t.Cleanup(srv.Close)
+
+	return srv
+}
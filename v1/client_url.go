@@ -0,0 +1,270 @@
+package shortify
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	neturl "net/url"
+	"strconv"
+)
+
+// ErrPreview is returned by Client.Resolve for preview URLs
+// which render a page instead of redirecting.
+var ErrPreview = errors.New("shortify: preview page instead of redirect")
+
+// CreateURL creates new URL and generates an alias for it.
+func (c *Client) CreateURL(ctx context.Context, req CreateURLRequest) (*CreateURLResponse, error) {
+	var resp CreateURLResponse
+
+	err := c.do(ctx, http.MethodPost, "/api/v1/urls", nil, req, &resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return &resp, nil
+}
+
+// GetURL gets URL by its alias.
+func (c *Client) GetURL(ctx context.Context, alias string) (*GetURLByAliasResponse, error) {
+	var resp GetURLByAliasResponse
+
+	err := c.do(ctx, http.MethodGet, "/api/v1/urls/"+escape(alias), nil, nil, &resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return &resp, nil
+}
+
+// ListURLs lists URLs ordered by creation.
+func (c *Client) ListURLs(ctx context.Context, req ListURLsRequest) (*ListURLsResponse, error) {
+	query := neturl.Values{}
+
+	if req.Tag != "" {
+		query.Set("tag", req.Tag)
+	}
+
+	for key, value := range req.Metadata {
+		query.Set("meta."+key, value)
+	}
+
+	if req.Limit > 0 {
+		query.Set("limit", strconv.Itoa(req.Limit))
+	}
+
+	if req.Offset > 0 {
+		query.Set("offset", strconv.Itoa(req.Offset))
+	}
+
+	var resp ListURLsResponse
+
+	err := c.do(ctx, http.MethodGet, "/api/v1/urls", query, nil, &resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return &resp, nil
+}
+
+// ExportURLs writes the URLs ordered by creation to w as a file in the requested format.
+func (c *Client) ExportURLs(ctx context.Context, w io.Writer, req ExportURLsRequest) error {
+	query := neturl.Values{}
+
+	if req.Format != "" {
+		query.Set("format", req.Format)
+	}
+
+	if req.Tag != "" {
+		query.Set("tag", req.Tag)
+	}
+
+	for key, value := range req.Metadata {
+		query.Set("meta."+key, value)
+	}
+
+	resp, err := c.send(ctx, http.MethodGet, "/api/v1/urls/export", query, nil, c.httpClient)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		return decodeError(resp)
+	}
+
+	_, err = io.Copy(w, resp.Body)
+	if err != nil {
+		return fmt.Errorf("shortify: failed to read export: %w", err)
+	}
+
+	return nil
+}
+
+// ImportURLs uploads the file read from r and creates its URLs keeping their aliases.
+// The upload isn't retried since r can't be read again.
+func (c *Client) ImportURLs(ctx context.Context, r io.Reader, req ImportURLsRequest) (*ImportURLsResponse, error) {
+	format := req.Format
+	if format == "" {
+		format = FormatJSONL
+	}
+
+	query := neturl.Values{}
+	query.Set("format", format)
+
+	if req.DryRun {
+		query.Set("dry_run", "true")
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+"/api/v1/urls/import?"+query.Encode(), r)
+	if err != nil {
+		return nil, fmt.Errorf("shortify: failed to create request: %w", err)
+	}
+
+	for key, values := range c.header {
+		httpReq.Header[key] = values
+	}
+
+	contentType := "application/x-ndjson"
+	if format == FormatCSV {
+		contentType = "text/csv"
+	}
+
+	httpReq.Header.Set("Accept", "application/json")
+	httpReq.Header.Set("Content-Type", contentType)
+
+	resp, err := c.httpClient.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("shortify: failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		return nil, decodeError(resp)
+	}
+
+	var out ImportURLsResponse
+
+	err = json.NewDecoder(resp.Body).Decode(&out)
+	if err != nil {
+		return nil, fmt.Errorf("shortify: failed to decode response: %w", err)
+	}
+
+	return &out, nil
+}
+
+// UpdateURL updates the fields of the URL present in the request.
+func (c *Client) UpdateURL(ctx context.Context, alias string, req UpdateURLRequest) (*GetURLByAliasResponse, error) {
+	var resp GetURLByAliasResponse
+
+	err := c.do(ctx, http.MethodPatch, "/api/v1/urls/"+escape(alias), nil, req, &resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return &resp, nil
+}
+
+// DeleteURL moves URL to the trash by its alias.
+// Requests to the deleted URL fail with ErrDeleted until it's restored.
+func (c *Client) DeleteURL(ctx context.Context, alias string) error {
+	return c.do(ctx, http.MethodDelete, "/api/v1/urls/"+escape(alias), nil, nil, nil)
+}
+
+// RestoreURL takes the deleted URL out of the trash.
+func (c *Client) RestoreURL(ctx context.Context, alias string) (*GetURLByAliasResponse, error) {
+	var resp GetURLByAliasResponse
+
+	err := c.do(ctx, http.MethodPost, "/api/v1/urls/"+escape(alias)+"/restore", nil, nil, &resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return &resp, nil
+}
+
+// AliasCapacity reports how much of the alias keyspace is occupied.
+func (c *Client) AliasCapacity(ctx context.Context) (*AliasCapacityResponse, error) {
+	var resp AliasCapacityResponse
+
+	err := c.do(ctx, http.MethodGet, "/api/v1/aliases/capacity", nil, nil, &resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return &resp, nil
+}
+
+// ListAliases lists the aliases the URL had before its current one.
+// The alias may be the current or a retired one.
+func (c *Client) ListAliases(ctx context.Context, alias string) (*AliasHistoryResponse, error) {
+	var resp AliasHistoryResponse
+
+	err := c.do(ctx, http.MethodGet, "/api/v1/urls/"+escape(alias)+"/aliases", nil, nil, &resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return &resp, nil
+}
+
+// URLHistory lists the recorded versions of the URL, oldest first.
+func (c *Client) URLHistory(ctx context.Context, alias string) (*URLHistoryResponse, error) {
+	var resp URLHistoryResponse
+
+	err := c.do(ctx, http.MethodGet, "/api/v1/urls/"+escape(alias)+"/history", nil, nil, &resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return &resp, nil
+}
+
+// RollbackURL restores the URL to the state it had after the given version.
+func (c *Client) RollbackURL(ctx context.Context, alias string, version int) (*GetURLByAliasResponse, error) {
+	var resp GetURLByAliasResponse
+
+	err := c.do(ctx, http.MethodPost, "/api/v1/urls/"+escape(alias)+"/rollback", nil, RollbackURLRequest{Version: version}, &resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return &resp, nil
+}
+
+// Resolve returns the location the alias redirects to without following it.
+// The escaped path is appended to the alias and the query is forwarded
+// as the URL query mode defines. Resolving counts a click.
+func (c *Client) Resolve(ctx context.Context, alias, path string, query neturl.Values) (string, error) {
+	httpClient := *c.httpClient
+	httpClient.CheckRedirect = func(*http.Request, []*http.Request) error {
+		return http.ErrUseLastResponse
+	}
+
+	p := "/" + escape(alias)
+	if path != "" {
+		p += "/" + path
+	}
+
+	resp, err := c.send(ctx, http.MethodGet, p, query, nil, &httpClient)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode >= http.StatusBadRequest:
+		return "", decodeError(resp)
+	case resp.StatusCode >= http.StatusMultipleChoices:
+		location := resp.Header.Get("Location")
+		if location == "" {
+			return "", fmt.Errorf("shortify: redirect without location")
+		}
+
+		return location, nil
+	default:
+		return "", ErrPreview
+	}
+}
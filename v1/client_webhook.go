@@ -0,0 +1,72 @@
+package shortify
+
+import (
+	"context"
+	"net/http"
+	neturl "net/url"
+	"strconv"
+)
+
+// CreateWebhook registers new webhook. The response carries the webhook secret.
+func (c *Client) CreateWebhook(ctx context.Context, req CreateWebhookRequest) (*WebhookResponse, error) {
+	var resp WebhookResponse
+
+	err := c.do(ctx, http.MethodPost, "/api/v1/webhooks", nil, req, &resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return &resp, nil
+}
+
+// GetWebhook gets webhook by its ID.
+func (c *Client) GetWebhook(ctx context.Context, id int64) (*WebhookResponse, error) {
+	var resp WebhookResponse
+
+	err := c.do(ctx, http.MethodGet, webhookPath(id), nil, nil, &resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return &resp, nil
+}
+
+// ListWebhooks lists all webhooks.
+func (c *Client) ListWebhooks(ctx context.Context) (*ListWebhooksResponse, error) {
+	var resp ListWebhooksResponse
+
+	err := c.do(ctx, http.MethodGet, "/api/v1/webhooks", nil, nil, &resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return &resp, nil
+}
+
+// DeleteWebhook deletes webhook by its ID.
+func (c *Client) DeleteWebhook(ctx context.Context, id int64) error {
+	return c.do(ctx, http.MethodDelete, webhookPath(id), nil, nil, nil)
+}
+
+// ListWebhookDeliveries lists the most recent deliveries of the webhook.
+// Zero limit lists the maximum number of deliveries the server allows.
+func (c *Client) ListWebhookDeliveries(ctx context.Context, id int64, limit int) (*ListDeliveriesResponse, error) {
+	query := neturl.Values{}
+
+	if limit > 0 {
+		query.Set("limit", strconv.Itoa(limit))
+	}
+
+	var resp ListDeliveriesResponse
+
+	err := c.do(ctx, http.MethodGet, webhookPath(id)+"/deliveries", query, nil, &resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return &resp, nil
+}
+
+func webhookPath(id int64) string {
+	return "/api/v1/webhooks/" + strconv.FormatInt(id, 10)
+}
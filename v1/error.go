@@ -1,6 +1,52 @@
 package shortify
 
+import (
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// Errors returned by Client matched with errors.Is.
+var (
+	ErrBadRequest    = errors.New("shortify: bad request")
+	ErrNotFound      = errors.New("shortify: not found")
+	ErrDeleted       = errors.New("shortify: deleted")
+	ErrAlreadyExists = errors.New("shortify: already exists")
+	ErrRateLimited   = errors.New("shortify: rate limited")
+	ErrServer        = errors.New("shortify: server error")
+)
+
 type ErrorResponse struct {
 	Status  int    `json:"status"`
 	Message string `json:"message"`
 }
+
+// Error is returned by Client when the API responds with an unsuccessful status.
+type Error struct {
+	StatusCode int
+	Message    string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("shortify: %d %s: %s", e.StatusCode, http.StatusText(e.StatusCode), e.Message)
+}
+
+// Is reports whether the error corresponds to target sentinel error.
+func (e *Error) Is(target error) bool {
+	switch target {
+	case ErrBadRequest:
+		return e.StatusCode == http.StatusBadRequest
+	case ErrNotFound:
+		return e.StatusCode == http.StatusNotFound
+	case ErrDeleted:
+		return e.StatusCode == http.StatusGone
+	case ErrAlreadyExists:
+		return e.StatusCode == http.StatusConflict
+	case ErrRateLimited:
+		return e.StatusCode == http.StatusTooManyRequests
+	case ErrServer:
+		return e.StatusCode >= http.StatusInternalServerError
+	default:
+		return false
+	}
+}
@@ -0,0 +1,628 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        (unknown)
+// source: shortify/v1/url.proto
+
+package shortifypb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type CreateURLRequest struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	Original string                 `protobuf:"bytes,1,opt,name=original,proto3" json:"original,omitempty"`
+	// One of "ignore", "merge" or "override". Defaults to "ignore".
+	QueryMode     string            `protobuf:"bytes,2,opt,name=query_mode,json=queryMode,proto3" json:"query_mode,omitempty"`
+	Preview       bool              `protobuf:"varint,3,opt,name=preview,proto3" json:"preview,omitempty"`
+	Title         string            `protobuf:"bytes,4,opt,name=title,proto3" json:"title,omitempty"`
+	Description   string            `protobuf:"bytes,5,opt,name=description,proto3" json:"description,omitempty"`
+	Tags          []string          `protobuf:"bytes,6,rep,name=tags,proto3" json:"tags,omitempty"`
+	Metadata      map[string]string `protobuf:"bytes,7,rep,name=metadata,proto3" json:"metadata,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateURLRequest) Reset() {
+	*x = CreateURLRequest{}
+	mi := &file_shortify_v1_url_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateURLRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateURLRequest) ProtoMessage() {}
+
+func (x *CreateURLRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_shortify_v1_url_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateURLRequest.ProtoReflect.Descriptor instead.
+func (*CreateURLRequest) Descriptor() ([]byte, []int) {
+	return file_shortify_v1_url_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *CreateURLRequest) GetOriginal() string {
+	if x != nil {
+		return x.Original
+	}
+	return ""
+}
+
+func (x *CreateURLRequest) GetQueryMode() string {
+	if x != nil {
+		return x.QueryMode
+	}
+	return ""
+}
+
+func (x *CreateURLRequest) GetPreview() bool {
+	if x != nil {
+		return x.Preview
+	}
+	return false
+}
+
+func (x *CreateURLRequest) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *CreateURLRequest) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *CreateURLRequest) GetTags() []string {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
+func (x *CreateURLRequest) GetMetadata() map[string]string {
+	if x != nil {
+		return x.Metadata
+	}
+	return nil
+}
+
+type GetURLByAliasRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Alias         string                 `protobuf:"bytes,1,opt,name=alias,proto3" json:"alias,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetURLByAliasRequest) Reset() {
+	*x = GetURLByAliasRequest{}
+	mi := &file_shortify_v1_url_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetURLByAliasRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetURLByAliasRequest) ProtoMessage() {}
+
+func (x *GetURLByAliasRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_shortify_v1_url_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetURLByAliasRequest.ProtoReflect.Descriptor instead.
+func (*GetURLByAliasRequest) Descriptor() ([]byte, []int) {
+	return file_shortify_v1_url_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *GetURLByAliasRequest) GetAlias() string {
+	if x != nil {
+		return x.Alias
+	}
+	return ""
+}
+
+type URL struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Original      string                 `protobuf:"bytes,1,opt,name=original,proto3" json:"original,omitempty"`
+	Alias         string                 `protobuf:"bytes,2,opt,name=alias,proto3" json:"alias,omitempty"`
+	QueryMode     string                 `protobuf:"bytes,3,opt,name=query_mode,json=queryMode,proto3" json:"query_mode,omitempty"`
+	Preview       bool                   `protobuf:"varint,4,opt,name=preview,proto3" json:"preview,omitempty"`
+	Title         string                 `protobuf:"bytes,5,opt,name=title,proto3" json:"title,omitempty"`
+	Description   string                 `protobuf:"bytes,6,opt,name=description,proto3" json:"description,omitempty"`
+	Tags          []string               `protobuf:"bytes,7,rep,name=tags,proto3" json:"tags,omitempty"`
+	Metadata      map[string]string      `protobuf:"bytes,8,rep,name=metadata,proto3" json:"metadata,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	Clicks        int64                  `protobuf:"varint,9,opt,name=clicks,proto3" json:"clicks,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,10,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	OpenGraph     *OpenGraph             `protobuf:"bytes,11,opt,name=open_graph,json=openGraph,proto3" json:"open_graph,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *URL) Reset() {
+	*x = URL{}
+	mi := &file_shortify_v1_url_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *URL) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*URL) ProtoMessage() {}
+
+func (x *URL) ProtoReflect() protoreflect.Message {
+	mi := &file_shortify_v1_url_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use URL.ProtoReflect.Descriptor instead.
+func (*URL) Descriptor() ([]byte, []int) {
+	return file_shortify_v1_url_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *URL) GetOriginal() string {
+	if x != nil {
+		return x.Original
+	}
+	return ""
+}
+
+func (x *URL) GetAlias() string {
+	if x != nil {
+		return x.Alias
+	}
+	return ""
+}
+
+func (x *URL) GetQueryMode() string {
+	if x != nil {
+		return x.QueryMode
+	}
+	return ""
+}
+
+func (x *URL) GetPreview() bool {
+	if x != nil {
+		return x.Preview
+	}
+	return false
+}
+
+func (x *URL) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *URL) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *URL) GetTags() []string {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
+func (x *URL) GetMetadata() map[string]string {
+	if x != nil {
+		return x.Metadata
+	}
+	return nil
+}
+
+func (x *URL) GetClicks() int64 {
+	if x != nil {
+		return x.Clicks
+	}
+	return 0
+}
+
+func (x *URL) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *URL) GetOpenGraph() *OpenGraph {
+	if x != nil {
+		return x.OpenGraph
+	}
+	return nil
+}
+
+type OpenGraph struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Title         string                 `protobuf:"bytes,1,opt,name=title,proto3" json:"title,omitempty"`
+	Description   string                 `protobuf:"bytes,2,opt,name=description,proto3" json:"description,omitempty"`
+	Image         string                 `protobuf:"bytes,3,opt,name=image,proto3" json:"image,omitempty"`
+	FetchedAt     *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=fetched_at,json=fetchedAt,proto3" json:"fetched_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *OpenGraph) Reset() {
+	*x = OpenGraph{}
+	mi := &file_shortify_v1_url_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OpenGraph) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OpenGraph) ProtoMessage() {}
+
+func (x *OpenGraph) ProtoReflect() protoreflect.Message {
+	mi := &file_shortify_v1_url_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OpenGraph.ProtoReflect.Descriptor instead.
+func (*OpenGraph) Descriptor() ([]byte, []int) {
+	return file_shortify_v1_url_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *OpenGraph) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *OpenGraph) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *OpenGraph) GetImage() string {
+	if x != nil {
+		return x.Image
+	}
+	return ""
+}
+
+func (x *OpenGraph) GetFetchedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.FetchedAt
+	}
+	return nil
+}
+
+type BatchCreateURLResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Index of the request in the stream starting from 0.
+	Index int64 `protobuf:"varint,1,opt,name=index,proto3" json:"index,omitempty"`
+	// Types that are valid to be assigned to Result:
+	//
+	//	*BatchCreateURLResponse_Url
+	//	*BatchCreateURLResponse_Error
+	Result        isBatchCreateURLResponse_Result `protobuf_oneof:"result"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BatchCreateURLResponse) Reset() {
+	*x = BatchCreateURLResponse{}
+	mi := &file_shortify_v1_url_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BatchCreateURLResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchCreateURLResponse) ProtoMessage() {}
+
+func (x *BatchCreateURLResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_shortify_v1_url_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchCreateURLResponse.ProtoReflect.Descriptor instead.
+func (*BatchCreateURLResponse) Descriptor() ([]byte, []int) {
+	return file_shortify_v1_url_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *BatchCreateURLResponse) GetIndex() int64 {
+	if x != nil {
+		return x.Index
+	}
+	return 0
+}
+
+func (x *BatchCreateURLResponse) GetResult() isBatchCreateURLResponse_Result {
+	if x != nil {
+		return x.Result
+	}
+	return nil
+}
+
+func (x *BatchCreateURLResponse) GetUrl() *URL {
+	if x != nil {
+		if x, ok := x.Result.(*BatchCreateURLResponse_Url); ok {
+			return x.Url
+		}
+	}
+	return nil
+}
+
+func (x *BatchCreateURLResponse) GetError() *Error {
+	if x != nil {
+		if x, ok := x.Result.(*BatchCreateURLResponse_Error); ok {
+			return x.Error
+		}
+	}
+	return nil
+}
+
+type isBatchCreateURLResponse_Result interface {
+	isBatchCreateURLResponse_Result()
+}
+
+type BatchCreateURLResponse_Url struct {
+	Url *URL `protobuf:"bytes,2,opt,name=url,proto3,oneof"`
+}
+
+type BatchCreateURLResponse_Error struct {
+	Error *Error `protobuf:"bytes,3,opt,name=error,proto3,oneof"`
+}
+
+func (*BatchCreateURLResponse_Url) isBatchCreateURLResponse_Result() {}
+
+func (*BatchCreateURLResponse_Error) isBatchCreateURLResponse_Result() {}
+
+type Error struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// gRPC status code.
+	Code          int32  `protobuf:"varint,1,opt,name=code,proto3" json:"code,omitempty"`
+	Message       string `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Error) Reset() {
+	*x = Error{}
+	mi := &file_shortify_v1_url_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Error) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Error) ProtoMessage() {}
+
+func (x *Error) ProtoReflect() protoreflect.Message {
+	mi := &file_shortify_v1_url_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Error.ProtoReflect.Descriptor instead.
+func (*Error) Descriptor() ([]byte, []int) {
+	return file_shortify_v1_url_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *Error) GetCode() int32 {
+	if x != nil {
+		return x.Code
+	}
+	return 0
+}
+
+func (x *Error) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+var File_shortify_v1_url_proto protoreflect.FileDescriptor
+
+const file_shortify_v1_url_proto_rawDesc = "" +
+	"\n" +
+	"\x15shortify/v1/url.proto\x12\vshortify.v1\x1a\x1fgoogle/protobuf/timestamp.proto\"\xb9\x02\n" +
+	"\x10CreateURLRequest\x12\x1a\n" +
+	"\boriginal\x18\x01 \x01(\tR\boriginal\x12\x1d\n" +
+	"\n" +
+	"query_mode\x18\x02 \x01(\tR\tqueryMode\x12\x18\n" +
+	"\apreview\x18\x03 \x01(\bR\apreview\x12\x14\n" +
+	"\x05title\x18\x04 \x01(\tR\x05title\x12 \n" +
+	"\vdescription\x18\x05 \x01(\tR\vdescription\x12\x12\n" +
+	"\x04tags\x18\x06 \x03(\tR\x04tags\x12G\n" +
+	"\bmetadata\x18\a \x03(\v2+.shortify.v1.CreateURLRequest.MetadataEntryR\bmetadata\x1a;\n" +
+	"\rMetadataEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\",\n" +
+	"\x14GetURLByAliasRequest\x12\x14\n" +
+	"\x05alias\x18\x01 \x01(\tR\x05alias\"\xbf\x03\n" +
+	"\x03URL\x12\x1a\n" +
+	"\boriginal\x18\x01 \x01(\tR\boriginal\x12\x14\n" +
+	"\x05alias\x18\x02 \x01(\tR\x05alias\x12\x1d\n" +
+	"\n" +
+	"query_mode\x18\x03 \x01(\tR\tqueryMode\x12\x18\n" +
+	"\apreview\x18\x04 \x01(\bR\apreview\x12\x14\n" +
+	"\x05title\x18\x05 \x01(\tR\x05title\x12 \n" +
+	"\vdescription\x18\x06 \x01(\tR\vdescription\x12\x12\n" +
+	"\x04tags\x18\a \x03(\tR\x04tags\x12:\n" +
+	"\bmetadata\x18\b \x03(\v2\x1e.shortify.v1.URL.MetadataEntryR\bmetadata\x12\x16\n" +
+	"\x06clicks\x18\t \x01(\x03R\x06clicks\x129\n" +
+	"\n" +
+	"created_at\x18\n" +
+	" \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x125\n" +
+	"\n" +
+	"open_graph\x18\v \x01(\v2\x16.shortify.v1.OpenGraphR\topenGraph\x1a;\n" +
+	"\rMetadataEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\x94\x01\n" +
+	"\tOpenGraph\x12\x14\n" +
+	"\x05title\x18\x01 \x01(\tR\x05title\x12 \n" +
+	"\vdescription\x18\x02 \x01(\tR\vdescription\x12\x14\n" +
+	"\x05image\x18\x03 \x01(\tR\x05image\x129\n" +
+	"\n" +
+	"fetched_at\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\tfetchedAt\"\x8a\x01\n" +
+	"\x16BatchCreateURLResponse\x12\x14\n" +
+	"\x05index\x18\x01 \x01(\x03R\x05index\x12$\n" +
+	"\x03url\x18\x02 \x01(\v2\x10.shortify.v1.URLH\x00R\x03url\x12*\n" +
+	"\x05error\x18\x03 \x01(\v2\x12.shortify.v1.ErrorH\x00R\x05errorB\b\n" +
+	"\x06result\"5\n" +
+	"\x05Error\x12\x12\n" +
+	"\x04code\x18\x01 \x01(\x05R\x04code\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage2\xe1\x01\n" +
+	"\n" +
+	"URLService\x129\n" +
+	"\x06Create\x12\x1d.shortify.v1.CreateURLRequest\x1a\x10.shortify.v1.URL\x12A\n" +
+	"\n" +
+	"GetByAlias\x12!.shortify.v1.GetURLByAliasRequest\x1a\x10.shortify.v1.URL\x12U\n" +
+	"\vBatchCreate\x12\x1d.shortify.v1.CreateURLRequest\x1a#.shortify.v1.BatchCreateURLResponse(\x010\x01B7Z5github.com/kodeyeen/shortify/v1/shortifypb;shortifypbb\x06proto3"
+
+var (
+	file_shortify_v1_url_proto_rawDescOnce sync.Once
+	file_shortify_v1_url_proto_rawDescData []byte
+)
+
+func file_shortify_v1_url_proto_rawDescGZIP() []byte {
+	file_shortify_v1_url_proto_rawDescOnce.Do(func() {
+		file_shortify_v1_url_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_shortify_v1_url_proto_rawDesc), len(file_shortify_v1_url_proto_rawDesc)))
+	})
+	return file_shortify_v1_url_proto_rawDescData
+}
+
+var file_shortify_v1_url_proto_msgTypes = make([]protoimpl.MessageInfo, 8)
+var file_shortify_v1_url_proto_goTypes = []any{
+	(*CreateURLRequest)(nil),       // 0: shortify.v1.CreateURLRequest
+	(*GetURLByAliasRequest)(nil),   // 1: shortify.v1.GetURLByAliasRequest
+	(*URL)(nil),                    // 2: shortify.v1.URL
+	(*OpenGraph)(nil),              // 3: shortify.v1.OpenGraph
+	(*BatchCreateURLResponse)(nil), // 4: shortify.v1.BatchCreateURLResponse
+	(*Error)(nil),                  // 5: shortify.v1.Error
+	nil,                            // 6: shortify.v1.CreateURLRequest.MetadataEntry
+	nil,                            // 7: shortify.v1.URL.MetadataEntry
+	(*timestamppb.Timestamp)(nil),  // 8: google.protobuf.Timestamp
+}
+var file_shortify_v1_url_proto_depIdxs = []int32{
+	6,  // 0: shortify.v1.CreateURLRequest.metadata:type_name -> shortify.v1.CreateURLRequest.MetadataEntry
+	7,  // 1: shortify.v1.URL.metadata:type_name -> shortify.v1.URL.MetadataEntry
+	8,  // 2: shortify.v1.URL.created_at:type_name -> google.protobuf.Timestamp
+	3,  // 3: shortify.v1.URL.open_graph:type_name -> shortify.v1.OpenGraph
+	8,  // 4: shortify.v1.OpenGraph.fetched_at:type_name -> google.protobuf.Timestamp
+	2,  // 5: shortify.v1.BatchCreateURLResponse.url:type_name -> shortify.v1.URL
+	5,  // 6: shortify.v1.BatchCreateURLResponse.error:type_name -> shortify.v1.Error
+	0,  // 7: shortify.v1.URLService.Create:input_type -> shortify.v1.CreateURLRequest
+	1,  // 8: shortify.v1.URLService.GetByAlias:input_type -> shortify.v1.GetURLByAliasRequest
+	0,  // 9: shortify.v1.URLService.BatchCreate:input_type -> shortify.v1.CreateURLRequest
+	2,  // 10: shortify.v1.URLService.Create:output_type -> shortify.v1.URL
+	2,  // 11: shortify.v1.URLService.GetByAlias:output_type -> shortify.v1.URL
+	4,  // 12: shortify.v1.URLService.BatchCreate:output_type -> shortify.v1.BatchCreateURLResponse
+	10, // [10:13] is the sub-list for method output_type
+	7,  // [7:10] is the sub-list for method input_type
+	7,  // [7:7] is the sub-list for extension type_name
+	7,  // [7:7] is the sub-list for extension extendee
+	0,  // [0:7] is the sub-list for field type_name
+}
+
+func init() { file_shortify_v1_url_proto_init() }
+func file_shortify_v1_url_proto_init() {
+	if File_shortify_v1_url_proto != nil {
+		return
+	}
+	file_shortify_v1_url_proto_msgTypes[4].OneofWrappers = []any{
+		(*BatchCreateURLResponse_Url)(nil),
+		(*BatchCreateURLResponse_Error)(nil),
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_shortify_v1_url_proto_rawDesc), len(file_shortify_v1_url_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   8,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_shortify_v1_url_proto_goTypes,
+		DependencyIndexes: file_shortify_v1_url_proto_depIdxs,
+		MessageInfos:      file_shortify_v1_url_proto_msgTypes,
+	}.Build()
+	File_shortify_v1_url_proto = out.File
+	file_shortify_v1_url_proto_goTypes = nil
+	file_shortify_v1_url_proto_depIdxs = nil
+}
@@ -0,0 +1,206 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             (unknown)
+// source: shortify/v1/url.proto
+
+package shortifypb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	URLService_Create_FullMethodName      = "/shortify.v1.URLService/Create"
+	URLService_GetByAlias_FullMethodName  = "/shortify.v1.URLService/GetByAlias"
+	URLService_BatchCreate_FullMethodName = "/shortify.v1.URLService/BatchCreate"
+)
+
+// URLServiceClient is the client API for URLService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// URLService creates and resolves short URLs.
+type URLServiceClient interface {
+	// Create creates new URL and generates an alias for it.
+	Create(ctx context.Context, in *CreateURLRequest, opts ...grpc.CallOption) (*URL, error)
+	// GetByAlias gets URL by its alias.
+	GetByAlias(ctx context.Context, in *GetURLByAliasRequest, opts ...grpc.CallOption) (*URL, error)
+	// BatchCreate creates URLs as they are streamed. Every request gets
+	// a response with the same index carrying either the URL or the error
+	// so a single failure doesn't abort the stream.
+	BatchCreate(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[CreateURLRequest, BatchCreateURLResponse], error)
+}
+
+type uRLServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewURLServiceClient(cc grpc.ClientConnInterface) URLServiceClient {
+	return &uRLServiceClient{cc}
+}
+
+func (c *uRLServiceClient) Create(ctx context.Context, in *CreateURLRequest, opts ...grpc.CallOption) (*URL, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(URL)
+	err := c.cc.Invoke(ctx, URLService_Create_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *uRLServiceClient) GetByAlias(ctx context.Context, in *GetURLByAliasRequest, opts ...grpc.CallOption) (*URL, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(URL)
+	err := c.cc.Invoke(ctx, URLService_GetByAlias_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *uRLServiceClient) BatchCreate(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[CreateURLRequest, BatchCreateURLResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &URLService_ServiceDesc.Streams[0], URLService_BatchCreate_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[CreateURLRequest, BatchCreateURLResponse]{ClientStream: stream}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type URLService_BatchCreateClient = grpc.BidiStreamingClient[CreateURLRequest, BatchCreateURLResponse]
+
+// URLServiceServer is the server API for URLService service.
+// All implementations must embed UnimplementedURLServiceServer
+// for forward compatibility.
+//
+// URLService creates and resolves short URLs.
+type URLServiceServer interface {
+	// Create creates new URL and generates an alias for it.
+	Create(context.Context, *CreateURLRequest) (*URL, error)
+	// GetByAlias gets URL by its alias.
+	GetByAlias(context.Context, *GetURLByAliasRequest) (*URL, error)
+	// BatchCreate creates URLs as they are streamed. Every request gets
+	// a response with the same index carrying either the URL or the error
+	// so a single failure doesn't abort the stream.
+	BatchCreate(grpc.BidiStreamingServer[CreateURLRequest, BatchCreateURLResponse]) error
+	mustEmbedUnimplementedURLServiceServer()
+}
+
+// UnimplementedURLServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedURLServiceServer struct{}
+
+func (UnimplementedURLServiceServer) Create(context.Context, *CreateURLRequest) (*URL, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Create not implemented")
+}
+func (UnimplementedURLServiceServer) GetByAlias(context.Context, *GetURLByAliasRequest) (*URL, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetByAlias not implemented")
+}
+func (UnimplementedURLServiceServer) BatchCreate(grpc.BidiStreamingServer[CreateURLRequest, BatchCreateURLResponse]) error {
+	return status.Errorf(codes.Unimplemented, "method BatchCreate not implemented")
+}
+func (UnimplementedURLServiceServer) mustEmbedUnimplementedURLServiceServer() {}
+func (UnimplementedURLServiceServer) testEmbeddedByValue()                    {}
+
+// UnsafeURLServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to URLServiceServer will
+// result in compilation errors.
+type UnsafeURLServiceServer interface {
+	mustEmbedUnimplementedURLServiceServer()
+}
+
+func RegisterURLServiceServer(s grpc.ServiceRegistrar, srv URLServiceServer) {
+	// If the following call pancis, it indicates UnimplementedURLServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&URLService_ServiceDesc, srv)
+}
+
+func _URLService_Create_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateURLRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(URLServiceServer).Create(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: URLService_Create_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(URLServiceServer).Create(ctx, req.(*CreateURLRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _URLService_GetByAlias_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetURLByAliasRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(URLServiceServer).GetByAlias(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: URLService_GetByAlias_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(URLServiceServer).GetByAlias(ctx, req.(*GetURLByAliasRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _URLService_BatchCreate_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(URLServiceServer).BatchCreate(&grpc.GenericServerStream[CreateURLRequest, BatchCreateURLResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type URLService_BatchCreateServer = grpc.BidiStreamingServer[CreateURLRequest, BatchCreateURLResponse]
+
+// URLService_ServiceDesc is the grpc.ServiceDesc for URLService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var URLService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "shortify.v1.URLService",
+	HandlerType: (*URLServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Create",
+			Handler:    _URLService_Create_Handler,
+		},
+		{
+			MethodName: "GetByAlias",
+			Handler:    _URLService_GetByAlias_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "BatchCreate",
+			Handler:       _URLService_BatchCreate_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+	},
+	Metadata: "shortify/v1/url.proto",
+}
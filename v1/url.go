@@ -1,18 +1,197 @@
 package shortify
 
+import "time"
+
 type CreateURLRequest struct {
-	Original string `json:"original" validate:"required,url"`
+	Original    string            `json:"original" validate:"required,url"`
+	QueryMode   string            `json:"query_mode,omitempty" validate:"omitempty,oneof=ignore merge override"`
+	Preview     bool              `json:"preview,omitempty"`
+	Title       string            `json:"title,omitempty" validate:"max=256"`
+	Description string            `json:"description,omitempty" validate:"max=1024"`
+	Tags        []string          `json:"tags,omitempty" validate:"max=32,dive,required,max=64"`
+	Metadata    map[string]string `json:"metadata,omitempty" validate:"max=64,dive,keys,required,max=64,endkeys,max=1024"`
+	// AliasProvider chooses how the alias is generated, e.g. "words" for aliases like brave-orange-tiger.
+	AliasProvider string `json:"alias_provider,omitempty" validate:"max=32"`
 }
 
 type CreateURLResponse struct {
-	Original string `json:"original"`
-	Alias    string `json:"alias"`
+	Original    string            `json:"original"`
+	Alias       string            `json:"alias"`
+	QueryMode   string            `json:"query_mode"`
+	Preview     bool              `json:"preview"`
+	Title       string            `json:"title"`
+	Description string            `json:"description"`
+	Tags        []string          `json:"tags"`
+	Metadata    map[string]string `json:"metadata"`
+	CreatedAt   time.Time         `json:"created_at"`
 }
 
 type GetURLByAliasRequest struct {
 }
 
 type GetURLByAliasResponse struct {
-	Original string `json:"original"`
-	Alias    string `json:"alias"`
+	Original    string            `json:"original"`
+	Alias       string            `json:"alias"`
+	QueryMode   string            `json:"query_mode"`
+	Preview     bool              `json:"preview"`
+	Title       string            `json:"title"`
+	Description string            `json:"description"`
+	Tags        []string          `json:"tags"`
+	Metadata    map[string]string `json:"metadata"`
+	Clicks      int64             `json:"clicks"`
+	CreatedAt   time.Time         `json:"created_at"`
+	OpenGraph   *OpenGraph        `json:"open_graph,omitempty"`
+}
+
+// OpenGraph is the preview metadata of the page behind the URL.
+// It is fetched in the background after the URL is created.
+type OpenGraph struct {
+	Title       string    `json:"title"`
+	Description string    `json:"description"`
+	Image       string    `json:"image"`
+	FetchedAt   time.Time `json:"fetched_at"`
+}
+
+// UpdateURLRequest changes the fields that are present in the request.
+// Tags and metadata are replaced as a whole.
+type UpdateURLRequest struct {
+	// Alias changes the alias. The old alias keeps resolving to the URL.
+	Alias       *string            `json:"alias,omitempty" validate:"omitempty,alias,max=64"`
+	Original    *string            `json:"original,omitempty" validate:"omitempty,url"`
+	QueryMode   *string            `json:"query_mode,omitempty" validate:"omitempty,oneof=ignore merge override"`
+	Preview     *bool              `json:"preview,omitempty"`
+	Title       *string            `json:"title,omitempty" validate:"omitempty,max=256"`
+	Description *string            `json:"description,omitempty" validate:"omitempty,max=1024"`
+	Tags        *[]string          `json:"tags,omitempty" validate:"omitempty,max=32,dive,required,max=64"`
+	Metadata    *map[string]string `json:"metadata,omitempty" validate:"omitempty,max=64,dive,keys,required,max=64,endkeys,max=1024"`
+}
+
+// AliasHistoryResponse lists the aliases the URL had before its current one.
+type AliasHistoryResponse struct {
+	Alias   string         `json:"alias"`
+	Retired []RetiredAlias `json:"retired"`
+}
+
+// RetiredAlias keeps resolving to its URL. Other URLs can't take it until QuarantinedUntil.
+type RetiredAlias struct {
+	Alias            string    `json:"alias"`
+	RetiredAt        time.Time `json:"retired_at"`
+	QuarantinedUntil time.Time `json:"quarantined_until"`
+}
+
+// URLHistoryResponse lists the changes to the URL, the oldest first.
+type URLHistoryResponse struct {
+	Alias    string       `json:"alias"`
+	Versions []URLVersion `json:"versions"`
+}
+
+// URLVersion is a change to the URL. Versions are numbered from 1 in the order of changes.
+type URLVersion struct {
+	Version int `json:"version"`
+	// Action is one of created, updated, deleted, restored and rolled_back.
+	Action    string        `json:"action"`
+	Actor     string        `json:"actor"`
+	RequestID string        `json:"request_id"`
+	CreatedAt time.Time     `json:"created_at"`
+	Changes   []FieldChange `json:"changes"`
+}
+
+// FieldChange is a changed URL field, the missing side of a creation or deletion is null.
+type FieldChange struct {
+	Field  string `json:"field"`
+	Before any    `json:"before"`
+	After  any    `json:"after"`
+}
+
+// RollbackURLRequest restores the URL fields to the state after the version.
+type RollbackURLRequest struct {
+	Version int `json:"version" validate:"required,min=1"`
+}
+
+// ListURLsRequest filters listed URLs. Zero values are omitted.
+type ListURLsRequest struct {
+	Tag      string
+	Metadata map[string]string
+	Limit    int
+	Offset   int
+}
+
+type ListURLsResponse struct {
+	URLs []GetURLByAliasResponse `json:"urls"`
+}
+
+// AliasCapacityResponse describes how much of the alias keyspace is occupied.
+type AliasCapacityResponse struct {
+	// Provider is the default alias provider.
+	Provider string `json:"provider"`
+	// KeyspaceSize is the number of distinct aliases in decimal, it may not fit into a JSON number.
+	KeyspaceSize string  `json:"keyspace_size"`
+	KeyspaceBits float64 `json:"keyspace_bits"`
+	// Occupied is the number of stored URLs.
+	Occupied  int64   `json:"occupied"`
+	Occupancy float64 `json:"occupancy"`
+	// CollisionProbability is the chance a generated alias is taken.
+	CollisionProbability float64 `json:"collision_probability"`
+	// FailureProbability is the chance creating a URL fails since every generated alias is taken.
+	FailureProbability float64 `json:"failure_probability"`
+}
+
+// Formats of the import and export files.
+const (
+	// FormatCSV is a CSV file with a header. Tags are given as a JSON array
+	// and metadata as a JSON object.
+	FormatCSV = "csv"
+	// FormatJSONL is a file with a URLRecord object per line.
+	FormatJSONL = "jsonl"
+)
+
+// URLRecord is a URL in the import and export files.
+type URLRecord struct {
+	// Alias is kept on import, a generated one is used when it's empty.
+	Alias       string            `json:"alias,omitempty" validate:"omitempty,alias,max=64"`
+	Original    string            `json:"original" validate:"required,url"`
+	QueryMode   string            `json:"query_mode,omitempty" validate:"omitempty,oneof=ignore merge override"`
+	Preview     bool              `json:"preview,omitempty"`
+	Title       string            `json:"title,omitempty" validate:"max=256"`
+	Description string            `json:"description,omitempty" validate:"max=1024"`
+	Tags        []string          `json:"tags,omitempty" validate:"max=32,dive,required,max=64"`
+	Metadata    map[string]string `json:"metadata,omitempty" validate:"max=64,dive,keys,required,max=64,endkeys,max=1024"`
+	// Clicks is ignored on import, imported URLs start without clicks.
+	Clicks int64 `json:"clicks,omitempty"`
+	// CreatedAt is the time of import when it's omitted.
+	CreatedAt time.Time `json:"created_at,omitzero"`
+}
+
+// ExportURLsRequest chooses the format and filters exported URLs. Zero values are omitted.
+type ExportURLsRequest struct {
+	// Format is FormatCSV or FormatJSONL, the default one.
+	Format   string
+	Tag      string
+	Metadata map[string]string
+}
+
+// ImportURLsRequest describes the uploaded file.
+type ImportURLsRequest struct {
+	// Format is FormatCSV or FormatJSONL, the default one.
+	Format string
+	// DryRun checks the URLs for conflicts without adding them.
+	DryRun bool
+}
+
+// ImportURLsResponse reports the imported rows. Rows that weren't imported are listed in Errors.
+type ImportURLsResponse struct {
+	DryRun bool `json:"dry_run"`
+	// Imported is the number of added URLs, or the number of URLs that would be added in a dry run.
+	Imported int           `json:"imported"`
+	Failed   int           `json:"failed"`
+	Errors   []ImportError `json:"errors"`
+}
+
+// ImportError is a row that wasn't imported. Status is 409 for conflicts with stored URLs.
+type ImportError struct {
+	Line     int    `json:"line"`
+	Alias    string `json:"alias,omitempty"`
+	Original string `json:"original,omitempty"`
+	Status   int    `json:"status"`
+	Message  string `json:"message"`
 }
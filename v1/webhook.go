@@ -0,0 +1,66 @@
+package shortify
+
+import (
+	"encoding/json"
+	"time"
+)
+
+type CreateWebhookRequest struct {
+	URL string `json:"url" validate:"required,url"`
+	// Secret signs the deliveries. It's generated when empty.
+	Secret string   `json:"secret,omitempty" validate:"omitempty,min=16,max=256"`
+	Events []string `json:"events" validate:"required,min=1,dive,oneof=url.created url.updated url.deleted url.restored url.click_threshold"`
+}
+
+type WebhookResponse struct {
+	ID  int64  `json:"id"`
+	URL string `json:"url"`
+	// Secret is returned only when the webhook is created.
+	Secret    string    `json:"secret,omitempty"`
+	Events    []string  `json:"events"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type ListWebhooksResponse struct {
+	Webhooks []WebhookResponse `json:"webhooks"`
+}
+
+type DeliveryResponse struct {
+	ID             int64           `json:"id"`
+	Event          string          `json:"event"`
+	Payload        json.RawMessage `json:"payload" swaggertype:"object"`
+	Status         string          `json:"status"`
+	Attempts       int             `json:"attempts"`
+	NextAttemptAt  time.Time       `json:"next_attempt_at"`
+	LastError      string          `json:"last_error"`
+	ResponseStatus int             `json:"response_status"`
+	CreatedAt      time.Time       `json:"created_at"`
+	DeliveredAt    *time.Time      `json:"delivered_at"`
+}
+
+type ListDeliveriesResponse struct {
+	Deliveries []DeliveryResponse `json:"deliveries"`
+}
+
+// WebhookPayload is the body of webhook deliveries.
+//
+// Deliveries are POST requests carrying the X-Shortify-Event, X-Shortify-Delivery,
+// X-Shortify-Timestamp and X-Shortify-Signature headers. The signature is
+// "sha256=" followed by the hex encoded HMAC-SHA256 of the timestamp, a dot
+// and the request body keyed with the webhook secret.
+type WebhookPayload struct {
+	Event      string     `json:"event"`
+	OccurredAt time.Time  `json:"occurred_at"`
+	URL        WebhookURL `json:"url"`
+}
+
+type WebhookURL struct {
+	Original    string            `json:"original"`
+	Alias       string            `json:"alias"`
+	Title       string            `json:"title"`
+	Description string            `json:"description"`
+	Tags        []string          `json:"tags"`
+	Metadata    map[string]string `json:"metadata"`
+	Clicks      int64             `json:"clicks"`
+	CreatedAt   time.Time         `json:"created_at"`
+}